@@ -4,6 +4,8 @@ import (
 	"os"
 	"testing"
 
+	"github.com/bigpanther/trober/blobstore"
+	"github.com/bigpanther/trober/mailer"
 	"github.com/gobuffalo/suite/v4"
 	"github.com/golang/mock/gomock"
 )
@@ -13,12 +15,18 @@ type ActionSuite struct {
 }
 
 var mockFirebase *MockFirebase
+var fakeMailer *mailer.Fake
 
 func Test_ActionSuite(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	mockFirebase = NewMockFirebase(ctrl)
-	action, err := suite.NewActionWithFixtures(App(mockFirebase), os.DirFS("../fixtures"))
+	blobStore, err := blobstore.NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	fakeMailer = mailer.NewFake()
+	action, err := suite.NewActionWithFixtures(App(mockFirebase, blobStore, fakeMailer), os.DirFS("../fixtures"))
 	if err != nil {
 		t.Fatal(err)
 	}
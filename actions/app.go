@@ -8,9 +8,11 @@ import (
 	"os"
 
 	"firebase.google.com/go/v4/auth"
+	"github.com/bigpanther/trober/blobstore"
 	"github.com/bigpanther/trober/firebase"
 	"github.com/bigpanther/trober/models"
 	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/buffalo/mail"
 	"github.com/gobuffalo/envy"
 	forcessl "github.com/gobuffalo/mw-forcessl"
 	i18n "github.com/gobuffalo/mw-i18n/v2"
@@ -50,11 +52,17 @@ var T *i18n.Translator
 // `ServeFiles` is a CATCH-ALL route, so it should always be
 // placed last in the route declarations, as it will prevent routes
 // declared after it to never be called.
-func App(f firebase.Firebase) *buffalo.App {
+func App(f firebase.Firebase, b blobstore.Store, m mail.Sender) *buffalo.App {
 	if app == nil {
 		if f == nil {
 			log.Fatalln("firebase.Firebase cannot be nil")
 		}
+		if b == nil {
+			log.Fatalln("blobstore.Store cannot be nil")
+		}
+		if m == nil {
+			log.Fatalln("mail.Sender cannot be nil")
+		}
 		app = buffalo.New(buffalo.Options{
 			Env:          ENV,
 			SessionStore: sessions.Null{},
@@ -90,8 +98,19 @@ func App(f firebase.Firebase) *buffalo.App {
 		var selfGroup = app.Group("/self")
 		selfGroup.GET("/", selfGet)
 		selfGroup.GET("/tenant", selfGetTenant)
+		selfGroup.PUT("/tenant", requireAdminUser(selfPutTenant))
 		selfGroup.POST("/device-register", selfPostDeviceRegister(f))
 		selfGroup.POST("/device-remove", selfPostDeviceRemove(f))
+		selfGroup.GET("/settlements", requireDriverUser(selfSettlementsList))
+		selfGroup.GET("/settlements/{settlement_id}", requireDriverUser(selfSettlementsShow))
+		selfGroup.GET("/shifts", requireDriverUser(selfShiftsList))
+		selfGroup.GET("/compliance", requireDriverUser(selfComplianceShow))
+		selfGroup.GET("/time-off", requireDriverUser(selfTimeOffList))
+		selfGroup.POST("/time-off", requireDriverUser(selfTimeOffCreate))
+		selfGroup.DELETE("/time-off/{time_off_request_id}", requireDriverUser(selfTimeOffDestroy))
+		selfGroup.POST("/locations", requireDriverUser(selfLocationsCreate))
+		selfGroup.GET("/notification-preferences", selfNotificationPreferencesList)
+		selfGroup.PUT("/notification-preferences", selfNotificationPreferencesUpdate)
 		var tenantGroup = app.Group("/tenants")
 		tenantGroup.GET("/", requireSuperAdminUser(tenantsList))
 		tenantGroup.GET("/{tenant_id}", requireSuperAdminUser(tenantsShow))
@@ -112,20 +131,61 @@ func App(f firebase.Firebase) *buffalo.App {
 		customerGroup.DELETE("/{customer_id}", requireAtLeastBackOfficeUser(customersDestroy))
 		var terminalGroup = app.Group("/terminals")
 		terminalGroup.GET("/", terminalsList)
+		terminalGroup.GET("/nearby", terminalsNearby)
 		terminalGroup.GET("/{terminal_id}", terminalsShow)
 		terminalGroup.POST("/", requireAtLeastBackOfficeUser(terminalsCreate))
 		terminalGroup.PUT("/{terminal_id}", requireAtLeastBackOfficeUser(terminalsUpdate))
 		terminalGroup.DELETE("/{terminal_id}", requireAtLeastBackOfficeUser(terminalsDestroy))
+		terminalGroup.GET("/{terminal_id}/slots", terminalSlotsList)
+		terminalGroup.POST("/{terminal_id}/slots", requireAtLeastBackOfficeUser(terminalSlotsCreate))
+		terminalGroup.PUT("/{terminal_id}/slots/{slot_id}", requireAtLeastBackOfficeUser(terminalSlotsUpdate))
+		terminalGroup.DELETE("/{terminal_id}/slots/{slot_id}", requireAtLeastBackOfficeUser(terminalSlotsDestroy))
 		var carrierGroup = app.Group("/carriers")
 		carrierGroup.GET("/", carriersList)
 		carrierGroup.GET("/{carrier_id}", carriersShow)
 		carrierGroup.POST("/", requireAtLeastBackOfficeUser(carriersCreate))
 		carrierGroup.PUT("/{carrier_id}", requireAtLeastBackOfficeUser(carriersUpdate))
 		carrierGroup.DELETE("/{carrier_id}", requireAtLeastBackOfficeUser(carriersDestroy))
+		var locationGroup = app.Group("/locations")
+		locationGroup.GET("/", locationsList)
+		locationGroup.GET("/{location_id}", locationsShow)
+		locationGroup.POST("/", requireAtLeastBackOfficeUser(locationsCreate))
+		locationGroup.PUT("/{location_id}", requireAtLeastBackOfficeUser(locationsUpdate))
+		locationGroup.DELETE("/{location_id}", requireAtLeastBackOfficeUser(locationsDestroy))
+		var equipmentGroup = app.Group("/equipment")
+		equipmentGroup.GET("/", requireAtLeastDriverUser(equipmentList))
+		equipmentGroup.GET("/maintenance-due", requireAtLeastBackOfficeUser(maintenanceItemsDue))
+		equipmentGroup.GET("/{equipment_id}", requireAtLeastDriverUser(equipmentShow))
+		equipmentGroup.POST("/", requireAtLeastBackOfficeUser(equipmentCreate))
+		equipmentGroup.PUT("/{equipment_id}", requireAtLeastBackOfficeUser(equipmentUpdate))
+		equipmentGroup.DELETE("/{equipment_id}", requireAtLeastBackOfficeUser(equipmentDestroy))
+		equipmentGroup.GET("/{equipment_id}/maintenance", requireAtLeastBackOfficeUser(maintenanceItemsList))
+		equipmentGroup.POST("/{equipment_id}/maintenance", requireAtLeastBackOfficeUser(maintenanceItemsCreate))
+		equipmentGroup.PUT("/{equipment_id}/maintenance/{maintenance_item_id}", requireAtLeastBackOfficeUser(maintenanceItemsUpdate))
+		equipmentGroup.DELETE("/{equipment_id}/maintenance/{maintenance_item_id}", requireAtLeastBackOfficeUser(maintenanceItemsDestroy))
 		var shipmentGroup = app.Group("/shipments")
 		shipmentGroup.GET("/", shipmentsList)
+		shipmentGroup.GET("/at-risk", requireAtLeastBackOfficeUser(shipmentsAtRisk))
 		shipmentGroup.GET("/{shipment_id}", shipmentsShow)
+		shipmentGroup.GET("/{shipment_id}/transitions", shipmentsTransitions)
+		shipmentGroup.GET("/{shipment_id}/history", shipmentsHistory)
+		shipmentGroup.GET("/{shipment_id}/track", shipmentsTrack)
+		shipmentGroup.GET("/{shipment_id}/pod", requireAtLeastCustomerUser(shipmentsPodShow))
+		shipmentGroup.GET("/{shipment_id}/pod/files/{file_id}", requireAtLeastCustomerUser(shipmentsPodFile(b)))
+		shipmentGroup.POST("/{shipment_id}/pod", requireAtLeastDriverUser(shipmentsPodCreate(b)))
+		shipmentGroup.GET("/{shipment_id}/documents", documentsList)
+		shipmentGroup.GET("/{shipment_id}/documents/{document_id}", documentsShow)
+		shipmentGroup.GET("/{shipment_id}/documents/{document_id}/download", documentsDownload(b))
+		shipmentGroup.POST("/{shipment_id}/documents", documentsCreate(b))
+		shipmentGroup.DELETE("/{shipment_id}/documents/{document_id}", requireAtLeastBackOfficeUser(documentsDestroy(b)))
+		shipmentGroup.GET("/{shipment_id}/charges", requireAtLeastCustomerUser(shipmentChargesList))
+		shipmentGroup.GET("/{shipment_id}/charges/{charge_id}", requireAtLeastCustomerUser(shipmentChargesShow))
+		shipmentGroup.POST("/{shipment_id}/charges", requireAtLeastBackOfficeUser(shipmentChargesCreate))
+		shipmentGroup.PUT("/{shipment_id}/charges/{charge_id}", requireAtLeastBackOfficeUser(shipmentChargesUpdate))
+		shipmentGroup.DELETE("/{shipment_id}/charges/{charge_id}", requireAtLeastBackOfficeUser(shipmentChargesDestroy))
 		shipmentGroup.POST("/", requireAtLeastDriverUser(shipmentsCreate))
+		shipmentGroup.POST("/auto-assign", requireAtLeastBackOfficeUser(shipmentsAutoAssign))
+		shipmentGroup.POST("/{shipment_id}/suggest-drivers", requireAtLeastBackOfficeUser(shipmentsSuggestDrivers))
 		shipmentGroup.PUT("/{shipment_id}", requireAtLeastDriverUser(shipmentsUpdate))
 		shipmentGroup.DELETE("/{shipment_id}", requireAtLeastBackOfficeUser(shipmentsDestroy))
 		var orderGroup = app.Group("/orders")
@@ -133,10 +193,94 @@ func App(f firebase.Firebase) *buffalo.App {
 		orderGroup.GET("/{order_id}", requireAtLeastCustomerUser(ordersShow))
 		orderGroup.POST("/", requireAtLeastCustomerUser(ordersCreate))
 		orderGroup.PUT("/{order_id}", requireAtLeastBackOfficeUser(ordersUpdate))
+		orderGroup.GET("/{order_id}/documents", requireAtLeastCustomerUser(documentsList))
+		orderGroup.GET("/{order_id}/documents/{document_id}", requireAtLeastCustomerUser(documentsShow))
+		orderGroup.GET("/{order_id}/documents/{document_id}/download", requireAtLeastCustomerUser(documentsDownload(b)))
+		orderGroup.POST("/{order_id}/documents", requireAtLeastCustomerUser(documentsCreate(b)))
+		orderGroup.DELETE("/{order_id}/documents/{document_id}", requireAtLeastBackOfficeUser(documentsDestroy(b)))
 		orderGroup.DELETE("/{order_id}", requireAtLeastBackOfficeUser(ordersDestroy))
+		var invoiceGroup = app.Group("/invoices")
+		invoiceGroup.GET("/", requireAtLeastCustomerUser(invoicesList))
+		invoiceGroup.GET("/{invoice_id}", requireAtLeastCustomerUser(invoicesShow))
+		invoiceGroup.POST("/", requireAtLeastBackOfficeUser(invoicesCreate))
+		invoiceGroup.POST("/{invoice_id}/payments", requireAtLeastBackOfficeUser(invoicesPay))
+		invoiceGroup.DELETE("/{invoice_id}", requireAtLeastBackOfficeUser(invoicesDestroy))
+		var rateCardGroup = app.Group("/rate-cards")
+		rateCardGroup.GET("/", requireAtLeastCustomerUser(rateCardsList))
+		rateCardGroup.GET("/{rate_card_id}", requireAtLeastCustomerUser(rateCardsShow))
+		rateCardGroup.POST("/", requireAtLeastBackOfficeUser(rateCardsCreate))
+		rateCardGroup.PUT("/{rate_card_id}", requireAtLeastBackOfficeUser(rateCardsUpdate))
+		rateCardGroup.DELETE("/{rate_card_id}", requireAtLeastBackOfficeUser(rateCardsDestroy))
+		app.POST("/quotes", requireAtLeastCustomerUser(quotesCreate))
+		var driverPayRuleGroup = app.Group("/driver-pay-rules")
+		driverPayRuleGroup.GET("/", requireAtLeastBackOfficeUser(driverPayRulesList))
+		driverPayRuleGroup.GET("/{driver_pay_rule_id}", requireAtLeastBackOfficeUser(driverPayRulesShow))
+		driverPayRuleGroup.POST("/", requireAtLeastBackOfficeUser(driverPayRulesCreate))
+		driverPayRuleGroup.PUT("/{driver_pay_rule_id}", requireAtLeastBackOfficeUser(driverPayRulesUpdate))
+		driverPayRuleGroup.DELETE("/{driver_pay_rule_id}", requireAtLeastBackOfficeUser(driverPayRulesDestroy))
+		var settlementGroup = app.Group("/settlements")
+		settlementGroup.GET("/", requireAtLeastBackOfficeUser(driverSettlementsList))
+		settlementGroup.GET("/{settlement_id}", requireAtLeastBackOfficeUser(driverSettlementsShow))
+		settlementGroup.POST("/", requireAtLeastBackOfficeUser(driverSettlementsCreate))
+		settlementGroup.POST("/{settlement_id}/lines", requireAtLeastBackOfficeUser(driverSettlementLinesCreate))
+		settlementGroup.DELETE("/{settlement_id}/lines/{line_id}", requireAtLeastBackOfficeUser(driverSettlementLinesDestroy))
+		settlementGroup.POST("/{settlement_id}/finalize", requireAtLeastBackOfficeUser(driverSettlementsFinalize))
+		settlementGroup.DELETE("/{settlement_id}", requireAtLeastBackOfficeUser(driverSettlementsDestroy))
+		var driverShiftGroup = app.Group("/driver-shifts")
+		driverShiftGroup.GET("/", requireAtLeastBackOfficeUser(driverShiftsList))
+		driverShiftGroup.GET("/{driver_shift_id}", requireAtLeastBackOfficeUser(driverShiftsShow))
+		driverShiftGroup.POST("/", requireAtLeastBackOfficeUser(driverShiftsCreate))
+		driverShiftGroup.PUT("/{driver_shift_id}", requireAtLeastBackOfficeUser(driverShiftsUpdate))
+		driverShiftGroup.DELETE("/{driver_shift_id}", requireAtLeastBackOfficeUser(driverShiftsDestroy))
+		var driverComplianceGroup = app.Group("/driver-compliance")
+		driverComplianceGroup.GET("/", requireAtLeastBackOfficeUser(driverCompliancesList))
+		driverComplianceGroup.GET("/{driver_compliance_id}", requireAtLeastBackOfficeUser(driverCompliancesShow))
+		driverComplianceGroup.POST("/", requireAtLeastBackOfficeUser(driverCompliancesCreate))
+		driverComplianceGroup.PUT("/{driver_compliance_id}", requireAtLeastBackOfficeUser(driverCompliancesUpdate))
+		driverComplianceGroup.DELETE("/{driver_compliance_id}", requireAtLeastBackOfficeUser(driverCompliancesDestroy))
+		var timeOffGroup = app.Group("/time-off")
+		timeOffGroup.GET("/", requireAtLeastBackOfficeUser(timeOffRequestsList))
+		timeOffGroup.GET("/{time_off_request_id}", requireAtLeastBackOfficeUser(timeOffRequestsShow))
+		timeOffGroup.POST("/", requireAtLeastBackOfficeUser(timeOffRequestsCreate))
+		timeOffGroup.POST("/{time_off_request_id}/approve", requireAtLeastBackOfficeUser(timeOffRequestsApprove))
+		timeOffGroup.POST("/{time_off_request_id}/reject", requireAtLeastBackOfficeUser(timeOffRequestsReject))
+		timeOffGroup.DELETE("/{time_off_request_id}", requireAtLeastBackOfficeUser(timeOffRequestsDestroy))
+		var statusSuggestionGroup = app.Group("/status-suggestions")
+		statusSuggestionGroup.GET("/", requireAtLeastDriverUser(statusSuggestionsList))
+		statusSuggestionGroup.POST("/{status_suggestion_id}/accept", requireAtLeastDriverUser(statusSuggestionsAccept))
+		statusSuggestionGroup.POST("/{status_suggestion_id}/dismiss", requireAtLeastDriverUser(statusSuggestionsDismiss))
+		var dispatchGroup = app.Group("/dispatch")
+		dispatchGroup.GET("/board", requireAtLeastBackOfficeUser(dispatchBoard))
+		var reportGroup = app.Group("/reports")
+		reportGroup.GET("/profitability", requireAtLeastBackOfficeUser(reportsProfitability))
+		reportGroup.GET("/compliance", requireAtLeastBackOfficeUser(reportsCompliance))
 
 		app.Worker.Register("sendNotifications", sendNotifications(f))
+		app.Worker.Register("sendEmails", sendEmails(m))
 		app.Worker.Register("testWorker", testWorker)
+		app.Worker.Register(demurrageAlertsJob, demurrageAlerts)
+		app.Worker.Register(driverLocationsPruneJob, driverLocationsPrune)
+		app.Worker.Register(maintenanceAlertsJob, maintenanceAlerts)
+		if ENV != "test" {
+			interval, _, err := demurrageAlertSettings()
+			if err != nil {
+				app.Stop(err)
+			}
+			scheduleDemurrageAlerts(interval)
+			pruneInterval, err := driverLocationsPruneInterval()
+			if err != nil {
+				app.Stop(err)
+			}
+			scheduleDriverLocationsPrune(pruneInterval)
+			if _, err := etaAlertThreshold(); err != nil {
+				app.Stop(err)
+			}
+			maintenanceInterval, err := maintenanceAlertInterval()
+			if err != nil {
+				app.Stop(err)
+			}
+			scheduleMaintenanceAlerts(maintenanceInterval)
+		}
 	}
 
 	return app
@@ -252,6 +396,7 @@ func createOrUpdateUserOnFirstLogin(c buffalo.Context, firebaseUser *auth.UserRe
 		message = "New user validation failed"
 	}
 	sendNotificationsAsync(
+		models.NotificationEventNewUser,
 		topics,
 		message,
 		fmt.Sprintf("Name: %s", u.Name),
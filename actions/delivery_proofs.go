@@ -0,0 +1,206 @@
+package actions
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bigpanther/trober/blobstore"
+	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/buffalo/render"
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gobuffalo/validate/v3"
+)
+
+// Following naming logic is implemented in Buffalo:
+// Model: Singular (DeliveryProof)
+// DB Table: Plural (delivery_proofs)
+// Resource: Singular (Pod)
+// Path: Singular (/shipments/{shipment_id}/pod)
+
+var errDeliveryProofExists = errors.New("shipment already has a proof of delivery")
+var errDeliveryProofRequired = errors.New("shipment has no proof of delivery, deliver it with POST /shipments/{shipment_id}/pod")
+
+// deliveryProofPayload is a proof of delivery with its images, base64 encoded
+type deliveryProofPayload struct {
+	models.DeliveryProof
+	Signature []byte   `json:"signature"`
+	Photos    [][]byte `json:"photos"`
+}
+
+// shipmentsPodCreate captures the proof of delivery of a Shipment and marks it delivered. Drivers can only capture it
+// for their shipments. The signature and the photos are stored in the blob store.
+// This function is mapped to the path POST /shipments/{shipment_id}/pod
+func shipmentsPodCreate(b blobstore.Store) buffalo.Handler {
+	return func(c buffalo.Context) error {
+		tx := c.Value("tx").(*pop.Connection)
+		var loggedInUser = loggedInUser(c)
+		shipment := &models.Shipment{}
+		q := tx.Scope(restrictedScope(c))
+		if loggedInUser.IsDriver() {
+			q = q.Where("driver_id = ?", loggedInUser.ID)
+		}
+		if err := q.Find(shipment, c.Param("shipment_id")); err != nil {
+			return c.Error(http.StatusNotFound, err)
+		}
+		exists, err := tx.Where("shipment_id = ?", shipment.ID).Exists(&models.DeliveryProof{})
+		if err != nil {
+			return err
+		}
+		if exists {
+			return c.Error(http.StatusConflict, errDeliveryProofExists)
+		}
+		payload := &deliveryProofPayload{}
+		if err := c.Bind(payload); err != nil {
+			c.Logger().Errorf("error binding delivery proof: %v\n", err)
+			return err
+		}
+		if len(payload.Signature) == 0 {
+			return c.Error(http.StatusBadRequest, errors.New("missing signature"))
+		}
+		if len(payload.Photos) > models.MaxDeliveryProofPhotos {
+			return c.Error(http.StatusBadRequest, fmt.Errorf("at most %d photos can be sent", models.MaxDeliveryProofPhotos))
+		}
+		if err := checkOrderNotLocked(tx, shipment.OrderID); err != nil {
+			return c.Error(http.StatusConflict, err)
+		}
+		if err := models.CheckShipmentStatusTransition(loggedInUser, models.ShipmentStatus(shipment.Status), models.ShipmentStatusDelivered); err != nil {
+			return renderShipmentStatusTransitionError(c, err)
+		}
+		proof := &payload.DeliveryProof
+		proof.CreatedBy = loggedInUser.ID
+		proof.TenantID = shipment.TenantID
+		proof.ShipmentID = shipment.ID
+		proof.Files = nil
+		if proof.DeliveredAt.IsZero() {
+			proof.DeliveredAt = time.Now().UTC()
+		}
+		verrs, err := tx.ValidateAndCreate(proof)
+		if err != nil {
+			return err
+		}
+		if verrs.HasAny() {
+			return c.Render(http.StatusUnprocessableEntity, r.JSON(verrs))
+		}
+		var blobs = map[string][]byte{}
+		var addFile = func(kind models.DeliveryProofFileKind, data []byte) (*validate.Errors, error) {
+			file := models.NewDeliveryProofFile(proof, kind, data)
+			verrs, err := tx.ValidateAndCreate(file)
+			if err != nil || verrs.HasAny() {
+				return verrs, err
+			}
+			proof.Files = append(proof.Files, *file)
+			blobs[file.BlobKey] = data
+			return verrs, nil
+		}
+		if verrs, err := addFile(models.DeliveryProofFileKindSignature, payload.Signature); err != nil || verrs.HasAny() {
+			if err != nil {
+				return err
+			}
+			return c.Render(http.StatusUnprocessableEntity, r.JSON(verrs))
+		}
+		for _, photo := range payload.Photos {
+			if verrs, err := addFile(models.DeliveryProofFileKindPhoto, photo); err != nil || verrs.HasAny() {
+				if err != nil {
+					return err
+				}
+				return c.Render(http.StatusUnprocessableEntity, r.JSON(verrs))
+			}
+		}
+		// A proof can be captured for a shipment that was already marked delivered
+		if shipment.Status != models.ShipmentStatusDelivered.String() {
+			verrs, err := changeShipmentStatus(c, tx, loggedInUser, shipment, models.ShipmentStatusDelivered)
+			if err != nil {
+				return err
+			}
+			if verrs.HasAny() {
+				return c.Render(http.StatusUnprocessableEntity, r.JSON(verrs))
+			}
+		}
+		if err := putBlobs(c, b, blobs); err != nil {
+			return err
+		}
+		return c.Render(http.StatusCreated, r.JSON(proof))
+	}
+}
+
+// shipmentsPodShow gets the proof of delivery of a Shipment. Customers only get the proofs of their shipments.
+// This function is mapped to the path GET /shipments/{shipment_id}/pod
+func shipmentsPodShow(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	proof, err := findDeliveryProof(c, tx)
+	if err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	return c.Render(http.StatusOK, r.JSON(proof))
+}
+
+// shipmentsPodFile downloads the signature or a photo of the proof of delivery of a Shipment.
+// This function is mapped to the path GET /shipments/{shipment_id}/pod/files/{file_id}
+func shipmentsPodFile(b blobstore.Store) buffalo.Handler {
+	return func(c buffalo.Context) error {
+		tx := c.Value("tx").(*pop.Connection)
+		proof, err := findDeliveryProof(c, tx)
+		if err != nil {
+			return c.Error(http.StatusNotFound, err)
+		}
+		file := &models.DeliveryProofFile{}
+		if err := tx.Where("delivery_proof_id = ?", proof.ID).Find(file, c.Param("file_id")); err != nil {
+			return c.Error(http.StatusNotFound, err)
+		}
+		blob, err := b.Get(c, file.BlobKey)
+		if errors.Is(err, blobstore.ErrNotFound) {
+			return c.Error(http.StatusNotFound, err)
+		}
+		if err != nil {
+			return err
+		}
+		defer blob.Close()
+		c.Response().Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s-%s.%s\"", file.Kind, file.ID, strings.TrimPrefix(file.ContentType, "image/")))
+		return c.Render(http.StatusOK, r.Func(file.ContentType, func(w io.Writer, _ render.Data) error {
+			_, err := io.Copy(w, blob)
+			return err
+		}))
+	}
+}
+
+// findDeliveryProof gets the proof of delivery, with its files, of the Shipment of the path param "shipment_id".
+// Customers only find the proofs of their shipments.
+func findDeliveryProof(c buffalo.Context, tx *pop.Connection) (*models.DeliveryProof, error) {
+	var loggedInUser = loggedInUser(c)
+	shipment := &models.Shipment{}
+	q := tx.Scope(restrictedScope(c))
+	if loggedInUser.IsCustomer() {
+		q = q.Where("customer_id = ?", loggedInUser.CustomerID)
+	}
+	if err := q.Find(shipment, c.Param("shipment_id")); err != nil {
+		return nil, err
+	}
+	proof := &models.DeliveryProof{}
+	if err := tx.Eager("Files").Where("shipment_id = ?", shipment.ID).First(proof); err != nil {
+		return nil, err
+	}
+	return proof, nil
+}
+
+// putBlobs stores the blobs of a proof of delivery, removing the ones already stored when one fails
+func putBlobs(c buffalo.Context, b blobstore.Store, blobs map[string][]byte) error {
+	var stored []string
+	for key, data := range blobs {
+		if err := b.Put(c, key, bytes.NewReader(data)); err != nil {
+			for _, k := range stored {
+				if err := b.Delete(c, k); err != nil {
+					c.Logger().Errorf("error deleting blob %s: %v\n", k, err)
+				}
+			}
+			return err
+		}
+		stored = append(stored, key)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package actions
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/nulls"
+	"github.com/golang/mock/gomock"
+)
+
+// pngHeader is enough of a png image for its content type to be sniffed
+var pngHeader = []byte("\x89PNG\x0D\x0A\x1A\x0A\x00\x00\x00\x0DIHDR")
+
+// deliverWithProof delivers a shipment the way drivers do, with a proof of delivery
+func (as *ActionSuite) deliverWithProof(driver *models.User, shipment *models.Shipment) {
+	var proof = deliveryProofPayload{DeliveryProof: models.DeliveryProof{ReceiverName: "Jordan"}, Signature: pngHeader}
+	res := as.setupRequest(driver, fmt.Sprintf("/shipments/%s/pod", shipment.ID)).Post(proof)
+	as.Equal(http.StatusCreated, res.Code, res.Body.String())
+}
+
+func (as *ActionSuite) Test_ShipmentsPodCreate() {
+	as.LoadFixture("Tenant bootstrap")
+	mockFirebase.EXPECT().SendAll(gomock.Any(), gomock.Any()).AnyTimes()
+	salah := as.getLoggedInUser("salah")
+	shipment := as.createGeofencedShipment("pod", models.ShipmentStatusLoaded)
+	var path = fmt.Sprintf("/shipments/%s/pod", shipment.ID)
+	var proof = deliveryProofPayload{
+		DeliveryProof: models.DeliveryProof{ReceiverName: "Jordan", SealNumber: nulls.NewString("SEAL1"),
+			Latitude: nulls.NewFloat64(deltaport.Latitude), Longitude: nulls.NewFloat64(deltaport.Longitude)},
+		Signature: pngHeader,
+		Photos:    [][]byte{pngHeader, pngHeader},
+	}
+	var tests = []struct {
+		username     string
+		payload      deliveryProofPayload
+		responseCode int
+	}{
+		{"nike", proof, http.StatusNotFound},
+		{"lewin", proof, http.StatusNotFound},
+		{"rodriguez", proof, http.StatusNotFound},
+		{"salah", deliveryProofPayload{DeliveryProof: proof.DeliveryProof}, http.StatusBadRequest},
+		{"salah", deliveryProofPayload{DeliveryProof: proof.DeliveryProof, Signature: []byte("not an image")}, http.StatusUnprocessableEntity},
+		{"salah", deliveryProofPayload{Signature: pngHeader}, http.StatusUnprocessableEntity},
+		{"salah", proof, http.StatusCreated},
+		{"mane", proof, http.StatusConflict},
+	}
+	for i, test := range tests {
+		as.T().Run(fmt.Sprint(i), func(t *testing.T) {
+			res := as.setupRequest(as.getLoggedInUser(test.username), path).Post(test.payload)
+			as.Equal(test.responseCode, res.Code, res.Body.String())
+		})
+	}
+	as.Nil(as.DB.Reload(shipment))
+	as.Equal(models.ShipmentStatusDelivered.String(), shipment.Status)
+	created := &models.DeliveryProof{}
+	as.Nil(as.DB.Eager("Files").Where("shipment_id = ?", shipment.ID).First(created))
+	as.Equal(salah.ID, created.CreatedBy)
+	as.Equal("Jordan", created.ReceiverName)
+	as.Len(created.Files, 3)
+	count, err := as.DB.Where("shipment_id = ?", shipment.ID).Where("to_status = ?", models.ShipmentStatusDelivered.String()).Count(&models.ShipmentEvent{})
+	as.Nil(err)
+	as.Equal(1, count)
+}
+
+func (as *ActionSuite) Test_ShipmentsPodCreateNotAllowed() {
+	as.LoadFixture("Tenant bootstrap")
+	shipment := as.createGeofencedShipment("pod", models.ShipmentStatusAssigned)
+	res := as.setupRequest(as.getLoggedInUser("salah"), fmt.Sprintf("/shipments/%s/pod", shipment.ID)).Post(deliveryProofPayload{
+		DeliveryProof: models.DeliveryProof{ReceiverName: "Jordan"}, Signature: pngHeader})
+	as.Equal(http.StatusConflict, res.Code, res.Body.String())
+	count, err := as.DB.Where("shipment_id = ?", shipment.ID).Count(&models.DeliveryProof{})
+	as.Nil(err)
+	as.Equal(0, count)
+}
+
+func (as *ActionSuite) Test_ShipmentsPodShow() {
+	as.LoadFixture("Tenant bootstrap")
+	mockFirebase.EXPECT().SendAll(gomock.Any(), gomock.Any()).AnyTimes()
+	shipment := as.createGeofencedShipment("pod", models.ShipmentStatusInTransit)
+	var path = fmt.Sprintf("/shipments/%s/pod", shipment.ID)
+	res := as.setupRequest(as.getLoggedInUser("mane"), path).Get()
+	as.Equal(http.StatusNotFound, res.Code)
+	res = as.setupRequest(as.getLoggedInUser("salah"), path).Post(deliveryProofPayload{
+		DeliveryProof: models.DeliveryProof{ReceiverName: "Jordan"}, Signature: pngHeader})
+	as.Equal(http.StatusCreated, res.Code, res.Body.String())
+
+	var tests = []struct {
+		username     string
+		responseCode int
+	}{
+		{"salah", http.StatusNotFound},
+		{"lewin", http.StatusNotFound},
+		{"rodriguez", http.StatusNotFound},
+		{"adidas", http.StatusNotFound},
+		{"mane", http.StatusOK},
+		{"firmino", http.StatusOK},
+		{"nike", http.StatusOK},
+	}
+	for _, test := range tests {
+		as.T().Run(test.username, func(t *testing.T) {
+			res := as.setupRequest(as.getLoggedInUser(test.username), path).Get()
+			as.Equal(test.responseCode, res.Code, res.Body.String())
+			if res.Code != http.StatusOK {
+				return
+			}
+			proof := models.DeliveryProof{}
+			res.Bind(&proof)
+			as.Equal(shipment.ID, proof.ShipmentID)
+			as.Len(proof.Files, 1)
+			file := proof.Files[0]
+			as.Equal(models.DeliveryProofFileKindSignature.String(), file.Kind)
+			res = as.setupRequest(as.getLoggedInUser(test.username), fmt.Sprintf("%s/files/%s", path, file.ID)).Get()
+			as.Equal(http.StatusOK, res.Code)
+			as.Equal("image/png", res.Header().Get("Content-Type"))
+			as.Equal(pngHeader, res.Body.Bytes())
+		})
+	}
+	res = as.setupRequest(as.getLoggedInUser("mane"), fmt.Sprintf("%s/files/%s", path, shipment.ID)).Get()
+	as.Equal(http.StatusNotFound, res.Code)
+}
@@ -0,0 +1,114 @@
+package actions
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bigpanther/trober/firebase"
+	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/buffalo/worker"
+	"github.com/gobuffalo/envy"
+	"github.com/gobuffalo/nulls"
+	"github.com/gobuffalo/pop/v6"
+)
+
+const demurrageAlertsJob = "demurrageAlerts"
+
+const defaultAtRiskWindow = 48 * time.Hour
+
+// shipmentsAtRisk gets the demurrage exposure of the shipments whose last free day is near or past.
+// This function is mapped to the path GET /shipments/at-risk
+func shipmentsAtRisk(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	within := defaultAtRiskWindow
+	if w := c.Param("within"); w != "" {
+		d, err := time.ParseDuration(w)
+		if err != nil {
+			return c.Error(http.StatusBadRequest, fmt.Errorf("invalid within duration %q", w))
+		}
+		within = d
+	}
+	shipments := models.Shipments{}
+	q := tx.Eager("Order", "Terminal.Holidays").Scope(restrictedScope(c)).Where("status in (?)", models.ContainerAtTerminalStatuses()...)
+	if err := q.All(&shipments); err != nil {
+		return err
+	}
+	return c.Render(http.StatusOK, r.JSON(models.DemurrageExposures(shipments, time.Now().UTC(), within)))
+}
+
+// demurrageAlertSettings reads how often the alerts run and the time before the last free day at which they fire.
+// DEMURRAGE_ALERT_THRESHOLDS is a comma separated list of durations, a zero duration alerts when the last free day passes.
+func demurrageAlertSettings() (time.Duration, []time.Duration, error) {
+	interval, err := time.ParseDuration(envy.Get("DEMURRAGE_ALERT_INTERVAL", "1h"))
+	if err != nil || interval <= 0 {
+		return 0, nil, errors.New("invalid DEMURRAGE_ALERT_INTERVAL")
+	}
+	var thresholds = []time.Duration{}
+	for _, s := range strings.Split(envy.Get("DEMURRAGE_ALERT_THRESHOLDS", "48h,24h,0h"), ",") {
+		t, err := time.ParseDuration(strings.TrimSpace(s))
+		if err != nil {
+			return 0, nil, fmt.Errorf("invalid DEMURRAGE_ALERT_THRESHOLDS: %v", err)
+		}
+		thresholds = append(thresholds, t)
+	}
+	// The closest threshold is reported when several are crossed at once
+	sort.Slice(thresholds, func(i, j int) bool { return thresholds[i] < thresholds[j] })
+	return interval, thresholds, nil
+}
+
+func scheduleDemurrageAlerts(interval time.Duration) {
+	if err := app.Worker.PerformIn(worker.Job{Queue: "default", Handler: demurrageAlertsJob}, interval); err != nil {
+		app.Logger.Errorf("error scheduling demurrage alerts: %v", err)
+	}
+}
+
+// demurrageAlerts notifies the back office of every tenant about the shipments that reached an alert threshold
+// they were not alerted for yet, and schedules the next run.
+func demurrageAlerts(args worker.Args) error {
+	interval, thresholds, err := demurrageAlertSettings()
+	if err != nil {
+		return err
+	}
+	defer scheduleDemurrageAlerts(interval)
+	shipments := models.Shipments{}
+	if err := models.DB.Eager("Order", "Terminal.Holidays").Where("status in (?)", models.ContainerAtTerminalStatuses()...).All(&shipments); err != nil {
+		return err
+	}
+	within := thresholds[len(thresholds)-1]
+	for _, e := range models.DemurrageExposures(shipments, time.Now().UTC(), within) {
+		threshold, ok := e.DueThreshold(thresholds)
+		if !ok {
+			continue
+		}
+		shipment := e.Shipment
+		shipment.LfdAlertedThreshold = nulls.NewInt(int(threshold.Seconds()))
+		if err := models.DB.UpdateColumns(&shipment, "lfd_alerted_threshold"); err != nil {
+			return err
+		}
+		message := fmt.Sprintf("Last free day in %s - %s", threshold, e.Shipment.SerialNumber)
+		if threshold <= 0 {
+			message = fmt.Sprintf("Last free day has passed - %s", e.Shipment.SerialNumber)
+		}
+		data := map[string]string{
+			"shipment.id":           e.Shipment.ID.String(),
+			"shipment.serialNumber": e.Shipment.SerialNumber,
+			"shipment.lfd":          e.Lfd.Format(time.RFC3339),
+		}
+		if e.ProjectedDemurrage.Valid {
+			data["shipment.projectedDemurrage"] = e.ProjectedDemurrage.Money.String()
+		}
+		sendNotificationsAsync(
+			models.NotificationEventLfdWarning,
+			[]string{firebase.GetBackOfficeTopic(&models.User{TenantID: e.Shipment.TenantID})},
+			message,
+			e.Shipment.SerialNumber,
+			data,
+		)
+	}
+	return nil
+}
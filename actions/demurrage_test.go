@@ -0,0 +1,102 @@
+package actions
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/buffalo/worker"
+	"github.com/gobuffalo/nulls"
+	"github.com/golang/mock/gomock"
+)
+
+func (as *ActionSuite) Test_ShipmentsAtRisk() {
+	as.LoadFixture("Tenant bootstrap")
+	var tests = []struct {
+		username     string
+		within       string
+		responseCode int
+		count        int
+	}{
+		{"klopp", "", http.StatusOK, 3},
+		{"firmino", "", http.StatusOK, 2},
+		{"mane", "", http.StatusOK, 2},
+		{"mane", "100h", http.StatusOK, 3},
+		{"mane", "1h", http.StatusOK, 1},
+		{"mane", "tomorrow", http.StatusBadRequest, 0},
+		{"rodriguez", "", http.StatusOK, 1},
+		{"salah", "", http.StatusNotFound, 0},
+		{"nike", "", http.StatusNotFound, 0},
+		{"coutinho", "", http.StatusNotFound, 0},
+	}
+	firmino := as.getLoggedInUser("firmino")
+	richarlson := as.getLoggedInUser("richarlson")
+	efaLiv := as.getCustomer("EFA Liv")
+	efaEve := as.getCustomer("EFA Eve")
+	terminal := as.createTerminal("Deltaport", models.TerminalTypePort, firmino.TenantID, firmino.ID)
+	terminal.FreeTimeDays = nulls.NewInt(3)
+	terminal.DemurrageRate = models.NewNullMoney(20000, models.DefaultCurrency)
+	as.Nil(as.DB.Update(terminal))
+	order := as.createOrder("ord1", models.OrderStatusOpen, firmino.TenantID, firmino.ID, efaLiv.ID)
+	order.Eta = nulls.NewTime(time.Now().UTC().AddDate(0, 0, -4).Add(time.Hour))
+	as.Nil(as.DB.Update(order))
+	now := time.Now().UTC()
+	var newShipment = func(serial string, status models.ShipmentStatus, lfd nulls.Time) models.Shipment {
+		return models.Shipment{SerialNumber: serial, Status: status.String(), Type: models.ShipmentTypeInbound.String(), CreatedBy: firmino.ID, TenantID: firmino.TenantID, Lfd: lfd, TerminalID: nulls.NewUUID(terminal.ID)}
+	}
+	// Past the free time of the terminal
+	as.createShipment(newShipment("eta", models.ShipmentStatusAssigned, nulls.Time{}), order)
+	as.createShipment(newShipment("soon", models.ShipmentStatusUnassigned, nulls.NewTime(now.Add(24*time.Hour))), nil)
+	as.createShipment(newShipment("later", models.ShipmentStatusAccepted, nulls.NewTime(now.Add(96*time.Hour))), nil)
+	as.createShipment(newShipment("loaded", models.ShipmentStatusLoaded, nulls.NewTime(now.Add(-24*time.Hour))), nil)
+	as.createShipment(models.Shipment{SerialNumber: "eve", Status: models.ShipmentStatusAssigned.String(), Type: models.ShipmentTypeInbound.String(), CreatedBy: richarlson.ID, TenantID: richarlson.TenantID, Lfd: nulls.NewTime(now.Add(time.Hour)), CustomerID: nulls.NewUUID(efaEve.ID)}, nil)
+
+	for _, test := range tests {
+		as.T().Run(fmt.Sprintf("%s-%s", test.username, test.within), func(t *testing.T) {
+			user := as.getLoggedInUser(test.username)
+			path := "/shipments/at-risk"
+			if test.within != "" {
+				path = fmt.Sprintf("%s?within=%s", path, test.within)
+			}
+			res := as.setupRequest(user, path).Get()
+			as.Equal(test.responseCode, res.Code)
+			if res.Code != http.StatusOK {
+				return
+			}
+			var exposures = []models.DemurrageExposure{}
+			res.Bind(&exposures)
+			as.Equal(test.count, len(exposures))
+			if user.TenantID == firmino.TenantID {
+				as.Equal("eta", exposures[0].Shipment.SerialNumber)
+				as.Equal(1, exposures[0].ChargeableDays)
+				as.Equal(models.NewNullMoney(20000, models.DefaultCurrency), exposures[0].ProjectedDemurrage)
+			}
+		})
+	}
+}
+
+func (as *ActionSuite) Test_DemurrageAlerts() {
+	as.LoadFixture("Tenant bootstrap")
+	firmino := as.getLoggedInUser("firmino")
+	shipment := as.createShipment(models.Shipment{SerialNumber: "lfd", Status: models.ShipmentStatusAssigned.String(), Type: models.ShipmentTypeInbound.String(), CreatedBy: firmino.ID, TenantID: firmino.TenantID, Lfd: nulls.NewTime(time.Now().UTC().Add(24*time.Hour - time.Minute))}, nil)
+	mockFirebase.EXPECT().SendAll(gomock.Any(), gomock.Any()).AnyTimes()
+	as.Nil(demurrageAlerts(worker.Args{}))
+	as.Nil(as.DB.Reload(shipment))
+	as.Equal(nulls.NewInt(24*3600), shipment.LfdAlertedThreshold)
+	// The threshold is not alerted again, the next one is once it is reached
+	as.Nil(demurrageAlerts(worker.Args{}))
+	as.Nil(as.DB.Reload(shipment))
+	as.Equal(nulls.NewInt(24*3600), shipment.LfdAlertedThreshold)
+	shipment.Lfd = nulls.NewTime(time.Now().UTC().Add(-time.Minute))
+	as.Nil(as.DB.Update(shipment))
+	as.Nil(demurrageAlerts(worker.Args{}))
+	as.Nil(as.DB.Reload(shipment))
+	as.Equal(nulls.NewInt(0), shipment.LfdAlertedThreshold)
+
+	interval, thresholds, err := demurrageAlertSettings()
+	as.Nil(err)
+	as.Equal(time.Hour, interval)
+	as.Equal([]time.Duration{0, 24 * time.Hour, 48 * time.Hour}, thresholds)
+}
@@ -0,0 +1,211 @@
+package actions
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bigpanther/trober/firebase"
+	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/nulls"
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gofrs/uuid"
+)
+
+// dispatchBoard gets every driver of the tenant with the shipments reserved for a day.
+// This function is mapped to the path GET /dispatch/board
+// Param "date" defaults to today and "window" is how many minutes a reservation keeps a driver busy.
+func dispatchBoard(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	date := time.Now().UTC().Truncate(24 * time.Hour)
+	if d := c.Param("date"); d != "" {
+		parsed, err := time.Parse(reportDateFormat, d)
+		if err != nil {
+			return c.Error(http.StatusBadRequest, fmt.Errorf("invalid date %q", d))
+		}
+		date = parsed
+	}
+	window, err := reservationWindowParam(c)
+	if err != nil {
+		return c.Error(http.StatusBadRequest, err)
+	}
+	drivers := models.Users{}
+	if err := tx.Scope(restrictedScope(c)).Where("role = ?", models.UserRoleDriver.String()).Order("name ASC").All(&drivers); err != nil {
+		return err
+	}
+	shipments := models.Shipments{}
+	err = tx.Scope(restrictedScope(c)).Where("driver_id IS NOT NULL").
+		Where("reservation_time >= ?", date).
+		Where("reservation_time < ?", date.AddDate(0, 0, 1)).
+		Order("reservation_time ASC").All(&shipments)
+	if err != nil {
+		return err
+	}
+	return c.Render(http.StatusOK, r.JSON(models.NewDispatchBoard(date, drivers, shipments, window)))
+}
+
+// shipmentsSuggestDrivers ranks the drivers of the tenant for an Unassigned or Rejected Shipment, with the
+// reasons behind the score of each one. This function is mapped to the path POST /shipments/{shipment_id}/suggest-drivers
+func shipmentsSuggestDrivers(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	shipment := &models.Shipment{}
+	if err := tx.Eager("Terminal").Scope(restrictedScope(c)).Find(shipment, c.Param("shipment_id")); err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	if shipment.Status != models.ShipmentStatusUnassigned.String() && !shipment.IsRejected() {
+		return c.Error(http.StatusConflict, fmt.Errorf("shipment is %s, only unassigned or rejected shipments need a driver", shipment.Status))
+	}
+	window, err := reservationWindowParam(c)
+	if err != nil {
+		return c.Error(http.StatusBadRequest, err)
+	}
+	workloads, err := models.LoadDriverWorkloads(tx, shipment.TenantID, time.Now().UTC().Add(-models.RecentRejectionsPeriod))
+	if err != nil {
+		return err
+	}
+	return c.Render(http.StatusOK, r.JSON(models.RankDrivers(shipment, workloads, window)))
+}
+
+type autoAssignRequest struct {
+	ShipmentIDs []uuid.UUID `json:"shipment_ids"`
+	DryRun      bool        `json:"dry_run"`
+}
+
+type autoAssignResult struct {
+	DryRun      bool                       `json:"dry_run"`
+	Assignments models.ShipmentAssignments `json:"assignments"`
+	Unassigned  models.Shipments           `json:"unassigned"`
+}
+
+// shipmentsAutoAssign assigns drivers to a batch of Unassigned Shipments, all of them when no ids are given.
+// A dry run returns the assignments without saving them. This function is mapped to the path POST /shipments/auto-assign
+func shipmentsAutoAssign(c buffalo.Context) error {
+	var loggedInUser = loggedInUser(c)
+	req := &autoAssignRequest{}
+	if err := c.Bind(req); err != nil {
+		c.Logger().Errorf("error binding auto assign: %v\n", err)
+		return err
+	}
+	window, err := reservationWindowParam(c)
+	if err != nil {
+		return c.Error(http.StatusBadRequest, err)
+	}
+	tx := c.Value("tx").(*pop.Connection)
+	q := tx.Scope(restrictedScope(c)).Where("status = ?", models.ShipmentStatusUnassigned.String())
+	if len(req.ShipmentIDs) > 0 {
+		var ids = make([]interface{}, len(req.ShipmentIDs))
+		for i, id := range req.ShipmentIDs {
+			ids[i] = id
+		}
+		q = q.Where("id IN (?)", ids...)
+	}
+	candidates := models.Shipments{}
+	if err := q.Eager("Terminal").Order("reservation_time ASC NULLS LAST").All(&candidates); err != nil {
+		return err
+	}
+	var result = autoAssignResult{DryRun: req.DryRun, Unassigned: models.Shipments{}}
+	var shipments = models.Shipments{}
+	var chassisIDs = map[uuid.UUID]bool{}
+	for _, s := range candidates {
+		if err := checkOrderNotLocked(tx, s.OrderID); err != nil {
+			result.Unassigned = append(result.Unassigned, s)
+			continue
+		}
+		// The equipment must be usable once the shipment is assigned, a chassis goes to one shipment only
+		dispatched := s
+		dispatched.Status = models.ShipmentStatusAssigned.String()
+		if err := checkShipmentEquipment(tx, &dispatched); err != nil {
+			if !isShipmentEquipmentError(err) {
+				return err
+			}
+			result.Unassigned = append(result.Unassigned, s)
+			continue
+		}
+		if s.ChassisID.Valid {
+			if chassisIDs[s.ChassisID.UUID] {
+				result.Unassigned = append(result.Unassigned, s)
+				continue
+			}
+			chassisIDs[s.ChassisID.UUID] = true
+		}
+		shipments = append(shipments, s)
+	}
+	workloads, err := models.LoadDriverWorkloads(tx, loggedInUser.TenantID, time.Now().UTC().Add(-models.RecentRejectionsPeriod))
+	if err != nil {
+		return err
+	}
+	assignments, unassigned := models.AssignShipments(shipments, workloads, window)
+	result.Assignments = models.ShipmentAssignments{}
+	result.Unassigned = append(result.Unassigned, unassigned...)
+	for _, a := range assignments {
+		// A shipment whose driver cannot be associated with it stays unassigned, the others are still assigned
+		if err := checkDriverID(c, tx, loggedInUser, &a.Shipment); err != nil {
+			if !isDriverIDError(err) {
+				return err
+			}
+			a.Shipment.DriverID = nulls.UUID{}
+			result.Unassigned = append(result.Unassigned, a.Shipment)
+			continue
+		}
+		result.Assignments = append(result.Assignments, a)
+	}
+	if req.DryRun {
+		return c.Render(http.StatusOK, r.JSON(result))
+	}
+	for i := range result.Assignments {
+		if err := assignShipmentDriver(c, tx, loggedInUser, &result.Assignments[i].Shipment); err != nil {
+			return err
+		}
+	}
+	return c.Render(http.StatusOK, r.JSON(result))
+}
+
+// assignShipmentDriver saves an Unassigned shipment with the driver it was given and notifies the driver,
+// as when back office assigns one through shipmentsUpdate. The driver must have been checked with checkDriverID.
+func assignShipmentDriver(c buffalo.Context, tx *pop.Connection, loggedInUser *models.User, shipment *models.Shipment) error {
+	fromStatus := shipment.Status
+	if err := models.CheckShipmentStatusTransition(loggedInUser, models.ShipmentStatus(fromStatus), models.ShipmentStatusAssigned); err != nil {
+		return err
+	}
+	shipment.Status = models.ShipmentStatusAssigned.String()
+	shipment.UpdatedAt = time.Now().UTC()
+	verrs, err := tx.ValidateAndUpdate(shipment)
+	if err != nil {
+		return err
+	}
+	if verrs.HasAny() {
+		return verrs
+	}
+	if err := createShipmentEvent(tx, shipment, fromStatus, nulls.UUID{}, loggedInUser); err != nil {
+		return err
+	}
+	if err := syncOrderStatus(c, tx, shipment.OrderID); err != nil {
+		return err
+	}
+	sendNotificationsAsync(
+		models.NotificationEventShipmentAssigned,
+		[]string{firebase.GetDriverTopic(shipment.TenantID.String(), shipment.DriverID.UUID.String())},
+		fmt.Sprintf("You have been assigned a pickup - %s", shipment.SerialNumber),
+		shipment.SerialNumber,
+		map[string]string{
+			"shipment.id":           shipment.ID.String(),
+			"shipment.serialNumber": shipment.SerialNumber,
+		},
+	)
+	return nil
+}
+
+// reservationWindowParam returns how long a reservation keeps a driver busy, param "window" is in minutes
+func reservationWindowParam(c buffalo.Context) (time.Duration, error) {
+	w := c.Param("window")
+	if w == "" {
+		return models.DefaultReservationWindow, nil
+	}
+	minutes, err := strconv.Atoi(w)
+	if err != nil || minutes <= 0 {
+		return 0, fmt.Errorf("invalid window %q", w)
+	}
+	return time.Duration(minutes) * time.Minute, nil
+}
@@ -0,0 +1,166 @@
+package actions
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/nulls"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+)
+
+func (as *ActionSuite) Test_DispatchBoard() {
+	as.LoadFixture("Tenant bootstrap")
+	var tests = []struct {
+		username     string
+		responseCode int
+		drivers      int
+	}{
+		{"klopp", http.StatusOK, 3},
+		{"firmino", http.StatusOK, 2},
+		{"mane", http.StatusOK, 2},
+		{"rodriguez", http.StatusOK, 1},
+		{"salah", http.StatusNotFound, 0},
+		{"nike", http.StatusNotFound, 0},
+		{"coutinho", http.StatusNotFound, 0},
+	}
+	firmino := as.getLoggedInUser("firmino")
+	salah := as.getLoggedInUser("salah")
+	as.createUser("henderson", models.UserRoleDriver, "henderson@bigpanther.ca", firmino.TenantID, nulls.UUID{})
+	day := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)
+	for i, hour := range []int{13, 8, 9, 32} {
+		as.createShipment(models.Shipment{SerialNumber: fmt.Sprintf("board%c", 'a'+i), Status: models.ShipmentStatusAssigned.String(), CreatedBy: firmino.ID, TenantID: firmino.TenantID, Type: models.ShipmentTypeInbound.String(), DriverID: nulls.NewUUID(salah.ID), ReservationTime: nulls.NewTime(day.Add(time.Duration(hour) * time.Hour))}, nil)
+	}
+	for _, test := range tests {
+		as.T().Run(test.username, func(t *testing.T) {
+			user := as.getLoggedInUser(test.username)
+			res := as.setupRequest(user, "/dispatch/board?date=2026-10-20").Get()
+			as.Equal(test.responseCode, res.Code)
+			if res.Code != http.StatusOK {
+				return
+			}
+			var board = models.DispatchBoard{}
+			res.Bind(&board)
+			as.Equal(test.drivers, len(board.Drivers))
+			for _, d := range board.Drivers {
+				if d.Driver.ID != salah.ID {
+					as.Equal(0, len(d.Shipments))
+					continue
+				}
+				as.Equal(3, len(d.Shipments))
+				as.Equal("boardb", d.Shipments[0].SerialNumber)
+				as.Equal("boarda", d.Shipments[2].SerialNumber)
+				as.Equal(3, d.StatusCounts[models.ShipmentStatusAssigned.String()])
+				as.Equal(1, len(d.Conflicts))
+			}
+		})
+	}
+}
+
+func (as *ActionSuite) Test_DispatchBoardInvalidParams() {
+	as.LoadFixture("Tenant bootstrap")
+	mane := as.getLoggedInUser("mane")
+	for _, route := range []string{"/dispatch/board?date=20-10-2026", "/dispatch/board?window=0", "/dispatch/board?window=two"} {
+		res := as.setupRequest(mane, route).Get()
+		as.Equal(http.StatusBadRequest, res.Code, route)
+	}
+	res := as.setupRequest(mane, "/dispatch/board?window=300").Get()
+	as.Equal(http.StatusOK, res.Code)
+}
+
+func (as *ActionSuite) Test_ShipmentsSuggestDrivers() {
+	as.LoadFixture("Tenant bootstrap")
+	var tests = []struct {
+		username     string
+		responseCode int
+	}{
+		{"firmino", http.StatusOK},
+		{"mane", http.StatusOK},
+		{"rodriguez", http.StatusNotFound},
+		{"salah", http.StatusNotFound},
+		{"nike", http.StatusNotFound},
+	}
+	firmino := as.getLoggedInUser("firmino")
+	salah := as.getLoggedInUser("salah")
+	henderson := as.createUser("henderson", models.UserRoleDriver, "henderson@bigpanther.ca", firmino.TenantID, nulls.UUID{})
+	at := time.Date(2026, 10, 20, 10, 0, 0, 0, time.UTC)
+	as.createShipment(models.Shipment{SerialNumber: "busy1", Status: models.ShipmentStatusAssigned.String(), CreatedBy: firmino.ID, TenantID: firmino.TenantID, Type: models.ShipmentTypeInbound.String(), DriverID: nulls.NewUUID(salah.ID), ReservationTime: nulls.NewTime(at)}, nil)
+	shipment := as.createShipment(models.Shipment{SerialNumber: "todo1", Status: models.ShipmentStatusUnassigned.String(), CreatedBy: firmino.ID, TenantID: firmino.TenantID, Type: models.ShipmentTypeInbound.String(), ReservationTime: nulls.NewTime(at.Add(30 * time.Minute))}, nil)
+	for _, test := range tests {
+		as.T().Run(test.username, func(t *testing.T) {
+			res := as.setupRequest(as.getLoggedInUser(test.username), fmt.Sprintf("/shipments/%s/suggest-drivers", shipment.ID)).Post(nil)
+			as.Equal(test.responseCode, res.Code)
+			if res.Code != http.StatusOK {
+				return
+			}
+			var candidates = models.DriverCandidates{}
+			res.Bind(&candidates)
+			as.Equal(2, len(candidates))
+			as.Equal(henderson.ID, candidates[0].Driver.ID)
+			as.False(candidates[0].HasConflict)
+			as.Equal(salah.ID, candidates[1].Driver.ID)
+			as.True(candidates[1].HasConflict)
+			as.NotEmpty(candidates[1].Reasons)
+		})
+	}
+	assigned := as.createShipment(models.Shipment{SerialNumber: "done1", Status: models.ShipmentStatusAssigned.String(), CreatedBy: firmino.ID, TenantID: firmino.TenantID, Type: models.ShipmentTypeInbound.String(), DriverID: nulls.NewUUID(salah.ID)}, nil)
+	res := as.setupRequest(firmino, fmt.Sprintf("/shipments/%s/suggest-drivers", assigned.ID)).Post(nil)
+	as.Equal(http.StatusConflict, res.Code)
+}
+
+func (as *ActionSuite) Test_ShipmentsAutoAssign() {
+	as.LoadFixture("Tenant bootstrap")
+	firmino := as.getLoggedInUser("firmino")
+	mane := as.getLoggedInUser("mane")
+	richarlson := as.getLoggedInUser("richarlson")
+	as.createUser("henderson", models.UserRoleDriver, "henderson@bigpanther.ca", firmino.TenantID, nulls.UUID{})
+	mockFirebase.EXPECT().SendAll(gomock.Any(), gomock.Any()).AnyTimes()
+	at := time.Date(2026, 10, 20, 10, 0, 0, 0, time.UTC)
+	var shipments = models.Shipments{}
+	for i, reservation := range []time.Time{at, at, at.Add(30 * time.Minute)} {
+		shipments = append(shipments, *as.createShipment(models.Shipment{SerialNumber: fmt.Sprintf("auto%d", i), Status: models.ShipmentStatusUnassigned.String(), CreatedBy: firmino.ID, TenantID: firmino.TenantID, Type: models.ShipmentTypeInbound.String(), ReservationTime: nulls.NewTime(reservation)}, nil))
+	}
+	other := as.createShipment(models.Shipment{SerialNumber: "other", Status: models.ShipmentStatusUnassigned.String(), CreatedBy: richarlson.ID, TenantID: richarlson.TenantID, Type: models.ShipmentTypeInbound.String(), ReservationTime: nulls.NewTime(at)}, nil)
+
+	for _, username := range []string{"salah", "nike"} {
+		res := as.setupRequest(as.getLoggedInUser(username), "/shipments/auto-assign").Post(autoAssignRequest{DryRun: true})
+		as.Equal(http.StatusNotFound, res.Code)
+	}
+
+	res := as.setupRequest(mane, "/shipments/auto-assign").Post(autoAssignRequest{DryRun: true})
+	as.Equal(http.StatusOK, res.Code)
+	var result = autoAssignResult{}
+	res.Bind(&result)
+	as.True(result.DryRun)
+	as.Equal(2, len(result.Assignments))
+	as.NotEqual(result.Assignments[0].Candidate.Driver.ID, result.Assignments[1].Candidate.Driver.ID)
+	// Both drivers are busy for the third one
+	as.Equal(1, len(result.Unassigned))
+	as.Equal("auto2", result.Unassigned[0].SerialNumber)
+	for i := range shipments {
+		as.Nil(as.DB.Reload(&shipments[i]))
+		as.Equal(models.ShipmentStatusUnassigned.String(), shipments[i].Status)
+	}
+
+	res = as.setupRequest(mane, "/shipments/auto-assign").Post(autoAssignRequest{ShipmentIDs: []uuid.UUID{shipments[0].ID, shipments[2].ID, other.ID}})
+	as.Equal(http.StatusOK, res.Code)
+	res.Bind(&result)
+	as.False(result.DryRun)
+	// The reservations are less than the window apart, but there are two drivers
+	as.Equal(2, len(result.Assignments))
+	as.Equal(0, len(result.Unassigned))
+	for _, i := range []int{0, 2} {
+		as.Nil(as.DB.Reload(&shipments[i]))
+		as.Equal(models.ShipmentStatusAssigned.String(), shipments[i].Status)
+		as.True(shipments[i].DriverID.Valid)
+		count, err := as.DB.Where("shipment_id = ?", shipments[i].ID).Where("to_status = ?", models.ShipmentStatusAssigned.String()).Count(&models.ShipmentEvents{})
+		as.Nil(err)
+		as.Equal(1, count)
+	}
+	as.NotEqual(shipments[0].DriverID, shipments[2].DriverID)
+	as.Nil(as.DB.Reload(other))
+	as.Equal(models.ShipmentStatusUnassigned.String(), other.Status)
+}
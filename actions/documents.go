@@ -0,0 +1,189 @@
+package actions
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/bigpanther/trober/blobstore"
+	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/buffalo/render"
+	"github.com/gobuffalo/nulls"
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gofrs/uuid"
+)
+
+// Following naming logic is implemented in Buffalo:
+// Model: Singular (Document)
+// DB Table: Plural (documents)
+// Resource: Plural (Documents)
+// Path: Plural (/orders/{order_id}/documents, /shipments/{shipment_id}/documents)
+
+// documentPayload is a document with its file, base64 encoded
+type documentPayload struct {
+	models.Document
+	Content []byte `json:"content"`
+}
+
+// documentsList gets the Documents of an Order or a Shipment. Param "type" filters them.
+// This function is mapped to the paths GET /orders/{order_id}/documents and GET /shipments/{shipment_id}/documents
+func documentsList(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	owner, err := findDocumentOwner(c, tx)
+	if err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	documents := &models.Documents{}
+	q := documentsOf(tx, owner)
+	if documentType := c.Param("type"); documentType != "" {
+		q = q.Where("type = ?", documentType)
+	}
+	if err := q.Order("created_at DESC").All(documents); err != nil {
+		return err
+	}
+	return c.Render(http.StatusOK, r.JSON(documents))
+}
+
+// documentsShow gets the data for one Document of an Order or a Shipment. This function is mapped to the paths
+// GET /orders/{order_id}/documents/{document_id} and GET /shipments/{shipment_id}/documents/{document_id}
+func documentsShow(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	document, err := findDocument(c, tx)
+	if err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	return c.Render(http.StatusOK, r.JSON(document))
+}
+
+// documentsCreate attaches a Document to an Order or a Shipment, its file is stored in the blob store.
+// This function is mapped to the paths POST /orders/{order_id}/documents and POST /shipments/{shipment_id}/documents
+func documentsCreate(b blobstore.Store) buffalo.Handler {
+	return func(c buffalo.Context) error {
+		tx := c.Value("tx").(*pop.Connection)
+		var loggedInUser = loggedInUser(c)
+		owner, err := findDocumentOwner(c, tx)
+		if err != nil {
+			return c.Error(http.StatusNotFound, err)
+		}
+		payload := &documentPayload{}
+		if err := c.Bind(payload); err != nil {
+			c.Logger().Errorf("error binding document: %v\n", err)
+			return err
+		}
+		if len(payload.Content) == 0 {
+			return c.Error(http.StatusBadRequest, errors.New("missing content"))
+		}
+		document := &payload.Document
+		document.ID = uuid.Nil
+		document.CreatedBy = loggedInUser.ID
+		document.TenantID = owner.TenantID
+		document.OrderID = owner.OrderID
+		document.ShipmentID = owner.ShipmentID
+		document.SetContent(payload.Content)
+		verrs, err := tx.ValidateAndCreate(document)
+		if err != nil {
+			return err
+		}
+		if verrs.HasAny() {
+			return c.Render(http.StatusUnprocessableEntity, r.JSON(verrs))
+		}
+		if err := b.Put(c, document.BlobKey, bytes.NewReader(payload.Content)); err != nil {
+			return err
+		}
+		return c.Render(http.StatusCreated, r.JSON(document))
+	}
+}
+
+// documentsDownload downloads the file of a Document of an Order or a Shipment. This function is mapped to the paths
+// GET /orders/{order_id}/documents/{document_id}/download and GET /shipments/{shipment_id}/documents/{document_id}/download
+func documentsDownload(b blobstore.Store) buffalo.Handler {
+	return func(c buffalo.Context) error {
+		tx := c.Value("tx").(*pop.Connection)
+		document, err := findDocument(c, tx)
+		if err != nil {
+			return c.Error(http.StatusNotFound, err)
+		}
+		blob, err := b.Get(c, document.BlobKey)
+		if errors.Is(err, blobstore.ErrNotFound) {
+			return c.Error(http.StatusNotFound, err)
+		}
+		if err != nil {
+			return err
+		}
+		defer blob.Close()
+		c.Response().Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": document.Name}))
+		return c.Render(http.StatusOK, r.Func(document.ContentType, func(w io.Writer, _ render.Data) error {
+			_, err := io.Copy(w, blob)
+			return err
+		}))
+	}
+}
+
+// documentsDestroy deletes a Document of an Order or a Shipment and its file. This function is mapped to the paths
+// DELETE /orders/{order_id}/documents/{document_id} and DELETE /shipments/{shipment_id}/documents/{document_id}
+func documentsDestroy(b blobstore.Store) buffalo.Handler {
+	return func(c buffalo.Context) error {
+		tx := c.Value("tx").(*pop.Connection)
+		document, err := findDocument(c, tx)
+		if err != nil {
+			return c.Error(http.StatusNotFound, err)
+		}
+		if err := tx.Destroy(document); err != nil {
+			return err
+		}
+		if err := b.Delete(c, document.BlobKey); err != nil {
+			return err
+		}
+		c.Response().WriteHeader(http.StatusNoContent)
+		return nil
+	}
+}
+
+// findDocumentOwner gets the Order of the path param "order_id" or the Shipment of the path param "shipment_id", and
+// returns a Document attached to it. Customers only find their orders and shipments, drivers only their shipments.
+func findDocumentOwner(c buffalo.Context, tx *pop.Connection) (*models.Document, error) {
+	var loggedInUser = loggedInUser(c)
+	q := tx.Scope(restrictedScope(c))
+	if loggedInUser.IsCustomer() {
+		q = q.Where("customer_id = ?", loggedInUser.CustomerID)
+	}
+	if orderID := c.Param("order_id"); orderID != "" {
+		order := &models.Order{}
+		if err := q.Find(order, orderID); err != nil {
+			return nil, err
+		}
+		return &models.Document{TenantID: order.TenantID, OrderID: nulls.NewUUID(order.ID)}, nil
+	}
+	if loggedInUser.IsDriver() {
+		q = q.Where("driver_id = ?", loggedInUser.ID)
+	}
+	shipment := &models.Shipment{}
+	if err := q.Find(shipment, c.Param("shipment_id")); err != nil {
+		return nil, err
+	}
+	return &models.Document{TenantID: shipment.TenantID, ShipmentID: nulls.NewUUID(shipment.ID)}, nil
+}
+
+// findDocument gets the Document of the path param "document_id" attached to the Order or the Shipment of the path
+func findDocument(c buffalo.Context, tx *pop.Connection) (*models.Document, error) {
+	owner, err := findDocumentOwner(c, tx)
+	if err != nil {
+		return nil, err
+	}
+	document := &models.Document{}
+	if err := documentsOf(tx, owner).Find(document, c.Param("document_id")); err != nil {
+		return nil, err
+	}
+	return document, nil
+}
+
+// documentsOf returns the query of the documents attached to the same Order or Shipment as the owner
+func documentsOf(tx *pop.Connection, owner *models.Document) *pop.Query {
+	if owner.OrderID.Valid {
+		return tx.Where("order_id = ?", owner.OrderID)
+	}
+	return tx.Where("shipment_id = ?", owner.ShipmentID)
+}
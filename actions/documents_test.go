@@ -0,0 +1,145 @@
+package actions
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/nulls"
+)
+
+var pdfContent = []byte("%PDF-1.7\n%delivery order")
+
+func (as *ActionSuite) Test_ShipmentDocuments() {
+	as.LoadFixture("Tenant bootstrap")
+	salah := as.getLoggedInUser("salah")
+	efaLiv := as.getCustomer("EFA Liv")
+	order := as.createOrder("doc", models.OrderStatusAccepted, salah.TenantID, salah.ID, efaLiv.ID)
+	shipment := as.createShipment(models.Shipment{SerialNumber: "doc", Status: models.ShipmentStatusAccepted.String(), Type: models.ShipmentTypeInbound.String(),
+		CreatedBy: salah.ID, TenantID: salah.TenantID, DriverID: nulls.NewUUID(salah.ID)}, order)
+	var path = fmt.Sprintf("/shipments/%s/documents", shipment.ID)
+	var document = documentPayload{Document: models.Document{Type: models.DocumentTypeDeliveryOrder.String(), Name: "do.pdf"}, Content: pdfContent}
+	var tests = []struct {
+		username     string
+		payload      documentPayload
+		responseCode int
+	}{
+		{"lewin", document, http.StatusNotFound},
+		{"rodriguez", document, http.StatusNotFound},
+		{"adidas", document, http.StatusNotFound},
+		{"mane", documentPayload{Document: document.Document}, http.StatusBadRequest},
+		{"mane", documentPayload{Document: models.Document{Type: "Receipt", Name: "do.pdf"}, Content: pdfContent}, http.StatusUnprocessableEntity},
+		{"mane", documentPayload{Document: document.Document, Content: []byte("plain text")}, http.StatusUnprocessableEntity},
+		{"mane", document, http.StatusCreated},
+		{"salah", document, http.StatusCreated},
+		{"nike", documentPayload{Document: models.Document{Type: models.DocumentTypeCustomsRelease.String(), Name: "release.pdf"}, Content: pdfContent}, http.StatusCreated},
+	}
+	for i, test := range tests {
+		as.T().Run(fmt.Sprint(i), func(t *testing.T) {
+			res := as.setupRequest(as.getLoggedInUser(test.username), path).Post(test.payload)
+			as.Equal(test.responseCode, res.Code, res.Body.String())
+		})
+	}
+
+	var documents = models.Documents{}
+	res := as.setupRequest(as.getLoggedInUser("nike"), path+"?type=DeliveryOrder").Get()
+	as.Equal(http.StatusOK, res.Code)
+	res.Bind(&documents)
+	as.Len(documents, 2)
+	created := documents[0]
+	as.Equal(shipment.ID, created.ShipmentID.UUID)
+	as.False(created.OrderID.Valid)
+	as.Equal("application/pdf", created.ContentType)
+	as.Equal(len(pdfContent), created.Size)
+	as.Len(created.Checksum, 64)
+
+	var readTests = []struct {
+		username     string
+		responseCode int
+	}{
+		{"lewin", http.StatusNotFound},
+		{"rodriguez", http.StatusNotFound},
+		{"adidas", http.StatusNotFound},
+		{"salah", http.StatusOK},
+		{"nike", http.StatusOK},
+		{"mane", http.StatusOK},
+	}
+	for _, test := range readTests {
+		as.T().Run(test.username, func(t *testing.T) {
+			res := as.setupRequest(as.getLoggedInUser(test.username), fmt.Sprintf("%s/%s", path, created.ID)).Get()
+			as.Equal(test.responseCode, res.Code)
+			res = as.setupRequest(as.getLoggedInUser(test.username), fmt.Sprintf("%s/%s/download", path, created.ID)).Get()
+			as.Equal(test.responseCode, res.Code)
+			if test.responseCode == http.StatusOK {
+				as.Equal("application/pdf", res.Header().Get("Content-Type"))
+				as.Equal(`attachment; filename=do.pdf`, res.Header().Get("Content-Disposition"))
+				as.Equal(pdfContent, res.Body.Bytes())
+			}
+		})
+	}
+
+	var deleteTests = []struct {
+		username     string
+		responseCode int
+	}{
+		{"salah", http.StatusNotFound},
+		{"nike", http.StatusNotFound},
+		{"rodriguez", http.StatusNotFound},
+		{"mane", http.StatusNoContent},
+		{"mane", http.StatusNotFound},
+	}
+	for _, test := range deleteTests {
+		as.T().Run(test.username, func(t *testing.T) {
+			res := as.setupRequest(as.getLoggedInUser(test.username), fmt.Sprintf("%s/%s", path, created.ID)).Delete()
+			as.Equal(test.responseCode, res.Code)
+		})
+	}
+	count, err := as.DB.Where("shipment_id = ?", shipment.ID).Count(&models.Document{})
+	as.Nil(err)
+	as.Equal(2, count)
+}
+
+func (as *ActionSuite) Test_OrderDocuments() {
+	as.LoadFixture("Tenant bootstrap")
+	firmino := as.getLoggedInUser("firmino")
+	efaLiv := as.getCustomer("EFA Liv")
+	order := as.createOrder("doc", models.OrderStatusAccepted, firmino.TenantID, firmino.ID, efaLiv.ID)
+	var path = fmt.Sprintf("/orders/%s/documents", order.ID)
+	var document = documentPayload{Document: models.Document{Type: models.DocumentTypeBillOfLading.String(), Name: "bol.pdf"}, Content: pdfContent}
+	var tests = []struct {
+		username     string
+		responseCode int
+	}{
+		{"salah", http.StatusNotFound},
+		{"rodriguez", http.StatusNotFound},
+		{"adidas", http.StatusNotFound},
+		{"nike", http.StatusCreated},
+		{"firmino", http.StatusCreated},
+		{"klopp", http.StatusCreated},
+	}
+	for _, test := range tests {
+		as.T().Run(test.username, func(t *testing.T) {
+			res := as.setupRequest(as.getLoggedInUser(test.username), path).Post(document)
+			as.Equal(test.responseCode, res.Code, res.Body.String())
+			if test.responseCode != http.StatusCreated {
+				return
+			}
+			created := models.Document{}
+			res.Bind(&created)
+			as.Equal(order.ID, created.OrderID.UUID)
+			as.Equal(order.TenantID, created.TenantID)
+			as.Equal(as.getLoggedInUser(test.username).ID, created.CreatedBy)
+		})
+	}
+	var documents = models.Documents{}
+	res := as.setupRequest(as.getLoggedInUser("nike"), path).Get()
+	as.Equal(http.StatusOK, res.Code)
+	res.Bind(&documents)
+	as.Len(documents, 3)
+	res = as.setupRequest(as.getLoggedInUser("salah"), fmt.Sprintf("%s/%s/download", path, documents[0].ID)).Get()
+	as.Equal(http.StatusNotFound, res.Code)
+	// A document is only found through its owner
+	res = as.setupRequest(as.getLoggedInUser("mane"), fmt.Sprintf("/orders/%s/documents/%s", efaLiv.ID, documents[0].ID)).Get()
+	as.Equal(http.StatusNotFound, res.Code)
+}
@@ -0,0 +1,328 @@
+package actions
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bigpanther/trober/firebase"
+	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/nulls"
+	"github.com/gobuffalo/pop/v6"
+)
+
+// Following naming logic is implemented in Buffalo:
+// Model: Singular (DriverShift, TimeOffRequest)
+// DB Table: Plural (driver_shifts, time_off_requests)
+// Resource: Plural (DriverShifts, TimeOffRequests)
+// Path: Plural (/driver-shifts, /time-off)
+
+var errTimeOffReviewed = errors.New("time off request has already been reviewed")
+
+// driverShiftsList gets all DriverShifts. This function is mapped to the path
+// GET /driver-shifts
+func driverShiftsList(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	driverShifts := &models.DriverShifts{}
+
+	// Paginate results. Params "page" and "per_page" control pagination.
+	// Default values are "page=1" and "per_page=20".
+	q := tx.PaginateFromParams(c.Params())
+
+	if driverID := c.Param("driver_id"); driverID != "" {
+		q = q.Where("driver_id = ?", driverID)
+	}
+	if err := q.Scope(restrictedScope(c)).Order("driver_id, weekday ASC, start_time ASC").All(driverShifts); err != nil {
+		return err
+	}
+	return c.Render(http.StatusOK, r.JSON(driverShifts))
+}
+
+// driverShiftsShow gets the data for one DriverShift. This function is mapped to
+// the path GET /driver-shifts/{driver_shift_id}
+func driverShiftsShow(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	driverShift := &models.DriverShift{}
+	if err := tx.Scope(restrictedScope(c)).Find(driverShift, c.Param("driver_shift_id")); err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	return c.Render(http.StatusOK, r.JSON(driverShift))
+}
+
+// driverShiftsCreate adds a DriverShift to the DB. The timezone defaults to UTC.
+// This function is mapped to the path POST /driver-shifts
+func driverShiftsCreate(c buffalo.Context) error {
+	var loggedInUser = loggedInUser(c)
+	driverShift := &models.DriverShift{}
+	if err := c.Bind(driverShift); err != nil {
+		c.Logger().Errorf("error binding driver shift: %v\n", err)
+		return err
+	}
+	tx := c.Value("tx").(*pop.Connection)
+	driverShift.CreatedBy = loggedInUser.ID
+	driverShift.TenantID = loggedInUser.TenantID
+	if err := checkDriverUserID(c, tx, loggedInUser, nulls.NewUUID(driverShift.DriverID)); err != nil {
+		return c.Error(http.StatusBadRequest, err)
+	}
+	if driverShift.Timezone == "" {
+		driverShift.Timezone = time.UTC.String()
+	}
+	verrs, err := tx.ValidateAndCreate(driverShift)
+	if err != nil {
+		return err
+	}
+	if verrs.HasAny() {
+		return c.Render(http.StatusUnprocessableEntity, r.JSON(verrs))
+	}
+	return c.Render(http.StatusCreated, r.JSON(driverShift))
+}
+
+// driverShiftsUpdate changes a DriverShift in the DB. Shipments already assigned are not checked again.
+// This function is mapped to the path PUT /driver-shifts/{driver_shift_id}
+func driverShiftsUpdate(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	driverShift := &models.DriverShift{}
+	if err := tx.Scope(restrictedScope(c)).Find(driverShift, c.Param("driver_shift_id")); err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	newDriverShift := &models.DriverShift{}
+	if err := c.Bind(newDriverShift); err != nil {
+		c.Logger().Errorf("error binding driver shift: %v\n", err)
+		return err
+	}
+	driverShift.UpdatedAt = time.Now().UTC()
+	driverShift.Weekday = newDriverShift.Weekday
+	driverShift.StartTime = newDriverShift.StartTime
+	driverShift.EndTime = newDriverShift.EndTime
+	driverShift.Capacity = newDriverShift.Capacity
+	if newDriverShift.Timezone != "" {
+		driverShift.Timezone = newDriverShift.Timezone
+	}
+	verrs, err := tx.ValidateAndUpdate(driverShift)
+	if err != nil {
+		return err
+	}
+	if verrs.HasAny() {
+		return c.Render(http.StatusUnprocessableEntity, r.JSON(verrs))
+	}
+	return c.Render(http.StatusOK, r.JSON(driverShift))
+}
+
+// driverShiftsDestroy deletes a DriverShift from the DB. This function is mapped
+// to the path DELETE /driver-shifts/{driver_shift_id}
+func driverShiftsDestroy(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	driverShift := &models.DriverShift{}
+	if err := tx.Scope(restrictedScope(c)).Find(driverShift, c.Param("driver_shift_id")); err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	if err := tx.Destroy(driverShift); err != nil {
+		return err
+	}
+	c.Response().WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// timeOffRequestsList gets all TimeOffRequests. Params "driver_id" and "status" filter the requests.
+// This function is mapped to the path GET /time-off
+func timeOffRequestsList(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	timeOffRequests := &models.TimeOffRequests{}
+
+	// Paginate results. Params "page" and "per_page" control pagination.
+	// Default values are "page=1" and "per_page=20".
+	q := tx.PaginateFromParams(c.Params())
+
+	if driverID := c.Param("driver_id"); driverID != "" {
+		q = q.Where("driver_id = ?", driverID)
+	}
+	if status := c.Param("status"); status != "" {
+		q = q.Where("status = ?", status)
+	}
+	if err := q.Scope(restrictedScope(c)).Order("starts_at DESC").All(timeOffRequests); err != nil {
+		return err
+	}
+	return c.Render(http.StatusOK, r.JSON(timeOffRequests))
+}
+
+// timeOffRequestsShow gets the data for one TimeOffRequest. This function is mapped to
+// the path GET /time-off/{time_off_request_id}
+func timeOffRequestsShow(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	timeOffRequest := &models.TimeOffRequest{}
+	if err := tx.Scope(restrictedScope(c)).Find(timeOffRequest, c.Param("time_off_request_id")); err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	return c.Render(http.StatusOK, r.JSON(timeOffRequest))
+}
+
+// timeOffRequestsCreate adds a TimeOffRequest of a driver to the DB. Time off entered by back office
+// is approved. This function is mapped to the path POST /time-off
+func timeOffRequestsCreate(c buffalo.Context) error {
+	var loggedInUser = loggedInUser(c)
+	timeOffRequest := &models.TimeOffRequest{}
+	if err := c.Bind(timeOffRequest); err != nil {
+		c.Logger().Errorf("error binding time off request: %v\n", err)
+		return err
+	}
+	tx := c.Value("tx").(*pop.Connection)
+	if err := checkDriverUserID(c, tx, loggedInUser, nulls.NewUUID(timeOffRequest.DriverID)); err != nil {
+		return c.Error(http.StatusBadRequest, err)
+	}
+	timeOffRequest.CreatedBy = loggedInUser.ID
+	timeOffRequest.TenantID = loggedInUser.TenantID
+	timeOffRequest.Status = models.TimeOffRequestStatusApproved.String()
+	timeOffRequest.ReviewedBy = nulls.NewUUID(loggedInUser.ID)
+	timeOffRequest.ReviewedAt = nulls.NewTime(time.Now().UTC())
+	verrs, err := tx.ValidateAndCreate(timeOffRequest)
+	if err != nil {
+		return err
+	}
+	if verrs.HasAny() {
+		return c.Render(http.StatusUnprocessableEntity, r.JSON(verrs))
+	}
+	return c.Render(http.StatusCreated, r.JSON(timeOffRequest))
+}
+
+// timeOffRequestsApprove approves a TimeOffRequest and notifies the driver. This function is mapped to
+// the path POST /time-off/{time_off_request_id}/approve
+func timeOffRequestsApprove(c buffalo.Context) error {
+	return reviewTimeOffRequest(c, models.TimeOffRequestStatusApproved)
+}
+
+// timeOffRequestsReject rejects a TimeOffRequest and notifies the driver. This function is mapped to
+// the path POST /time-off/{time_off_request_id}/reject
+func timeOffRequestsReject(c buffalo.Context) error {
+	return reviewTimeOffRequest(c, models.TimeOffRequestStatusRejected)
+}
+
+// timeOffRequestsDestroy deletes a TimeOffRequest from the DB. This function is mapped
+// to the path DELETE /time-off/{time_off_request_id}
+func timeOffRequestsDestroy(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	timeOffRequest := &models.TimeOffRequest{}
+	if err := tx.Scope(restrictedScope(c)).Find(timeOffRequest, c.Param("time_off_request_id")); err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	if err := tx.Destroy(timeOffRequest); err != nil {
+		return err
+	}
+	c.Response().WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// selfShiftsList gets the shifts of the logged in driver. This function is mapped to the path
+// GET /self/shifts
+func selfShiftsList(c buffalo.Context) error {
+	var loggedInUser = loggedInUser(c)
+	tx := c.Value("tx").(*pop.Connection)
+	driverShifts := &models.DriverShifts{}
+	if err := tx.Where("driver_id = ?", loggedInUser.ID).Order("weekday ASC, start_time ASC").All(driverShifts); err != nil {
+		return err
+	}
+	return c.Render(http.StatusOK, r.JSON(driverShifts))
+}
+
+// selfTimeOffList gets the time off requests of the logged in driver. This function is mapped to the path
+// GET /self/time-off
+func selfTimeOffList(c buffalo.Context) error {
+	var loggedInUser = loggedInUser(c)
+	tx := c.Value("tx").(*pop.Connection)
+	timeOffRequests := &models.TimeOffRequests{}
+	q := tx.PaginateFromParams(c.Params()).Where("driver_id = ?", loggedInUser.ID)
+	if err := q.Order("starts_at DESC").All(timeOffRequests); err != nil {
+		return err
+	}
+	return c.Render(http.StatusOK, r.JSON(timeOffRequests))
+}
+
+// selfTimeOffCreate requests time off for the logged in driver and notifies back office. This function is mapped
+// to the path POST /self/time-off
+func selfTimeOffCreate(c buffalo.Context) error {
+	var loggedInUser = loggedInUser(c)
+	timeOffRequest := &models.TimeOffRequest{}
+	if err := c.Bind(timeOffRequest); err != nil {
+		c.Logger().Errorf("error binding time off request: %v\n", err)
+		return err
+	}
+	tx := c.Value("tx").(*pop.Connection)
+	timeOffRequest.CreatedBy = loggedInUser.ID
+	timeOffRequest.TenantID = loggedInUser.TenantID
+	timeOffRequest.DriverID = loggedInUser.ID
+	timeOffRequest.Status = models.TimeOffRequestStatusRequested.String()
+	timeOffRequest.ReviewedBy = nulls.UUID{}
+	timeOffRequest.ReviewedAt = nulls.Time{}
+	verrs, err := tx.ValidateAndCreate(timeOffRequest)
+	if err != nil {
+		return err
+	}
+	if verrs.HasAny() {
+		return c.Render(http.StatusUnprocessableEntity, r.JSON(verrs))
+	}
+	sendNotificationsAsync(
+		models.NotificationEventTimeOff,
+		[]string{firebase.GetBackOfficeTopic(loggedInUser)},
+		fmt.Sprintf("Time off requested by %s", loggedInUser.Name),
+		fmt.Sprintf("%s - %s", timeOffRequest.StartsAt.Format(reportDateFormat), timeOffRequest.EndsAt.Format(reportDateFormat)),
+		map[string]string{
+			"timeOffRequest.id": timeOffRequest.ID.String(),
+		},
+	)
+	return c.Render(http.StatusCreated, r.JSON(timeOffRequest))
+}
+
+// selfTimeOffDestroy withdraws a time off request of the logged in driver that is not reviewed yet.
+// This function is mapped to the path DELETE /self/time-off/{time_off_request_id}
+func selfTimeOffDestroy(c buffalo.Context) error {
+	var loggedInUser = loggedInUser(c)
+	tx := c.Value("tx").(*pop.Connection)
+	timeOffRequest := &models.TimeOffRequest{}
+	if err := tx.Where("driver_id = ?", loggedInUser.ID).Find(timeOffRequest, c.Param("time_off_request_id")); err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	if timeOffRequest.IsReviewed() {
+		return c.Error(http.StatusConflict, errTimeOffReviewed)
+	}
+	if err := tx.Destroy(timeOffRequest); err != nil {
+		return err
+	}
+	c.Response().WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// reviewTimeOffRequest approves or rejects a requested time off and notifies the driver
+func reviewTimeOffRequest(c buffalo.Context, status models.TimeOffRequestStatus) error {
+	var loggedInUser = loggedInUser(c)
+	tx := c.Value("tx").(*pop.Connection)
+	timeOffRequest := &models.TimeOffRequest{}
+	if err := tx.Scope(restrictedScope(c)).Find(timeOffRequest, c.Param("time_off_request_id")); err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	if timeOffRequest.IsReviewed() {
+		return c.Error(http.StatusConflict, errTimeOffReviewed)
+	}
+	timeOffRequest.UpdatedAt = time.Now().UTC()
+	timeOffRequest.Status = status.String()
+	timeOffRequest.ReviewedBy = nulls.NewUUID(loggedInUser.ID)
+	timeOffRequest.ReviewedAt = nulls.NewTime(timeOffRequest.UpdatedAt)
+	verrs, err := tx.ValidateAndUpdate(timeOffRequest)
+	if err != nil {
+		return err
+	}
+	if verrs.HasAny() {
+		return c.Render(http.StatusUnprocessableEntity, r.JSON(verrs))
+	}
+	sendNotificationsAsync(
+		models.NotificationEventTimeOff,
+		[]string{firebase.GetDriverTopic(timeOffRequest.TenantID.String(), timeOffRequest.DriverID.String())},
+		fmt.Sprintf("Your time off has been %s", strings.ToLower(status.String())),
+		fmt.Sprintf("%s - %s", timeOffRequest.StartsAt.Format(reportDateFormat), timeOffRequest.EndsAt.Format(reportDateFormat)),
+		map[string]string{
+			"timeOffRequest.id": timeOffRequest.ID.String(),
+		},
+	)
+	return c.Render(http.StatusOK, r.JSON(timeOffRequest))
+}
@@ -0,0 +1,177 @@
+package actions
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/nulls"
+	"github.com/golang/mock/gomock"
+)
+
+func (as *ActionSuite) createDriverShift(user *models.User, shift models.DriverShift) *models.DriverShift {
+	res := as.setupRequest(user, "/driver-shifts").Post(shift)
+	as.Equal(http.StatusCreated, res.Code, res.Body.String())
+	var created = &models.DriverShift{}
+	res.Bind(created)
+	return created
+}
+
+func (as *ActionSuite) Test_DriverShiftsCreate() {
+	as.LoadFixture("Tenant bootstrap")
+	salah := as.getLoggedInUser("salah")
+	var tests = []struct {
+		username     string
+		responseCode int
+	}{
+		{"firmino", http.StatusCreated},
+		{"mane", http.StatusCreated},
+		{"rodriguez", http.StatusBadRequest},
+		{"salah", http.StatusNotFound},
+		{"nike", http.StatusNotFound},
+		{"coutinho", http.StatusNotFound},
+	}
+	for _, test := range tests {
+		as.T().Run(test.username, func(t *testing.T) {
+			user := as.getLoggedInUser(test.username)
+			res := as.setupRequest(user, "/driver-shifts").Post(models.DriverShift{DriverID: salah.ID, Weekday: int(time.Monday), StartTime: "08:00", EndTime: "16:00"})
+			as.Equal(test.responseCode, res.Code)
+			if res.Code != http.StatusCreated {
+				return
+			}
+			var shift = models.DriverShift{}
+			res.Bind(&shift)
+			as.Equal(user.TenantID, shift.TenantID)
+			as.Equal(salah.ID, shift.DriverID)
+			as.Equal("UTC", shift.Timezone)
+		})
+	}
+	mane := as.getLoggedInUser("mane")
+	nike := as.getLoggedInUser("nike")
+	res := as.setupRequest(mane, "/driver-shifts").Post(models.DriverShift{DriverID: nike.ID, Weekday: int(time.Monday), StartTime: "08:00", EndTime: "16:00"})
+	as.Equal(http.StatusBadRequest, res.Code)
+	res = as.setupRequest(mane, "/driver-shifts").Post(models.DriverShift{DriverID: salah.ID, Weekday: 9, StartTime: "08:00", EndTime: "16:00", Timezone: "Nowhere"})
+	as.Equal(http.StatusUnprocessableEntity, res.Code)
+	res = as.setupRequest(mane, fmt.Sprintf("/driver-shifts?driver_id=%s", salah.ID)).Get()
+	as.Equal(http.StatusOK, res.Code)
+	var shifts = models.DriverShifts{}
+	res.Bind(&shifts)
+	as.Len(shifts, 2)
+	res = as.setupRequest(salah, "/self/shifts").Get()
+	as.Equal(http.StatusOK, res.Code)
+	res.Bind(&shifts)
+	as.Len(shifts, 2)
+	res = as.setupRequest(mane, "/self/shifts").Get()
+	as.Equal(http.StatusNotFound, res.Code)
+}
+
+func (as *ActionSuite) Test_TimeOffRequests() {
+	as.LoadFixture("Tenant bootstrap")
+	mockFirebase.EXPECT().SendAll(gomock.Any(), gomock.Any()).AnyTimes()
+	salah := as.getLoggedInUser("salah")
+	mane := as.getLoggedInUser("mane")
+	richarlson := as.getLoggedInUser("richarlson")
+	var start = time.Date(2026, 11, 2, 0, 0, 0, 0, time.UTC)
+
+	res := as.setupRequest(salah, "/self/time-off").Post(models.TimeOffRequest{StartsAt: start, EndsAt: start.AddDate(0, 0, 3), Reason: nulls.NewString("Vacation"), Status: models.TimeOffRequestStatusApproved.String()})
+	as.Equal(http.StatusCreated, res.Code)
+	var requested = models.TimeOffRequest{}
+	res.Bind(&requested)
+	as.Equal(salah.ID, requested.DriverID)
+	as.Equal(models.TimeOffRequestStatusRequested.String(), requested.Status)
+
+	res = as.setupRequest(salah, "/self/time-off").Post(models.TimeOffRequest{StartsAt: start, EndsAt: start})
+	as.Equal(http.StatusUnprocessableEntity, res.Code)
+	res = as.setupRequest(as.getLoggedInUser("nike"), "/self/time-off").Post(models.TimeOffRequest{StartsAt: start, EndsAt: start.AddDate(0, 0, 1)})
+	as.Equal(http.StatusNotFound, res.Code)
+	res = as.setupRequest(mane, "/self/time-off").Post(models.TimeOffRequest{StartsAt: start, EndsAt: start.AddDate(0, 0, 1)})
+	as.Equal(http.StatusNotFound, res.Code)
+
+	res = as.setupRequest(richarlson, fmt.Sprintf("/time-off/%s/approve", requested.ID)).Post(nil)
+	as.Equal(http.StatusNotFound, res.Code)
+	res = as.setupRequest(mane, fmt.Sprintf("/time-off?status=%s", models.TimeOffRequestStatusRequested)).Get()
+	as.Equal(http.StatusOK, res.Code)
+	var timeOff = models.TimeOffRequests{}
+	res.Bind(&timeOff)
+	as.Len(timeOff, 1)
+
+	res = as.setupRequest(mane, fmt.Sprintf("/time-off/%s/approve", requested.ID)).Post(nil)
+	as.Equal(http.StatusOK, res.Code)
+	var approved = models.TimeOffRequest{}
+	res.Bind(&approved)
+	as.Equal(models.TimeOffRequestStatusApproved.String(), approved.Status)
+	as.Equal(nulls.NewUUID(mane.ID), approved.ReviewedBy)
+	as.True(approved.ReviewedAt.Valid)
+	res = as.setupRequest(mane, fmt.Sprintf("/time-off/%s/reject", requested.ID)).Post(nil)
+	as.Equal(http.StatusConflict, res.Code)
+	res = as.setupRequest(salah, fmt.Sprintf("/self/time-off/%s", requested.ID)).Delete()
+	as.Equal(http.StatusConflict, res.Code)
+
+	res = as.setupRequest(salah, "/self/time-off").Post(models.TimeOffRequest{StartsAt: start.AddDate(0, 1, 0), EndsAt: start.AddDate(0, 1, 1)})
+	as.Equal(http.StatusCreated, res.Code)
+	res.Bind(&requested)
+	res = as.setupRequest(as.getLoggedInUser("lewin"), fmt.Sprintf("/self/time-off/%s", requested.ID)).Delete()
+	as.Equal(http.StatusNotFound, res.Code)
+	res = as.setupRequest(salah, fmt.Sprintf("/self/time-off/%s", requested.ID)).Delete()
+	as.Equal(http.StatusNoContent, res.Code)
+
+	res = as.setupRequest(mane, "/time-off").Post(models.TimeOffRequest{DriverID: salah.ID, StartsAt: start.AddDate(0, 2, 0), EndsAt: start.AddDate(0, 2, 1)})
+	as.Equal(http.StatusCreated, res.Code)
+	res.Bind(&approved)
+	as.Equal(models.TimeOffRequestStatusApproved.String(), approved.Status)
+	res = as.setupRequest(salah, "/self/time-off").Get()
+	as.Equal(http.StatusOK, res.Code)
+	res.Bind(&timeOff)
+	as.Len(timeOff, 2)
+}
+
+func (as *ActionSuite) Test_ShipmentsDriverAvailability() {
+	as.LoadFixture("Tenant bootstrap")
+	mockFirebase.EXPECT().SendAll(gomock.Any(), gomock.Any()).AnyTimes()
+	mane := as.getLoggedInUser("mane")
+	salah := as.getLoggedInUser("salah")
+	efaLiv := as.getCustomer("EFA Liv")
+	order := as.createOrder("ord1", models.OrderStatusOpen, mane.TenantID, mane.ID, efaLiv.ID)
+	var monday = time.Date(2026, 11, 2, 0, 0, 0, 0, time.UTC)
+	as.createDriverShift(mane, models.DriverShift{DriverID: salah.ID, Weekday: int(time.Monday), StartTime: "08:00", EndTime: "16:00", Capacity: nulls.NewInt(1)})
+	res := as.setupRequest(mane, "/time-off").Post(models.TimeOffRequest{DriverID: salah.ID, StartsAt: monday.AddDate(0, 0, 7), EndsAt: monday.AddDate(0, 0, 8)})
+	as.Equal(http.StatusCreated, res.Code)
+
+	var newShipment = func(serialNumber string, at time.Time) models.Shipment {
+		return models.Shipment{SerialNumber: serialNumber, Type: models.ShipmentTypeInbound.String(), OrderID: nulls.NewUUID(order.ID), DriverID: nulls.NewUUID(salah.ID), ReservationTime: nulls.NewTime(at)}
+	}
+	var tests = []struct {
+		name         string
+		route        string
+		shipment     models.Shipment
+		responseCode int
+	}{
+		{"outside of the shifts", "/shipments", newShipment("s1", monday.Add(18*time.Hour)), http.StatusConflict},
+		{"on time off", "/shipments", newShipment("s2", monday.AddDate(0, 0, 7).Add(10*time.Hour)), http.StatusConflict},
+		{"during a shift", "/shipments", newShipment("s3", monday.Add(10*time.Hour)), http.StatusCreated},
+		{"shift at capacity", "/shipments", newShipment("s4", monday.Add(12*time.Hour)), http.StatusConflict},
+		{"override", "/shipments?override_availability=true", newShipment("s5", monday.Add(18*time.Hour)), http.StatusCreated},
+	}
+	for _, test := range tests {
+		as.T().Run(test.name, func(t *testing.T) {
+			res := as.setupRequest(mane, test.route).Post(test.shipment)
+			as.Equal(test.responseCode, res.Code, res.Body.String())
+			if test.route != "/shipments" {
+				as.Contains(res.Header().Get("Warning"), models.ErrDriverUnavailable.Error())
+			}
+		})
+	}
+	shipment := as.createShipment(models.Shipment{SerialNumber: "s6", Status: models.ShipmentStatusUnassigned.String(), Type: models.ShipmentTypeInbound.String(), CreatedBy: mane.ID, TenantID: mane.TenantID, ReservationTime: nulls.NewTime(monday.Add(20 * time.Hour))}, order)
+	var update = *shipment
+	update.Status = models.ShipmentStatusAssigned.String()
+	update.DriverID = nulls.NewUUID(salah.ID)
+	res = as.setupRequest(mane, fmt.Sprintf("/shipments/%s", shipment.ID)).Put(update)
+	as.Equal(http.StatusConflict, res.Code)
+	// A driver without shifts is available
+	henderson := as.createUser("henderson", models.UserRoleDriver, "henderson@bigpanther.ca", mane.TenantID, nulls.UUID{})
+	update.DriverID = nulls.NewUUID(henderson.ID)
+	res = as.setupRequest(mane, fmt.Sprintf("/shipments/%s", shipment.ID)).Put(update)
+	as.Equal(http.StatusOK, res.Code)
+}
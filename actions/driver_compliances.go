@@ -0,0 +1,136 @@
+package actions
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/nulls"
+	"github.com/gobuffalo/pop/v6"
+)
+
+// Following naming logic is implemented in Buffalo:
+// Model: Singular (DriverCompliance)
+// DB Table: Plural (driver_compliances)
+// Resource: Plural (DriverCompliances)
+// Path: Plural (/driver-compliance)
+
+// driverCompliancesList gets all DriverCompliances. Param "driver_id" filters them.
+// This function is mapped to the path GET /driver-compliance
+func driverCompliancesList(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	driverCompliances := &models.DriverCompliances{}
+
+	// Paginate results. Params "page" and "per_page" control pagination.
+	// Default values are "page=1" and "per_page=20".
+	q := tx.PaginateFromParams(c.Params())
+
+	if driverID := c.Param("driver_id"); driverID != "" {
+		q = q.Where("driver_id = ?", driverID)
+	}
+	if err := q.Eager("Driver").Scope(restrictedScope(c)).Order("created_at DESC").All(driverCompliances); err != nil {
+		return err
+	}
+	return c.Render(http.StatusOK, r.JSON(driverCompliances))
+}
+
+// driverCompliancesShow gets the data for one DriverCompliance. This function is mapped to
+// the path GET /driver-compliance/{driver_compliance_id}
+func driverCompliancesShow(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	driverCompliance := &models.DriverCompliance{}
+	if err := tx.Eager("Driver").Scope(restrictedScope(c)).Find(driverCompliance, c.Param("driver_compliance_id")); err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	return c.Render(http.StatusOK, r.JSON(driverCompliance))
+}
+
+// driverCompliancesCreate adds a DriverCompliance to the DB, a driver has one at most.
+// This function is mapped to the path POST /driver-compliance
+func driverCompliancesCreate(c buffalo.Context) error {
+	var loggedInUser = loggedInUser(c)
+	driverCompliance := &models.DriverCompliance{}
+	if err := c.Bind(driverCompliance); err != nil {
+		c.Logger().Errorf("error binding driver compliance: %v\n", err)
+		return err
+	}
+	tx := c.Value("tx").(*pop.Connection)
+	driverCompliance.CreatedBy = loggedInUser.ID
+	driverCompliance.TenantID = loggedInUser.TenantID
+	if err := checkDriverUserID(c, tx, loggedInUser, nulls.NewUUID(driverCompliance.DriverID)); err != nil {
+		return c.Error(http.StatusBadRequest, err)
+	}
+	exists, err := tx.Where("driver_id = ?", driverCompliance.DriverID).Exists(&models.DriverCompliance{})
+	if err != nil {
+		return err
+	}
+	if exists {
+		return c.Error(http.StatusConflict, fmt.Errorf("driver %s already has a compliance record", driverCompliance.DriverID))
+	}
+	verrs, err := tx.ValidateAndCreate(driverCompliance)
+	if err != nil {
+		return err
+	}
+	if verrs.HasAny() {
+		return c.Render(http.StatusUnprocessableEntity, r.JSON(verrs))
+	}
+	return c.Render(http.StatusCreated, r.JSON(driverCompliance))
+}
+
+// driverCompliancesUpdate changes a DriverCompliance in the DB. Shipments already assigned are not checked again.
+// This function is mapped to the path PUT /driver-compliance/{driver_compliance_id}
+func driverCompliancesUpdate(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	driverCompliance := &models.DriverCompliance{}
+	if err := tx.Scope(restrictedScope(c)).Find(driverCompliance, c.Param("driver_compliance_id")); err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	newDriverCompliance := &models.DriverCompliance{}
+	if err := c.Bind(newDriverCompliance); err != nil {
+		c.Logger().Errorf("error binding driver compliance: %v\n", err)
+		return err
+	}
+	driverCompliance.UpdatedAt = time.Now().UTC()
+	driverCompliance.LicenceClass = newDriverCompliance.LicenceClass
+	driverCompliance.LicenceExpiry = newDriverCompliance.LicenceExpiry
+	driverCompliance.MedicalCertificateExpiry = newDriverCompliance.MedicalCertificateExpiry
+	driverCompliance.TwicExpiry = newDriverCompliance.TwicExpiry
+	driverCompliance.PortPassExpiry = newDriverCompliance.PortPassExpiry
+	verrs, err := tx.ValidateAndUpdate(driverCompliance)
+	if err != nil {
+		return err
+	}
+	if verrs.HasAny() {
+		return c.Render(http.StatusUnprocessableEntity, r.JSON(verrs))
+	}
+	return c.Render(http.StatusOK, r.JSON(driverCompliance))
+}
+
+// driverCompliancesDestroy deletes a DriverCompliance from the DB. This function is mapped
+// to the path DELETE /driver-compliance/{driver_compliance_id}
+func driverCompliancesDestroy(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	driverCompliance := &models.DriverCompliance{}
+	if err := tx.Scope(restrictedScope(c)).Find(driverCompliance, c.Param("driver_compliance_id")); err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	if err := tx.Destroy(driverCompliance); err != nil {
+		return err
+	}
+	c.Response().WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// selfComplianceShow gets the compliance record of the logged in driver. This function is mapped to the path
+// GET /self/compliance
+func selfComplianceShow(c buffalo.Context) error {
+	var loggedInUser = loggedInUser(c)
+	tx := c.Value("tx").(*pop.Connection)
+	driverCompliance := &models.DriverCompliance{}
+	if err := tx.Where("driver_id = ?", loggedInUser.ID).First(driverCompliance); err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	return c.Render(http.StatusOK, r.JSON(driverCompliance))
+}
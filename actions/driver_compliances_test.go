@@ -0,0 +1,138 @@
+package actions
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/nulls"
+	"github.com/golang/mock/gomock"
+)
+
+func (as *ActionSuite) createDriverCompliance(user *models.User, compliance models.DriverCompliance) *models.DriverCompliance {
+	res := as.setupRequest(user, "/driver-compliance").Post(compliance)
+	as.Equal(http.StatusCreated, res.Code, res.Body.String())
+	var created = &models.DriverCompliance{}
+	res.Bind(created)
+	return created
+}
+
+func (as *ActionSuite) Test_DriverCompliances() {
+	as.LoadFixture("Tenant bootstrap")
+	mane := as.getLoggedInUser("mane")
+	salah := as.getLoggedInUser("salah")
+	lewin := as.getLoggedInUser("lewin")
+	nike := as.getLoggedInUser("nike")
+	expiry := nulls.NewTime(time.Now().UTC().AddDate(1, 0, 0))
+	var tests = []struct {
+		username     string
+		compliance   models.DriverCompliance
+		responseCode int
+	}{
+		{"salah", models.DriverCompliance{DriverID: salah.ID, PortPassExpiry: expiry}, http.StatusNotFound},
+		{"nike", models.DriverCompliance{DriverID: salah.ID, PortPassExpiry: expiry}, http.StatusNotFound},
+		{"mane", models.DriverCompliance{DriverID: nike.ID, PortPassExpiry: expiry}, http.StatusBadRequest},
+		{"mane", models.DriverCompliance{DriverID: lewin.ID, PortPassExpiry: expiry}, http.StatusBadRequest},
+		{"mane", models.DriverCompliance{DriverID: salah.ID, LicenceExpiry: expiry}, http.StatusUnprocessableEntity},
+		{"mane", models.DriverCompliance{DriverID: salah.ID, LicenceClass: nulls.NewString("1"), LicenceExpiry: expiry, PortPassExpiry: expiry}, http.StatusCreated},
+		{"firmino", models.DriverCompliance{DriverID: salah.ID, PortPassExpiry: expiry}, http.StatusConflict},
+		{"rodriguez", models.DriverCompliance{DriverID: lewin.ID, TwicExpiry: expiry}, http.StatusCreated},
+	}
+	for i, test := range tests {
+		as.T().Run(fmt.Sprint(i), func(t *testing.T) {
+			res := as.setupRequest(as.getLoggedInUser(test.username), "/driver-compliance").Post(test.compliance)
+			as.Equal(test.responseCode, res.Code, res.Body.String())
+		})
+	}
+	res := as.setupRequest(mane, "/driver-compliance").Get()
+	as.Equal(http.StatusOK, res.Code)
+	var compliances = models.DriverCompliances{}
+	res.Bind(&compliances)
+	as.Equal(1, len(compliances))
+	compliance := compliances[0]
+	as.Equal("salah", compliance.Driver.Name)
+
+	res = as.setupRequest(salah, "/self/compliance").Get()
+	as.Equal(http.StatusOK, res.Code)
+	res = as.setupRequest(mane, "/self/compliance").Get()
+	as.Equal(http.StatusNotFound, res.Code)
+
+	compliance.TwicExpiry = expiry
+	res = as.setupRequest(as.getLoggedInUser("rodriguez"), fmt.Sprintf("/driver-compliance/%s", compliance.ID)).Put(compliance)
+	as.Equal(http.StatusNotFound, res.Code)
+	res = as.setupRequest(mane, fmt.Sprintf("/driver-compliance/%s", compliance.ID)).Put(compliance)
+	as.Equal(http.StatusOK, res.Code, res.Body.String())
+	res = as.setupRequest(mane, fmt.Sprintf("/driver-compliance/%s", compliance.ID)).Get()
+	as.Equal(http.StatusOK, res.Code)
+	var updated = models.DriverCompliance{}
+	res.Bind(&updated)
+	as.True(updated.TwicExpiry.Valid)
+
+	res = as.setupRequest(mane, fmt.Sprintf("/driver-compliance/%s", compliance.ID)).Delete()
+	as.Equal(http.StatusNoContent, res.Code)
+	res = as.setupRequest(salah, "/self/compliance").Get()
+	as.Equal(http.StatusNotFound, res.Code)
+}
+
+func (as *ActionSuite) Test_ShipmentsDriverCompliance() {
+	as.LoadFixture("Tenant bootstrap")
+	mockFirebase.EXPECT().SendAll(gomock.Any(), gomock.Any()).AnyTimes()
+	mane := as.getLoggedInUser("mane")
+	salah := as.getLoggedInUser("salah")
+	efaLiv := as.getCustomer("EFA Liv")
+	order := as.createOrder("twic", models.OrderStatusOpen, mane.TenantID, mane.ID, efaLiv.ID)
+	terminal := as.createTerminal("Deltaport", models.TerminalTypePort, mane.TenantID, mane.ID)
+	terminal.RequiredCredentials = models.DriverCredentials{models.DriverCredentialPortPass.String()}
+	res := as.setupRequest(mane, fmt.Sprintf("/terminals/%s", terminal.ID)).Put(terminal)
+	as.Equal(http.StatusOK, res.Code, res.Body.String())
+	as.Nil(as.DB.Reload(terminal))
+	as.Equal(models.DriverCredentials{"PortPass"}, terminal.RequiredCredentials)
+	yard := as.createTerminal("Yard", models.TerminalTypeYard, mane.TenantID, mane.ID)
+	reservation := time.Now().UTC().AddDate(0, 0, 3)
+
+	var newShipment = func(serial string, terminalID nulls.UUID, driverID nulls.UUID) models.Shipment {
+		return models.Shipment{SerialNumber: serial, Type: models.ShipmentTypeInbound.String(), OrderID: nulls.NewUUID(order.ID),
+			TerminalID: terminalID, DriverID: driverID, ReservationTime: nulls.NewTime(reservation)}
+	}
+	res = as.setupRequest(mane, "/shipments").Post(newShipment("no record", nulls.NewUUID(terminal.ID), nulls.NewUUID(salah.ID)))
+	as.Equal(http.StatusConflict, res.Code, res.Body.String())
+	as.Contains(res.Body.String(), "No PortPass on file")
+	res = as.setupRequest(mane, "/shipments").Post(newShipment("yard", nulls.NewUUID(yard.ID), nulls.NewUUID(salah.ID)))
+	as.Equal(http.StatusCreated, res.Code, res.Body.String())
+	var atYard = &models.Shipment{}
+	res.Bind(atYard)
+
+	// The port pass expires before the reservation
+	compliance := as.createDriverCompliance(mane, models.DriverCompliance{DriverID: salah.ID, PortPassExpiry: nulls.NewTime(time.Now().UTC().AddDate(0, 0, 1))})
+	res = as.setupRequest(mane, "/shipments").Post(newShipment("expired", nulls.NewUUID(terminal.ID), nulls.NewUUID(salah.ID)))
+	as.Equal(http.StatusConflict, res.Code, res.Body.String())
+	as.Contains(res.Body.String(), "PortPass expired on")
+	atYard.TerminalID = nulls.NewUUID(terminal.ID)
+	res = as.setupRequest(mane, fmt.Sprintf("/shipments/%s", atYard.ID)).Put(atYard)
+	as.Equal(http.StatusConflict, res.Code, res.Body.String())
+
+	// Auto assign leaves the shipment for later
+	unassigned := newShipment("unassigned", nulls.NewUUID(terminal.ID), nulls.UUID{})
+	unassigned.ReservationTime = nulls.NewTime(reservation.Add(8 * time.Hour))
+	res = as.setupRequest(mane, "/shipments").Post(unassigned)
+	as.Equal(http.StatusCreated, res.Code, res.Body.String())
+	res = as.setupRequest(mane, "/shipments/auto-assign").Post(autoAssignRequest{DryRun: true})
+	as.Equal(http.StatusOK, res.Code)
+	var result = autoAssignResult{}
+	res.Bind(&result)
+	as.Equal(0, len(result.Assignments))
+	as.Equal(1, len(result.Unassigned))
+
+	compliance.PortPassExpiry = nulls.NewTime(time.Now().UTC().AddDate(1, 0, 0))
+	res = as.setupRequest(mane, fmt.Sprintf("/driver-compliance/%s", compliance.ID)).Put(compliance)
+	as.Equal(http.StatusOK, res.Code, res.Body.String())
+	res = as.setupRequest(mane, fmt.Sprintf("/shipments/%s", atYard.ID)).Put(atYard)
+	as.Equal(http.StatusOK, res.Code, res.Body.String())
+	res = as.setupRequest(mane, "/shipments/auto-assign").Post(autoAssignRequest{DryRun: true})
+	as.Equal(http.StatusOK, res.Code)
+	res.Bind(&result)
+	as.Equal(1, len(result.Assignments))
+	as.Equal(salah.ID, result.Assignments[0].Candidate.Driver.ID)
+}
@@ -0,0 +1,175 @@
+package actions
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/buffalo/worker"
+	"github.com/gobuffalo/envy"
+	"github.com/gobuffalo/nulls"
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gofrs/uuid"
+)
+
+// Following naming logic is implemented in Buffalo:
+// Model: Singular (DriverLocation)
+// DB Table: Plural (driver_locations)
+// Resource: Plural (DriverLocations)
+// Path: Plural (/self/locations)
+
+const driverLocationsPruneJob = "driverLocationsPrune"
+
+// maxDriverLocationBatch is how many GPS points a driver can send at once
+const maxDriverLocationBatch = 500
+
+// Number of GPS points in the trail of a shipment, by default and at most
+const (
+	defaultTrackLimit = 500
+	maxTrackLimit     = 5000
+)
+
+type selfLocationsResult struct {
+	Accepted    int         `json:"accepted"`
+	ShipmentIDs []uuid.UUID `json:"shipment_ids"`
+}
+
+// selfLocationsCreate stores a batch of GPS points of the logged in driver, linked to the shipments the driver is working on.
+// Crossing the geofence of the terminal of a shipment changes its status or suggests the change,
+// the ETA of the shipments in transit is predicted again.
+// This function is mapped to the path POST /self/locations
+func selfLocationsCreate(c buffalo.Context) error {
+	var loggedInUser = loggedInUser(c)
+	points := models.DriverLocations{}
+	if err := c.Bind(&points); err != nil {
+		c.Logger().Errorf("error binding driver locations: %v\n", err)
+		return err
+	}
+	if len(points) == 0 {
+		return c.Error(http.StatusBadRequest, errors.New("no locations"))
+	}
+	if len(points) > maxDriverLocationBatch {
+		return c.Error(http.StatusBadRequest, fmt.Errorf("at most %d locations can be sent at once", maxDriverLocationBatch))
+	}
+	tx := c.Value("tx").(*pop.Connection)
+	shipments := models.Shipments{}
+	if err := tx.Eager("Terminal").Where("tenant_id = ?", loggedInUser.TenantID).Where("driver_id = ?", loggedInUser.ID).
+		Where("status IN (?)", models.ShipmentTrackedStatuses()...).All(&shipments); err != nil {
+		return err
+	}
+	var shipmentIDs = []nulls.UUID{{}}
+	if len(shipments) > 0 {
+		shipmentIDs = make([]nulls.UUID, len(shipments))
+		for i, s := range shipments {
+			shipmentIDs[i] = nulls.NewUUID(s.ID)
+		}
+	}
+	locations := models.DriverLocations{}
+	for _, p := range points {
+		for _, shipmentID := range shipmentIDs {
+			locations = append(locations, models.DriverLocation{
+				TenantID:   loggedInUser.TenantID,
+				DriverID:   loggedInUser.ID,
+				ShipmentID: shipmentID,
+				RecordedAt: p.RecordedAt.UTC(),
+				Latitude:   p.Latitude,
+				Longitude:  p.Longitude,
+				Accuracy:   p.Accuracy,
+				Speed:      p.Speed,
+				Heading:    p.Heading,
+			})
+		}
+	}
+	verrs, err := tx.ValidateAndCreate(&locations)
+	if err != nil {
+		return err
+	}
+	if verrs.HasAny() {
+		return c.Render(http.StatusUnprocessableEntity, r.JSON(verrs))
+	}
+	if err := applyGeofenceTransitions(c, tx, loggedInUser, shipments, locations); err != nil {
+		return err
+	}
+	if err := updateShipmentEtas(tx, shipments, locations); err != nil {
+		return err
+	}
+	var result = selfLocationsResult{Accepted: len(points), ShipmentIDs: []uuid.UUID{}}
+	for _, s := range shipments {
+		result.ShipmentIDs = append(result.ShipmentIDs, s.ID)
+	}
+	return c.Render(http.StatusCreated, r.JSON(result))
+}
+
+// shipmentsTrack gets the latest position of a Shipment and the trail of GPS points of its driver.
+// Customers can only track their shipments in transit. Params "since" (RFC 3339) and "limit" narrow the trail.
+// This function is mapped to the path GET /shipments/{shipment_id}/track
+func shipmentsTrack(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	var loggedInUser = loggedInUser(c)
+	shipment := &models.Shipment{}
+	q := tx.Scope(restrictedScope(c))
+	if loggedInUser.IsDriver() {
+		q = q.Where("driver_id = ?", loggedInUser.ID)
+	}
+	if loggedInUser.IsCustomer() {
+		q = q.Where("customer_id = ?", loggedInUser.CustomerID).Where("status = ?", models.ShipmentStatusInTransit.String())
+	}
+	if err := q.Find(shipment, c.Param("shipment_id")); err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	var since time.Time
+	if s := c.Param("since"); s != "" {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return c.Error(http.StatusBadRequest, fmt.Errorf("invalid since %q", s))
+		}
+		since = t
+	}
+	var limit = defaultTrackLimit
+	if l := c.Param("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n <= 0 || n > maxTrackLimit {
+			return c.Error(http.StatusBadRequest, fmt.Errorf("invalid limit %q", l))
+		}
+		limit = n
+	}
+	track, err := models.LoadShipmentTrack(tx, shipment, since, limit)
+	if err != nil {
+		return err
+	}
+	return c.Render(http.StatusOK, r.JSON(track))
+}
+
+// driverLocationsPruneInterval reads how often the GPS points past the retention period of their tenant are deleted
+func driverLocationsPruneInterval() (time.Duration, error) {
+	interval, err := time.ParseDuration(envy.Get("DRIVER_LOCATIONS_PRUNE_INTERVAL", "6h"))
+	if err != nil || interval <= 0 {
+		return 0, errors.New("invalid DRIVER_LOCATIONS_PRUNE_INTERVAL")
+	}
+	return interval, nil
+}
+
+func scheduleDriverLocationsPrune(interval time.Duration) {
+	if err := app.Worker.PerformIn(worker.Job{Queue: "default", Handler: driverLocationsPruneJob}, interval); err != nil {
+		app.Logger.Errorf("error scheduling driver locations prune: %v", err)
+	}
+}
+
+// driverLocationsPrune deletes the GPS points past the retention period of their tenant, and schedules the next run
+func driverLocationsPrune(args worker.Args) error {
+	interval, err := driverLocationsPruneInterval()
+	if err != nil {
+		return err
+	}
+	defer scheduleDriverLocationsPrune(interval)
+	count, err := models.PruneDriverLocations(models.DB, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	app.Logger.Infof("pruned %d driver locations", count)
+	return nil
+}
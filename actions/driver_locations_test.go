@@ -0,0 +1,137 @@
+package actions
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/nulls"
+)
+
+func (as *ActionSuite) Test_SelfLocationsCreate() {
+	as.LoadFixture("Tenant bootstrap")
+	salah := as.getLoggedInUser("salah")
+	efaLiv := as.getCustomer("EFA Liv")
+	order := as.createOrder("ord1", models.OrderStatusAccepted, salah.TenantID, salah.ID, efaLiv.ID)
+	accepted := as.createShipment(models.Shipment{SerialNumber: "accepted", Status: models.ShipmentStatusAccepted.String(), Type: models.ShipmentTypeInbound.String(),
+		CreatedBy: salah.ID, TenantID: salah.TenantID, DriverID: nulls.NewUUID(salah.ID)}, order)
+	as.createShipment(models.Shipment{SerialNumber: "delivered", Status: models.ShipmentStatusDelivered.String(), Type: models.ShipmentTypeInbound.String(),
+		CreatedBy: salah.ID, TenantID: salah.TenantID, DriverID: nulls.NewUUID(salah.ID)}, order)
+	var now = time.Now().UTC()
+	var points = models.DriverLocations{
+		{RecordedAt: now.Add(-2 * time.Minute), Latitude: 49.2827, Longitude: -123.1207},
+		{RecordedAt: now.Add(-time.Minute), Latitude: 49.2, Longitude: -123.1, Speed: nulls.NewFloat64(15)},
+	}
+	var tests = []struct {
+		username     string
+		points       models.DriverLocations
+		responseCode int
+	}{
+		{"salah", points, http.StatusCreated},
+		{"salah", models.DriverLocations{}, http.StatusBadRequest},
+		{"salah", make(models.DriverLocations, maxDriverLocationBatch+1), http.StatusBadRequest},
+		{"salah", models.DriverLocations{{RecordedAt: now, Latitude: 91, Longitude: -123.1}}, http.StatusUnprocessableEntity},
+		{"lewin", points, http.StatusCreated},
+		{"mane", points, http.StatusNotFound},
+		{"nike", points, http.StatusNotFound},
+		{"klopp", points, http.StatusNotFound},
+	}
+	for _, test := range tests {
+		as.T().Run(fmt.Sprintf("%s %d", test.username, len(test.points)), func(t *testing.T) {
+			res := as.setupRequest(as.getLoggedInUser(test.username), "/self/locations").Post(test.points)
+			as.Equal(test.responseCode, res.Code, res.Body.String())
+		})
+	}
+	count, err := as.DB.Where("shipment_id = ?", accepted.ID).Count(&models.DriverLocation{})
+	as.Nil(err)
+	as.Equal(len(points), count)
+	// The points of a driver without an active shipment are kept without a shipment
+	lewin := as.getLoggedInUser("lewin")
+	count, err = as.DB.Where("driver_id = ?", lewin.ID).Where("shipment_id IS NULL").Count(&models.DriverLocation{})
+	as.Nil(err)
+	as.Equal(len(points), count)
+}
+
+func (as *ActionSuite) Test_ShipmentsTrack() {
+	as.LoadFixture("Tenant bootstrap")
+	salah := as.getLoggedInUser("salah")
+	efaLiv := as.getCustomer("EFA Liv")
+	order := as.createOrder("ord1", models.OrderStatusAccepted, salah.TenantID, salah.ID, efaLiv.ID)
+	shipment := as.createShipment(models.Shipment{SerialNumber: "loaded", Status: models.ShipmentStatusLoaded.String(), Type: models.ShipmentTypeInbound.String(),
+		CreatedBy: salah.ID, TenantID: salah.TenantID, DriverID: nulls.NewUUID(salah.ID)}, order)
+	var now = time.Now().UTC()
+	var points = models.DriverLocations{
+		{RecordedAt: now.Add(-3 * time.Minute), Latitude: 49.0, Longitude: -123.1},
+		{RecordedAt: now.Add(-2 * time.Minute), Latitude: 49.1, Longitude: -123.1},
+		{RecordedAt: now.Add(-time.Minute), Latitude: 49.2, Longitude: -123.1},
+	}
+	res := as.setupRequest(salah, "/self/locations").Post(points)
+	as.Equal(http.StatusCreated, res.Code, res.Body.String())
+
+	var tests = []struct {
+		username     string
+		query        string
+		responseCode int
+		trail        int
+	}{
+		{"mane", "", http.StatusOK, 3},
+		{"salah", "", http.StatusOK, 3},
+		{"salah", "?limit=2", http.StatusOK, 2},
+		{"salah", "?since=" + now.Add(-90*time.Second).Format(time.RFC3339), http.StatusOK, 1},
+		{"salah", "?limit=0", http.StatusBadRequest, 0},
+		{"salah", "?since=yesterday", http.StatusBadRequest, 0},
+		{"nike", "", http.StatusNotFound, 0},
+		{"lewin", "", http.StatusNotFound, 0},
+		{"rodriguez", "", http.StatusNotFound, 0},
+	}
+	for _, test := range tests {
+		as.T().Run(test.username+test.query, func(t *testing.T) {
+			res := as.setupRequest(as.getLoggedInUser(test.username), fmt.Sprintf("/shipments/%s/track%s", shipment.ID, test.query)).Get()
+			as.Equal(test.responseCode, res.Code, res.Body.String())
+			if res.Code == http.StatusOK {
+				var track = models.ShipmentTrack{}
+				res.Bind(&track)
+				as.Len(track.Trail, test.trail)
+				as.Equal(49.2, track.Latest.Latitude)
+				as.Equal(track.Latest.ID, track.Trail[len(track.Trail)-1].ID)
+			}
+		})
+	}
+	// Customers track their shipments in transit
+	shipment.Status = models.ShipmentStatusInTransit.String()
+	as.Nil(as.DB.Update(shipment))
+	res = as.setupRequest(as.getLoggedInUser("nike"), fmt.Sprintf("/shipments/%s/track", shipment.ID)).Get()
+	as.Equal(http.StatusOK, res.Code, res.Body.String())
+}
+
+func (as *ActionSuite) Test_DriverLocationsPrune() {
+	as.LoadFixture("Tenant bootstrap")
+	salah := as.getLoggedInUser("salah")
+	lewin := as.getLoggedInUser("lewin")
+	var now = time.Now().UTC()
+	for _, user := range []*models.User{salah, lewin} {
+		for _, age := range []int{1, 10, 40} {
+			location := &models.DriverLocation{TenantID: user.TenantID, DriverID: user.ID, RecordedAt: now.AddDate(0, 0, -age), Latitude: 49.2, Longitude: -123.1}
+			as.Nil(as.DB.Create(location))
+		}
+	}
+	res := as.setupRequest(as.getLoggedInUser("klopp"), fmt.Sprintf("/tenants/%s", salah.TenantID)).Get()
+	var tenant = models.Tenant{}
+	res.Bind(&tenant)
+	tenant.LocationRetentionDays = nulls.NewInt(7)
+	res = as.setupRequest(as.getLoggedInUser("klopp"), fmt.Sprintf("/tenants/%s", salah.TenantID)).Put(tenant)
+	as.Equal(http.StatusOK, res.Code, res.Body.String())
+
+	count, err := models.PruneDriverLocations(as.DB, now)
+	as.Nil(err)
+	// Liverpool keeps a week, Everton the default
+	as.Equal(3, count)
+	count, err = as.DB.Where("driver_id = ?", salah.ID).Count(&models.DriverLocation{})
+	as.Nil(err)
+	as.Equal(1, count)
+	count, err = as.DB.Where("driver_id = ?", lewin.ID).Count(&models.DriverLocation{})
+	as.Nil(err)
+	as.Equal(2, count)
+}
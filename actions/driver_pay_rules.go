@@ -0,0 +1,131 @@
+package actions
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/pop/v6"
+)
+
+// Following naming logic is implemented in Buffalo:
+// Model: Singular (DriverPayRule)
+// DB Table: Plural (driver_pay_rules)
+// Resource: Plural (DriverPayRules)
+// Path: Plural (/driver-pay-rules)
+
+var errDriverPayRuleExists = errors.New("a pay rule already exists for this driver")
+
+// driverPayRulesList gets all DriverPayRules. This function is mapped to the path
+// GET /driver-pay-rules
+func driverPayRulesList(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	driverPayRules := &models.DriverPayRules{}
+
+	// Paginate results. Params "page" and "per_page" control pagination.
+	// Default values are "page=1" and "per_page=20".
+	q := tx.PaginateFromParams(c.Params())
+
+	if driverID := c.Param("driver_id"); driverID != "" {
+		q = q.Where("driver_id = ?", driverID)
+	}
+	if err := q.Scope(restrictedScope(c)).Order("driver_id NULLS FIRST").All(driverPayRules); err != nil {
+		return err
+	}
+	return c.Render(http.StatusOK, r.JSON(driverPayRules))
+}
+
+// driverPayRulesShow gets the data for one DriverPayRule. This function is mapped to
+// the path GET /driver-pay-rules/{driver_pay_rule_id}
+func driverPayRulesShow(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	driverPayRule := &models.DriverPayRule{}
+	if err := tx.Scope(restrictedScope(c)).Find(driverPayRule, c.Param("driver_pay_rule_id")); err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	return c.Render(http.StatusOK, r.JSON(driverPayRule))
+}
+
+// driverPayRulesCreate adds a DriverPayRule to the DB. A rule without a driver is the default of the
+// tenant. This function is mapped to the path POST /driver-pay-rules
+func driverPayRulesCreate(c buffalo.Context) error {
+	var loggedInUser = loggedInUser(c)
+	driverPayRule := &models.DriverPayRule{}
+	if err := c.Bind(driverPayRule); err != nil {
+		c.Logger().Errorf("error binding driver pay rule: %v\n", err)
+		return err
+	}
+	tx := c.Value("tx").(*pop.Connection)
+	driverPayRule.CreatedBy = loggedInUser.ID
+	driverPayRule.TenantID = loggedInUser.TenantID
+	if err := checkDriverUserID(c, tx, loggedInUser, driverPayRule.DriverID); err != nil {
+		return c.Error(http.StatusBadRequest, err)
+	}
+	if driverPayRule.Currency == "" {
+		currency, err := tenantCurrency(tx, driverPayRule.TenantID)
+		if err != nil {
+			return err
+		}
+		driverPayRule.Currency = currency
+	}
+	if exists, err := driverPayRule.Exists(tx); err != nil {
+		return err
+	} else if exists {
+		return c.Error(http.StatusConflict, errDriverPayRuleExists)
+	}
+	verrs, err := tx.ValidateAndCreate(driverPayRule)
+	if err != nil {
+		return err
+	}
+	if verrs.HasAny() {
+		return c.Render(http.StatusUnprocessableEntity, r.JSON(verrs))
+	}
+	return c.Render(http.StatusCreated, r.JSON(driverPayRule))
+}
+
+// driverPayRulesUpdate changes a DriverPayRule in the DB. Finalized settlements keep the amounts
+// they were computed with. This function is mapped to the path PUT /driver-pay-rules/{driver_pay_rule_id}
+func driverPayRulesUpdate(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	driverPayRule := &models.DriverPayRule{}
+	if err := tx.Scope(restrictedScope(c)).Find(driverPayRule, c.Param("driver_pay_rule_id")); err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	newDriverPayRule := &models.DriverPayRule{}
+	if err := c.Bind(newDriverPayRule); err != nil {
+		c.Logger().Errorf("error binding driver pay rule: %v\n", err)
+		return err
+	}
+	driverPayRule.UpdatedAt = time.Now().UTC()
+	driverPayRule.Type = newDriverPayRule.Type
+	driverPayRule.Rate = newDriverPayRule.Rate
+	driverPayRule.Percent = newDriverPayRule.Percent
+	if newDriverPayRule.Currency != "" {
+		driverPayRule.Currency = newDriverPayRule.Currency
+	}
+	verrs, err := tx.ValidateAndUpdate(driverPayRule)
+	if err != nil {
+		return err
+	}
+	if verrs.HasAny() {
+		return c.Render(http.StatusUnprocessableEntity, r.JSON(verrs))
+	}
+	return c.Render(http.StatusOK, r.JSON(driverPayRule))
+}
+
+// driverPayRulesDestroy deletes a DriverPayRule from the DB. This function is mapped
+// to the path DELETE /driver-pay-rules/{driver_pay_rule_id}
+func driverPayRulesDestroy(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	driverPayRule := &models.DriverPayRule{}
+	if err := tx.Scope(restrictedScope(c)).Find(driverPayRule, c.Param("driver_pay_rule_id")); err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	if err := tx.Destroy(driverPayRule); err != nil {
+		return err
+	}
+	c.Response().WriteHeader(http.StatusNoContent)
+	return nil
+}
@@ -0,0 +1,107 @@
+package actions
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/nulls"
+)
+
+func (as *ActionSuite) createDriverPayRule(user *models.User, rule models.DriverPayRule) *models.DriverPayRule {
+	res := as.setupRequest(user, "/driver-pay-rules").Post(rule)
+	as.Equal(http.StatusCreated, res.Code, res.Body.String())
+	var created = &models.DriverPayRule{}
+	res.Bind(created)
+	return created
+}
+
+func (as *ActionSuite) Test_DriverPayRulesCreate() {
+	as.LoadFixture("Tenant bootstrap")
+	var tests = []struct {
+		username     string
+		responseCode int
+	}{
+		{"firmino", http.StatusCreated},
+		{"mane", http.StatusConflict},
+		{"salah", http.StatusNotFound},
+		{"nike", http.StatusNotFound},
+		{"coutinho", http.StatusNotFound},
+	}
+	for _, test := range tests {
+		as.T().Run(test.username, func(t *testing.T) {
+			user := as.getLoggedInUser(test.username)
+			res := as.setupRequest(user, "/driver-pay-rules").Post(models.DriverPayRule{Type: models.DriverPayRuleTypePerMove.String(), Rate: 7500})
+			as.Equal(test.responseCode, res.Code)
+			if res.Code != http.StatusCreated {
+				return
+			}
+			var rule = models.DriverPayRule{}
+			res.Bind(&rule)
+			as.Equal(user.TenantID, rule.TenantID)
+			as.Equal(user.ID, rule.CreatedBy)
+			as.Equal("CAD", rule.Currency)
+			as.False(rule.DriverID.Valid)
+		})
+	}
+}
+
+func (as *ActionSuite) Test_DriverPayRulesCreateForDriver() {
+	as.LoadFixture("Tenant bootstrap")
+	mane := as.getLoggedInUser("mane")
+	salah := as.getLoggedInUser("salah")
+	lewin := as.getLoggedInUser("lewin")
+	nike := as.getLoggedInUser("nike")
+	as.createDriverPayRule(mane, models.DriverPayRule{Type: models.DriverPayRuleTypePerMove.String(), Rate: 7500})
+	var tests = []struct {
+		name         string
+		rule         models.DriverPayRule
+		responseCode int
+	}{
+		{"other tenant driver", models.DriverPayRule{DriverID: nulls.NewUUID(lewin.ID), Type: models.DriverPayRuleTypePerMove.String()}, http.StatusBadRequest},
+		{"not a driver", models.DriverPayRule{DriverID: nulls.NewUUID(nike.ID), Type: models.DriverPayRuleTypePerMove.String()}, http.StatusBadRequest},
+		{"invalid percent", models.DriverPayRule{DriverID: nulls.NewUUID(salah.ID), Type: models.DriverPayRuleTypePercentOfLinehaul.String(), Percent: 120}, http.StatusUnprocessableEntity},
+		{"driver", models.DriverPayRule{DriverID: nulls.NewUUID(salah.ID), Type: models.DriverPayRuleTypePercentOfLinehaul.String(), Percent: 25}, http.StatusCreated},
+		{"driver again", models.DriverPayRule{DriverID: nulls.NewUUID(salah.ID), Type: models.DriverPayRuleTypePerMile.String(), Rate: 150}, http.StatusConflict},
+	}
+	for _, test := range tests {
+		as.T().Run(test.name, func(t *testing.T) {
+			res := as.setupRequest(mane, "/driver-pay-rules").Post(test.rule)
+			as.Equal(test.responseCode, res.Code)
+		})
+	}
+	rule, err := models.FindDriverPayRule(as.DB, mane.TenantID, salah.ID)
+	as.Nil(err)
+	as.Equal(models.DriverPayRuleTypePercentOfLinehaul.String(), rule.Type)
+	rule, err = models.FindDriverPayRule(as.DB, mane.TenantID, mane.ID)
+	as.Nil(err)
+	as.Equal(models.DriverPayRuleTypePerMove.String(), rule.Type)
+}
+
+func (as *ActionSuite) Test_DriverPayRulesUpdateAndDestroy() {
+	as.LoadFixture("Tenant bootstrap")
+	mane := as.getLoggedInUser("mane")
+	rodriguez := as.getLoggedInUser("rodriguez")
+	rule := as.createDriverPayRule(mane, models.DriverPayRule{Type: models.DriverPayRuleTypePerMove.String(), Rate: 7500})
+
+	update := *rule
+	update.Type = models.DriverPayRuleTypePerMile.String()
+	update.Rate = 150
+	res := as.setupRequest(rodriguez, fmt.Sprintf("/driver-pay-rules/%s", rule.ID)).Put(update)
+	as.Equal(http.StatusNotFound, res.Code)
+	res = as.setupRequest(mane, fmt.Sprintf("/driver-pay-rules/%s", rule.ID)).Put(update)
+	as.Equal(http.StatusOK, res.Code)
+	var updated = models.DriverPayRule{}
+	as.Nil(as.DB.Find(&updated, rule.ID))
+	as.Equal(models.DriverPayRuleTypePerMile.String(), updated.Type)
+	as.Equal(150, updated.Rate)
+
+	res = as.setupRequest(rodriguez, fmt.Sprintf("/driver-pay-rules/%s", rule.ID)).Delete()
+	as.Equal(http.StatusNotFound, res.Code)
+	res = as.setupRequest(mane, fmt.Sprintf("/driver-pay-rules/%s", rule.ID)).Delete()
+	as.Equal(http.StatusNoContent, res.Code)
+	count, err := as.DB.Count(&models.DriverPayRules{})
+	as.Nil(err)
+	as.Equal(0, count)
+}
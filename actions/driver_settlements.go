@@ -0,0 +1,290 @@
+package actions
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bigpanther/trober/firebase"
+	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/nulls"
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gofrs/uuid"
+)
+
+// Following naming logic is implemented in Buffalo:
+// Model: Singular (DriverSettlement)
+// DB Table: Plural (driver_settlements)
+// Resource: Plural (DriverSettlements)
+// Path: Plural (/settlements)
+
+var errSettlementFinalized = errors.New("settlement has been finalized and cannot change")
+
+// driverSettlementsList gets all DriverSettlements. This function is mapped to the path
+// GET /settlements
+func driverSettlementsList(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	driverSettlements := &models.DriverSettlements{}
+
+	// Paginate results. Params "page" and "per_page" control pagination.
+	// Default values are "page=1" and "per_page=20".
+	q := tx.PaginateFromParams(c.Params())
+
+	if driverID := c.Param("driver_id"); driverID != "" {
+		q = q.Where("driver_id = ?", driverID)
+	}
+	if status := c.Param("status"); status != "" {
+		q = q.Where("status = ?", status)
+	}
+	if err := q.Scope(restrictedScope(c)).Order("period_start DESC").All(driverSettlements); err != nil {
+		return err
+	}
+	return c.Render(http.StatusOK, r.JSON(driverSettlements))
+}
+
+// driverSettlementsShow gets the data for one DriverSettlement with its lines. This function is mapped to
+// the path GET /settlements/{settlement_id}
+func driverSettlementsShow(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	driverSettlement := &models.DriverSettlement{}
+	if err := tx.Eager("Driver", "Lines").Scope(restrictedScope(c)).Find(driverSettlement, c.Param("settlement_id")); err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	return c.Render(http.StatusOK, r.JSON(driverSettlement))
+}
+
+type settlementRequest struct {
+	DriverID    uuid.UUID `json:"driver_id"`
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+}
+
+// driverSettlementsCreate computes a draft DriverSettlement from the shipments the driver delivered during
+// the pay period, which are not on another settlement. This function is mapped to the path POST /settlements
+func driverSettlementsCreate(c buffalo.Context) error {
+	var loggedInUser = loggedInUser(c)
+	req := &settlementRequest{}
+	if err := c.Bind(req); err != nil {
+		c.Logger().Errorf("error binding settlement: %v\n", err)
+		return err
+	}
+	tx := c.Value("tx").(*pop.Connection)
+	if err := checkDriverUserID(c, tx, loggedInUser, nulls.NewUUID(req.DriverID)); err != nil {
+		return c.Error(http.StatusBadRequest, err)
+	}
+	rule, err := models.FindDriverPayRule(tx, loggedInUser.TenantID, req.DriverID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Error(http.StatusBadRequest, errors.New("no pay rule for the driver"))
+		}
+		return err
+	}
+	shipments, err := models.UnsettledDeliveries(tx, loggedInUser.TenantID, req.DriverID, req.PeriodStart, req.PeriodEnd)
+	if err != nil {
+		return err
+	}
+	var lines = models.DriverSettlementLines{}
+	for i := range shipments {
+		linehaul, err := models.ShipmentLinehaul(tx, &shipments[i])
+		if err != nil {
+			return c.Error(http.StatusConflict, err)
+		}
+		line, err := rule.Earning(&shipments[i], linehaul)
+		if err != nil {
+			return c.Error(http.StatusConflict, err)
+		}
+		lines = append(lines, *line)
+	}
+	driverSettlement := models.NewDriverSettlement(rule.Currency)
+	driverSettlement.CreatedBy = loggedInUser.ID
+	driverSettlement.TenantID = loggedInUser.TenantID
+	driverSettlement.DriverID = req.DriverID
+	driverSettlement.PeriodStart = req.PeriodStart
+	driverSettlement.PeriodEnd = req.PeriodEnd
+	driverSettlement.Lines = lines
+	if err := driverSettlement.UpdateTotals(); err != nil {
+		return c.Error(http.StatusConflict, err)
+	}
+	verrs, err := tx.Eager("Lines").ValidateAndCreate(driverSettlement)
+	if err != nil {
+		return err
+	}
+	if verrs.HasAny() {
+		return c.Render(http.StatusUnprocessableEntity, r.JSON(verrs))
+	}
+	return c.Render(http.StatusCreated, r.JSON(driverSettlement))
+}
+
+// driverSettlementsDestroy deletes a draft DriverSettlement, its shipments can be paid on another one.
+// This function is mapped to the path DELETE /settlements/{settlement_id}
+func driverSettlementsDestroy(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	driverSettlement := &models.DriverSettlement{}
+	if err := tx.Scope(restrictedScope(c)).Find(driverSettlement, c.Param("settlement_id")); err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	if driverSettlement.IsFinalized() {
+		return c.Error(http.StatusConflict, errSettlementFinalized)
+	}
+	if err := tx.Destroy(driverSettlement); err != nil {
+		return err
+	}
+	c.Response().WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// driverSettlementLinesCreate adds a deduction or an advance to a draft DriverSettlement. This function is
+// mapped to the path POST /settlements/{settlement_id}/lines
+func driverSettlementLinesCreate(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	driverSettlement := &models.DriverSettlement{}
+	if err := tx.Eager("Lines").Scope(restrictedScope(c)).Find(driverSettlement, c.Param("settlement_id")); err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	if driverSettlement.IsFinalized() {
+		return c.Error(http.StatusConflict, errSettlementFinalized)
+	}
+	line := &models.DriverSettlementLine{}
+	if err := c.Bind(line); err != nil {
+		c.Logger().Errorf("error binding settlement line: %v\n", err)
+		return err
+	}
+	line.ID = uuid.Nil
+	line.DriverSettlementID = driverSettlement.ID
+	line.ShipmentID = nulls.UUID{}
+	if !line.IsAdjustment() {
+		return c.Error(http.StatusBadRequest, errors.New("only deductions and advances can be added to a settlement"))
+	}
+	if line.Amount.Currency == "" {
+		line.Amount.Currency = driverSettlement.Currency()
+	}
+	if line.Amount.Currency != driverSettlement.Currency() {
+		return c.Error(http.StatusConflict, fmt.Errorf("%w: settlement is paid in %s", models.ErrCurrencyMismatch, driverSettlement.Currency()))
+	}
+	verrs, err := tx.ValidateAndCreate(line)
+	if err != nil {
+		return err
+	}
+	if verrs.HasAny() {
+		return c.Render(http.StatusUnprocessableEntity, r.JSON(verrs))
+	}
+	driverSettlement.Lines = append(driverSettlement.Lines, *line)
+	if err := updateSettlementTotals(tx, driverSettlement); err != nil {
+		return err
+	}
+	return c.Render(http.StatusCreated, r.JSON(driverSettlement))
+}
+
+// driverSettlementLinesDestroy removes a deduction or an advance from a draft DriverSettlement. This function is
+// mapped to the path DELETE /settlements/{settlement_id}/lines/{line_id}
+func driverSettlementLinesDestroy(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	driverSettlement := &models.DriverSettlement{}
+	if err := tx.Eager("Lines").Scope(restrictedScope(c)).Find(driverSettlement, c.Param("settlement_id")); err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	if driverSettlement.IsFinalized() {
+		return c.Error(http.StatusConflict, errSettlementFinalized)
+	}
+	var lines = models.DriverSettlementLines{}
+	var line *models.DriverSettlementLine
+	for i := range driverSettlement.Lines {
+		if driverSettlement.Lines[i].ID.String() == c.Param("line_id") {
+			line = &driverSettlement.Lines[i]
+			continue
+		}
+		lines = append(lines, driverSettlement.Lines[i])
+	}
+	if line == nil {
+		return c.Error(http.StatusNotFound, errors.New("settlement line not found"))
+	}
+	if !line.IsAdjustment() {
+		return c.Error(http.StatusBadRequest, errors.New("only deductions and advances can be removed from a settlement"))
+	}
+	if err := tx.Destroy(line); err != nil {
+		return err
+	}
+	driverSettlement.Lines = lines
+	if err := updateSettlementTotals(tx, driverSettlement); err != nil {
+		return err
+	}
+	c.Response().WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// driverSettlementsFinalize locks a DriverSettlement and makes it visible to the driver. This function is
+// mapped to the path POST /settlements/{settlement_id}/finalize
+func driverSettlementsFinalize(c buffalo.Context) error {
+	var loggedInUser = loggedInUser(c)
+	tx := c.Value("tx").(*pop.Connection)
+	driverSettlement := &models.DriverSettlement{}
+	if err := tx.Eager("Lines").Scope(restrictedScope(c)).Find(driverSettlement, c.Param("settlement_id")); err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	if driverSettlement.IsFinalized() {
+		return c.Error(http.StatusConflict, errSettlementFinalized)
+	}
+	driverSettlement.Status = models.DriverSettlementStatusFinalized.String()
+	driverSettlement.FinalizedAt = nulls.NewTime(time.Now().UTC())
+	driverSettlement.FinalizedBy = nulls.NewUUID(loggedInUser.ID)
+	if err := updateSettlementTotals(tx, driverSettlement); err != nil {
+		return err
+	}
+	sendNotificationsAsync(
+		models.NotificationEventPayStatement,
+		[]string{firebase.GetDriverTopic(driverSettlement.TenantID.String(), driverSettlement.DriverID.String())},
+		fmt.Sprintf("Your pay statement is ready - %s", driverSettlement.PeriodStart.Format(reportDateFormat)),
+		fmt.Sprintf("Net pay %s", driverSettlement.NetPay),
+		map[string]string{
+			"settlement.id": driverSettlement.ID.String(),
+		},
+	)
+	return c.Render(http.StatusOK, r.JSON(driverSettlement))
+}
+
+// selfSettlementsList gets the finalized settlements of the logged in driver. This function is mapped to the path
+// GET /self/settlements
+func selfSettlementsList(c buffalo.Context) error {
+	var loggedInUser = loggedInUser(c)
+	tx := c.Value("tx").(*pop.Connection)
+	driverSettlements := &models.DriverSettlements{}
+	q := tx.PaginateFromParams(c.Params()).Where("driver_id = ?", loggedInUser.ID).
+		Where("status = ?", models.DriverSettlementStatusFinalized.String())
+	if err := q.Order("period_start DESC").All(driverSettlements); err != nil {
+		return err
+	}
+	return c.Render(http.StatusOK, r.JSON(driverSettlements))
+}
+
+// selfSettlementsShow gets one finalized settlement of the logged in driver with its lines. This function is mapped
+// to the path GET /self/settlements/{settlement_id}
+func selfSettlementsShow(c buffalo.Context) error {
+	var loggedInUser = loggedInUser(c)
+	tx := c.Value("tx").(*pop.Connection)
+	driverSettlement := &models.DriverSettlement{}
+	q := tx.Eager("Lines").Where("driver_id = ?", loggedInUser.ID).
+		Where("status = ?", models.DriverSettlementStatusFinalized.String())
+	if err := q.Find(driverSettlement, c.Param("settlement_id")); err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	return c.Render(http.StatusOK, r.JSON(driverSettlement))
+}
+
+// updateSettlementTotals saves the settlement with the totals of its lines
+func updateSettlementTotals(tx *pop.Connection, driverSettlement *models.DriverSettlement) error {
+	if err := driverSettlement.UpdateTotals(); err != nil {
+		return err
+	}
+	driverSettlement.UpdatedAt = time.Now().UTC()
+	verrs, err := tx.ValidateAndUpdate(driverSettlement)
+	if err != nil {
+		return err
+	}
+	if verrs.HasAny() {
+		return verrs
+	}
+	return nil
+}
@@ -0,0 +1,253 @@
+package actions
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/nulls"
+	"github.com/golang/mock/gomock"
+)
+
+// deliverShipment creates a loaded shipment of the order and has the driver deliver it
+func (as *ActionSuite) deliverShipment(driver *models.User, order *models.Order, serialNumber string) *models.Shipment {
+	shipment := as.createShipment(models.Shipment{SerialNumber: serialNumber, Status: models.ShipmentStatusLoaded.String(), CreatedBy: order.CreatedBy, TenantID: order.TenantID, Type: models.ShipmentTypeInbound.String(), DriverID: nulls.NewUUID(driver.ID)}, order)
+	as.deliverWithProof(driver, shipment)
+	return shipment
+}
+
+func (as *ActionSuite) Test_DriverSettlementsCreate() {
+	as.LoadFixture("Tenant bootstrap")
+	var tests = []struct {
+		username     string
+		responseCode int
+	}{
+		{"salah", http.StatusNotFound},
+		{"nike", http.StatusNotFound},
+		{"coutinho", http.StatusNotFound},
+		{"rodriguez", http.StatusBadRequest},
+		{"mane", http.StatusCreated},
+	}
+	firmino := as.getLoggedInUser("firmino")
+	salah := as.getLoggedInUser("salah")
+	efaLiv := as.getCustomer("EFA Liv")
+	mockFirebase.EXPECT().SendAll(gomock.Any(), gomock.Any()).AnyTimes()
+	order := as.createOrder("order", models.OrderStatusOpen, firmino.TenantID, firmino.ID, efaLiv.ID)
+	as.deliverShipment(salah, order, "s1")
+	as.deliverShipment(salah, order, "s2")
+	as.createShipment(models.Shipment{SerialNumber: "s3", Status: models.ShipmentStatusLoaded.String(), CreatedBy: firmino.ID, TenantID: firmino.TenantID, Type: models.ShipmentTypeInbound.String(), DriverID: nulls.NewUUID(salah.ID)}, order)
+	req := settlementRequest{DriverID: salah.ID, PeriodStart: time.Now().UTC().AddDate(0, 0, -1), PeriodEnd: time.Now().UTC().AddDate(0, 0, 1)}
+
+	res := as.setupRequest(firmino, "/settlements").Post(req)
+	as.Equal(http.StatusBadRequest, res.Code, "no pay rule")
+	as.createDriverPayRule(firmino, models.DriverPayRule{Type: models.DriverPayRuleTypePerMove.String(), Rate: 7500})
+	for _, test := range tests {
+		as.T().Run(test.username, func(t *testing.T) {
+			user := as.getLoggedInUser(test.username)
+			res := as.setupRequest(user, "/settlements").Post(req)
+			as.Equal(test.responseCode, res.Code)
+			if res.Code != http.StatusCreated {
+				return
+			}
+			var settlement = models.DriverSettlement{}
+			res.Bind(&settlement)
+			as.Equal(models.DriverSettlementStatusDraft.String(), settlement.Status)
+			as.Equal("CAD", settlement.Currency())
+			as.Equal(2, len(settlement.Lines))
+			as.Equal(models.NewMoney(15000, "CAD"), settlement.Earnings)
+			as.Equal(models.NewMoney(15000, "CAD"), settlement.NetPay)
+		})
+	}
+	// Shipments are paid once
+	res = as.setupRequest(firmino, "/settlements").Post(req)
+	as.Equal(http.StatusCreated, res.Code)
+	var settlement = models.DriverSettlement{}
+	res.Bind(&settlement)
+	as.Equal(0, len(settlement.Lines))
+	as.Equal(models.NewMoney(0, "CAD"), settlement.NetPay)
+}
+
+func (as *ActionSuite) Test_DriverSettlementsPercentOfLinehaul() {
+	as.LoadFixture("Tenant bootstrap")
+	firmino := as.getLoggedInUser("firmino")
+	salah := as.getLoggedInUser("salah")
+	efaLiv := as.getCustomer("EFA Liv")
+	mockFirebase.EXPECT().SendAll(gomock.Any(), gomock.Any()).AnyTimes()
+	order := as.createOrder("order", models.OrderStatusOpen, firmino.TenantID, firmino.ID, efaLiv.ID)
+	order.PickupCharges = models.NewNullMoney(20000, "CAD")
+	order.DropoffCharges = models.NewNullMoney(10000, "CAD")
+	as.Nil(as.DB.Update(order))
+	as.deliverShipment(salah, order, "s1")
+	as.createShipment(models.Shipment{SerialNumber: "s2", Status: models.ShipmentStatusUnassigned.String(), CreatedBy: firmino.ID, TenantID: firmino.TenantID, Type: models.ShipmentTypeInbound.String()}, order)
+	as.createDriverPayRule(firmino, models.DriverPayRule{Type: models.DriverPayRuleTypePerMove.String(), Rate: 7500})
+	as.createDriverPayRule(firmino, models.DriverPayRule{DriverID: nulls.NewUUID(salah.ID), Type: models.DriverPayRuleTypePercentOfLinehaul.String(), Percent: 25})
+
+	req := settlementRequest{DriverID: salah.ID, PeriodStart: time.Now().UTC().AddDate(0, 0, -1), PeriodEnd: time.Now().UTC().AddDate(0, 0, 1)}
+	res := as.setupRequest(firmino, "/settlements").Post(req)
+	as.Equal(http.StatusCreated, res.Code, res.Body.String())
+	var settlement = models.DriverSettlement{}
+	res.Bind(&settlement)
+	as.Equal(1, len(settlement.Lines))
+	// A quarter of the half of the order charges
+	as.Equal(models.NewMoney(3750, "CAD"), settlement.Earnings)
+}
+
+func (as *ActionSuite) Test_DriverSettlementsPerMileWithoutMiles() {
+	as.LoadFixture("Tenant bootstrap")
+	firmino := as.getLoggedInUser("firmino")
+	salah := as.getLoggedInUser("salah")
+	efaLiv := as.getCustomer("EFA Liv")
+	mockFirebase.EXPECT().SendAll(gomock.Any(), gomock.Any()).AnyTimes()
+	order := as.createOrder("order", models.OrderStatusOpen, firmino.TenantID, firmino.ID, efaLiv.ID)
+	shipment := as.deliverShipment(salah, order, "s1")
+	as.createDriverPayRule(firmino, models.DriverPayRule{Type: models.DriverPayRuleTypePerMile.String(), Rate: 150})
+
+	req := settlementRequest{DriverID: salah.ID, PeriodStart: time.Now().UTC().AddDate(0, 0, -1), PeriodEnd: time.Now().UTC().AddDate(0, 0, 1)}
+	res := as.setupRequest(firmino, "/settlements").Post(req)
+	as.Equal(http.StatusConflict, res.Code)
+	as.Contains(res.Body.String(), "s1")
+
+	as.Nil(as.DB.Reload(shipment))
+	shipment.Miles = nulls.NewInt(40)
+	as.Nil(as.DB.Update(shipment))
+	res = as.setupRequest(firmino, "/settlements").Post(req)
+	as.Equal(http.StatusCreated, res.Code, res.Body.String())
+	var settlement = models.DriverSettlement{}
+	res.Bind(&settlement)
+	as.Equal(models.NewMoney(6000, "CAD"), settlement.Earnings)
+}
+
+func (as *ActionSuite) Test_DriverSettlementsAdjustAndFinalize() {
+	as.LoadFixture("Tenant bootstrap")
+	firmino := as.getLoggedInUser("firmino")
+	mane := as.getLoggedInUser("mane")
+	salah := as.getLoggedInUser("salah")
+	lewin := as.getLoggedInUser("lewin")
+	efaLiv := as.getCustomer("EFA Liv")
+	mockFirebase.EXPECT().SendAll(gomock.Any(), gomock.Any()).AnyTimes()
+	order := as.createOrder("order", models.OrderStatusOpen, firmino.TenantID, firmino.ID, efaLiv.ID)
+	as.deliverShipment(salah, order, "s1")
+	as.createDriverPayRule(firmino, models.DriverPayRule{Type: models.DriverPayRuleTypePerMove.String(), Rate: 7500})
+	res := as.setupRequest(mane, "/settlements").Post(settlementRequest{DriverID: salah.ID, PeriodStart: time.Now().UTC().AddDate(0, 0, -1), PeriodEnd: time.Now().UTC().AddDate(0, 0, 1)})
+	as.Equal(http.StatusCreated, res.Code)
+	var settlement = models.DriverSettlement{}
+	res.Bind(&settlement)
+	linesPath := fmt.Sprintf("/settlements/%s/lines", settlement.ID)
+
+	var tests = []struct {
+		name         string
+		line         models.DriverSettlementLine
+		responseCode int
+		netPay       int
+	}{
+		{"deduction", models.DriverSettlementLine{Type: models.DriverSettlementLineTypeDeduction.String(), Description: "Fuel card", Amount: models.NewMoney(2500, "")}, http.StatusCreated, 5000},
+		{"earning", models.DriverSettlementLine{Type: models.DriverSettlementLineTypeEarning.String(), Description: "Bonus", Amount: models.NewMoney(2500, "")}, http.StatusBadRequest, 5000},
+		{"negative", models.DriverSettlementLine{Type: models.DriverSettlementLineTypeAdvance.String(), Description: "Advance", Amount: models.NewMoney(-1, "")}, http.StatusUnprocessableEntity, 5000},
+		{"other currency", models.DriverSettlementLine{Type: models.DriverSettlementLineTypeAdvance.String(), Description: "Advance", Amount: models.NewMoney(1000, "USD")}, http.StatusConflict, 5000},
+		{"advance", models.DriverSettlementLine{Type: models.DriverSettlementLineTypeAdvance.String(), Description: "Advance", Amount: models.NewMoney(1000, "CAD")}, http.StatusCreated, 4000},
+	}
+	for _, test := range tests {
+		as.T().Run(test.name, func(t *testing.T) {
+			res := as.setupRequest(mane, linesPath).Post(test.line)
+			as.Equal(test.responseCode, res.Code)
+			var dbSettlement = settlement
+			as.Nil(as.DB.Reload(&dbSettlement))
+			as.Equal(models.NewMoney(test.netPay, "CAD"), dbSettlement.NetPay)
+		})
+	}
+
+	// Drafts are not visible to the driver
+	res = as.setupRequest(salah, "/self/settlements").Get()
+	as.Equal(http.StatusOK, res.Code)
+	var own = models.DriverSettlements{}
+	res.Bind(&own)
+	as.Equal(0, len(own))
+
+	lines := models.DriverSettlementLines{}
+	as.Nil(as.DB.Where("driver_settlement_id = ?", settlement.ID).Order("created_at asc").All(&lines))
+	as.Equal(3, len(lines))
+	res = as.setupRequest(mane, fmt.Sprintf("%s/%s", linesPath, lines[0].ID)).Delete()
+	as.Equal(http.StatusBadRequest, res.Code)
+	res = as.setupRequest(mane, fmt.Sprintf("%s/%s", linesPath, lines[2].ID)).Delete()
+	as.Equal(http.StatusNoContent, res.Code)
+
+	res = as.setupRequest(mane, fmt.Sprintf("/settlements/%s/finalize", settlement.ID)).Post(nil)
+	as.Equal(http.StatusOK, res.Code)
+	res.Bind(&settlement)
+	as.Equal(models.DriverSettlementStatusFinalized.String(), settlement.Status)
+	as.Equal(nulls.NewUUID(mane.ID), settlement.FinalizedBy)
+	as.Equal(models.NewMoney(5000, "CAD"), settlement.NetPay)
+
+	// Finalized settlements are locked
+	res = as.setupRequest(mane, fmt.Sprintf("/settlements/%s/finalize", settlement.ID)).Post(nil)
+	as.Equal(http.StatusConflict, res.Code)
+	res = as.setupRequest(mane, linesPath).Post(tests[0].line)
+	as.Equal(http.StatusConflict, res.Code)
+	res = as.setupRequest(mane, fmt.Sprintf("%s/%s", linesPath, lines[1].ID)).Delete()
+	as.Equal(http.StatusConflict, res.Code)
+	res = as.setupRequest(mane, fmt.Sprintf("/settlements/%s", settlement.ID)).Delete()
+	as.Equal(http.StatusConflict, res.Code)
+
+	res = as.setupRequest(salah, "/self/settlements").Get()
+	as.Equal(http.StatusOK, res.Code)
+	res.Bind(&own)
+	as.Equal(1, len(own))
+	res = as.setupRequest(salah, fmt.Sprintf("/self/settlements/%s", settlement.ID)).Get()
+	as.Equal(http.StatusOK, res.Code)
+	var shown = models.DriverSettlement{}
+	res.Bind(&shown)
+	as.Equal(2, len(shown.Lines))
+	res = as.setupRequest(lewin, fmt.Sprintf("/self/settlements/%s", settlement.ID)).Get()
+	as.Equal(http.StatusNotFound, res.Code)
+	res = as.setupRequest(as.getLoggedInUser("nike"), "/self/settlements").Get()
+	as.Equal(http.StatusNotFound, res.Code)
+	res = as.setupRequest(mane, fmt.Sprintf("/self/settlements/%s", settlement.ID)).Get()
+	as.Equal(http.StatusNotFound, res.Code)
+}
+
+func (as *ActionSuite) Test_DriverSettlementsListAndDestroy() {
+	as.LoadFixture("Tenant bootstrap")
+	firmino := as.getLoggedInUser("firmino")
+	salah := as.getLoggedInUser("salah")
+	efaLiv := as.getCustomer("EFA Liv")
+	mockFirebase.EXPECT().SendAll(gomock.Any(), gomock.Any()).AnyTimes()
+	order := as.createOrder("order", models.OrderStatusOpen, firmino.TenantID, firmino.ID, efaLiv.ID)
+	as.deliverShipment(salah, order, "s1")
+	as.createDriverPayRule(firmino, models.DriverPayRule{Type: models.DriverPayRuleTypePerMove.String(), Rate: 7500})
+	req := settlementRequest{DriverID: salah.ID, PeriodStart: time.Now().UTC().AddDate(0, 0, -1), PeriodEnd: time.Now().UTC().AddDate(0, 0, 1)}
+	res := as.setupRequest(firmino, "/settlements").Post(req)
+	as.Equal(http.StatusCreated, res.Code)
+	var settlement = models.DriverSettlement{}
+	res.Bind(&settlement)
+
+	var tests = []struct {
+		username string
+		count    int
+	}{
+		{"klopp", 1},
+		{"firmino", 1},
+		{"mane", 1},
+		{"rodriguez", 0},
+	}
+	for _, test := range tests {
+		as.T().Run(test.username, func(t *testing.T) {
+			res := as.setupRequest(as.getLoggedInUser(test.username), fmt.Sprintf("/settlements?driver_id=%s", salah.ID)).Get()
+			as.Equal(http.StatusOK, res.Code)
+			var settlements = models.DriverSettlements{}
+			res.Bind(&settlements)
+			as.Equal(test.count, len(settlements))
+		})
+	}
+	res = as.setupRequest(as.getLoggedInUser("salah"), "/settlements").Get()
+	as.Equal(http.StatusNotFound, res.Code)
+
+	res = as.setupRequest(firmino, fmt.Sprintf("/settlements/%s", settlement.ID)).Delete()
+	as.Equal(http.StatusNoContent, res.Code)
+	// The shipment can be paid on a new settlement
+	res = as.setupRequest(firmino, "/settlements").Post(req)
+	as.Equal(http.StatusCreated, res.Code)
+	res.Bind(&settlement)
+	as.Equal(1, len(settlement.Lines))
+}
@@ -0,0 +1,211 @@
+package actions
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/nulls"
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gofrs/uuid"
+)
+
+// Following naming logic is implemented in Buffalo:
+// Model: Singular (Equipment)
+// DB Table: Plural (equipment)
+// Resource: Plural (Equipment)
+// Path: Plural (/equipment)
+
+// equipmentList gets all Equipment. Params "type", "status" and "unit_number" filter it. This function is mapped to
+// the path GET /equipment
+func equipmentList(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	equipment := &models.EquipmentList{}
+
+	// Paginate results. Params "page" and "per_page" control pagination.
+	// Default values are "page=1" and "per_page=20".
+	q := tx.PaginateFromParams(c.Params())
+
+	unitNumber := c.Param("unit_number")
+	if unitNumber != "" {
+		if len(unitNumber) < 2 {
+			return c.Render(http.StatusOK, r.JSON(equipment))
+		}
+		q = q.Where("unit_number ILIKE ?", fmt.Sprintf("%%%s%%", unitNumber))
+	}
+	if equipmentType := c.Param("type"); equipmentType != "" {
+		q = q.Where("type = ?", equipmentType)
+	}
+	if status := c.Param("status"); status != "" {
+		q = q.Where("status = ?", status)
+	}
+	if err := q.Scope(restrictedScope(c)).Order("type ASC, unit_number ASC").All(equipment); err != nil {
+		return err
+	}
+	return c.Render(http.StatusOK, r.JSON(equipment))
+}
+
+// equipmentShow gets the data for one Equipment. This function is mapped to
+// the path GET /equipment/{equipment_id}
+func equipmentShow(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	equipment := &models.Equipment{}
+	if err := tx.Scope(restrictedScope(c)).Find(equipment, c.Param("equipment_id")); err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	return c.Render(http.StatusOK, r.JSON(equipment))
+}
+
+// equipmentCreate adds an Equipment to the DB. This function is mapped to the
+// path POST /equipment
+func equipmentCreate(c buffalo.Context) error {
+	var loggedInUser = loggedInUser(c)
+	equipment := &models.Equipment{}
+	if err := c.Bind(equipment); err != nil {
+		c.Logger().Errorf("error binding equipment: %v\n", err)
+		return err
+	}
+	tx := c.Value("tx").(*pop.Connection)
+	equipment.CreatedBy = loggedInUser.ID
+	equipment.TenantID = loggedInUser.TenantID
+	if err := checkEquipmentUnitNumber(tx, equipment); err != nil {
+		return c.Error(http.StatusConflict, err)
+	}
+	verrs, err := tx.ValidateAndCreate(equipment)
+	if err != nil {
+		return err
+	}
+	if verrs.HasAny() {
+		return c.Render(http.StatusUnprocessableEntity, r.JSON(verrs))
+	}
+	return c.Render(http.StatusCreated, r.JSON(equipment))
+}
+
+// equipmentUpdate changes an Equipment in the DB. This function is mapped to
+// the path PUT /equipment/{equipment_id}
+func equipmentUpdate(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	equipment := &models.Equipment{}
+	if err := tx.Scope(restrictedScope(c)).Find(equipment, c.Param("equipment_id")); err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	newEquipment := &models.Equipment{}
+	if err := c.Bind(newEquipment); err != nil {
+		c.Logger().Errorf("error binding equipment: %v\n", err)
+		return err
+	}
+	newEquipment.ID = equipment.ID
+	newEquipment.CreatedAt = equipment.CreatedAt
+	newEquipment.CreatedBy = equipment.CreatedBy
+	newEquipment.TenantID = equipment.TenantID
+	newEquipment.UpdatedAt = time.Now().UTC()
+	if newEquipment.Type != equipment.Type || newEquipment.UnitNumber != equipment.UnitNumber {
+		if err := checkEquipmentUnitNumber(tx, newEquipment); err != nil {
+			return c.Error(http.StatusConflict, err)
+		}
+	}
+	verrs, err := tx.ValidateAndUpdate(newEquipment)
+	if err != nil {
+		return err
+	}
+	if verrs.HasAny() {
+		return c.Render(http.StatusUnprocessableEntity, r.JSON(verrs))
+	}
+	return c.Render(http.StatusOK, r.JSON(newEquipment))
+}
+
+// equipmentDestroy deletes an Equipment from the DB, shipments keep no reference to it.
+// This function is mapped to the path DELETE /equipment/{equipment_id}
+func equipmentDestroy(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	equipment := &models.Equipment{}
+	if err := tx.Scope(restrictedScope(c)).Find(equipment, c.Param("equipment_id")); err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	if err := tx.Destroy(equipment); err != nil {
+		return err
+	}
+	c.Response().WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// checkEquipmentUnitNumber ensures no other equipment of the tenant has the same type and unit number
+func checkEquipmentUnitNumber(tx *pop.Connection, equipment *models.Equipment) error {
+	exists, err := tx.Where("tenant_id = ?", equipment.TenantID).Where("type = ?", equipment.Type).
+		Where("unit_number = ?", equipment.UnitNumber).Where("id <> ?", equipment.ID).Exists(&models.Equipment{})
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("%s %s already exists", strings.ToLower(equipment.Type), equipment.UnitNumber)
+	}
+	return nil
+}
+
+func checkEquipmentID(c buffalo.Context, tx *pop.Connection, loggedInUser *models.User, ID nulls.UUID, equipmentType models.EquipmentType) error {
+	if !ID.Valid {
+		return nil
+	}
+	equipment := &models.Equipment{}
+	// Equipment must belong to the same tenant and be of the expected type
+	err := tx.Scope(restrictedScope(c)).Where("type = ?", equipmentType.String()).Find(equipment, ID)
+	if err != nil || equipment.ID == uuid.Nil {
+		return fmt.Errorf("invalid %s association", strings.ToLower(equipmentType.String()))
+	}
+	return nil
+}
+
+// checkShipmentEquipment checks the equipment of a shipment being dispatched is in service, and its chassis is not on
+// another active shipment
+func checkShipmentEquipment(tx *pop.Connection, shipment *models.Shipment) error {
+	if !shipment.IsActive() {
+		return nil
+	}
+	var now = time.Now().UTC()
+	var reasons = []string{}
+	for _, ID := range []nulls.UUID{shipment.TractorID, shipment.ChassisID} {
+		if !ID.Valid {
+			continue
+		}
+		equipment := &models.Equipment{}
+		if err := tx.Find(equipment, ID.UUID); err != nil {
+			return err
+		}
+		r, err := models.EquipmentOutOfServiceReasons(tx, equipment, now)
+		if err != nil {
+			return err
+		}
+		reasons = append(reasons, r...)
+	}
+	if len(reasons) > 0 {
+		return fmt.Errorf("%w: %s", models.ErrEquipmentOutOfService, strings.Join(reasons, "; "))
+	}
+	if !shipment.ChassisID.Valid {
+		return nil
+	}
+	count, err := models.CountChassisShipments(tx, shipment.ChassisID.UUID, shipment.ID)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: it is on %d other active shipment(s)", models.ErrChassisInUse, count)
+	}
+	return nil
+}
+
+// isShipmentEquipmentError checks if a shipment cannot be dispatched because of its equipment
+func isShipmentEquipmentError(err error) bool {
+	return errors.Is(err, models.ErrChassisInUse) || errors.Is(err, models.ErrEquipmentOutOfService)
+}
+
+// renderShipmentEquipmentError renders a conflict when the equipment cannot be used, an internal error otherwise
+func renderShipmentEquipmentError(c buffalo.Context, err error) error {
+	if isShipmentEquipmentError(err) {
+		return c.Error(http.StatusConflict, err)
+	}
+	return err
+}
@@ -0,0 +1,153 @@
+package actions
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/nulls"
+	"github.com/golang/mock/gomock"
+)
+
+func (as *ActionSuite) createEquipment(user *models.User, equipmentType models.EquipmentType, unitNumber string) *models.Equipment {
+	res := as.setupRequest(user, "/equipment").Post(models.Equipment{Type: equipmentType.String(), UnitNumber: unitNumber, Owner: models.EquipmentOwnerOwn.String()})
+	as.Equal(http.StatusCreated, res.Code, res.Body.String())
+	var created = &models.Equipment{}
+	res.Bind(created)
+	return created
+}
+
+func (as *ActionSuite) Test_EquipmentCreate() {
+	as.LoadFixture("Tenant bootstrap")
+	var tests = []struct {
+		username     string
+		equipment    models.Equipment
+		responseCode int
+	}{
+		{"firmino", models.Equipment{Type: "Tractor", UnitNumber: "T1", Owner: "Own", Plate: nulls.NewString("BC1234")}, http.StatusCreated},
+		{"mane", models.Equipment{Type: "Chassis", UnitNumber: "C1", Owner: "Pool"}, http.StatusCreated},
+		{"rodriguez", models.Equipment{Type: "Chassis", UnitNumber: "C1", Owner: "Leased"}, http.StatusCreated},
+		{"mane", models.Equipment{Type: "Chassis", UnitNumber: "C1", Owner: "Pool"}, http.StatusConflict},
+		{"mane", models.Equipment{Type: "Tractor", UnitNumber: "C1", Owner: "Pool"}, http.StatusCreated},
+		{"mane", models.Equipment{Type: "Trailer", UnitNumber: "X1", Owner: "Pool"}, http.StatusUnprocessableEntity},
+		{"salah", models.Equipment{Type: "Tractor", UnitNumber: "T2", Owner: "Own"}, http.StatusNotFound},
+		{"nike", models.Equipment{Type: "Tractor", UnitNumber: "T3", Owner: "Own"}, http.StatusNotFound},
+	}
+	for i, test := range tests {
+		as.T().Run(fmt.Sprint(i), func(t *testing.T) {
+			user := as.getLoggedInUser(test.username)
+			res := as.setupRequest(user, "/equipment").Post(test.equipment)
+			as.Equal(test.responseCode, res.Code, res.Body.String())
+			if res.Code == http.StatusCreated {
+				var equipment = models.Equipment{}
+				res.Bind(&equipment)
+				as.Equal(test.equipment.UnitNumber, equipment.UnitNumber)
+				as.Equal(models.EquipmentStatusActive.String(), equipment.Status)
+				as.Equal(user.TenantID, equipment.TenantID)
+			}
+		})
+	}
+}
+
+func (as *ActionSuite) Test_EquipmentListUpdateDestroy() {
+	as.LoadFixture("Tenant bootstrap")
+	mane := as.getLoggedInUser("mane")
+	tractor := as.createEquipment(mane, models.EquipmentTypeTractor, "T100")
+	as.createEquipment(mane, models.EquipmentTypeChassis, "C100")
+	as.createEquipment(mane, models.EquipmentTypeChassis, "C200")
+	var tests = []struct {
+		username     string
+		query        string
+		responseCode int
+		count        int
+	}{
+		{"mane", "", http.StatusOK, 3},
+		{"salah", "?type=Chassis", http.StatusOK, 2},
+		{"mane", "?unit_number=c1", http.StatusOK, 1},
+		{"mane", "?status=OutOfService", http.StatusOK, 0},
+		{"rodriguez", "", http.StatusOK, 0},
+		{"nike", "", http.StatusNotFound, 0},
+	}
+	for _, test := range tests {
+		as.T().Run(test.username+test.query, func(t *testing.T) {
+			res := as.setupRequest(as.getLoggedInUser(test.username), "/equipment"+test.query).Get()
+			as.Equal(test.responseCode, res.Code)
+			var equipment = models.EquipmentList{}
+			res.Bind(&equipment)
+			as.Len(equipment, test.count)
+		})
+	}
+	tractor.Status = models.EquipmentStatusOutOfService.String()
+	res := as.setupRequest(as.getLoggedInUser("salah"), fmt.Sprintf("/equipment/%s", tractor.ID)).Put(tractor)
+	as.Equal(http.StatusNotFound, res.Code)
+	res = as.setupRequest(as.getLoggedInUser("rodriguez"), fmt.Sprintf("/equipment/%s", tractor.ID)).Put(tractor)
+	as.Equal(http.StatusNotFound, res.Code)
+	res = as.setupRequest(mane, fmt.Sprintf("/equipment/%s", tractor.ID)).Put(tractor)
+	as.Equal(http.StatusOK, res.Code, res.Body.String())
+	res = as.setupRequest(mane, "/equipment?status=OutOfService").Get()
+	var equipment = models.EquipmentList{}
+	res.Bind(&equipment)
+	as.Len(equipment, 1)
+	res = as.setupRequest(as.getLoggedInUser("salah"), fmt.Sprintf("/equipment/%s", tractor.ID)).Get()
+	as.Equal(http.StatusOK, res.Code)
+	res = as.setupRequest(mane, fmt.Sprintf("/equipment/%s", tractor.ID)).Delete()
+	as.Equal(http.StatusNoContent, res.Code)
+	res = as.setupRequest(mane, fmt.Sprintf("/equipment/%s", tractor.ID)).Get()
+	as.Equal(http.StatusNotFound, res.Code)
+}
+
+func (as *ActionSuite) Test_ShipmentsEquipment() {
+	as.LoadFixture("Tenant bootstrap")
+	mockFirebase.EXPECT().SendAll(gomock.Any(), gomock.Any()).AnyTimes()
+	mane := as.getLoggedInUser("mane")
+	salah := as.getLoggedInUser("salah")
+	efaLiv := as.getCustomer("EFA Liv")
+	order := as.createOrder("eq", models.OrderStatusOpen, mane.TenantID, mane.ID, efaLiv.ID)
+	tractor := as.createEquipment(mane, models.EquipmentTypeTractor, "T100")
+	chassis := as.createEquipment(mane, models.EquipmentTypeChassis, "C100")
+	otherChassis := as.createEquipment(as.getLoggedInUser("rodriguez"), models.EquipmentTypeChassis, "C100")
+	var tests = []struct {
+		serialNumber string
+		tractorID    nulls.UUID
+		chassisID    nulls.UUID
+		driverID     nulls.UUID
+		responseCode int
+	}{
+		{"chassis as tractor", nulls.NewUUID(chassis.ID), nulls.UUID{}, nulls.UUID{}, http.StatusBadRequest},
+		{"tractor as chassis", nulls.UUID{}, nulls.NewUUID(tractor.ID), nulls.UUID{}, http.StatusBadRequest},
+		{"other tenant", nulls.UUID{}, nulls.NewUUID(otherChassis.ID), nulls.UUID{}, http.StatusBadRequest},
+		{"first", nulls.NewUUID(tractor.ID), nulls.NewUUID(chassis.ID), nulls.NewUUID(salah.ID), http.StatusCreated},
+		{"unassigned", nulls.UUID{}, nulls.NewUUID(chassis.ID), nulls.UUID{}, http.StatusCreated},
+		{"second", nulls.NewUUID(tractor.ID), nulls.NewUUID(chassis.ID), nulls.NewUUID(salah.ID), http.StatusConflict},
+	}
+	for _, test := range tests {
+		as.T().Run(test.serialNumber, func(t *testing.T) {
+			newShipment := models.Shipment{SerialNumber: test.serialNumber, Type: models.ShipmentTypeInbound.String(), OrderID: nulls.NewUUID(order.ID),
+				TractorID: test.tractorID, ChassisID: test.chassisID, DriverID: test.driverID}
+			res := as.setupRequest(mane, "/shipments").Post(newShipment)
+			as.Equal(test.responseCode, res.Code, res.Body.String())
+		})
+	}
+	unassigned := &models.Shipment{}
+	as.Nil(as.DB.Where("serial_number = ?", "unassigned").First(unassigned))
+	unassigned.DriverID = nulls.NewUUID(salah.ID)
+	unassigned.Status = models.ShipmentStatusAssigned.String()
+	res := as.setupRequest(mane, fmt.Sprintf("/shipments/%s", unassigned.ID)).Put(unassigned)
+	as.Equal(http.StatusConflict, res.Code, res.Body.String())
+
+	first := &models.Shipment{}
+	as.Nil(as.DB.Where("serial_number = ?", "first").First(first))
+	first.Status = models.ShipmentStatusRejected.String()
+	res = as.setupRequest(mane, fmt.Sprintf("/shipments/%s", first.ID)).Put(first)
+	as.Equal(http.StatusOK, res.Code, res.Body.String())
+	res = as.setupRequest(mane, fmt.Sprintf("/shipments/%s", unassigned.ID)).Put(unassigned)
+	as.Equal(http.StatusOK, res.Code, res.Body.String())
+
+	// Drivers cannot change the equipment
+	unassigned.ChassisID = nulls.UUID{}
+	res = as.setupRequest(salah, fmt.Sprintf("/shipments/%s", unassigned.ID)).Put(unassigned)
+	as.Equal(http.StatusOK, res.Code, res.Body.String())
+	as.Nil(as.DB.Reload(unassigned))
+	as.Equal(chassis.ID, unassigned.ChassisID.UUID)
+}
@@ -0,0 +1,85 @@
+package actions
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bigpanther/trober/firebase"
+	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/envy"
+	"github.com/gobuffalo/nulls"
+	"github.com/gobuffalo/pop/v6"
+)
+
+// etaAlertThreshold reads how late past its reservation time a shipment is predicted to arrive before the customer is told
+func etaAlertThreshold() (time.Duration, error) {
+	threshold, err := time.ParseDuration(envy.Get("ETA_ALERT_THRESHOLD", "30m"))
+	if err != nil || threshold < 0 {
+		return 0, errors.New("invalid ETA_ALERT_THRESHOLD")
+	}
+	return threshold, nil
+}
+
+// updateShipmentEtas predicts when the shipments in transit reach their destination from the latest GPS point of the driver
+// and the average speed of their lane. The customer is told once when a shipment runs late.
+func updateShipmentEtas(tx *pop.Connection, shipments models.Shipments, locations models.DriverLocations) error {
+	threshold, err := etaAlertThreshold()
+	if err != nil {
+		return err
+	}
+	for i := range shipments {
+		var shipment = &shipments[i]
+		if shipment.Status != models.ShipmentStatusInTransit.String() {
+			continue
+		}
+		var latest *models.DriverLocation
+		for j, l := range locations {
+			if l.ShipmentID.Valid && l.ShipmentID.UUID == shipment.ID && (latest == nil || l.RecordedAt.After(latest.RecordedAt)) {
+				latest = &locations[j]
+			}
+		}
+		if latest == nil || (shipment.EtaUpdatedAt.Valid && !latest.RecordedAt.After(shipment.EtaUpdatedAt.Time)) {
+			continue
+		}
+		route, err := models.LoadShipmentRoute(tx, shipment)
+		if err != nil {
+			return err
+		}
+		if route.Destination == nil {
+			continue
+		}
+		trips, err := models.LoadLaneTrips(tx, shipment, route)
+		if err != nil {
+			return err
+		}
+		shipment.Eta = nulls.NewTime(models.PredictEta(latest.RecordedAt, latest.Position(), *route.Destination, models.LaneSpeedKmh(trips)))
+		shipment.EtaUpdatedAt = nulls.NewTime(latest.RecordedAt)
+		var shouldNotifyCustomer = shipment.EtaSlipped(threshold) && !shipment.EtaAlertedAt.Valid && shipment.CustomerID.Valid
+		if shouldNotifyCustomer {
+			shipment.EtaAlertedAt = nulls.NewTime(time.Now().UTC())
+		}
+		shipment.UpdatedAt = time.Now().UTC()
+		verrs, err := tx.ValidateAndUpdate(shipment)
+		if err != nil {
+			return err
+		}
+		if verrs.HasAny() {
+			return verrs
+		}
+		if shouldNotifyCustomer {
+			sendNotificationsAsync(
+				models.NotificationEventShipmentLate,
+				[]string{firebase.GetCustomerTopic(shipment.TenantID.String(), shipment.CustomerID.UUID.String())},
+				fmt.Sprintf("Your shipment is running late - %s", shipment.SerialNumber),
+				fmt.Sprintf("Expected at %s", shipment.Eta.Time.Format(time.RFC3339)),
+				map[string]string{
+					"shipment.id":           shipment.ID.String(),
+					"shipment.serialNumber": shipment.SerialNumber,
+					"shipment.eta":          shipment.Eta.Time.Format(time.RFC3339),
+				},
+			)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package actions
+
+import (
+	"time"
+
+	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/nulls"
+	"github.com/golang/mock/gomock"
+)
+
+func (as *ActionSuite) Test_ShipmentsEta() {
+	as.LoadFixture("Tenant bootstrap")
+	mockFirebase.EXPECT().SendAll(gomock.Any(), gomock.Any()).AnyTimes()
+	salah := as.getLoggedInUser("salah")
+	mane := as.getLoggedInUser("mane")
+	efaLiv := as.getCustomer("EFA Liv")
+	terminal := as.createTerminal("Deltaport", models.TerminalTypePort, salah.TenantID, salah.ID)
+	terminal.Latitude = nulls.NewFloat64(deltaport.Latitude)
+	terminal.Longitude = nulls.NewFloat64(deltaport.Longitude)
+	as.Nil(as.DB.Update(terminal))
+	site := as.createLocation(mane, models.Location{Name: "EFA Warehouse", CustomerID: nulls.NewUUID(efaLiv.ID),
+		Latitude: nulls.NewFloat64(49.2827), Longitude: nulls.NewFloat64(-123.1207)})
+	order := as.createOrder("ord1", models.OrderStatusAccepted, salah.TenantID, salah.ID, efaLiv.ID)
+	var lane = func(serialNumber string, status models.ShipmentStatus, reservationTime nulls.Time) *models.Shipment {
+		return as.createShipment(models.Shipment{SerialNumber: serialNumber, Status: status.String(), Type: models.ShipmentTypeInbound.String(),
+			CreatedBy: salah.ID, TenantID: salah.TenantID, DriverID: nulls.NewUUID(salah.ID), TerminalID: nulls.NewUUID(terminal.ID),
+			DestinationLocationID: nulls.NewUUID(site.ID), ReservationTime: reservationTime}, order)
+	}
+	// The lane took an hour before
+	past := lane("past", models.ShipmentStatusDelivered, nulls.Time{})
+	var departed = time.Now().UTC().AddDate(0, 0, -1)
+	for _, e := range []models.ShipmentEvent{
+		{ShipmentID: past.ID, TenantID: past.TenantID, CreatedBy: salah.ID, ToStatus: models.ShipmentStatusInTransit.String(), CreatedAt: departed},
+		{ShipmentID: past.ID, TenantID: past.TenantID, CreatedBy: salah.ID, ToStatus: models.ShipmentStatusDelivered.String(), CreatedAt: departed.Add(time.Hour)},
+	} {
+		event := e
+		as.Nil(as.DB.Create(&event))
+	}
+	late := lane("late", models.ShipmentStatusInTransit, nulls.NewTime(time.Now().UTC().Add(-time.Hour)))
+	onTime := lane("ontime", models.ShipmentStatusInTransit, nulls.NewTime(time.Now().UTC().Add(3*time.Hour)))
+
+	as.postDriverLocations("salah", deltaport)
+	as.Nil(as.DB.Reload(late))
+	as.True(late.Eta.Valid)
+	as.True(late.EtaUpdatedAt.Valid)
+	as.WithinDuration(late.EtaUpdatedAt.Time.Add(time.Hour), late.Eta.Time, time.Second)
+	as.True(late.EtaAlertedAt.Valid)
+	as.Nil(as.DB.Reload(onTime))
+	as.True(onTime.Eta.Valid)
+	as.False(onTime.EtaAlertedAt.Valid)
+
+	// The customer is told once
+	alertedAt := late.EtaAlertedAt
+	as.postDriverLocations("salah", tsawwasen)
+	as.Nil(as.DB.Reload(late))
+	as.Equal(alertedAt.Time.Unix(), late.EtaAlertedAt.Time.Unix())
+	as.True(late.Eta.Time.Before(late.EtaUpdatedAt.Time.Add(time.Hour)))
+
+	// Only the shipments in transit are predicted
+	as.Nil(as.DB.Reload(past))
+	as.False(past.Eta.Valid)
+}
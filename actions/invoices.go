@@ -0,0 +1,272 @@
+package actions
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/nulls"
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gofrs/uuid"
+)
+
+// Following naming logic is implemented in Buffalo:
+// Model: Singular (Invoice)
+// DB Table: Plural (invoices)
+// Resource: Plural (Invoices)
+// Path: Plural (/invoices)
+
+// invoicesList gets all Invoices. This function is mapped to the path
+// GET /invoices
+func invoicesList(c buffalo.Context) error {
+	var loggedInUser = loggedInUser(c)
+	tx := c.Value("tx").(*pop.Connection)
+	invoiceStatus := c.Param("status")
+	invoices := &models.Invoices{}
+
+	// Paginate results. Params "page" and "per_page" control pagination.
+	// Default values are "page=1" and "per_page=20".
+	q := tx.PaginateFromParams(c.Params())
+
+	customerID := c.Param("customer_id")
+	if loggedInUser.IsCustomer() {
+		if !loggedInUser.CustomerID.Valid {
+			return c.Error(http.StatusNotFound, errors.New("invalid user"))
+		}
+		customerID = loggedInUser.CustomerID.UUID.String()
+	}
+	if customerID != "" {
+		q = q.Where("customer_id = ?", customerID)
+	}
+	if invoiceStatus != "" {
+		q = q.Where("status = ?", invoiceStatus)
+	}
+	// Retrieve all Invoices from the DB
+	if err := q.Scope(restrictedScope(c)).Order(orderByCreatedAtDesc).All(invoices); err != nil {
+		return err
+	}
+
+	return c.Render(http.StatusOK, r.JSON(invoices))
+}
+
+// invoicesShow gets the data for one Invoice. This function is mapped to
+// the path GET /invoices/{invoice_id}
+func invoicesShow(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	var loggedInUser = loggedInUser(c)
+
+	var populatedFields = []string{"Customer", "Lines", "Orders"}
+	q := tx.Eager(populatedFields...).Scope(restrictedScope(c))
+	if loggedInUser.IsCustomer() {
+		q = q.Where("customer_id = ?", loggedInUser.CustomerID)
+	}
+
+	invoice := &models.Invoice{}
+	if err := q.Find(invoice, c.Param("invoice_id")); err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	return c.Render(http.StatusOK, r.JSON(invoice))
+}
+
+type invoiceRequest struct {
+	CustomerID   uuid.UUID           `json:"customer_id"`
+	OrderIDs     []uuid.UUID         `json:"order_ids"`
+	DueDate      nulls.Time          `json:"due_date"`
+	Accessorials models.InvoiceLines `json:"accessorials"`
+}
+
+// invoicesCreate generates an Invoice for delivered orders of a customer. This function is mapped to the
+// path POST /invoices
+func invoicesCreate(c buffalo.Context) error {
+	var loggedInUser = loggedInUser(c)
+	req := &invoiceRequest{}
+	if err := c.Bind(req); err != nil {
+		c.Logger().Errorf("error binding invoice: %v\n", err)
+		return err
+	}
+	if len(req.OrderIDs) == 0 {
+		return c.Error(http.StatusBadRequest, errors.New("at least one order is required"))
+	}
+	tx := c.Value("tx").(*pop.Connection)
+	customer := &models.Customer{}
+	// Customer must belong to the same tenant
+	if err := tx.Scope(restrictedScope(c)).Where("tenant_id = ?", loggedInUser.TenantID).Find(customer, req.CustomerID); err != nil {
+		return c.Error(http.StatusBadRequest, errors.New("invalid customer association"))
+	}
+	orders, err := checkInvoiceOrders(c, tx, customer, req.OrderIDs)
+	if err != nil {
+		return c.Error(http.StatusBadRequest, err)
+	}
+	currency, err := tenantCurrency(tx, loggedInUser.TenantID)
+	if err != nil {
+		return err
+	}
+	var lines = models.InvoiceLines{}
+	var invoiceOrders = models.InvoiceOrders{}
+	var billed = map[uuid.UUID]bool{}
+	for i := range orders {
+		if orders[i].Status != models.OrderStatusDelivered.String() {
+			return c.Error(http.StatusConflict, fmt.Errorf("order %s is %s, only delivered orders can be invoiced", orders[i].SerialNumber, orders[i].Status))
+		}
+		billed[orders[i].ID] = true
+		invoiceOrders = append(invoiceOrders, models.InvoiceOrder{TenantID: loggedInUser.TenantID, OrderID: orders[i].ID})
+		lines = append(lines, models.InvoiceLinesForOrder(&orders[i])...)
+	}
+	for _, l := range req.Accessorials {
+		if l.OrderID.Valid && !billed[l.OrderID.UUID] {
+			return c.Error(http.StatusBadRequest, errors.New("accessorial charges must belong to an invoiced order"))
+		}
+		l.TenantID = loggedInUser.TenantID
+		l.Type = models.InvoiceLineTypeAccessorial.String()
+		if l.Amount.Currency == "" {
+			l.Amount.Currency = currency
+		}
+		lines = append(lines, l)
+	}
+	total, err := lines.Total()
+	if err != nil {
+		return c.Error(http.StatusConflict, err)
+	}
+	if total.Currency == "" {
+		total.Currency = currency
+	}
+	number, err := models.NextInvoiceNumber(tx, loggedInUser.TenantID)
+	if err != nil {
+		return err
+	}
+	invoice := &models.Invoice{
+		CreatedBy:  loggedInUser.ID,
+		TenantID:   loggedInUser.TenantID,
+		CustomerID: customer.ID,
+		Number:     number,
+		Status:     models.InvoiceStatusIssued.String(),
+		DueDate:    req.DueDate,
+		Total:      total,
+		Lines:      lines,
+		Orders:     invoiceOrders,
+	}
+	verrs, err := tx.Eager("Lines", "Orders").ValidateAndCreate(invoice)
+	if err != nil {
+		return err
+	}
+	if verrs.HasAny() {
+		return c.Render(http.StatusUnprocessableEntity, r.JSON(verrs))
+	}
+	if err := updateInvoiceOrdersStatus(tx, invoice, models.OrderStatusDelivered, models.OrderStatusInvoiced); err != nil {
+		return err
+	}
+	return c.Render(http.StatusCreated, r.JSON(invoice))
+}
+
+type invoicePayment struct {
+	Reference string     `json:"reference"`
+	PaidAt    nulls.Time `json:"paid_at"`
+}
+
+// invoicesPay records the payment of an Invoice. This function is mapped to the
+// path POST /invoices/{invoice_id}/payments
+func invoicesPay(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	invoice := &models.Invoice{}
+	if err := tx.Eager("Lines", "Orders").Scope(restrictedScope(c)).Find(invoice, c.Param("invoice_id")); err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	if invoice.IsPaid() {
+		return c.Error(http.StatusConflict, errors.New("invoice has already been paid"))
+	}
+	payment := &invoicePayment{}
+	if err := c.Bind(payment); err != nil {
+		c.Logger().Errorf("error binding payment: %v\n", err)
+		return err
+	}
+	if !payment.PaidAt.Valid {
+		payment.PaidAt = nulls.NewTime(time.Now().UTC())
+	}
+	invoice.UpdatedAt = time.Now().UTC()
+	invoice.Status = models.InvoiceStatusPaid.String()
+	invoice.PaidAt = payment.PaidAt
+	if payment.Reference != "" {
+		invoice.PaymentReference = nulls.NewString(payment.Reference)
+	}
+	verrs, err := tx.ValidateAndUpdate(invoice)
+	if err != nil {
+		return err
+	}
+	if verrs.HasAny() {
+		return c.Render(http.StatusUnprocessableEntity, r.JSON(verrs))
+	}
+	if err := updateInvoiceOrdersStatus(tx, invoice, models.OrderStatusInvoiced, models.OrderStatusPaymentReceived); err != nil {
+		return err
+	}
+	return c.Render(http.StatusOK, r.JSON(invoice))
+}
+
+// invoicesDestroy deletes an unpaid Invoice and returns its orders to Delivered. This function is mapped
+// to the path DELETE /invoices/{invoice_id}
+func invoicesDestroy(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	invoice := &models.Invoice{}
+	if err := tx.Eager("Lines", "Orders").Scope(restrictedScope(c)).Find(invoice, c.Param("invoice_id")); err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	if invoice.IsPaid() {
+		return c.Error(http.StatusConflict, errors.New("a paid invoice cannot be deleted"))
+	}
+	if err := updateInvoiceOrdersStatus(tx, invoice, models.OrderStatusInvoiced, models.OrderStatusDelivered); err != nil {
+		return err
+	}
+	if err := tx.Destroy(invoice); err != nil {
+		return err
+	}
+	c.Response().WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func checkInvoiceOrders(c buffalo.Context, tx *pop.Connection, customer *models.Customer, orderIDs []uuid.UUID) (models.Orders, error) {
+	var ids = []interface{}{}
+	var seen = map[uuid.UUID]bool{}
+	for _, id := range orderIDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	orders := models.Orders{}
+	// Orders must belong to the customer in the same tenant
+	err := tx.Scope(restrictedScope(c)).Where("customer_id = ?", customer.ID).Where("id in (?)", ids...).Order("created_at ASC").All(&orders)
+	if err != nil || len(orders) != len(ids) {
+		return nil, errors.New("invalid order association")
+	}
+	return orders, nil
+}
+
+// updateInvoiceOrdersStatus moves the orders billed on an invoice between statuses
+func updateInvoiceOrdersStatus(tx *pop.Connection, invoice *models.Invoice, from models.OrderStatus, to models.OrderStatus) error {
+	var ids = invoice.OrderIDs()
+	if len(ids) == 0 {
+		return nil
+	}
+	var args = []interface{}{}
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	orders := models.Orders{}
+	if err := tx.Where("status = ?", from).Where("id in (?)", args...).All(&orders); err != nil {
+		return err
+	}
+	for i := range orders {
+		orders[i].UpdatedAt = time.Now().UTC()
+		orders[i].Status = to.String()
+		verrs, err := tx.ValidateAndUpdate(&orders[i])
+		if err != nil {
+			return err
+		}
+		if verrs.HasAny() {
+			return verrs
+		}
+	}
+	return nil
+}
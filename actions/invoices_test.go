@@ -0,0 +1,234 @@
+package actions
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/nulls"
+	"github.com/gofrs/uuid"
+)
+
+func (as *ActionSuite) createInvoice(user *models.User, customerID uuid.UUID, orders ...*models.Order) *models.Invoice {
+	var orderIDs = []uuid.UUID{}
+	for _, o := range orders {
+		orderIDs = append(orderIDs, o.ID)
+	}
+	res := as.setupRequest(user, "/invoices").Post(invoiceRequest{CustomerID: customerID, OrderIDs: orderIDs})
+	as.Equal(http.StatusCreated, res.Code, res.Body.String())
+	var invoice = &models.Invoice{}
+	res.Bind(invoice)
+	return invoice
+}
+
+func (as *ActionSuite) Test_InvoicesCreate() {
+	as.LoadFixture("Tenant bootstrap")
+	var tests = []struct {
+		username     string
+		responseCode int
+	}{
+		{"firmino", http.StatusCreated},
+		{"mane", http.StatusCreated},
+		{"klopp", http.StatusBadRequest},
+		{"rodriguez", http.StatusBadRequest},
+		{"salah", http.StatusNotFound},
+		{"nike", http.StatusNotFound},
+		{"coutinho", http.StatusNotFound},
+	}
+	firmino := as.getLoggedInUser("firmino")
+	efaLiv := as.getCustomer("EFA Liv")
+	var invoiceCount int
+	for _, test := range tests {
+		as.T().Run(test.username, func(t *testing.T) {
+			user := as.getLoggedInUser(test.username)
+			order := as.createOrder(fmt.Sprintf("ord%s", test.username), models.OrderStatusDelivered, firmino.TenantID, firmino.ID, efaLiv.ID)
+			req := invoiceRequest{
+				CustomerID: efaLiv.ID,
+				OrderIDs:   []uuid.UUID{order.ID},
+				Accessorials: models.InvoiceLines{
+					{Description: "Detention", Amount: models.Money{Amount: 5000}, OrderID: nulls.NewUUID(order.ID)},
+				},
+			}
+			res := as.setupRequest(user, "/invoices").Post(req)
+			as.Equal(test.responseCode, res.Code)
+			as.Nil(as.DB.Reload(order))
+			if res.Code != http.StatusCreated {
+				as.Equal(models.OrderStatusDelivered.String(), order.Status)
+				return
+			}
+			invoiceCount++
+			var invoice = models.Invoice{}
+			res.Bind(&invoice)
+			as.Equal(invoiceCount, invoice.Number)
+			as.Equal(models.InvoiceStatusIssued.String(), invoice.Status)
+			as.Equal(efaLiv.ID, invoice.CustomerID)
+			as.Equal(firmino.TenantID, invoice.TenantID)
+			as.Equal(3, len(invoice.Lines))
+			as.Equal(models.NewMoney(order.PickupCharges.Money.Amount+order.DropoffCharges.Money.Amount+5000, "CAD"), invoice.Total)
+			as.Equal(models.OrderStatusInvoiced.String(), order.Status)
+		})
+	}
+}
+
+func (as *ActionSuite) Test_InvoicesCreateInvalidOrders() {
+	as.LoadFixture("Tenant bootstrap")
+	firmino := as.getLoggedInUser("firmino")
+	richarlson := as.getLoggedInUser("richarlson")
+	efaLiv := as.getCustomer("EFA Liv")
+	uefaLiv := as.getCustomer("UEFA Liv")
+	efaEve := as.getCustomer("EFA Eve")
+	openOrder := as.createOrder("open", models.OrderStatusOpen, firmino.TenantID, firmino.ID, efaLiv.ID)
+	otherCustomerOrder := as.createOrder("other", models.OrderStatusDelivered, firmino.TenantID, firmino.ID, uefaLiv.ID)
+	otherTenantOrder := as.createOrder("eve", models.OrderStatusDelivered, richarlson.TenantID, richarlson.ID, efaEve.ID)
+	deliveredOrder := as.createOrder("delivered", models.OrderStatusDelivered, firmino.TenantID, firmino.ID, efaLiv.ID)
+	usdOrder := as.createOrder("usd", models.OrderStatusDelivered, firmino.TenantID, firmino.ID, efaLiv.ID)
+	usdOrder.PickupCharges = models.NewNullMoney(100, "USD")
+	usdOrder.DropoffCharges = models.NewNullMoney(200, "USD")
+	as.Nil(as.DB.Update(usdOrder))
+	var tests = []struct {
+		name         string
+		req          invoiceRequest
+		responseCode int
+	}{
+		{"no orders", invoiceRequest{CustomerID: efaLiv.ID}, http.StatusBadRequest},
+		{"open order", invoiceRequest{CustomerID: efaLiv.ID, OrderIDs: []uuid.UUID{openOrder.ID}}, http.StatusConflict},
+		{"other customer", invoiceRequest{CustomerID: efaLiv.ID, OrderIDs: []uuid.UUID{otherCustomerOrder.ID}}, http.StatusBadRequest},
+		{"other tenant", invoiceRequest{CustomerID: efaLiv.ID, OrderIDs: []uuid.UUID{otherTenantOrder.ID}}, http.StatusBadRequest},
+		{"mixed currencies", invoiceRequest{CustomerID: efaLiv.ID, OrderIDs: []uuid.UUID{deliveredOrder.ID, usdOrder.ID}}, http.StatusConflict},
+		{"accessorial in tenant currency", invoiceRequest{CustomerID: efaLiv.ID, OrderIDs: []uuid.UUID{usdOrder.ID}, Accessorials: models.InvoiceLines{{Description: "Storage", Amount: models.Money{Amount: 100}}}}, http.StatusConflict},
+		{"foreign accessorial", invoiceRequest{CustomerID: efaLiv.ID, OrderIDs: []uuid.UUID{deliveredOrder.ID}, Accessorials: models.InvoiceLines{{Description: "Storage", Amount: models.NewMoney(100, "CAD"), OrderID: nulls.NewUUID(openOrder.ID)}}}, http.StatusBadRequest},
+	}
+	for _, test := range tests {
+		as.T().Run(test.name, func(t *testing.T) {
+			res := as.setupRequest(firmino, "/invoices").Post(test.req)
+			as.Equal(test.responseCode, res.Code)
+		})
+	}
+	count, err := as.DB.Count(&models.Invoices{})
+	as.Nil(err)
+	as.Equal(0, count)
+}
+
+func (as *ActionSuite) Test_InvoicesShow() {
+	as.LoadFixture("Tenant bootstrap")
+	var tests = []struct {
+		username     string
+		responseCode int
+	}{
+		{"klopp", http.StatusOK},
+		{"firmino", http.StatusOK},
+		{"mane", http.StatusOK},
+		{"nike", http.StatusOK},
+		{"salah", http.StatusNotFound},
+		{"richarlson", http.StatusNotFound},
+		{"adidas", http.StatusNotFound},
+	}
+	firmino := as.getLoggedInUser("firmino")
+	efaLiv := as.getCustomer("EFA Liv")
+	order := as.createOrder("ord1", models.OrderStatusDelivered, firmino.TenantID, firmino.ID, efaLiv.ID)
+	invoice := as.createInvoice(firmino, efaLiv.ID, order)
+	for _, test := range tests {
+		as.T().Run(test.username, func(t *testing.T) {
+			user := as.getLoggedInUser(test.username)
+			res := as.setupRequest(user, fmt.Sprintf("/invoices/%s", invoice.ID)).Get()
+			as.Equal(test.responseCode, res.Code)
+			if res.Code == http.StatusOK {
+				var shown = models.Invoice{}
+				res.Bind(&shown)
+				as.Equal(invoice.Number, shown.Number)
+				as.Equal(2, len(shown.Lines))
+				as.Equal(efaLiv.Name, shown.Customer.Name)
+			}
+			res = as.setupRequest(user, "/invoices").Get()
+			if res.Code == http.StatusOK {
+				var invoices = models.Invoices{}
+				res.Bind(&invoices)
+				if test.responseCode == http.StatusOK {
+					as.Equal(1, len(invoices))
+				} else {
+					as.Equal(0, len(invoices))
+				}
+			}
+		})
+	}
+}
+
+func (as *ActionSuite) Test_InvoicesPay() {
+	as.LoadFixture("Tenant bootstrap")
+	firmino := as.getLoggedInUser("firmino")
+	mane := as.getLoggedInUser("mane")
+	nike := as.getLoggedInUser("nike")
+	efaLiv := as.getCustomer("EFA Liv")
+	orders := []*models.Order{
+		as.createOrder("ord1", models.OrderStatusDelivered, firmino.TenantID, firmino.ID, efaLiv.ID),
+		as.createOrder("ord2", models.OrderStatusDelivered, firmino.TenantID, firmino.ID, efaLiv.ID),
+	}
+	invoice := as.createInvoice(mane, efaLiv.ID, orders...)
+
+	res := as.setupRequest(nike, fmt.Sprintf("/invoices/%s/payments", invoice.ID)).Post(invoicePayment{Reference: "cheque 42"})
+	as.Equal(http.StatusNotFound, res.Code)
+
+	res = as.setupRequest(mane, fmt.Sprintf("/invoices/%s/payments", invoice.ID)).Post(invoicePayment{Reference: "cheque 42"})
+	as.Equal(http.StatusOK, res.Code)
+	var paid = models.Invoice{}
+	res.Bind(&paid)
+	as.Equal(models.InvoiceStatusPaid.String(), paid.Status)
+	as.Equal("cheque 42", paid.PaymentReference.String)
+	as.True(paid.PaidAt.Valid)
+	for _, o := range orders {
+		as.Nil(as.DB.Reload(o))
+		as.Equal(models.OrderStatusPaymentReceived.String(), o.Status)
+	}
+
+	res = as.setupRequest(mane, fmt.Sprintf("/invoices/%s/payments", invoice.ID)).Post(invoicePayment{Reference: "again"})
+	as.Equal(http.StatusConflict, res.Code)
+	res = as.setupRequest(mane, fmt.Sprintf("/invoices/%s", invoice.ID)).Delete()
+	as.Equal(http.StatusConflict, res.Code)
+}
+
+func (as *ActionSuite) Test_InvoicesDestroy() {
+	as.LoadFixture("Tenant bootstrap")
+	firmino := as.getLoggedInUser("firmino")
+	efaLiv := as.getCustomer("EFA Liv")
+	order := as.createOrder("ord1", models.OrderStatusDelivered, firmino.TenantID, firmino.ID, efaLiv.ID)
+	invoice := as.createInvoice(firmino, efaLiv.ID, order)
+	as.Nil(as.DB.Reload(order))
+	as.Equal(models.OrderStatusInvoiced.String(), order.Status)
+
+	res := as.setupRequest(firmino, fmt.Sprintf("/invoices/%s", invoice.ID)).Delete()
+	as.Equal(http.StatusNoContent, res.Code)
+	as.Nil(as.DB.Reload(order))
+	as.Equal(models.OrderStatusDelivered.String(), order.Status)
+	count, err := as.DB.Count(&models.InvoiceLines{})
+	as.Nil(err)
+	as.Equal(0, count)
+
+	// The invoice number is reused once the invoice is deleted
+	invoice = as.createInvoice(firmino, efaLiv.ID, order)
+	as.Equal(1, invoice.Number)
+}
+
+func (as *ActionSuite) Test_InvoicesZeroChargeOrder() {
+	as.LoadFixture("Tenant bootstrap")
+	firmino := as.getLoggedInUser("firmino")
+	efaLiv := as.getCustomer("EFA Liv")
+	order := as.createOrder("free", models.OrderStatusDelivered, firmino.TenantID, firmino.ID, efaLiv.ID)
+	order.PickupCharges = models.NullMoney{}
+	order.DropoffCharges = models.NullMoney{}
+	as.Nil(as.DB.Update(order))
+
+	// An order without charges has no line but is billed on the invoice all the same
+	invoice := as.createInvoice(firmino, efaLiv.ID, order)
+	as.Equal(0, len(invoice.Lines))
+	as.Equal(1, len(invoice.Orders))
+	as.Nil(as.DB.Reload(order))
+	as.Equal(models.OrderStatusInvoiced.String(), order.Status)
+	res := as.setupRequest(firmino, "/invoices").Post(invoiceRequest{CustomerID: efaLiv.ID, OrderIDs: []uuid.UUID{order.ID}})
+	as.Equal(http.StatusConflict, res.Code, res.Body.String())
+
+	res = as.setupRequest(firmino, fmt.Sprintf("/invoices/%s/payments", invoice.ID)).Post(invoicePayment{Reference: "n/a"})
+	as.Equal(http.StatusOK, res.Code, res.Body.String())
+	as.Nil(as.DB.Reload(order))
+	as.Equal(models.OrderStatusPaymentReceived.String(), order.Status)
+}
@@ -0,0 +1,181 @@
+package actions
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/nulls"
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gofrs/uuid"
+)
+
+// Following naming logic is implemented in Buffalo:
+// Model: Singular (Location)
+// DB Table: Plural (locations)
+// Resource: Plural (Locations)
+// Path: Plural (/locations)
+
+// locationsList gets all Locations. Customers only get their own locations. This function is mapped to the path
+// GET /locations
+func locationsList(c buffalo.Context) error {
+	var loggedInUser = loggedInUser(c)
+	tx := c.Value("tx").(*pop.Connection)
+	locations := &models.Locations{}
+
+	// Paginate results. Params "page" and "per_page" control pagination.
+	// Default values are "page=1" and "per_page=20".
+	q := tx.PaginateFromParams(c.Params())
+
+	locationName := c.Param("name")
+	if locationName != "" {
+		if len(locationName) < 2 {
+			return c.Render(http.StatusOK, r.JSON(locations))
+		}
+		q = q.Where("name ILIKE ?", fmt.Sprintf("%%%s%%", locationName))
+	}
+	customerID := c.Param("customer_id")
+	if loggedInUser.IsCustomer() {
+		if !loggedInUser.CustomerID.Valid {
+			return c.Error(http.StatusNotFound, errors.New("invalid user"))
+		}
+		customerID = loggedInUser.CustomerID.UUID.String()
+	}
+	if customerID != "" {
+		q = q.Where("customer_id = ?", customerID)
+	}
+	if err := q.Scope(restrictedScope(c)).Order("name ASC").All(locations); err != nil {
+		return err
+	}
+	return c.Render(http.StatusOK, r.JSON(locations))
+}
+
+// locationsShow gets the data for one Location. This function is mapped to
+// the path GET /locations/{location_id}
+func locationsShow(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	location, err := findLocation(c, tx)
+	if err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	return c.Render(http.StatusOK, r.JSON(location))
+}
+
+// locationsCreate adds a Location to the DB. This function is mapped to the
+// path POST /locations
+func locationsCreate(c buffalo.Context) error {
+	var loggedInUser = loggedInUser(c)
+	location := &models.Location{}
+	if err := c.Bind(location); err != nil {
+		c.Logger().Errorf("error binding location: %v\n", err)
+		return err
+	}
+	tx := c.Value("tx").(*pop.Connection)
+	location.CreatedBy = loggedInUser.ID
+	location.TenantID = loggedInUser.TenantID
+	if err := checkLocationCustomerID(c, tx, loggedInUser, location.CustomerID); err != nil {
+		return c.Error(http.StatusBadRequest, err)
+	}
+	verrs, err := tx.ValidateAndCreate(location)
+	if err != nil {
+		return err
+	}
+	if verrs.HasAny() {
+		return c.Render(http.StatusUnprocessableEntity, r.JSON(verrs))
+	}
+	return c.Render(http.StatusCreated, r.JSON(location))
+}
+
+// locationsUpdate changes a Location in the DB. Shipments keep the origin and destination text they were saved with.
+// This function is mapped to the path PUT /locations/{location_id}
+func locationsUpdate(c buffalo.Context) error {
+	var loggedInUser = loggedInUser(c)
+	tx := c.Value("tx").(*pop.Connection)
+	location, err := findLocation(c, tx)
+	if err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	newLocation := &models.Location{}
+	if err := c.Bind(newLocation); err != nil {
+		c.Logger().Errorf("error binding location: %v\n", err)
+		return err
+	}
+	if newLocation.CustomerID != location.CustomerID {
+		if err := checkLocationCustomerID(c, tx, loggedInUser, newLocation.CustomerID); err != nil {
+			return c.Error(http.StatusBadRequest, err)
+		}
+	}
+	newLocation.ID = location.ID
+	newLocation.CreatedAt = location.CreatedAt
+	newLocation.CreatedBy = location.CreatedBy
+	newLocation.TenantID = location.TenantID
+	newLocation.UpdatedAt = time.Now().UTC()
+	verrs, err := tx.ValidateAndUpdate(newLocation)
+	if err != nil {
+		return err
+	}
+	if verrs.HasAny() {
+		return c.Render(http.StatusUnprocessableEntity, r.JSON(verrs))
+	}
+	return c.Render(http.StatusOK, r.JSON(newLocation))
+}
+
+// locationsDestroy deletes a Location from the DB, shipments keep their origin and destination text.
+// This function is mapped to the path DELETE /locations/{location_id}
+func locationsDestroy(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	location, err := findLocation(c, tx)
+	if err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	if err := tx.Destroy(location); err != nil {
+		return err
+	}
+	c.Response().WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// findLocation gets the Location of the path param "location_id", customers only find their own locations
+func findLocation(c buffalo.Context, tx *pop.Connection) (*models.Location, error) {
+	var loggedInUser = loggedInUser(c)
+	location := &models.Location{}
+	q := tx.Scope(restrictedScope(c))
+	if loggedInUser.IsCustomer() {
+		q = q.Where("customer_id = ?", loggedInUser.CustomerID)
+	}
+	if err := q.Find(location, c.Param("location_id")); err != nil {
+		return nil, err
+	}
+	return location, nil
+}
+
+// checkLocationCustomerID ensures the customer of a location belongs to the tenant
+func checkLocationCustomerID(c buffalo.Context, tx *pop.Connection, loggedInUser *models.User, ID nulls.UUID) error {
+	if !ID.Valid {
+		return nil
+	}
+	customer := &models.Customer{}
+	// Customer must belong to the same tenant
+	err := tx.Scope(restrictedScope(c)).Where("tenant_id = ?", loggedInUser.TenantID).Find(customer, ID.UUID)
+	if err != nil || customer.ID == uuid.Nil {
+		return errors.New("invalid customer association")
+	}
+	return nil
+}
+
+// checkLocationID ensures a location belongs to the tenant and returns it, nil when there is no location
+func checkLocationID(c buffalo.Context, tx *pop.Connection, ID nulls.UUID) (*models.Location, error) {
+	if !ID.Valid {
+		return nil, nil
+	}
+	location := &models.Location{}
+	// Location must belong to the same tenant
+	err := tx.Scope(restrictedScope(c)).Find(location, ID.UUID)
+	if err != nil || location.ID == uuid.Nil {
+		return nil, errors.New("invalid location association")
+	}
+	return location, nil
+}
@@ -0,0 +1,144 @@
+package actions
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/nulls"
+)
+
+func (as *ActionSuite) createLocation(user *models.User, location models.Location) *models.Location {
+	res := as.setupRequest(user, "/locations").Post(location)
+	as.Equal(http.StatusCreated, res.Code, res.Body.String())
+	var created = &models.Location{}
+	res.Bind(created)
+	return created
+}
+
+func (as *ActionSuite) Test_LocationsCreate() {
+	as.LoadFixture("Tenant bootstrap")
+	efaLiv := as.getCustomer("EFA Liv")
+	var tests = []struct {
+		username     string
+		customerID   nulls.UUID
+		responseCode int
+	}{
+		{"firmino", nulls.UUID{}, http.StatusCreated},
+		{"mane", nulls.NewUUID(efaLiv.ID), http.StatusCreated},
+		{"rodriguez", nulls.NewUUID(efaLiv.ID), http.StatusBadRequest},
+		{"salah", nulls.UUID{}, http.StatusNotFound},
+		{"nike", nulls.UUID{}, http.StatusNotFound},
+		{"coutinho", nulls.UUID{}, http.StatusNotFound},
+	}
+	for _, test := range tests {
+		as.T().Run(test.username, func(t *testing.T) {
+			user := as.getLoggedInUser(test.username)
+			newLocation := models.Location{Name: "Yard " + user.Username, CustomerID: test.customerID, City: nulls.NewString("Delta"),
+				Latitude: nulls.NewFloat64(49.0833), Longitude: nulls.NewFloat64(-123.0667)}
+			res := as.setupRequest(user, "/locations").Post(newLocation)
+			as.Equal(test.responseCode, res.Code, res.Body.String())
+			if res.Code == http.StatusCreated {
+				var location = models.Location{}
+				res.Bind(&location)
+				as.Equal(newLocation.Name, location.Name)
+				as.Equal(newLocation.Latitude, location.Latitude)
+				as.Equal(user.TenantID, location.TenantID)
+			}
+		})
+	}
+	res := as.setupRequest(as.getLoggedInUser("mane"), "/locations").Post(models.Location{Name: "Nowhere", Latitude: nulls.NewFloat64(49.0833)})
+	as.Equal(http.StatusUnprocessableEntity, res.Code)
+}
+
+func (as *ActionSuite) Test_LocationsList() {
+	as.LoadFixture("Tenant bootstrap")
+	mane := as.getLoggedInUser("mane")
+	efaLiv := as.getCustomer("EFA Liv")
+	as.createLocation(mane, models.Location{Name: "Annacis Yard"})
+	site := as.createLocation(mane, models.Location{Name: "EFA Warehouse", CustomerID: nulls.NewUUID(efaLiv.ID)})
+	var tests = []struct {
+		username string
+		query    string
+		count    int
+	}{
+		{"mane", "", 2},
+		{"mane", "?name=annacis", 1},
+		{"mane", fmt.Sprintf("?customer_id=%s", efaLiv.ID), 1},
+		{"salah", "", 2},
+		{"nike", "", 1},
+		{"rodriguez", "", 0},
+	}
+	for _, test := range tests {
+		as.T().Run(test.username+test.query, func(t *testing.T) {
+			res := as.setupRequest(as.getLoggedInUser(test.username), "/locations"+test.query).Get()
+			as.Equal(http.StatusOK, res.Code)
+			var locations = models.Locations{}
+			res.Bind(&locations)
+			as.Len(locations, test.count)
+		})
+	}
+	yard := as.createLocation(mane, models.Location{Name: "Tilbury Yard"})
+	res := as.setupRequest(as.getLoggedInUser("nike"), fmt.Sprintf("/locations/%s", yard.ID)).Get()
+	as.Equal(http.StatusNotFound, res.Code)
+	res = as.setupRequest(as.getLoggedInUser("nike"), fmt.Sprintf("/locations/%s", site.ID)).Get()
+	as.Equal(http.StatusOK, res.Code)
+}
+
+func (as *ActionSuite) Test_LocationsUpdateDestroy() {
+	as.LoadFixture("Tenant bootstrap")
+	mane := as.getLoggedInUser("mane")
+	location := as.createLocation(mane, models.Location{Name: "Annacis Yard"})
+	location.Latitude = nulls.NewFloat64(49.1713)
+	location.Longitude = nulls.NewFloat64(-122.9561)
+	location.GeofencePolygon = models.GeoPolygon{{Lat: 49.17, Lng: -122.96}, {Lat: 49.17, Lng: -122.95}, {Lat: 49.18, Lng: -122.95}}
+	res := as.setupRequest(mane, fmt.Sprintf("/locations/%s", location.ID)).Put(location)
+	as.Equal(http.StatusOK, res.Code, res.Body.String())
+	var updated = models.Location{}
+	res.Bind(&updated)
+	as.Equal(location.Latitude, updated.Latitude)
+	res = as.setupRequest(mane, fmt.Sprintf("/locations/%s", location.ID)).Get()
+	res.Bind(&updated)
+	as.Equal(location.GeofencePolygon, updated.GeofencePolygon)
+	res = as.setupRequest(as.getLoggedInUser("rodriguez"), fmt.Sprintf("/locations/%s", location.ID)).Put(location)
+	as.Equal(http.StatusNotFound, res.Code)
+	res = as.setupRequest(as.getLoggedInUser("salah"), fmt.Sprintf("/locations/%s", location.ID)).Delete()
+	as.Equal(http.StatusNotFound, res.Code)
+	res = as.setupRequest(mane, fmt.Sprintf("/locations/%s", location.ID)).Delete()
+	as.Equal(http.StatusNoContent, res.Code)
+}
+
+func (as *ActionSuite) Test_ShipmentsLocations() {
+	as.LoadFixture("Tenant bootstrap")
+	mane := as.getLoggedInUser("mane")
+	efaLiv := as.getCustomer("EFA Liv")
+	order := as.createOrder("ord1", models.OrderStatusOpen, mane.TenantID, mane.ID, efaLiv.ID)
+	yard := as.createLocation(mane, models.Location{Name: "Annacis Yard"})
+	site := as.createLocation(mane, models.Location{Name: "EFA Warehouse", CustomerID: nulls.NewUUID(efaLiv.ID)})
+	other := as.createLocation(as.getLoggedInUser("rodriguez"), models.Location{Name: "Goodison"})
+
+	newShipment := models.Shipment{SerialNumber: "s1", Type: models.ShipmentTypeInbound.String(), OrderID: nulls.NewUUID(order.ID),
+		OriginLocationID: nulls.NewUUID(yard.ID), DestinationLocationID: nulls.NewUUID(site.ID), Destination: nulls.NewString("Dock 4")}
+	res := as.setupRequest(mane, "/shipments").Post(newShipment)
+	as.Equal(http.StatusCreated, res.Code, res.Body.String())
+	var shipment = models.Shipment{}
+	res.Bind(&shipment)
+	as.Equal(nulls.NewUUID(yard.ID), shipment.OriginLocationID)
+	as.Equal("Annacis Yard", shipment.Origin.String)
+	as.Equal("Dock 4", shipment.Destination.String)
+
+	shipment.DestinationLocationID = nulls.NewUUID(other.ID)
+	res = as.setupRequest(mane, fmt.Sprintf("/shipments/%s", shipment.ID)).Put(shipment)
+	as.Equal(http.StatusBadRequest, res.Code)
+	shipment.DestinationLocationID = nulls.UUID{}
+	res = as.setupRequest(mane, fmt.Sprintf("/shipments/%s", shipment.ID)).Put(shipment)
+	as.Equal(http.StatusOK, res.Code, res.Body.String())
+	res.Bind(&shipment)
+	as.False(shipment.DestinationLocationID.Valid)
+
+	newShipment.SerialNumber = "s2"
+	newShipment.OriginLocationID = nulls.NewUUID(other.ID)
+	res = as.setupRequest(mane, "/shipments").Post(newShipment)
+	as.Equal(http.StatusBadRequest, res.Code)
+}
@@ -0,0 +1,208 @@
+package actions
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bigpanther/trober/firebase"
+	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/buffalo/worker"
+	"github.com/gobuffalo/envy"
+	"github.com/gobuffalo/nulls"
+	"github.com/gobuffalo/pop/v6"
+)
+
+// Following naming logic is implemented in Buffalo:
+// Model: Singular (MaintenanceItem)
+// DB Table: Plural (maintenance_items)
+// Resource: Plural (MaintenanceItems)
+// Path: Plural (/equipment/{equipment_id}/maintenance)
+
+const maintenanceAlertsJob = "maintenanceAlerts"
+
+const defaultMaintenanceDueWindow = 30 * 24 * time.Hour
+
+// maintenanceItemsList gets the maintenance log of an Equipment, by due date. Param "open=true" only gets the items
+// not completed. This function is mapped to the path GET /equipment/{equipment_id}/maintenance
+func maintenanceItemsList(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	equipment := &models.Equipment{}
+	if err := tx.Scope(restrictedScope(c)).Find(equipment, c.Param("equipment_id")); err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	items := &models.MaintenanceItems{}
+	q := tx.Where("equipment_id = ?", equipment.ID)
+	if c.Param("open") == "true" {
+		q = q.Where("completed_at IS NULL")
+	}
+	if err := q.Order("due_date ASC").All(items); err != nil {
+		return err
+	}
+	return c.Render(http.StatusOK, r.JSON(items))
+}
+
+// maintenanceItemsDue gets the maintenance items of all equipment that are not completed and due within a duration,
+// 30 days by default, including the ones past due. This function is mapped to the path GET /equipment/maintenance-due
+func maintenanceItemsDue(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	within := defaultMaintenanceDueWindow
+	if w := c.Param("within"); w != "" {
+		d, err := time.ParseDuration(w)
+		if err != nil {
+			return c.Error(http.StatusBadRequest, fmt.Errorf("invalid within duration %q", w))
+		}
+		within = d
+	}
+	items := &models.MaintenanceItems{}
+	if err := tx.Eager("Equipment").Scope(restrictedScope(c)).Where("completed_at IS NULL").
+		Where("due_date <= ?", time.Now().UTC().Add(within)).Order("due_date ASC").All(items); err != nil {
+		return err
+	}
+	return c.Render(http.StatusOK, r.JSON(items))
+}
+
+// maintenanceItemsCreate adds a MaintenanceItem to the log of an Equipment. This function is mapped to the
+// path POST /equipment/{equipment_id}/maintenance
+func maintenanceItemsCreate(c buffalo.Context) error {
+	var loggedInUser = loggedInUser(c)
+	tx := c.Value("tx").(*pop.Connection)
+	equipment := &models.Equipment{}
+	if err := tx.Scope(restrictedScope(c)).Find(equipment, c.Param("equipment_id")); err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	item := &models.MaintenanceItem{}
+	if err := c.Bind(item); err != nil {
+		c.Logger().Errorf("error binding maintenance item: %v\n", err)
+		return err
+	}
+	item.CreatedBy = loggedInUser.ID
+	item.TenantID = equipment.TenantID
+	item.EquipmentID = equipment.ID
+	item.LastAlertedDays = nulls.Int{}
+	verrs, err := tx.ValidateAndCreate(item)
+	if err != nil {
+		return err
+	}
+	if verrs.HasAny() {
+		return c.Render(http.StatusUnprocessableEntity, r.JSON(verrs))
+	}
+	return c.Render(http.StatusCreated, r.JSON(item))
+}
+
+// maintenanceItemsUpdate changes a MaintenanceItem, e.g. to complete it. This function is mapped to the
+// path PUT /equipment/{equipment_id}/maintenance/{maintenance_item_id}
+func maintenanceItemsUpdate(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	item, err := findMaintenanceItem(c, tx)
+	if err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	newItem := &models.MaintenanceItem{}
+	if err := c.Bind(newItem); err != nil {
+		c.Logger().Errorf("error binding maintenance item: %v\n", err)
+		return err
+	}
+	newItem.ID = item.ID
+	newItem.CreatedAt = item.CreatedAt
+	newItem.CreatedBy = item.CreatedBy
+	newItem.TenantID = item.TenantID
+	newItem.EquipmentID = item.EquipmentID
+	newItem.LastAlertedDays = item.LastAlertedDays
+	newItem.UpdatedAt = time.Now().UTC()
+	verrs, err := tx.ValidateAndUpdate(newItem)
+	if err != nil {
+		return err
+	}
+	if verrs.HasAny() {
+		return c.Render(http.StatusUnprocessableEntity, r.JSON(verrs))
+	}
+	return c.Render(http.StatusOK, r.JSON(newItem))
+}
+
+// maintenanceItemsDestroy deletes a MaintenanceItem from the log. This function is mapped to the
+// path DELETE /equipment/{equipment_id}/maintenance/{maintenance_item_id}
+func maintenanceItemsDestroy(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	item, err := findMaintenanceItem(c, tx)
+	if err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	if err := tx.Destroy(item); err != nil {
+		return err
+	}
+	c.Response().WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// findMaintenanceItem gets the MaintenanceItem of the path param "maintenance_item_id" of the Equipment of the path
+func findMaintenanceItem(c buffalo.Context, tx *pop.Connection) (*models.MaintenanceItem, error) {
+	item := &models.MaintenanceItem{}
+	if err := tx.Scope(restrictedScope(c)).Where("equipment_id = ?", c.Param("equipment_id")).
+		Find(item, c.Param("maintenance_item_id")); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// maintenanceAlertInterval reads how often the maintenance items coming due are looked for
+func maintenanceAlertInterval() (time.Duration, error) {
+	interval, err := time.ParseDuration(envy.Get("MAINTENANCE_ALERT_INTERVAL", "1h"))
+	if err != nil || interval <= 0 {
+		return 0, errors.New("invalid MAINTENANCE_ALERT_INTERVAL")
+	}
+	return interval, nil
+}
+
+func scheduleMaintenanceAlerts(interval time.Duration) {
+	if err := app.Worker.PerformIn(worker.Job{Queue: "default", Handler: maintenanceAlertsJob}, interval); err != nil {
+		app.Logger.Errorf("error scheduling maintenance alerts: %v", err)
+	}
+}
+
+// maintenanceAlerts notifies the back office of every tenant about the maintenance items that came within
+// 30, 7 or 1 days of their due date and were not alerted for it yet, and schedules the next run.
+func maintenanceAlerts(args worker.Args) error {
+	interval, err := maintenanceAlertInterval()
+	if err != nil {
+		return err
+	}
+	defer scheduleMaintenanceAlerts(interval)
+	var now = time.Now().UTC()
+	var within = time.Duration(models.MaintenanceAlertDays[len(models.MaintenanceAlertDays)-1]) * 24 * time.Hour
+	items := models.MaintenanceItems{}
+	if err := models.DB.Eager("Equipment").Where("completed_at IS NULL").Where("due_date > ?", now).
+		Where("due_date <= ?", now.Add(within)).All(&items); err != nil {
+		return err
+	}
+	for _, m := range items {
+		days, ok := m.DueAlertDays(now)
+		if !ok || m.Equipment == nil {
+			continue
+		}
+		m.LastAlertedDays = nulls.NewInt(days)
+		if err := models.DB.UpdateColumns(&m, "last_alerted_days"); err != nil {
+			return err
+		}
+		due := fmt.Sprintf("due in %d days", days)
+		if days == 1 {
+			due = "due tomorrow"
+		}
+		sendNotificationsAsync(
+			models.NotificationEventMaintenanceDue,
+			[]string{firebase.GetBackOfficeTopic(&models.User{TenantID: m.TenantID})},
+			fmt.Sprintf("%s %s - %s", m.Type, due, m.Equipment.UnitNumber),
+			m.Equipment.UnitNumber,
+			map[string]string{
+				"equipment.id":            m.EquipmentID.String(),
+				"equipment.unitNumber":    m.Equipment.UnitNumber,
+				"maintenanceItem.id":      m.ID.String(),
+				"maintenanceItem.type":    m.Type,
+				"maintenanceItem.dueDate": m.DueDate.Format(time.RFC3339),
+			},
+		)
+	}
+	return nil
+}
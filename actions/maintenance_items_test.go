@@ -0,0 +1,176 @@
+package actions
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/buffalo/worker"
+	"github.com/gobuffalo/nulls"
+	"github.com/golang/mock/gomock"
+)
+
+func (as *ActionSuite) createMaintenanceItem(user *models.User, equipment *models.Equipment, itemType models.MaintenanceItemType, dueDate time.Time) *models.MaintenanceItem {
+	res := as.setupRequest(user, fmt.Sprintf("/equipment/%s/maintenance", equipment.ID)).Post(models.MaintenanceItem{Type: itemType.String(), DueDate: dueDate})
+	as.Equal(http.StatusCreated, res.Code, res.Body.String())
+	var created = &models.MaintenanceItem{}
+	res.Bind(created)
+	return created
+}
+
+func (as *ActionSuite) Test_MaintenanceItems() {
+	as.LoadFixture("Tenant bootstrap")
+	mane := as.getLoggedInUser("mane")
+	tractor := as.createEquipment(mane, models.EquipmentTypeTractor, "T100")
+	now := time.Now().UTC()
+	var tests = []struct {
+		username     string
+		item         models.MaintenanceItem
+		responseCode int
+	}{
+		{"firmino", models.MaintenanceItem{Type: "Inspection", DueDate: now.AddDate(1, 0, 0)}, http.StatusCreated},
+		{"mane", models.MaintenanceItem{Type: "Registration", DueDate: now.AddDate(0, 0, 10)}, http.StatusCreated},
+		{"mane", models.MaintenanceItem{Type: "WorkOrder", DueDate: now.AddDate(0, 0, -1), Description: nulls.NewString("Brakes")}, http.StatusCreated},
+		{"mane", models.MaintenanceItem{Type: "Oil", DueDate: now}, http.StatusUnprocessableEntity},
+		{"rodriguez", models.MaintenanceItem{Type: "Inspection", DueDate: now}, http.StatusNotFound},
+		{"salah", models.MaintenanceItem{Type: "Inspection", DueDate: now}, http.StatusNotFound},
+		{"nike", models.MaintenanceItem{Type: "Inspection", DueDate: now}, http.StatusNotFound},
+	}
+	for i, test := range tests {
+		as.T().Run(fmt.Sprint(i), func(t *testing.T) {
+			res := as.setupRequest(as.getLoggedInUser(test.username), fmt.Sprintf("/equipment/%s/maintenance", tractor.ID)).Post(test.item)
+			as.Equal(test.responseCode, res.Code, res.Body.String())
+			if res.Code == http.StatusCreated {
+				var item = models.MaintenanceItem{}
+				res.Bind(&item)
+				as.Equal(tractor.ID, item.EquipmentID)
+				as.Equal(tractor.TenantID, item.TenantID)
+			}
+		})
+	}
+	res := as.setupRequest(mane, fmt.Sprintf("/equipment/%s/maintenance", tractor.ID)).Get()
+	as.Equal(http.StatusOK, res.Code)
+	var items = models.MaintenanceItems{}
+	res.Bind(&items)
+	as.Equal(3, len(items))
+	as.Equal(models.MaintenanceItemTypeWorkOrder.String(), items[0].Type)
+
+	workOrder := items[0]
+	workOrder.CompletedAt = nulls.NewTime(now)
+	res = as.setupRequest(as.getLoggedInUser("rodriguez"), fmt.Sprintf("/equipment/%s/maintenance/%s", tractor.ID, workOrder.ID)).Put(workOrder)
+	as.Equal(http.StatusNotFound, res.Code)
+	res = as.setupRequest(mane, fmt.Sprintf("/equipment/%s/maintenance/%s", tractor.ID, workOrder.ID)).Put(workOrder)
+	as.Equal(http.StatusOK, res.Code, res.Body.String())
+	res = as.setupRequest(mane, fmt.Sprintf("/equipment/%s/maintenance?open=true", tractor.ID)).Get()
+	res.Bind(&items)
+	as.Equal(2, len(items))
+
+	var dueTests = []struct {
+		username     string
+		within       string
+		responseCode int
+		count        int
+	}{
+		{"mane", "", http.StatusOK, 1},
+		{"mane", "8760h", http.StatusOK, 2},
+		{"mane", "soon", http.StatusBadRequest, 0},
+		{"rodriguez", "", http.StatusOK, 0},
+		{"salah", "", http.StatusNotFound, 0},
+	}
+	for _, test := range dueTests {
+		as.T().Run(fmt.Sprintf("%s-%s", test.username, test.within), func(t *testing.T) {
+			res := as.setupRequest(as.getLoggedInUser(test.username), "/equipment/maintenance-due?within="+test.within).Get()
+			as.Equal(test.responseCode, res.Code)
+			var due = models.MaintenanceItems{}
+			res.Bind(&due)
+			as.Equal(test.count, len(due))
+			if test.count > 0 {
+				as.Equal(tractor.UnitNumber, due[0].Equipment.UnitNumber)
+			}
+		})
+	}
+
+	res = as.setupRequest(mane, fmt.Sprintf("/equipment/%s/maintenance/%s", tractor.ID, workOrder.ID)).Delete()
+	as.Equal(http.StatusNoContent, res.Code)
+	res = as.setupRequest(mane, fmt.Sprintf("/equipment/%s/maintenance/%s", tractor.ID, workOrder.ID)).Delete()
+	as.Equal(http.StatusNotFound, res.Code)
+}
+
+func (as *ActionSuite) Test_ShipmentsEquipmentOutOfService() {
+	as.LoadFixture("Tenant bootstrap")
+	mockFirebase.EXPECT().SendAll(gomock.Any(), gomock.Any()).AnyTimes()
+	mane := as.getLoggedInUser("mane")
+	salah := as.getLoggedInUser("salah")
+	efaLiv := as.getCustomer("EFA Liv")
+	order := as.createOrder("oos", models.OrderStatusOpen, mane.TenantID, mane.ID, efaLiv.ID)
+	tractor := as.createEquipment(mane, models.EquipmentTypeTractor, "T100")
+	chassis := as.createEquipment(mane, models.EquipmentTypeChassis, "C100")
+	inspection := as.createMaintenanceItem(mane, chassis, models.MaintenanceItemTypeInspection, time.Now().UTC().AddDate(0, 0, -1))
+	// A work order past due does not stop the tractor
+	as.createMaintenanceItem(mane, tractor, models.MaintenanceItemTypeWorkOrder, time.Now().UTC().AddDate(0, 0, -1))
+
+	var newShipment = func(serial string, driverID nulls.UUID) models.Shipment {
+		return models.Shipment{SerialNumber: serial, Type: models.ShipmentTypeInbound.String(), OrderID: nulls.NewUUID(order.ID),
+			TractorID: nulls.NewUUID(tractor.ID), ChassisID: nulls.NewUUID(chassis.ID), DriverID: driverID}
+	}
+	res := as.setupRequest(mane, "/shipments").Post(newShipment("expired", nulls.NewUUID(salah.ID)))
+	as.Equal(http.StatusConflict, res.Code, res.Body.String())
+	as.Contains(res.Body.String(), "inspection was due")
+	res = as.setupRequest(mane, "/shipments").Post(newShipment("unassigned", nulls.UUID{}))
+	as.Equal(http.StatusCreated, res.Code, res.Body.String())
+	var unassigned = &models.Shipment{}
+	res.Bind(unassigned)
+
+	// Auto assign leaves the shipment for later
+	res = as.setupRequest(mane, "/shipments/auto-assign").Post(autoAssignRequest{DryRun: true})
+	as.Equal(http.StatusOK, res.Code)
+	var result = autoAssignResult{}
+	res.Bind(&result)
+	as.Equal(0, len(result.Assignments))
+	as.Equal(1, len(result.Unassigned))
+
+	inspection.CompletedAt = nulls.NewTime(time.Now().UTC())
+	res = as.setupRequest(mane, fmt.Sprintf("/equipment/%s/maintenance/%s", chassis.ID, inspection.ID)).Put(inspection)
+	as.Equal(http.StatusOK, res.Code, res.Body.String())
+	tractor.Status = models.EquipmentStatusOutOfService.String()
+	res = as.setupRequest(mane, fmt.Sprintf("/equipment/%s", tractor.ID)).Put(tractor)
+	as.Equal(http.StatusOK, res.Code, res.Body.String())
+
+	unassigned.DriverID = nulls.NewUUID(salah.ID)
+	unassigned.Status = models.ShipmentStatusAssigned.String()
+	res = as.setupRequest(mane, fmt.Sprintf("/shipments/%s", unassigned.ID)).Put(unassigned)
+	as.Equal(http.StatusConflict, res.Code, res.Body.String())
+	as.Contains(res.Body.String(), "tractor T100 is OutOfService")
+
+	tractor.Status = models.EquipmentStatusActive.String()
+	res = as.setupRequest(mane, fmt.Sprintf("/equipment/%s", tractor.ID)).Put(tractor)
+	as.Equal(http.StatusOK, res.Code, res.Body.String())
+	res = as.setupRequest(mane, fmt.Sprintf("/shipments/%s", unassigned.ID)).Put(unassigned)
+	as.Equal(http.StatusOK, res.Code, res.Body.String())
+}
+
+func (as *ActionSuite) Test_MaintenanceAlerts() {
+	as.LoadFixture("Tenant bootstrap")
+	mane := as.getLoggedInUser("mane")
+	tractor := as.createEquipment(mane, models.EquipmentTypeTractor, "T100")
+	item := as.createMaintenanceItem(mane, tractor, models.MaintenanceItemTypeRegistration, time.Now().UTC().AddDate(0, 0, 7))
+	mockFirebase.EXPECT().SendAll(gomock.Any(), gomock.Any()).AnyTimes()
+	as.Nil(maintenanceAlerts(worker.Args{}))
+	as.Nil(as.DB.Reload(item))
+	as.Equal(nulls.NewInt(7), item.LastAlertedDays)
+	// The days are not alerted again, the next ones are once they are reached
+	as.Nil(maintenanceAlerts(worker.Args{}))
+	as.Nil(as.DB.Reload(item))
+	as.Equal(nulls.NewInt(7), item.LastAlertedDays)
+	item.DueDate = time.Now().UTC().AddDate(0, 0, 1)
+	as.Nil(as.DB.Update(item))
+	as.Nil(maintenanceAlerts(worker.Args{}))
+	as.Nil(as.DB.Reload(item))
+	as.Equal(nulls.NewInt(1), item.LastAlertedDays)
+
+	interval, err := maintenanceAlertInterval()
+	as.Nil(err)
+	as.Equal(time.Hour, interval)
+}
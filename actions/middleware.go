@@ -56,6 +56,15 @@ func requireSuperAdminUser(next buffalo.Handler) buffalo.Handler {
 		return next(c)
 	}
 }
+func requireAdminUser(next buffalo.Handler) buffalo.Handler {
+	return func(c buffalo.Context) error {
+		var loggedInUser = loggedInUser(c)
+		if !loggedInUser.IsAdmin() {
+			return c.Render(http.StatusNotFound, r.JSON(models.NewCustomError(http.StatusText(http.StatusNotFound), fmt.Sprint(http.StatusNotFound), errNotFound)))
+		}
+		return next(c)
+	}
+}
 func requireAtLeastBackOfficeUser(next buffalo.Handler) buffalo.Handler {
 	return func(c buffalo.Context) error {
 		var loggedInUser = loggedInUser(c)
@@ -83,3 +92,12 @@ func requireAtLeastDriverUser(next buffalo.Handler) buffalo.Handler {
 		return next(c)
 	}
 }
+func requireDriverUser(next buffalo.Handler) buffalo.Handler {
+	return func(c buffalo.Context) error {
+		var loggedInUser = loggedInUser(c)
+		if !loggedInUser.IsDriver() {
+			return c.Render(http.StatusNotFound, r.JSON(models.NewCustomError(http.StatusText(http.StatusNotFound), fmt.Sprint(http.StatusNotFound), errNotFound)))
+		}
+		return next(c)
+	}
+}
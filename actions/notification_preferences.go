@@ -0,0 +1,72 @@
+package actions
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gobuffalo/validate/v3"
+)
+
+// Following naming logic is implemented in Buffalo:
+// Model: Singular (NotificationPreference)
+// DB Table: Plural (notification_preferences)
+// Resource: Plural (NotificationPreferences)
+// Path: Plural (/self/notification-preferences)
+
+// selfNotificationPreferencesList gets the channel of every event for the logged in user, push for the events without
+// a preference. This function is mapped to the path GET /self/notification-preferences
+func selfNotificationPreferencesList(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	preferences := models.NotificationPreferences{}
+	if err := tx.Where("user_id = ?", loggedInUser(c).ID).All(&preferences); err != nil {
+		return err
+	}
+	return c.Render(http.StatusOK, r.JSON(preferences.Settings()))
+}
+
+// selfNotificationPreferencesUpdate sets the channel of the given events for the logged in user, the other events
+// keep theirs. This function is mapped to the path PUT /self/notification-preferences
+func selfNotificationPreferencesUpdate(c buffalo.Context) error {
+	var loggedInUser = loggedInUser(c)
+	settings := models.NotificationSettings{}
+	if err := c.Bind(&settings); err != nil {
+		c.Logger().Errorf("error binding notification preferences: %v\n", err)
+		return err
+	}
+	tx := c.Value("tx").(*pop.Connection)
+	preferences := models.NotificationPreferences{}
+	if err := tx.Where("user_id = ?", loggedInUser.ID).All(&preferences); err != nil {
+		return err
+	}
+	for _, setting := range settings {
+		var preference *models.NotificationPreference
+		for i := range preferences {
+			if preferences[i].Event == setting.Event {
+				preference = &preferences[i]
+			}
+		}
+		var verrs *validate.Errors
+		var err error
+		if preference == nil {
+			preference = &models.NotificationPreference{TenantID: loggedInUser.TenantID, UserID: loggedInUser.ID,
+				Event: setting.Event, Channel: setting.Channel}
+			if verrs, err = tx.ValidateAndCreate(preference); err == nil && !verrs.HasAny() {
+				preferences = append(preferences, *preference)
+			}
+		} else {
+			preference.Channel = setting.Channel
+			preference.UpdatedAt = time.Now().UTC()
+			verrs, err = tx.ValidateAndUpdate(preference)
+		}
+		if err != nil {
+			return err
+		}
+		if verrs.HasAny() {
+			return c.Render(http.StatusUnprocessableEntity, r.JSON(verrs))
+		}
+	}
+	return c.Render(http.StatusOK, r.JSON(preferences.Settings()))
+}
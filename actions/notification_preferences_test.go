@@ -0,0 +1,132 @@
+package actions
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/bigpanther/trober/firebase"
+	"github.com/bigpanther/trober/models"
+	"github.com/gofrs/uuid"
+)
+
+func (as *ActionSuite) Test_SelfNotificationPreferences() {
+	as.LoadFixture("Tenant bootstrap")
+	mane := as.getLoggedInUser("mane")
+	salah := as.getLoggedInUser("salah")
+	res := as.setupRequest(mane, "/self/notification-preferences").Get()
+	as.Equal(http.StatusOK, res.Code, res.Body.String())
+	var settings = models.NotificationSettings{}
+	res.Bind(&settings)
+	as.Equal(10, len(settings))
+	for _, s := range settings {
+		as.Equal(models.NotificationChannelPush.String(), s.Channel)
+	}
+
+	res = as.setupRequest(mane, "/self/notification-preferences").Put(models.NotificationSettings{
+		{Event: models.NotificationEventShipmentUpdated.String(), Channel: models.NotificationChannelNone.String()},
+		{Event: models.NotificationEventLfdWarning.String(), Channel: models.NotificationChannelEmail.String()},
+	})
+	as.Equal(http.StatusOK, res.Code, res.Body.String())
+	res = as.setupRequest(mane, "/self/notification-preferences").Put(models.NotificationSettings{
+		{Event: models.NotificationEventShipmentUpdated.String(), Channel: models.NotificationChannelPush.String()},
+	})
+	as.Equal(http.StatusOK, res.Code, res.Body.String())
+	settings = models.NotificationSettings{}
+	res.Bind(&settings)
+	for _, s := range settings {
+		expected := models.NotificationChannelPush.String()
+		if s.Event == models.NotificationEventLfdWarning.String() {
+			expected = models.NotificationChannelEmail.String()
+		}
+		as.Equal(expected, s.Channel, s.Event)
+	}
+	count, err := as.DB.Where("user_id = ?", mane.ID).Count(&models.NotificationPreference{})
+	as.Nil(err)
+	as.Equal(2, count)
+
+	res = as.setupRequest(mane, "/self/notification-preferences").Put(models.NotificationSettings{
+		{Event: models.NotificationEventLfdWarning.String(), Channel: "Fax"},
+	})
+	as.Equal(http.StatusUnprocessableEntity, res.Code, res.Body.String())
+	res = as.setupRequest(mane, "/self/notification-preferences").Put(models.NotificationSettings{
+		{Event: "Anything", Channel: models.NotificationChannelPush.String()},
+	})
+	as.Equal(http.StatusUnprocessableEntity, res.Code, res.Body.String())
+
+	// Preferences are per user
+	res = as.setupRequest(salah, "/self/notification-preferences").Get()
+	as.Equal(http.StatusOK, res.Code, res.Body.String())
+	settings = models.NotificationSettings{}
+	res.Bind(&settings)
+	for _, s := range settings {
+		as.Equal(models.NotificationChannelPush.String(), s.Channel)
+	}
+}
+
+func (as *ActionSuite) Test_NotificationRouting() {
+	as.LoadFixture("Tenant bootstrap")
+	klopp := as.getLoggedInUser("klopp")
+	firmino := as.getLoggedInUser("firmino")
+	mane := as.getLoggedInUser("mane")
+	salah := as.getLoggedInUser("salah")
+	nike := as.getLoggedInUser("nike")
+
+	recipients, err := notificationRecipients(as.DB, []string{
+		firebase.GetSuperAdminTopic(),
+		firebase.GetAdminTopic(firmino),
+		firebase.GetBackOfficeTopic(mane),
+		firebase.GetCustomerTopic(nike.TenantID.String(), nike.CustomerID.UUID.String()),
+		firebase.GetDriverTopic(salah.TenantID.String(), salah.ID.String()),
+		firebase.GetTopic(salah),
+		"unknown",
+	})
+	as.Nil(err)
+	var names = []string{}
+	for _, u := range recipients {
+		names = append(names, u.Username)
+	}
+	as.Equal([]string{"klopp", "firmino", "mane", "nike", "salah"}, names)
+
+	var channels = map[uuid.UUID]models.NotificationChannel{
+		klopp.ID:   models.NotificationChannelPush,
+		firmino.ID: models.NotificationChannelNone,
+		mane.ID:    models.NotificationChannelEmail,
+		nike.ID:    models.NotificationChannelPush,
+		salah.ID:   models.NotificationChannelPush,
+	}
+	pushTopics, emails := routeNotification(recipients, channels, false)
+	as.Equal([]string{firebase.GetTopic(klopp), firebase.GetTopic(nike), firebase.GetTopic(salah)}, pushTopics)
+	as.Equal([]string{mane.Email}, emails)
+	// Devices that have not registered again still listen on the topics of the roles
+	pushTopics, emails = routeNotification(recipients, channels, true)
+	as.Equal([]string{
+		firebase.GetTopic(klopp), firebase.GetSuperAdminTopic(),
+		firebase.GetTopic(nike), firebase.GetCustomerTopic(nike.TenantID.String(), nike.CustomerID.UUID.String()),
+		firebase.GetTopic(salah), firebase.GetDriverTopic(salah.TenantID.String(), salah.ID.String()),
+	}, pushTopics)
+	as.Equal([]string{mane.Email}, emails)
+	// The topic of a role is left out when one of its users opted out of push
+	rodriguez := *mane
+	rodriguez.ID, rodriguez.Email = uuid.Must(uuid.NewV4()), "rodriguez@bigpanther.ca"
+	channels[mane.ID], channels[rodriguez.ID] = models.NotificationChannelPush, models.NotificationChannelEmail
+	pushTopics, emails = routeNotification(models.Users{*mane, rodriguez, *salah}, channels, true)
+	as.Equal([]string{
+		firebase.GetTopic(mane),
+		firebase.GetTopic(salah), firebase.GetDriverTopic(salah.TenantID.String(), salah.ID.String()),
+	}, pushTopics)
+	as.Equal([]string{rodriguez.Email}, emails)
+
+	res := as.setupRequest(mane, "/self/notification-preferences").Put(models.NotificationSettings{
+		{Event: models.NotificationEventMaintenanceDue.String(), Channel: models.NotificationChannelEmail.String()},
+	})
+	as.Equal(http.StatusOK, res.Code, res.Body.String())
+	fakeMailer.Reset()
+	sendNotificationsAsync(models.NotificationEventMaintenanceDue, []string{firebase.GetBackOfficeTopic(mane)},
+		"Inspection due tomorrow - T100", "T100", map[string]string{})
+	as.Eventually(func() bool {
+		return len(fakeMailer.Messages()) == 1
+	}, time.Second*3, time.Millisecond*100)
+	message := fakeMailer.Messages()[0]
+	as.Equal([]string{mane.Email}, message.To)
+	as.Equal("Inspection due tomorrow - T100", message.Subject)
+}
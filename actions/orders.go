@@ -127,6 +127,11 @@ func ordersCreate(c buffalo.Context) error {
 	if err := checkTerminalID(c, tx, loggedInUser, order.TerminalID); err != nil {
 		return c.Error(http.StatusBadRequest, err)
 	}
+	currency, err := tenantCurrency(tx, order.TenantID)
+	if err != nil {
+		return err
+	}
+	order.SetDefaultCurrency(currency)
 	// Need a copy here
 	var shipments = models.Shipments{}
 	for _, s := range order.Shipments {
@@ -141,10 +146,16 @@ func ordersCreate(c buffalo.Context) error {
 		shipment.CreatedBy = loggedInUser.ID
 		shipment.SerialNumber = s.SerialNumber
 		shipment.Size = s.Size
-		shipment.Status = s.Status
+		shipment.Origin = s.Origin
+		shipment.Destination = s.Destination
+		// Shipments of a new order have no driver yet, their status moves through the transitions of the roles
+		shipment.Status = models.ShipmentStatusUnassigned.String()
 		shipments = append(shipments, shipment)
 	}
 	order.Shipments = shipments
+	if err := fillOrderCharges(c, tx, order); err != nil {
+		return err
+	}
 	c.Logger().Warnf("creating %d shipments with the order", len(order.Shipments))
 	verrs, err := tx.Eager("Shipments").ValidateAndCreate(order)
 	if err != nil {
@@ -154,6 +165,24 @@ func ordersCreate(c buffalo.Context) error {
 	if verrs.HasAny() {
 		return c.Render(http.StatusUnprocessableEntity, r.JSON(verrs))
 	}
+	// Slots are reserved once the shipments exist, so that each reservation counts towards the capacity for the next
+	for i := range order.Shipments {
+		shipment := &order.Shipments[i]
+		if err := reserveTerminal(c, tx, shipment); err != nil {
+			return renderTerminalReservationError(c, err)
+		}
+		if shipment.TerminalSlotID.Valid {
+			if err := tx.Update(shipment); err != nil {
+				return err
+			}
+		}
+		if err := createShipmentEvent(tx, shipment, "", nulls.UUID{}, loggedInUser); err != nil {
+			return err
+		}
+	}
+	if err := syncOrderStatus(c, tx, nulls.NewUUID(order.ID)); err != nil {
+		return err
+	}
 	shipmentsCount, err := shipmentsCount(c, tx, order.ID)
 	if err != nil {
 		return err
@@ -180,6 +209,21 @@ func ordersUpdate(c buffalo.Context) error {
 
 		return err
 	}
+	currency, err := tenantCurrency(tx, order.TenantID)
+	if err != nil {
+		return err
+	}
+	newOrder.SetDefaultCurrency(currency)
+	if newOrder.Status == "" {
+		newOrder.Status = order.Status
+	}
+	if newOrder.Status != order.Status && !order.CanSetStatus(newOrder.Status) {
+		return c.Error(http.StatusConflict, errOrderStatusDerived)
+	}
+	// Billed orders keep the charges and costs they were invoiced with
+	if order.IsLocked() && (order.DropoffCharges != newOrder.DropoffCharges || order.DropoffCost != newOrder.DropoffCost || order.PickupCharges != newOrder.PickupCharges || order.PickupCost != newOrder.PickupCost) {
+		return c.Error(http.StatusConflict, errOrderLocked)
+	}
 	if newOrder.SerialNumber != order.SerialNumber || newOrder.Status != order.Status || newOrder.Eta != order.Eta || order.Docco != newOrder.Docco || order.ContainterStatus != newOrder.ContainterStatus || order.CarrierID != newOrder.CarrierID || order.TerminalID != newOrder.TerminalID || order.DropoffCharges != newOrder.DropoffCharges || order.DropoffCost != newOrder.DropoffCost || order.PickupCharges != newOrder.PickupCharges || order.PickupCost != newOrder.PickupCost || order.Rld != newOrder.Rld || order.Shipline != newOrder.Shipline || order.Erd != newOrder.Erd || order.Lfd != newOrder.Lfd || order.SoNumber != newOrder.SoNumber {
 		order.UpdatedAt = time.Now().UTC()
 		order.Eta = newOrder.Eta
@@ -248,3 +292,52 @@ func shipmentsCount(c buffalo.Context, tx *pop.Connection, orderID uuid.UUID) (i
 	}
 	return shipmentsCount, err
 }
+
+var errOrderLocked = errors.New("order has been invoiced and its shipments and charges cannot be changed")
+
+var errOrderStatusDerived = errors.New("only Open, Accepted and Cancelled orders can be set to Open, Accepted or Cancelled, other statuses follow the shipments and invoices of the order")
+
+// checkOrderNotLocked ensures the shipments of an order can still be changed
+func checkOrderNotLocked(tx *pop.Connection, orderID nulls.UUID) error {
+	if !orderID.Valid {
+		return nil
+	}
+	order := &models.Order{}
+	if err := tx.Find(order, orderID.UUID); err != nil {
+		return err
+	}
+	if order.IsLocked() {
+		return errOrderLocked
+	}
+	return nil
+}
+
+// syncOrderStatus rolls up the status of an order from its shipments
+func syncOrderStatus(c buffalo.Context, tx *pop.Connection, orderID nulls.UUID) error {
+	if !orderID.Valid {
+		return nil
+	}
+	order := &models.Order{}
+	if err := tx.Find(order, orderID.UUID); err != nil {
+		return err
+	}
+	shipments := &models.Shipments{}
+	if err := tx.Where("order_id = ?", order.ID).All(shipments); err != nil {
+		return err
+	}
+	status := order.RollUpStatus(*shipments)
+	if status.String() == order.Status {
+		return nil
+	}
+	c.Logger().Infof("order %s moved from %s to %s by its shipments", order.ID, order.Status, status)
+	order.UpdatedAt = time.Now().UTC()
+	order.Status = status.String()
+	verrs, err := tx.ValidateAndUpdate(order)
+	if err != nil {
+		return err
+	}
+	if verrs.HasAny() {
+		return verrs
+	}
+	return nil
+}
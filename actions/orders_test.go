@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/nulls"
 )
 
 func (as *ActionSuite) Test_OrdersList() {
@@ -49,7 +51,7 @@ func (as *ActionSuite) Test_OrdersList() {
 					// Issue with golang time precision
 					//as.Equal(newOrder.Erd.Time.UTC(), orders[0].Erd.Time.UTC())
 					as.Equal(0, orders[0].ShipmentCount)
-					as.Equal(10000, orders[0].DropoffCost.Int)
+					as.Equal(models.NewNullMoney(10000, models.DefaultCurrency), orders[0].DropoffCost)
 				}
 			}
 		})
@@ -215,6 +217,140 @@ func (as *ActionSuite) Test_OrdersCreate() {
 	}
 }
 
+func (as *ActionSuite) Test_OrdersCreateDefaultCurrency() {
+	as.LoadFixture("Tenant bootstrap")
+	var firmino = as.getLoggedInUser("firmino")
+	efaLiv := as.getCustomer("EFA Liv")
+	tenant := &models.Tenant{}
+	as.Nil(as.DB.Find(tenant, firmino.TenantID))
+	tenant.Currency = "USD"
+	as.Nil(as.DB.Update(tenant))
+
+	res := as.setupRequest(firmino, "/orders").Post(map[string]interface{}{
+		"serial_number":   "usdOrder",
+		"customer_id":     efaLiv.ID,
+		"pickup_charges":  1500,
+		"dropoff_charges": map[string]interface{}{"amount": 2500},
+	})
+	as.Equal(http.StatusCreated, res.Code)
+	var order = models.Order{}
+	res.Bind(&order)
+	as.Equal(models.NewNullMoney(1500, "USD"), order.PickupCharges)
+	as.Equal(models.NewNullMoney(2500, "USD"), order.DropoffCharges)
+	as.False(order.PickupCost.Valid)
+
+	res = as.setupRequest(firmino, "/orders").Post(map[string]interface{}{
+		"serial_number":   "mixedOrder",
+		"customer_id":     efaLiv.ID,
+		"pickup_charges":  map[string]interface{}{"amount": 1500, "currency": "CAD"},
+		"dropoff_charges": 2500,
+	})
+	as.Equal(http.StatusUnprocessableEntity, res.Code)
+}
+
+func (as *ActionSuite) Test_OrdersCreateFromRateCard() {
+	as.LoadFixture("Tenant bootstrap")
+	var firmino = as.getLoggedInUser("firmino")
+	var nike = as.getLoggedInUser("nike")
+	efaLiv := as.getCustomer("EFA Liv")
+	terminal := as.createTerminal("Deltaport", models.TerminalTypePort, firmino.TenantID, firmino.ID)
+	yesterday := time.Now().UTC().AddDate(0, 0, -1)
+	// An expired rate card must not price new orders
+	expired := as.newRateCard(efaLiv.ID, terminal.ID, yesterday.AddDate(0, -1, 0), nulls.NewTime(yesterday))
+	expired.FuelSurchargePercent = 50
+	as.createRateCard(firmino, expired)
+	as.createRateCard(firmino, as.newRateCard(efaLiv.ID, terminal.ID, yesterday, nulls.Time{}))
+	var shipments = []map[string]interface{}{
+		{"serial_number": "s1", "destination": "Surrey", "size": models.ShipmentSize20ST.String(), "status": models.ShipmentStatusUnassigned.String()},
+		{"serial_number": "s2", "destination": "Surrey", "size": models.ShipmentSize40HC.String(), "status": models.ShipmentStatusUnassigned.String()},
+	}
+
+	res := as.setupRequest(nike, "/orders").Post(map[string]interface{}{
+		"serial_number": "priced",
+		"terminal_id":   terminal.ID,
+		"shipments":     shipments,
+	})
+	as.Equal(http.StatusCreated, res.Code, res.Body.String())
+	var order = models.Order{}
+	res.Bind(&order)
+	as.Equal(models.NewNullMoney(16500+22000, "CAD"), order.PickupCharges)
+	as.Equal(models.NewNullMoney(5500+11000, "CAD"), order.DropoffCharges)
+	as.False(order.PickupCost.Valid)
+	shipment := &models.Shipment{}
+	as.Nil(as.DB.Where("order_id = ?", order.ID).Where("serial_number = ?", "s1").First(shipment))
+	as.Equal("Surrey", shipment.Destination.String)
+
+	// Given charges are kept
+	res = as.setupRequest(nike, "/orders").Post(map[string]interface{}{
+		"serial_number":  "given",
+		"terminal_id":    terminal.ID,
+		"shipments":      shipments,
+		"pickup_charges": 100,
+	})
+	as.Equal(http.StatusCreated, res.Code)
+	order = models.Order{}
+	res.Bind(&order)
+	as.Equal(models.NewNullMoney(100, "CAD"), order.PickupCharges)
+	as.Equal(models.NewNullMoney(16500, "CAD"), order.DropoffCharges)
+
+	// Orders that cannot be priced are created without charges
+	res = as.setupRequest(nike, "/orders").Post(map[string]interface{}{
+		"serial_number": "unpriced",
+		"terminal_id":   terminal.ID,
+		"shipments":     []map[string]interface{}{{"serial_number": "s3", "destination": "Langley", "status": models.ShipmentStatusUnassigned.String()}},
+	})
+	as.Equal(http.StatusCreated, res.Code)
+	order = models.Order{}
+	res.Bind(&order)
+	as.False(order.PickupCharges.Valid)
+	as.False(order.DropoffCharges.Valid)
+}
+
+func (as *ActionSuite) Test_OrdersCreateShipments() {
+	as.LoadFixture("Tenant bootstrap")
+	nike := as.getLoggedInUser("nike")
+	mane := as.getLoggedInUser("mane")
+	terminal := as.createTerminal("Deltaport", models.TerminalTypePort, mane.TenantID, mane.ID)
+	as.createTerminalSlot(mane, terminal.ID, models.TerminalSlot{Weekday: int(time.Monday), StartTime: "08:00", EndTime: "10:00", Capacity: 2})
+	var monday = time.Date(2026, 11, 2, 9, 0, 0, 0, time.UTC)
+
+	// The status sent for the shipments cannot skip their transitions
+	res := as.setupRequest(nike, "/orders").Post(map[string]interface{}{
+		"serial_number": "delivered",
+		"terminal_id":   terminal.ID,
+		"erd":           monday,
+		"shipments": []map[string]interface{}{
+			{"serial_number": "s1", "status": models.ShipmentStatusDelivered.String()},
+			{"serial_number": "s2", "status": models.ShipmentStatusInTransit.String()},
+		},
+	})
+	as.Equal(http.StatusCreated, res.Code, res.Body.String())
+	var order = models.Order{}
+	res.Bind(&order)
+	as.Equal(models.OrderStatusOpen.String(), order.Status)
+	shipments := models.Shipments{}
+	as.Nil(as.DB.Where("order_id = ?", order.ID).Order("serial_number ASC").All(&shipments))
+	as.Equal(2, len(shipments))
+	for _, s := range shipments {
+		as.Equal(models.ShipmentStatusUnassigned.String(), s.Status)
+		// The shipments reserve a slot of the terminal and start their history
+		as.True(s.TerminalSlotID.Valid)
+		events := models.ShipmentEvents{}
+		as.Nil(as.DB.Where("shipment_id = ?", s.ID).All(&events))
+		as.Equal(1, len(events))
+		as.Equal(models.ShipmentStatusUnassigned.String(), events[0].ToStatus)
+	}
+
+	// Both shipments took the capacity of the slot
+	res = as.setupRequest(nike, "/orders").Post(map[string]interface{}{
+		"serial_number": "full",
+		"terminal_id":   terminal.ID,
+		"erd":           monday,
+		"shipments":     []map[string]interface{}{{"serial_number": "s3"}},
+	})
+	as.Equal(http.StatusConflict, res.Code)
+}
+
 func (as *ActionSuite) Test_OrdersUpdate() {
 	as.LoadFixture("Tenant bootstrap")
 	var tests = []struct {
@@ -261,6 +397,50 @@ func (as *ActionSuite) Test_OrdersUpdate() {
 	}
 }
 
+func (as *ActionSuite) Test_OrdersUpdateStatusAndCharges() {
+	as.LoadFixture("Tenant bootstrap")
+	var tests = []struct {
+		name         string
+		from         models.OrderStatus
+		to           models.OrderStatus
+		charges      models.NullMoney
+		responseCode int
+	}{
+		{"manual", models.OrderStatusOpen, models.OrderStatusCancelled, models.NullMoney{}, http.StatusOK},
+		{"invoiced to open", models.OrderStatusInvoiced, models.OrderStatusOpen, models.NullMoney{}, http.StatusConflict},
+		{"open to invoiced", models.OrderStatusOpen, models.OrderStatusInvoiced, models.NullMoney{}, http.StatusConflict},
+		{"open to delivered", models.OrderStatusOpen, models.OrderStatusDelivered, models.NullMoney{}, http.StatusConflict},
+		{"invoiced charges", models.OrderStatusInvoiced, models.OrderStatusInvoiced, models.NewNullMoney(100, "CAD"), http.StatusConflict},
+		{"invoiced serial number", models.OrderStatusInvoiced, models.OrderStatusInvoiced, models.NullMoney{}, http.StatusOK},
+		{"open charges", models.OrderStatusOpen, models.OrderStatusOpen, models.NewNullMoney(100, "CAD"), http.StatusOK},
+	}
+	var firmino = as.getLoggedInUser("firmino")
+	efaLiv := as.getCustomer("EFA Liv")
+
+	for _, test := range tests {
+		as.T().Run(test.name, func(t *testing.T) {
+			newOrder := as.createOrder("order", test.from, firmino.TenantID, firmino.ID, efaLiv.ID)
+			req := as.setupRequest(firmino, fmt.Sprintf("/orders/%s", newOrder.ID))
+			updatedOrder := *newOrder
+			updatedOrder.SerialNumber, updatedOrder.Status = "updated", test.to.String()
+			if test.charges.Valid {
+				updatedOrder.PickupCharges = test.charges
+			}
+			res := req.Put(updatedOrder)
+			as.Equal(test.responseCode, res.Code)
+			var dbOrder = *newOrder
+			as.Nil(as.DB.Reload(&dbOrder))
+			if res.Code == http.StatusOK {
+				as.Equal(test.to.String(), dbOrder.Status)
+				as.Equal("updated", dbOrder.SerialNumber)
+			} else {
+				as.Equal(test.from.String(), dbOrder.Status)
+				as.Equal(newOrder.SerialNumber, dbOrder.SerialNumber)
+			}
+		})
+	}
+}
+
 func (as *ActionSuite) Test_OrdersDestroy() {
 	as.LoadFixture("Tenant bootstrap")
 	var tests = []struct {
@@ -0,0 +1,85 @@
+package actions
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/nulls"
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gofrs/uuid"
+)
+
+type quoteRequest struct {
+	CustomerID   uuid.UUID              `json:"customer_id"`
+	TerminalID   nulls.UUID             `json:"terminal_id"`
+	Date         nulls.Time             `json:"date"`
+	Shipments    models.Shipments       `json:"shipments"`
+	Accessorials models.ShipmentCharges `json:"accessorials"`
+}
+
+// quotesCreate prices a prospective order from the rate card of its customer. This function is mapped to the
+// path POST /quotes
+// Customer users get quotes for themselves. The rate card effective at the given date, or now, is used.
+func quotesCreate(c buffalo.Context) error {
+	var loggedInUser = loggedInUser(c)
+	req := &quoteRequest{}
+	if err := c.Bind(req); err != nil {
+		c.Logger().Errorf("error binding quote request: %v\n", err)
+		return err
+	}
+	tx := c.Value("tx").(*pop.Connection)
+	if loggedInUser.IsCustomer() {
+		if !loggedInUser.CustomerID.Valid {
+			return c.Error(http.StatusNotFound, errors.New("invalid user"))
+		}
+		req.CustomerID = loggedInUser.CustomerID.UUID
+	} else {
+		customer := &models.Customer{}
+		// Customer must belong to the same tenant
+		if err := tx.Scope(restrictedScope(c)).Where("tenant_id = ?", loggedInUser.TenantID).Find(customer, req.CustomerID); err != nil {
+			return c.Error(http.StatusBadRequest, errors.New("invalid customer association"))
+		}
+	}
+	if err := checkTerminalID(c, tx, loggedInUser, req.TerminalID); err != nil {
+		return c.Error(http.StatusBadRequest, err)
+	}
+	at := time.Now().UTC()
+	if req.Date.Valid {
+		at = req.Date.Time
+	}
+	rateCard, err := models.FindRateCard(tx, req.CustomerID, at)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Error(http.StatusNotFound, errors.New("no rate card is effective for the customer"))
+		}
+		return err
+	}
+	quote, err := rateCard.Quote(req.TerminalID, req.Shipments, req.Accessorials)
+	if err != nil {
+		return c.Error(http.StatusBadRequest, err)
+	}
+	return c.Render(http.StatusOK, r.JSON(quote))
+}
+
+// fillOrderCharges sets the charges that were not given on a new order from the rate card of its customer.
+// Orders that cannot be priced are created with the charges they were given.
+func fillOrderCharges(c buffalo.Context, tx *pop.Connection, order *models.Order) error {
+	if order.PickupCharges.Valid && order.DropoffCharges.Valid {
+		return nil
+	}
+	rateCard, err := models.FindRateCard(tx, order.CustomerID, time.Now().UTC())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+	if err := rateCard.FillCharges(order); err != nil {
+		c.Logger().Warnf("order %s not priced from rate card %s: %v", order.SerialNumber, rateCard.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package actions
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/nulls"
+)
+
+func (as *ActionSuite) Test_QuotesCreate() {
+	as.LoadFixture("Tenant bootstrap")
+	var tests = []struct {
+		username     string
+		responseCode int
+	}{
+		{"nike", http.StatusOK},
+		{"firmino", http.StatusOK},
+		{"mane", http.StatusOK},
+		{"klopp", http.StatusBadRequest},
+		{"rodriguez", http.StatusBadRequest},
+		{"adidas", http.StatusNotFound},
+		{"salah", http.StatusNotFound},
+		{"coutinho", http.StatusNotFound},
+	}
+	firmino := as.getLoggedInUser("firmino")
+	efaLiv := as.getCustomer("EFA Liv")
+	terminal := as.createTerminal("Deltaport", models.TerminalTypePort, firmino.TenantID, firmino.ID)
+	as.createRateCard(firmino, as.newRateCard(efaLiv.ID, terminal.ID, time.Now().UTC().AddDate(0, 0, -1), nulls.Time{}))
+	req := quoteRequest{
+		CustomerID: efaLiv.ID,
+		TerminalID: nulls.NewUUID(terminal.ID),
+		Shipments: models.Shipments{
+			{Destination: nulls.NewString("Surrey"), Size: nulls.NewString(models.ShipmentSize20ST.String())},
+			{Destination: nulls.NewString("surrey"), Size: nulls.NewString(models.ShipmentSize40HC.String())},
+		},
+		Accessorials: models.ShipmentCharges{{Type: models.ShipmentChargeTypeDetention.String(), Quantity: 3}},
+	}
+	for _, test := range tests {
+		as.T().Run(test.username, func(t *testing.T) {
+			user := as.getLoggedInUser(test.username)
+			res := as.setupRequest(user, "/quotes").Post(req)
+			as.Equal(test.responseCode, res.Code)
+			if res.Code != http.StatusOK {
+				return
+			}
+			var quote = models.Quote{}
+			res.Bind(&quote)
+			as.Equal(efaLiv.ID, quote.CustomerID)
+			as.Equal(6, len(quote.Lines))
+			// Base rates, 10% fuel surcharge and 3 hours of detention
+			as.Equal(models.NewMoney(50000+5000+27000, "CAD"), quote.Total)
+		})
+	}
+}
+
+func (as *ActionSuite) Test_QuotesCreateEffectiveDate() {
+	as.LoadFixture("Tenant bootstrap")
+	firmino := as.getLoggedInUser("firmino")
+	nike := as.getLoggedInUser("nike")
+	efaLiv := as.getCustomer("EFA Liv")
+	terminal := as.createTerminal("Deltaport", models.TerminalTypePort, firmino.TenantID, firmino.ID)
+	jan := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	jul := time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)
+	first := as.newRateCard(efaLiv.ID, terminal.ID, jan, nulls.NewTime(jul))
+	first.FuelSurchargePercent = 0
+	as.createRateCard(firmino, first)
+	as.createRateCard(firmino, as.newRateCard(efaLiv.ID, terminal.ID, jul, nulls.Time{}))
+	var tests = []struct {
+		name         string
+		date         nulls.Time
+		terminalID   nulls.UUID
+		destination  string
+		responseCode int
+		total        int
+	}{
+		{"current", nulls.Time{}, nulls.NewUUID(terminal.ID), "Surrey", http.StatusOK, 33000},
+		{"first half", nulls.NewTime(jan.AddDate(0, 2, 0)), nulls.NewUUID(terminal.ID), "Surrey", http.StatusOK, 30000},
+		{"before any rate card", nulls.NewTime(jan.AddDate(0, 0, -1)), nulls.NewUUID(terminal.ID), "Surrey", http.StatusNotFound, 0},
+		{"no terminal", nulls.Time{}, nulls.UUID{}, "Surrey", http.StatusBadRequest, 0},
+		{"no lane", nulls.Time{}, nulls.NewUUID(terminal.ID), "Langley", http.StatusBadRequest, 0},
+	}
+	for _, test := range tests {
+		as.T().Run(test.name, func(t *testing.T) {
+			req := quoteRequest{
+				Date:       test.date,
+				TerminalID: test.terminalID,
+				Shipments:  models.Shipments{{Destination: nulls.NewString(test.destination), Size: nulls.NewString(models.ShipmentSize40ST.String())}},
+			}
+			res := as.setupRequest(nike, "/quotes").Post(req)
+			as.Equal(test.responseCode, res.Code)
+			if res.Code == http.StatusOK {
+				var quote = models.Quote{}
+				res.Bind(&quote)
+				as.Equal(models.NewMoney(test.total, "CAD"), quote.Total)
+			}
+		})
+	}
+}
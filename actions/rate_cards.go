@@ -0,0 +1,243 @@
+package actions
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/nulls"
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gofrs/uuid"
+)
+
+// Following naming logic is implemented in Buffalo:
+// Model: Singular (RateCard)
+// DB Table: Plural (rate_cards)
+// Resource: Plural (RateCards)
+// Path: Plural (/rate-cards)
+
+var errRateCardOverlap = errors.New("another rate card of the customer is effective during this period")
+
+// rateCardsList gets all RateCards. This function is mapped to the path
+// GET /rate-cards
+func rateCardsList(c buffalo.Context) error {
+	var loggedInUser = loggedInUser(c)
+	tx := c.Value("tx").(*pop.Connection)
+	rateCards := &models.RateCards{}
+
+	// Paginate results. Params "page" and "per_page" control pagination.
+	// Default values are "page=1" and "per_page=20".
+	q := tx.PaginateFromParams(c.Params())
+
+	customerID := c.Param("customer_id")
+	if loggedInUser.IsCustomer() {
+		if !loggedInUser.CustomerID.Valid {
+			return c.Error(http.StatusNotFound, errors.New("invalid user"))
+		}
+		customerID = loggedInUser.CustomerID.UUID.String()
+	}
+	if customerID != "" {
+		q = q.Where("customer_id = ?", customerID)
+	}
+	if err := q.Scope(restrictedScope(c)).Order("effective_from DESC").All(rateCards); err != nil {
+		return err
+	}
+	return c.Render(http.StatusOK, r.JSON(rateCards))
+}
+
+// rateCardsShow gets the data for one RateCard with its rates. This function is mapped to
+// the path GET /rate-cards/{rate_card_id}
+func rateCardsShow(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	rateCard, err := findRateCard(c, tx)
+	if err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	return c.Render(http.StatusOK, r.JSON(rateCard))
+}
+
+// rateCardsCreate adds a RateCard and its rates to the DB. This function is mapped to the
+// path POST /rate-cards
+func rateCardsCreate(c buffalo.Context) error {
+	var loggedInUser = loggedInUser(c)
+	rateCard := &models.RateCard{}
+	if err := c.Bind(rateCard); err != nil {
+		c.Logger().Errorf("error binding rate card: %v\n", err)
+		return err
+	}
+	tx := c.Value("tx").(*pop.Connection)
+	rateCard.CreatedBy = loggedInUser.ID
+	rateCard.TenantID = loggedInUser.TenantID
+	if err := checkRateCard(c, tx, loggedInUser, rateCard); err != nil {
+		return c.Error(http.StatusBadRequest, err)
+	}
+	currency, err := tenantCurrency(tx, rateCard.TenantID)
+	if err != nil {
+		return err
+	}
+	if rateCard.Currency == "" {
+		rateCard.Currency = currency
+	}
+	rateCard.SetDefaultCurrency()
+	if err := checkRateCardCurrency(rateCard, currency); err != nil {
+		return c.Error(http.StatusConflict, err)
+	}
+	if overlaps, err := rateCard.Overlaps(tx); err != nil {
+		return err
+	} else if overlaps {
+		return c.Error(http.StatusConflict, errRateCardOverlap)
+	}
+	resetRateCardRates(rateCard)
+	verrs, err := tx.Eager("Lanes", "Accessorials").ValidateAndCreate(rateCard)
+	if err != nil {
+		return err
+	}
+	if verrs.HasAny() {
+		return c.Render(http.StatusUnprocessableEntity, r.JSON(verrs))
+	}
+	return c.Render(http.StatusCreated, r.JSON(rateCard))
+}
+
+// rateCardsUpdate changes a RateCard in the DB and replaces its rates. This function is mapped to
+// the path PUT /rate-cards/{rate_card_id}
+// Orders keep the charges they were created with, to change prices from a date on, end the current
+// rate card and create a new one.
+func rateCardsUpdate(c buffalo.Context) error {
+	var loggedInUser = loggedInUser(c)
+	tx := c.Value("tx").(*pop.Connection)
+	rateCard, err := findRateCard(c, tx)
+	if err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	newRateCard := &models.RateCard{}
+	if err := c.Bind(newRateCard); err != nil {
+		c.Logger().Errorf("error binding rate card: %v\n", err)
+		return err
+	}
+	newRateCard.ID = rateCard.ID
+	newRateCard.CreatedAt = rateCard.CreatedAt
+	newRateCard.CreatedBy = rateCard.CreatedBy
+	newRateCard.TenantID = rateCard.TenantID
+	newRateCard.CustomerID = rateCard.CustomerID
+	newRateCard.UpdatedAt = time.Now().UTC()
+	if newRateCard.Currency == "" {
+		newRateCard.Currency = rateCard.Currency
+	}
+	newRateCard.SetDefaultCurrency()
+	if err := checkRateCard(c, tx, loggedInUser, newRateCard); err != nil {
+		return c.Error(http.StatusBadRequest, err)
+	}
+	currency, err := tenantCurrency(tx, newRateCard.TenantID)
+	if err != nil {
+		return err
+	}
+	if err := checkRateCardCurrency(newRateCard, currency); err != nil {
+		return c.Error(http.StatusConflict, err)
+	}
+	if overlaps, err := newRateCard.Overlaps(tx); err != nil {
+		return err
+	} else if overlaps {
+		return c.Error(http.StatusConflict, errRateCardOverlap)
+	}
+	verrs, err := tx.ValidateAndUpdate(newRateCard)
+	if err != nil {
+		return err
+	}
+	if verrs.HasAny() {
+		return c.Render(http.StatusUnprocessableEntity, r.JSON(verrs))
+	}
+	if err := tx.Destroy(&rateCard.Lanes); err != nil {
+		return err
+	}
+	if err := tx.Destroy(&rateCard.Accessorials); err != nil {
+		return err
+	}
+	resetRateCardRates(newRateCard)
+	verrs, err = tx.ValidateAndCreate(&newRateCard.Lanes)
+	if err != nil {
+		return err
+	}
+	if verrs.HasAny() {
+		return c.Render(http.StatusUnprocessableEntity, r.JSON(verrs))
+	}
+	verrs, err = tx.ValidateAndCreate(&newRateCard.Accessorials)
+	if err != nil {
+		return err
+	}
+	if verrs.HasAny() {
+		return c.Render(http.StatusUnprocessableEntity, r.JSON(verrs))
+	}
+	return c.Render(http.StatusOK, r.JSON(newRateCard))
+}
+
+// rateCardsDestroy deletes a RateCard from the DB. This function is mapped
+// to the path DELETE /rate-cards/{rate_card_id}
+func rateCardsDestroy(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	rateCard := &models.RateCard{}
+	if err := tx.Scope(restrictedScope(c)).Find(rateCard, c.Param("rate_card_id")); err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	if err := tx.Destroy(rateCard); err != nil {
+		return err
+	}
+	c.Response().WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// findRateCard returns the rate card of the path with its rates, customers only find their own
+func findRateCard(c buffalo.Context, tx *pop.Connection) (*models.RateCard, error) {
+	var loggedInUser = loggedInUser(c)
+	q := tx.Eager("Lanes", "Accessorials").Scope(restrictedScope(c))
+	if loggedInUser.IsCustomer() {
+		if !loggedInUser.CustomerID.Valid {
+			return nil, errors.New("invalid user")
+		}
+		q = q.Where("customer_id = ?", loggedInUser.CustomerID.UUID)
+	}
+	rateCard := &models.RateCard{}
+	if err := q.Find(rateCard, c.Param("rate_card_id")); err != nil {
+		return nil, err
+	}
+	return rateCard, nil
+}
+
+// checkRateCard ensures the customer and the terminals of the lanes of a rate card belong to the tenant
+func checkRateCard(c buffalo.Context, tx *pop.Connection, loggedInUser *models.User, rateCard *models.RateCard) error {
+	customer := &models.Customer{}
+	// Customer must belong to the same tenant
+	err := tx.Scope(restrictedScope(c)).Where("tenant_id = ?", loggedInUser.TenantID).Find(customer, rateCard.CustomerID)
+	if err != nil || rateCard.TenantID != customer.TenantID {
+		return errors.New("invalid customer association")
+	}
+	for _, l := range rateCard.Lanes {
+		if err := checkTerminalID(c, tx, loggedInUser, nulls.NewUUID(l.TerminalID)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkRateCardCurrency ensures a rate card prices in the currency of the tenant, the charges of the orders it prices
+// are in that currency
+func checkRateCardCurrency(rateCard *models.RateCard, tenantCurrency string) error {
+	if rateCard.Currency != tenantCurrency {
+		return fmt.Errorf("%w: the rate card is in %s, the tenant bills in %s", models.ErrCurrencyMismatch, rateCard.Currency, tenantCurrency)
+	}
+	return nil
+}
+
+// resetRateCardRates makes the rates of a rate card new rows of the rate card, the ones it had are replaced
+func resetRateCardRates(rateCard *models.RateCard) {
+	for i := range rateCard.Lanes {
+		rateCard.Lanes[i].ID = uuid.Nil
+		rateCard.Lanes[i].RateCardID = rateCard.ID
+	}
+	for i := range rateCard.Accessorials {
+		rateCard.Accessorials[i].ID = uuid.Nil
+		rateCard.Accessorials[i].RateCardID = rateCard.ID
+	}
+}
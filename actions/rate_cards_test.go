@@ -0,0 +1,225 @@
+package actions
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/nulls"
+	"github.com/gofrs/uuid"
+)
+
+func (as *ActionSuite) newRateCard(customerID uuid.UUID, terminalID uuid.UUID, from time.Time, to nulls.Time) models.RateCard {
+	return models.RateCard{
+		Name:                 fmt.Sprintf("from %s", from.Format(reportDateFormat)),
+		CustomerID:           customerID,
+		FuelSurchargePercent: 10,
+		EffectiveFrom:        from,
+		EffectiveTo:          to,
+		Lanes: models.RateCardLanes{
+			{TerminalID: terminalID, Destination: "Surrey", PickupRate: models.NewMoney(20000, ""), DropoffRate: models.NewMoney(10000, "")},
+			{TerminalID: terminalID, Destination: "Surrey", Size: nulls.NewString(models.ShipmentSize20ST.String()), PickupRate: models.NewMoney(15000, ""), DropoffRate: models.NewMoney(5000, "")},
+		},
+		Accessorials: models.RateCardAccessorials{
+			{Type: models.ShipmentChargeTypeDetention.String(), Rate: models.NewMoney(9000, "")},
+		},
+	}
+}
+
+func (as *ActionSuite) createRateCard(user *models.User, rateCard models.RateCard) *models.RateCard {
+	res := as.setupRequest(user, "/rate-cards").Post(rateCard)
+	as.Equal(http.StatusCreated, res.Code, res.Body.String())
+	var created = &models.RateCard{}
+	res.Bind(created)
+	return created
+}
+
+func (as *ActionSuite) Test_RateCardsCreate() {
+	as.LoadFixture("Tenant bootstrap")
+	var tests = []struct {
+		username     string
+		responseCode int
+	}{
+		{"firmino", http.StatusCreated},
+		{"mane", http.StatusCreated},
+		{"klopp", http.StatusBadRequest},
+		{"rodriguez", http.StatusBadRequest},
+		{"salah", http.StatusNotFound},
+		{"nike", http.StatusNotFound},
+		{"coutinho", http.StatusNotFound},
+	}
+	firmino := as.getLoggedInUser("firmino")
+	terminal := as.createTerminal("Deltaport", models.TerminalTypePort, firmino.TenantID, firmino.ID)
+	for _, test := range tests {
+		as.T().Run(test.username, func(t *testing.T) {
+			user := as.getLoggedInUser(test.username)
+			customer := as.createCustomer(fmt.Sprintf("customer %s", test.username), firmino.TenantID, nulls.NewUUID(firmino.ID))
+			res := as.setupRequest(user, "/rate-cards").Post(as.newRateCard(customer.ID, terminal.ID, time.Now().UTC(), nulls.Time{}))
+			as.Equal(test.responseCode, res.Code)
+			if res.Code != http.StatusCreated {
+				return
+			}
+			var rateCard = models.RateCard{}
+			res.Bind(&rateCard)
+			as.Equal(firmino.TenantID, rateCard.TenantID)
+			as.Equal(user.ID, rateCard.CreatedBy)
+			as.Equal("CAD", rateCard.Currency)
+			as.Equal(models.NewMoney(20000, "CAD"), rateCard.Lanes[0].PickupRate)
+			as.Equal(2, len(rateCard.Lanes))
+			as.Equal(1, len(rateCard.Accessorials))
+			count, err := as.DB.Where("rate_card_id = ?", rateCard.ID).Count(&models.RateCardLanes{})
+			as.Nil(err)
+			as.Equal(2, count)
+		})
+	}
+}
+
+func (as *ActionSuite) Test_RateCardsCreateInvalid() {
+	as.LoadFixture("Tenant bootstrap")
+	firmino := as.getLoggedInUser("firmino")
+	richarlson := as.getLoggedInUser("richarlson")
+	efaLiv := as.getCustomer("EFA Liv")
+	efaEve := as.getCustomer("EFA Eve")
+	terminal := as.createTerminal("Deltaport", models.TerminalTypePort, firmino.TenantID, firmino.ID)
+	otherTerminal := as.createTerminal("Centerm", models.TerminalTypePort, richarlson.TenantID, richarlson.ID)
+	jan := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	jul := time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)
+	as.createRateCard(firmino, as.newRateCard(efaLiv.ID, terminal.ID, jan, nulls.NewTime(jul)))
+	usdRateCard := as.newRateCard(efaLiv.ID, terminal.ID, jul, nulls.Time{})
+	usdRateCard.Currency = "USD"
+	usdRate := as.newRateCard(efaLiv.ID, terminal.ID, jul, nulls.Time{})
+	usdRate.Accessorials[0].Rate = models.NewMoney(9000, "USD")
+	var tests = []struct {
+		name         string
+		rateCard     models.RateCard
+		responseCode int
+	}{
+		{"other tenant customer", as.newRateCard(efaEve.ID, terminal.ID, jul, nulls.Time{}), http.StatusBadRequest},
+		{"other tenant terminal", as.newRateCard(efaLiv.ID, otherTerminal.ID, jul, nulls.Time{}), http.StatusBadRequest},
+		{"overlap", as.newRateCard(efaLiv.ID, terminal.ID, jan.AddDate(0, 3, 0), nulls.Time{}), http.StatusConflict},
+		{"overlap before", as.newRateCard(efaLiv.ID, terminal.ID, jan.AddDate(-1, 0, 0), nulls.NewTime(jan.AddDate(0, 0, 1))), http.StatusConflict},
+		{"ends before start", as.newRateCard(efaLiv.ID, terminal.ID, jul, nulls.NewTime(jan)), http.StatusUnprocessableEntity},
+		{"other currency", usdRateCard, http.StatusConflict},
+		{"rate in other currency", usdRate, http.StatusUnprocessableEntity},
+		{"after", as.newRateCard(efaLiv.ID, terminal.ID, jul, nulls.Time{}), http.StatusCreated},
+	}
+	for _, test := range tests {
+		as.T().Run(test.name, func(t *testing.T) {
+			res := as.setupRequest(firmino, "/rate-cards").Post(test.rateCard)
+			as.Equal(test.responseCode, res.Code)
+		})
+	}
+	count, err := as.DB.Count(&models.RateCards{})
+	as.Nil(err)
+	as.Equal(2, count)
+}
+
+func (as *ActionSuite) Test_RateCardsShow() {
+	as.LoadFixture("Tenant bootstrap")
+	var tests = []struct {
+		username     string
+		responseCode int
+	}{
+		{"klopp", http.StatusOK},
+		{"firmino", http.StatusOK},
+		{"mane", http.StatusOK},
+		{"nike", http.StatusOK},
+		{"salah", http.StatusNotFound},
+		{"richarlson", http.StatusNotFound},
+		{"adidas", http.StatusNotFound},
+	}
+	firmino := as.getLoggedInUser("firmino")
+	efaLiv := as.getCustomer("EFA Liv")
+	uefaLiv := as.getCustomer("UEFA Liv")
+	terminal := as.createTerminal("Deltaport", models.TerminalTypePort, firmino.TenantID, firmino.ID)
+	rateCard := as.createRateCard(firmino, as.newRateCard(efaLiv.ID, terminal.ID, time.Now().UTC(), nulls.Time{}))
+	as.createRateCard(firmino, as.newRateCard(uefaLiv.ID, terminal.ID, time.Now().UTC(), nulls.Time{}))
+	for _, test := range tests {
+		as.T().Run(test.username, func(t *testing.T) {
+			user := as.getLoggedInUser(test.username)
+			res := as.setupRequest(user, fmt.Sprintf("/rate-cards/%s", rateCard.ID)).Get()
+			as.Equal(test.responseCode, res.Code)
+			if res.Code == http.StatusOK {
+				var shown = models.RateCard{}
+				res.Bind(&shown)
+				as.Equal(rateCard.Name, shown.Name)
+				as.Equal(2, len(shown.Lanes))
+				as.Equal(1, len(shown.Accessorials))
+			}
+			res = as.setupRequest(user, "/rate-cards").Get()
+			if res.Code == http.StatusOK {
+				var rateCards = models.RateCards{}
+				res.Bind(&rateCards)
+				switch {
+				case user.IsCustomer() && test.responseCode == http.StatusOK:
+					as.Equal(1, len(rateCards))
+				case test.responseCode == http.StatusOK:
+					as.Equal(2, len(rateCards))
+				default:
+					as.Equal(0, len(rateCards))
+				}
+			}
+		})
+	}
+}
+
+func (as *ActionSuite) Test_RateCardsUpdate() {
+	as.LoadFixture("Tenant bootstrap")
+	firmino := as.getLoggedInUser("firmino")
+	nike := as.getLoggedInUser("nike")
+	rodriguez := as.getLoggedInUser("rodriguez")
+	efaLiv := as.getCustomer("EFA Liv")
+	terminal := as.createTerminal("Deltaport", models.TerminalTypePort, firmino.TenantID, firmino.ID)
+	jan := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	jul := time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)
+	rateCard := as.createRateCard(firmino, as.newRateCard(efaLiv.ID, terminal.ID, jan, nulls.Time{}))
+
+	update := as.newRateCard(efaLiv.ID, terminal.ID, jan, nulls.NewTime(jul))
+	update.Name = "first half"
+	update.Lanes = update.Lanes[:1]
+	update.Accessorials = models.RateCardAccessorials{}
+	for _, user := range []*models.User{nike, rodriguez} {
+		res := as.setupRequest(user, fmt.Sprintf("/rate-cards/%s", rateCard.ID)).Put(update)
+		as.Equal(http.StatusNotFound, res.Code)
+	}
+	res := as.setupRequest(firmino, fmt.Sprintf("/rate-cards/%s", rateCard.ID)).Put(update)
+	as.Equal(http.StatusOK, res.Code, res.Body.String())
+	var updated = models.RateCard{}
+	as.Nil(as.DB.Eager("Lanes", "Accessorials").Find(&updated, rateCard.ID))
+	as.Equal("first half", updated.Name)
+	as.Equal(nulls.NewTime(jul), updated.EffectiveTo)
+	as.Equal(rateCard.CreatedAt.Unix(), updated.CreatedAt.Unix())
+	as.Equal(1, len(updated.Lanes))
+	as.Equal(0, len(updated.Accessorials))
+
+	// The second half of the year can now get its own rate card
+	as.createRateCard(firmino, as.newRateCard(efaLiv.ID, terminal.ID, jul, nulls.Time{}))
+	update.EffectiveTo = nulls.Time{}
+	res = as.setupRequest(firmino, fmt.Sprintf("/rate-cards/%s", rateCard.ID)).Put(update)
+	as.Equal(http.StatusConflict, res.Code)
+
+	// Rate cards price in the currency of the tenant
+	update.EffectiveTo = nulls.NewTime(jul)
+	update.Currency = "USD"
+	res = as.setupRequest(firmino, fmt.Sprintf("/rate-cards/%s", rateCard.ID)).Put(update)
+	as.Equal(http.StatusConflict, res.Code)
+}
+
+func (as *ActionSuite) Test_RateCardsDestroy() {
+	as.LoadFixture("Tenant bootstrap")
+	firmino := as.getLoggedInUser("firmino")
+	efaLiv := as.getCustomer("EFA Liv")
+	terminal := as.createTerminal("Deltaport", models.TerminalTypePort, firmino.TenantID, firmino.ID)
+	rateCard := as.createRateCard(firmino, as.newRateCard(efaLiv.ID, terminal.ID, time.Now().UTC(), nulls.Time{}))
+	for _, username := range []string{"nike", "salah", "rodriguez"} {
+		res := as.setupRequest(as.getLoggedInUser(username), fmt.Sprintf("/rate-cards/%s", rateCard.ID)).Delete()
+		as.Equal(http.StatusNotFound, res.Code)
+	}
+	res := as.setupRequest(as.getLoggedInUser("mane"), fmt.Sprintf("/rate-cards/%s", rateCard.ID)).Delete()
+	as.Equal(http.StatusNoContent, res.Code)
+	count, err := as.DB.Count(&models.RateCardLanes{})
+	as.Nil(err)
+	as.Equal(0, count)
+}
@@ -0,0 +1,148 @@
+package actions
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/buffalo/render"
+	"github.com/gobuffalo/pop/v6"
+)
+
+const reportDateFormat = "2006-01-02"
+
+const defaultReportPeriod = 30 * 24 * time.Hour
+
+const defaultComplianceReportPeriod = 30 * 24 * time.Hour
+
+// reportsProfitability gets the revenue, cost and margin of the orders created in a date range.
+// This function is mapped to the path GET /reports/profitability
+// Params "from" and "to" are inclusive dates, "group_by" is one of Customer, Terminal, Carrier or Lane
+// and "format=csv" returns the report as CSV.
+func reportsProfitability(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	groupBy := models.ProfitabilityGroupCustomer
+	if g := c.Param("group_by"); g != "" {
+		if !models.IsValidProfitabilityGroup(g) {
+			return c.Error(http.StatusBadRequest, fmt.Errorf("invalid group_by %q", g))
+		}
+		groupBy = models.ProfitabilityGroup(g)
+	}
+	to := time.Now().UTC().Truncate(24 * time.Hour)
+	if t := c.Param("to"); t != "" {
+		d, err := time.Parse(reportDateFormat, t)
+		if err != nil {
+			return c.Error(http.StatusBadRequest, fmt.Errorf("invalid to date %q", t))
+		}
+		to = d
+	}
+	from := to.Add(-defaultReportPeriod)
+	if f := c.Param("from"); f != "" {
+		d, err := time.Parse(reportDateFormat, f)
+		if err != nil {
+			return c.Error(http.StatusBadRequest, fmt.Errorf("invalid from date %q", f))
+		}
+		from = d
+	}
+	if from.After(to) {
+		return c.Error(http.StatusBadRequest, fmt.Errorf("from date %s is after to date %s", from.Format(reportDateFormat), to.Format(reportDateFormat)))
+	}
+	q := tx.Scope(restrictedScope(c)).
+		Where("created_at >= ?", from).
+		Where("created_at < ?", to.AddDate(0, 0, 1)).
+		Where("status <> ?", models.OrderStatusCancelled.String())
+	rows, err := models.Profitability(tx, q, groupBy)
+	if err != nil {
+		return err
+	}
+	if c.Param("format") == "csv" || strings.Contains(c.Request().Header.Get("Accept"), "text/csv") {
+		c.Response().Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"profitability-%s-%s.csv\"", from.Format(reportDateFormat), to.Format(reportDateFormat)))
+		return c.Render(http.StatusOK, r.Func("text/csv", profitabilityCSV(rows)))
+	}
+	return c.Render(http.StatusOK, r.JSON(rows))
+}
+
+func profitabilityCSV(rows models.ProfitabilityRows) render.RendererFunc {
+	return func(w io.Writer, _ render.Data) error {
+		cw := csv.NewWriter(w)
+		if err := cw.Write([]string{"group_id", "group_name", "currency", "orders", "revenue", "cost", "margin", "margin_percent"}); err != nil {
+			return err
+		}
+		for _, row := range rows {
+			var marginPercent string
+			if row.MarginPercent.Valid {
+				marginPercent = strconv.FormatFloat(row.MarginPercent.Float64, 'f', 2, 64)
+			}
+			record := []string{
+				row.GroupID,
+				row.GroupName,
+				row.Currency,
+				strconv.Itoa(row.Orders),
+				strconv.Itoa(row.Revenue),
+				strconv.Itoa(row.Cost),
+				strconv.Itoa(row.Margin),
+				marginPercent,
+			}
+			if err := cw.Write(record); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	}
+}
+
+// reportsCompliance gets the credentials of the drivers that expire by a date, including the ones already expired.
+// This function is mapped to the path GET /reports/compliance
+// Param "to" is an inclusive date, 30 days from today by default, and "format=csv" returns the report as CSV.
+func reportsCompliance(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	now := time.Now().UTC()
+	to := now.Truncate(24 * time.Hour).Add(defaultComplianceReportPeriod)
+	if t := c.Param("to"); t != "" {
+		d, err := time.Parse(reportDateFormat, t)
+		if err != nil {
+			return c.Error(http.StatusBadRequest, fmt.Errorf("invalid to date %q", t))
+		}
+		to = d
+	}
+	driverCompliances := models.DriverCompliances{}
+	if err := tx.Eager("Driver").Scope(restrictedScope(c)).All(&driverCompliances); err != nil {
+		return err
+	}
+	expirations := models.NewComplianceExpirations(driverCompliances, now, to)
+	if c.Param("format") == "csv" || strings.Contains(c.Request().Header.Get("Accept"), "text/csv") {
+		c.Response().Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"compliance-%s.csv\"", to.Format(reportDateFormat)))
+		return c.Render(http.StatusOK, r.Func("text/csv", complianceCSV(expirations)))
+	}
+	return c.Render(http.StatusOK, r.JSON(expirations))
+}
+
+func complianceCSV(expirations models.ComplianceExpirations) render.RendererFunc {
+	return func(w io.Writer, _ render.Data) error {
+		cw := csv.NewWriter(w)
+		if err := cw.Write([]string{"driver_id", "driver_name", "credential", "expires_on", "expired"}); err != nil {
+			return err
+		}
+		for _, e := range expirations {
+			record := []string{
+				e.Driver.ID.String(),
+				e.Driver.Name,
+				e.Credential,
+				e.ExpiresOn.Format(reportDateFormat),
+				fmt.Sprint(e.Expired),
+			}
+			if err := cw.Write(record); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	}
+}
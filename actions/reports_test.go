@@ -0,0 +1,170 @@
+package actions
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/nulls"
+)
+
+func (as *ActionSuite) Test_ReportsProfitability() {
+	as.LoadFixture("Tenant bootstrap")
+	var tests = []struct {
+		username     string
+		responseCode int
+		count        int
+	}{
+		{"klopp", http.StatusOK, 3},
+		{"firmino", http.StatusOK, 2},
+		{"mane", http.StatusOK, 2},
+		{"rodriguez", http.StatusOK, 1},
+		{"salah", http.StatusNotFound, 0},
+		{"nike", http.StatusNotFound, 0},
+		{"coutinho", http.StatusNotFound, 0},
+	}
+	firmino := as.getLoggedInUser("firmino")
+	richarlson := as.getLoggedInUser("richarlson")
+	efaLiv := as.getCustomer("EFA Liv")
+	uefaLiv := as.getCustomer("UEFA Liv")
+	efaEve := as.getCustomer("EFA Eve")
+	order := as.createOrder("ord1", models.OrderStatusDelivered, firmino.TenantID, firmino.ID, efaLiv.ID)
+	order.PickupCharges = models.NewNullMoney(5000, "CAD")
+	order.DropoffCharges = models.NewNullMoney(5000, "CAD")
+	order.PickupCost = models.NewNullMoney(2000, "CAD")
+	order.DropoffCost = models.NewNullMoney(2000, "CAD")
+	as.Nil(as.DB.Update(order))
+	as.createOrder("ord2", models.OrderStatusOpen, firmino.TenantID, firmino.ID, uefaLiv.ID)
+	as.createOrder("cancelled", models.OrderStatusCancelled, firmino.TenantID, firmino.ID, efaLiv.ID)
+	as.createOrder("eve", models.OrderStatusDelivered, richarlson.TenantID, richarlson.ID, efaEve.ID)
+	old := as.createOrder("old", models.OrderStatusDelivered, firmino.TenantID, firmino.ID, efaLiv.ID)
+	as.Nil(as.DB.RawQuery("UPDATE orders SET created_at = ? WHERE id = ?", time.Now().AddDate(0, -2, 0), old.ID).Exec())
+
+	for _, test := range tests {
+		as.T().Run(test.username, func(t *testing.T) {
+			user := as.getLoggedInUser(test.username)
+			res := as.setupRequest(user, "/reports/profitability").Get()
+			as.Equal(test.responseCode, res.Code)
+			if res.Code != http.StatusOK {
+				return
+			}
+			var rows = models.ProfitabilityRows{}
+			res.Bind(&rows)
+			as.Equal(test.count, len(rows))
+			for _, row := range rows {
+				if row.GroupID != efaLiv.ID.String() {
+					continue
+				}
+				as.Equal(efaLiv.Name, row.GroupName)
+				as.Equal("CAD", row.Currency)
+				as.Equal(1, row.Orders)
+				as.Equal(10000, row.Revenue)
+				as.Equal(4000, row.Cost)
+				as.Equal(6000, row.Margin)
+				as.Equal(nulls.NewFloat64(60), row.MarginPercent)
+			}
+		})
+	}
+}
+
+func (as *ActionSuite) Test_ReportsProfitabilityByLane() {
+	as.LoadFixture("Tenant bootstrap")
+	firmino := as.getLoggedInUser("firmino")
+	efaLiv := as.getCustomer("EFA Liv")
+	order := as.createOrder("ord1", models.OrderStatusDelivered, firmino.TenantID, firmino.ID, efaLiv.ID)
+	order.PickupCharges = models.NewNullMoney(5000, "CAD")
+	order.DropoffCharges = models.NewNullMoney(5000, "CAD")
+	order.PickupCost = models.NewNullMoney(0, "CAD")
+	order.DropoffCost = models.NewNullMoney(4000, "CAD")
+	as.Nil(as.DB.Update(order))
+	var newShipment = func(serial string, destination string) models.Shipment {
+		return models.Shipment{SerialNumber: serial, Status: models.ShipmentStatusDelivered.String(), Type: models.ShipmentTypeInbound.String(), CreatedBy: firmino.ID, TenantID: firmino.TenantID, Origin: nulls.NewString("Deltaport"), Destination: nulls.NewString(destination)}
+	}
+	as.createShipment(newShipment("s1", "Surrey"), order)
+	s2 := as.createShipment(newShipment("s2", "Langley"), order)
+	charge := &models.ShipmentCharge{ShipmentID: s2.ID, TenantID: firmino.TenantID, CreatedBy: firmino.ID, Type: models.ShipmentChargeTypeDetention.String(), Quantity: 2, CustomerRate: models.NewMoney(1500, "CAD"), CostRate: models.NewMoney(500, "CAD")}
+	v, err := as.DB.ValidateAndCreate(charge)
+	as.Nil(err)
+	as.Equal(0, len(v.Errors))
+
+	res := as.setupRequest(firmino, "/reports/profitability?group_by=Lane").Get()
+	as.Equal(http.StatusOK, res.Code)
+	var rows = models.ProfitabilityRows{}
+	res.Bind(&rows)
+	as.Equal(2, len(rows))
+	as.Equal("Deltaport -> Langley", rows[0].GroupName)
+	as.Equal(8000, rows[0].Revenue)
+	as.Equal(3000, rows[0].Cost)
+	as.Equal("Deltaport -> Surrey", rows[1].GroupName)
+	as.Equal(5000, rows[1].Revenue)
+	as.Equal(2000, rows[1].Cost)
+
+	res = as.setupRequest(firmino, "/reports/profitability?group_by=Customer&format=csv").Get()
+	as.Equal(http.StatusOK, res.Code)
+	as.Contains(res.Header().Get("Content-Type"), "text/csv")
+	records, err := csv.NewReader(strings.NewReader(res.Body.String())).ReadAll()
+	as.Nil(err)
+	as.Equal(2, len(records))
+	as.Equal([]string{"group_id", "group_name", "currency", "orders", "revenue", "cost", "margin", "margin_percent"}, records[0])
+	as.Equal([]string{efaLiv.ID.String(), efaLiv.Name, "CAD", "1", "13000", "5000", "8000", "61.54"}, records[1])
+}
+
+func (as *ActionSuite) Test_ReportsProfitabilityInvalidParams() {
+	as.LoadFixture("Tenant bootstrap")
+	mane := as.getLoggedInUser("mane")
+	for _, params := range []string{"group_by=Driver", "from=yesterday", "to=2021-13-01", "from=2021-02-01&to=2021-01-01"} {
+		as.T().Run(params, func(t *testing.T) {
+			res := as.setupRequest(mane, fmt.Sprintf("/reports/profitability?%s", params)).Get()
+			as.Equal(http.StatusBadRequest, res.Code)
+		})
+	}
+	res := as.setupRequest(mane, "/reports/profitability?from=2021-01-01&to=2021-01-31&group_by=Carrier").Get()
+	as.Equal(http.StatusOK, res.Code)
+	as.Equal("[]", strings.TrimSpace(res.Body.String()))
+}
+
+func (as *ActionSuite) Test_ReportsCompliance() {
+	as.LoadFixture("Tenant bootstrap")
+	mane := as.getLoggedInUser("mane")
+	salah := as.getLoggedInUser("salah")
+	lewin := as.getLoggedInUser("lewin")
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	as.createDriverCompliance(mane, models.DriverCompliance{DriverID: salah.ID, PortPassExpiry: nulls.NewTime(today.AddDate(0, 0, -1)), TwicExpiry: nulls.NewTime(today.AddDate(0, 0, 20)), MedicalCertificateExpiry: nulls.NewTime(today.AddDate(0, 2, 0))})
+	as.createDriverCompliance(as.getLoggedInUser("rodriguez"), models.DriverCompliance{DriverID: lewin.ID, TwicExpiry: nulls.NewTime(today)})
+	var tests = []struct {
+		username     string
+		to           string
+		responseCode int
+		count        int
+	}{
+		{"mane", "", http.StatusOK, 2},
+		{"mane", today.AddDate(0, 3, 0).Format(reportDateFormat), http.StatusOK, 3},
+		{"mane", "next month", http.StatusBadRequest, 0},
+		{"rodriguez", "", http.StatusOK, 1},
+		{"klopp", "", http.StatusOK, 3},
+		{"salah", "", http.StatusNotFound, 0},
+		{"nike", "", http.StatusNotFound, 0},
+	}
+	for _, test := range tests {
+		as.T().Run(fmt.Sprintf("%s-%s", test.username, test.to), func(t *testing.T) {
+			res := as.setupRequest(as.getLoggedInUser(test.username), "/reports/compliance?to="+test.to).Get()
+			as.Equal(test.responseCode, res.Code)
+			if res.Code != http.StatusOK {
+				return
+			}
+			var expirations = models.ComplianceExpirations{}
+			res.Bind(&expirations)
+			as.Equal(test.count, len(expirations))
+		})
+	}
+	res := as.setupRequest(mane, "/reports/compliance?format=csv").Get()
+	as.Equal(http.StatusOK, res.Code)
+	records, err := csv.NewReader(strings.NewReader(res.Body.String())).ReadAll()
+	as.Nil(err)
+	as.Equal(3, len(records))
+	as.Equal([]string{salah.ID.String(), "salah", "PortPass", today.AddDate(0, 0, -1).Format(reportDateFormat), "true"}, records[1])
+}
@@ -2,10 +2,12 @@ package actions
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/bigpanther/trober/firebase"
 	"github.com/bigpanther/trober/models"
 	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/nulls"
 	"github.com/gobuffalo/pop/v6"
 )
 
@@ -23,6 +25,45 @@ func selfGetTenant(c buffalo.Context) error {
 	return c.Render(http.StatusOK, r.JSON(tenant))
 }
 
+// tenantSettings are the settings of a tenant its admins can change
+type tenantSettings struct {
+	LocationRetentionDays nulls.Int `json:"location_retention_days"`
+	GeofenceArrivedMode   string    `json:"geofence_arrived_mode"`
+	GeofenceInTransitMode string    `json:"geofence_in_transit_mode"`
+}
+
+// selfPutTenant changes the settings of the tenant of the logged in admin. This function is mapped to the path
+// PUT /self/tenant
+func selfPutTenant(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	tenant := &models.Tenant{}
+	if err := tx.Find(tenant, loggedInUser(c).TenantID); err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	settings := &tenantSettings{}
+	if err := c.Bind(settings); err != nil {
+		c.Logger().Errorf("error binding tenant settings: %v\n", err)
+		return err
+	}
+	tenant.LocationRetentionDays = settings.LocationRetentionDays
+	// The geofence modes are kept when they are not given
+	if settings.GeofenceArrivedMode != "" {
+		tenant.GeofenceArrivedMode = settings.GeofenceArrivedMode
+	}
+	if settings.GeofenceInTransitMode != "" {
+		tenant.GeofenceInTransitMode = settings.GeofenceInTransitMode
+	}
+	tenant.UpdatedAt = time.Now().UTC()
+	verrs, err := tx.ValidateAndUpdate(tenant)
+	if err != nil {
+		return err
+	}
+	if verrs.HasAny() {
+		return c.Render(http.StatusUnprocessableEntity, r.JSON(verrs))
+	}
+	return c.Render(http.StatusOK, r.JSON(tenant))
+}
+
 func selfPostDeviceRegister(f firebase.Firebase) func(c buffalo.Context) error {
 	return func(c buffalo.Context) error {
 		deviceB := deviceID{}
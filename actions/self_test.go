@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/nulls"
 )
 
 func (as *ActionSuite) Test_SelfGetTenant() {
@@ -34,6 +35,55 @@ func (as *ActionSuite) Test_SelfGetTenant() {
 	}
 }
 
+func (as *ActionSuite) Test_SelfPutTenant() {
+	as.LoadFixture("Tenant bootstrap")
+	var tests = []struct {
+		username      string
+		retentionDays nulls.Int
+		responseCode  int
+	}{
+		{"mane", nulls.NewInt(7), http.StatusNotFound},
+		{"salah", nulls.NewInt(7), http.StatusNotFound},
+		{"nike", nulls.NewInt(7), http.StatusNotFound},
+		{"klopp", nulls.NewInt(7), http.StatusNotFound},
+		{"firmino", nulls.NewInt(0), http.StatusUnprocessableEntity},
+		{"firmino", nulls.NewInt(7), http.StatusOK},
+		{"richarlson", nulls.Int{}, http.StatusOK},
+	}
+	for _, test := range tests {
+		as.T().Run(test.username, func(t *testing.T) {
+			user := as.getLoggedInUser(test.username)
+			res := as.setupRequest(user, "/self/tenant").Put(tenantSettings{LocationRetentionDays: test.retentionDays})
+			as.Equal(test.responseCode, res.Code)
+			if res.Code != http.StatusOK {
+				return
+			}
+			var tenant = &models.Tenant{}
+			as.Nil(as.DB.Find(tenant, user.TenantID))
+			as.Equal(test.retentionDays, tenant.LocationRetentionDays)
+		})
+	}
+	var everton = &models.Tenant{}
+	as.Nil(as.DB.Find(everton, as.getLoggedInUser("richarlson").TenantID))
+	as.False(everton.LocationRetentionDays.Valid)
+}
+
+func (as *ActionSuite) Test_SelfPutTenantGeofenceModes() {
+	as.LoadFixture("Tenant bootstrap")
+	firmino := as.getLoggedInUser("firmino")
+	res := as.setupRequest(firmino, "/self/tenant").Put(tenantSettings{GeofenceArrivedMode: models.GeofenceTransitionModeAuto.String()})
+	as.Equal(http.StatusOK, res.Code)
+	var tenant = &models.Tenant{}
+	as.Nil(as.DB.Find(tenant, firmino.TenantID))
+	as.Equal(models.GeofenceTransitionModeAuto.String(), tenant.GeofenceArrivedMode)
+	as.Equal(models.GeofenceTransitionModeSuggest.String(), tenant.GeofenceInTransitMode)
+
+	res = as.setupRequest(firmino, "/self/tenant").Put(tenantSettings{GeofenceInTransitMode: "Sometimes"})
+	as.Equal(http.StatusUnprocessableEntity, res.Code)
+	res = as.setupRequest(as.getLoggedInUser("mane"), "/self/tenant").Put(tenantSettings{GeofenceInTransitMode: models.GeofenceTransitionModeAuto.String()})
+	as.Equal(http.StatusNotFound, res.Code)
+}
+
 func (as *ActionSuite) Test_SelfGet() {
 	as.LoadFixture("Tenant bootstrap")
 	var tests = []struct {
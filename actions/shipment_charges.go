@@ -0,0 +1,160 @@
+package actions
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/pop/v6"
+)
+
+// Following naming logic is implemented in Buffalo:
+// Model: Singular (ShipmentCharge)
+// DB Table: Plural (shipment_charges)
+// Resource: Plural (ShipmentCharges)
+// Path: Plural (/shipments/{shipment_id}/charges)
+
+// shipmentChargesList gets all ShipmentCharges of a Shipment, customers do not see the cost side.
+// This function is mapped to the path GET /shipments/{shipment_id}/charges
+func shipmentChargesList(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	shipment, err := findChargedShipment(c, tx)
+	if err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	charges := &models.ShipmentCharges{}
+	if err := tx.Scope(restrictedScope(c)).Where("shipment_id = ?", shipment.ID).Order("created_at ASC").All(charges); err != nil {
+		return err
+	}
+	if loggedInUser(c).IsCustomer() {
+		return c.Render(http.StatusOK, r.JSON(charges.ForCustomer()))
+	}
+	return c.Render(http.StatusOK, r.JSON(charges))
+}
+
+// shipmentChargesShow gets the data for one ShipmentCharge, customers do not see the cost side. This function is
+// mapped to the path GET /shipments/{shipment_id}/charges/{charge_id}
+func shipmentChargesShow(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	shipment, err := findChargedShipment(c, tx)
+	if err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	charge := &models.ShipmentCharge{}
+	if err := tx.Scope(restrictedScope(c)).Where("shipment_id = ?", shipment.ID).Find(charge, c.Param("charge_id")); err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	if loggedInUser(c).IsCustomer() {
+		return c.Render(http.StatusOK, r.JSON(charge.ForCustomer()))
+	}
+	return c.Render(http.StatusOK, r.JSON(charge))
+}
+
+// shipmentChargesCreate adds a ShipmentCharge to the DB. This function is mapped to the
+// path POST /shipments/{shipment_id}/charges
+func shipmentChargesCreate(c buffalo.Context) error {
+	charge := &models.ShipmentCharge{}
+	if err := c.Bind(charge); err != nil {
+		c.Logger().Errorf("error binding shipment charge: %v\n", err)
+		return err
+	}
+	tx := c.Value("tx").(*pop.Connection)
+	var loggedInUser = loggedInUser(c)
+	shipment, err := findChargedShipment(c, tx)
+	if err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	if err := checkOrderNotLocked(tx, shipment.OrderID); err != nil {
+		return c.Error(http.StatusConflict, err)
+	}
+	charge.CreatedBy = loggedInUser.ID
+	charge.TenantID = shipment.TenantID
+	charge.ShipmentID = shipment.ID
+	currency, err := tenantCurrency(tx, shipment.TenantID)
+	if err != nil {
+		return err
+	}
+	charge.SetDefaultCurrency(currency)
+	verrs, err := tx.ValidateAndCreate(charge)
+	if err != nil {
+		return err
+	}
+	if verrs.HasAny() {
+		return c.Render(http.StatusUnprocessableEntity, r.JSON(verrs))
+	}
+	return c.Render(http.StatusCreated, r.JSON(charge))
+}
+
+// shipmentChargesUpdate changes a ShipmentCharge in the DB. This function is mapped to
+// the path PUT /shipments/{shipment_id}/charges/{charge_id}
+func shipmentChargesUpdate(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	shipment, err := findChargedShipment(c, tx)
+	if err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	charge := &models.ShipmentCharge{}
+	if err := tx.Scope(restrictedScope(c)).Where("shipment_id = ?", shipment.ID).Find(charge, c.Param("charge_id")); err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	if err := checkOrderNotLocked(tx, shipment.OrderID); err != nil {
+		return c.Error(http.StatusConflict, err)
+	}
+	newCharge := &models.ShipmentCharge{}
+	if err := c.Bind(newCharge); err != nil {
+		c.Logger().Errorf("error binding shipment charge: %v\n", err)
+		return err
+	}
+	charge.Type = newCharge.Type
+	charge.Description = newCharge.Description
+	charge.Quantity = newCharge.Quantity
+	newCharge.SetDefaultCurrency(charge.Currency())
+	charge.CustomerRate = newCharge.CustomerRate
+	charge.CostRate = newCharge.CostRate
+	charge.UpdatedAt = time.Now().UTC()
+	verrs, err := tx.ValidateAndUpdate(charge)
+	if err != nil {
+		return err
+	}
+	if verrs.HasAny() {
+		return c.Render(http.StatusUnprocessableEntity, r.JSON(verrs))
+	}
+	return c.Render(http.StatusOK, r.JSON(charge))
+}
+
+// shipmentChargesDestroy deletes a ShipmentCharge from the DB. This function is mapped
+// to the path DELETE /shipments/{shipment_id}/charges/{charge_id}
+func shipmentChargesDestroy(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	shipment, err := findChargedShipment(c, tx)
+	if err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	charge := &models.ShipmentCharge{}
+	if err := tx.Scope(restrictedScope(c)).Where("shipment_id = ?", shipment.ID).Find(charge, c.Param("charge_id")); err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	if err := checkOrderNotLocked(tx, shipment.OrderID); err != nil {
+		return c.Error(http.StatusConflict, err)
+	}
+	if err := tx.Destroy(charge); err != nil {
+		return err
+	}
+	c.Response().WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// findChargedShipment loads the shipment the charges belong to. Customers only see their own shipments.
+func findChargedShipment(c buffalo.Context, tx *pop.Connection) (*models.Shipment, error) {
+	var loggedInUser = loggedInUser(c)
+	q := tx.Scope(restrictedScope(c))
+	if loggedInUser.IsCustomer() {
+		q = q.Where("customer_id = ?", loggedInUser.CustomerID)
+	}
+	shipment := &models.Shipment{}
+	if err := q.Find(shipment, c.Param("shipment_id")); err != nil {
+		return nil, err
+	}
+	return shipment, nil
+}
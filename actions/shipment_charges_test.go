@@ -0,0 +1,190 @@
+package actions
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/nulls"
+)
+
+func (as *ActionSuite) Test_ShipmentChargesCreate() {
+	as.LoadFixture("Tenant bootstrap")
+	var tests = []struct {
+		username     string
+		responseCode int
+	}{
+		{"klopp", http.StatusCreated},
+		{"firmino", http.StatusCreated},
+		{"mane", http.StatusCreated},
+		{"salah", http.StatusNotFound},
+		{"nike", http.StatusNotFound},
+		{"richarlson", http.StatusNotFound},
+		{"rodriguez", http.StatusNotFound},
+		{"coutinho", http.StatusNotFound},
+	}
+	firmino := as.getLoggedInUser("firmino")
+	efaLiv := as.getCustomer("EFA Liv")
+	order := as.createOrder("ord1", models.OrderStatusOpen, firmino.TenantID, firmino.ID, efaLiv.ID)
+	shipment := as.createShipment(models.Shipment{SerialNumber: "chg1", Status: models.ShipmentStatusUnassigned.String(), Type: models.ShipmentTypeInbound.String(), CreatedBy: firmino.ID, TenantID: firmino.TenantID}, order)
+	for _, test := range tests {
+		as.T().Run(test.username, func(t *testing.T) {
+			user := as.getLoggedInUser(test.username)
+			charge := models.ShipmentCharge{Type: models.ShipmentChargeTypeDetention.String(), Quantity: 3, CustomerRate: models.NewMoney(7500, "CAD"), CostRate: models.NewMoney(5000, "CAD")}
+			res := as.setupRequest(user, fmt.Sprintf("/shipments/%s/charges", shipment.ID)).Post(charge)
+			as.Equal(test.responseCode, res.Code)
+			if res.Code != http.StatusCreated {
+				return
+			}
+			var created = models.ShipmentCharge{}
+			res.Bind(&created)
+			as.Equal(shipment.ID, created.ShipmentID)
+			as.Equal(shipment.TenantID, created.TenantID)
+			as.Equal(user.ID, created.CreatedBy)
+			as.Equal(models.NewMoney(22500, "CAD"), created.CustomerAmount())
+			as.Equal(models.NewMoney(15000, "CAD"), created.CostAmount())
+		})
+	}
+	mane := as.getLoggedInUser("mane")
+	res := as.setupRequest(mane, fmt.Sprintf("/shipments/%s/charges", shipment.ID)).Post(models.ShipmentCharge{Type: "invalid", CustomerRate: models.NewMoney(0, "cad")})
+	as.Equal(http.StatusUnprocessableEntity, res.Code)
+	// The currency defaults to the one of the tenant
+	res = as.setupRequest(mane, fmt.Sprintf("/shipments/%s/charges", shipment.ID)).Post(models.ShipmentCharge{Type: models.ShipmentChargeTypeOverweight.String(), Quantity: 1, CustomerRate: models.NewMoney(100, "")})
+	as.Equal(http.StatusCreated, res.Code)
+	var created = models.ShipmentCharge{}
+	res.Bind(&created)
+	as.Equal(models.NewMoney(100, models.DefaultCurrency), created.CustomerRate)
+	as.Equal(models.NewMoney(0, models.DefaultCurrency), created.CostRate)
+}
+
+func (as *ActionSuite) Test_ShipmentChargesList() {
+	as.LoadFixture("Tenant bootstrap")
+	var tests = []struct {
+		username     string
+		responseCode int
+	}{
+		{"klopp", http.StatusOK},
+		{"firmino", http.StatusOK},
+		{"mane", http.StatusOK},
+		{"nike", http.StatusOK},
+		{"salah", http.StatusNotFound},
+		{"richarlson", http.StatusNotFound},
+		{"adidas", http.StatusNotFound},
+		{"coutinho", http.StatusNotFound},
+	}
+	firmino := as.getLoggedInUser("firmino")
+	salah := as.getLoggedInUser("salah")
+	efaLiv := as.getCustomer("EFA Liv")
+	order := as.createOrder("ord1", models.OrderStatusOpen, firmino.TenantID, firmino.ID, efaLiv.ID)
+	shipment := as.createShipment(models.Shipment{SerialNumber: "chg1", Status: models.ShipmentStatusAssigned.String(), Type: models.ShipmentTypeInbound.String(), CreatedBy: firmino.ID, TenantID: firmino.TenantID, DriverID: nulls.NewUUID(salah.ID)}, order)
+	for _, chargeType := range []models.ShipmentChargeType{models.ShipmentChargeTypeStorage, models.ShipmentChargeTypeChassisSplit} {
+		res := as.setupRequest(firmino, fmt.Sprintf("/shipments/%s/charges", shipment.ID)).Post(models.ShipmentCharge{Type: chargeType.String(), Quantity: 1, CustomerRate: models.NewMoney(100, "CAD"), CostRate: models.NewMoney(50, "CAD")})
+		as.Equal(http.StatusCreated, res.Code)
+	}
+	for _, test := range tests {
+		as.T().Run(test.username, func(t *testing.T) {
+			user := as.getLoggedInUser(test.username)
+			res := as.setupRequest(user, fmt.Sprintf("/shipments/%s/charges", shipment.ID)).Get()
+			as.Equal(test.responseCode, res.Code)
+			if res.Code != http.StatusOK {
+				return
+			}
+			var charges = models.ShipmentCharges{}
+			res.Bind(&charges)
+			as.Equal(2, len(charges))
+			as.Equal(models.ShipmentChargeTypeStorage.String(), charges[0].Type)
+
+			res = as.setupRequest(user, fmt.Sprintf("/shipments/%s/charges/%s", shipment.ID, charges[1].ID)).Get()
+			as.Equal(http.StatusOK, res.Code)
+			var charge = models.ShipmentCharge{}
+			res.Bind(&charge)
+			as.Equal(models.ShipmentChargeTypeChassisSplit.String(), charge.Type)
+			as.Equal(models.NewMoney(100, "CAD"), charge.CustomerRate)
+			if user.IsCustomer() {
+				as.Equal(models.Money{}, charge.CostRate)
+			} else {
+				as.Equal(models.NewMoney(50, "CAD"), charge.CostRate)
+			}
+		})
+	}
+}
+
+func (as *ActionSuite) Test_ShipmentChargesHideCostFromCustomers() {
+	as.LoadFixture("Tenant bootstrap")
+	firmino := as.getLoggedInUser("firmino")
+	nike := as.getLoggedInUser("nike")
+	efaLiv := as.getCustomer("EFA Liv")
+	order := as.createOrder("ord1", models.OrderStatusOpen, firmino.TenantID, firmino.ID, efaLiv.ID)
+	shipment := as.createShipment(models.Shipment{SerialNumber: "chg1", Status: models.ShipmentStatusUnassigned.String(), Type: models.ShipmentTypeInbound.String(), CreatedBy: firmino.ID, TenantID: firmino.TenantID}, order)
+	res := as.setupRequest(firmino, fmt.Sprintf("/shipments/%s/charges", shipment.ID)).Post(models.ShipmentCharge{Type: models.ShipmentChargeTypeStorage.String(), Quantity: 2, CustomerRate: models.NewMoney(100, "CAD"), CostRate: models.NewMoney(50, "CAD")})
+	as.Equal(http.StatusCreated, res.Code)
+	var created = models.ShipmentCharge{}
+	res.Bind(&created)
+
+	res = as.setupRequest(nike, fmt.Sprintf("/shipments/%s/charges", shipment.ID)).Get()
+	as.Equal(http.StatusOK, res.Code)
+	var charges = []map[string]interface{}{}
+	res.Bind(&charges)
+	as.Equal(1, len(charges))
+	res = as.setupRequest(nike, fmt.Sprintf("/shipments/%s/charges/%s", shipment.ID, created.ID)).Get()
+	as.Equal(http.StatusOK, res.Code)
+	var charge = map[string]interface{}{}
+	res.Bind(&charge)
+	for _, c := range append(charges, charge) {
+		as.Equal(map[string]interface{}{"amount": float64(100), "currency": "CAD"}, c["customer_rate"])
+		for key := range c {
+			as.NotContains(key, "cost")
+		}
+	}
+
+	res = as.setupRequest(firmino, fmt.Sprintf("/shipments/%s/charges/%s", shipment.ID, created.ID)).Get()
+	as.Equal(http.StatusOK, res.Code)
+	charge = map[string]interface{}{}
+	res.Bind(&charge)
+	as.Equal(map[string]interface{}{"amount": float64(50), "currency": "CAD"}, charge["cost_rate"])
+}
+
+func (as *ActionSuite) Test_ShipmentChargesUpdateDestroy() {
+	as.LoadFixture("Tenant bootstrap")
+	firmino := as.getLoggedInUser("firmino")
+	mane := as.getLoggedInUser("mane")
+	nike := as.getLoggedInUser("nike")
+	rodriguez := as.getLoggedInUser("rodriguez")
+	efaLiv := as.getCustomer("EFA Liv")
+	order := as.createOrder("ord1", models.OrderStatusOpen, firmino.TenantID, firmino.ID, efaLiv.ID)
+	shipment := as.createShipment(models.Shipment{SerialNumber: "chg1", Status: models.ShipmentStatusUnassigned.String(), Type: models.ShipmentTypeInbound.String(), CreatedBy: firmino.ID, TenantID: firmino.TenantID}, order)
+	res := as.setupRequest(mane, fmt.Sprintf("/shipments/%s/charges", shipment.ID)).Post(models.ShipmentCharge{Type: models.ShipmentChargeTypePrePull.String(), Quantity: 1, CustomerRate: models.NewMoney(100, "CAD"), CostRate: models.NewMoney(50, "CAD")})
+	as.Equal(http.StatusCreated, res.Code)
+	var charge = models.ShipmentCharge{}
+	res.Bind(&charge)
+	var chargePath = fmt.Sprintf("/shipments/%s/charges/%s", shipment.ID, charge.ID)
+
+	charge.Quantity = 2
+	charge.Description = nulls.NewString("Pulled a day early")
+	for _, user := range []*models.User{nike, rodriguez} {
+		res = as.setupRequest(user, chargePath).Put(charge)
+		as.Equal(http.StatusNotFound, res.Code)
+	}
+	res = as.setupRequest(mane, chargePath).Put(charge)
+	as.Equal(http.StatusOK, res.Code)
+	var updated = models.ShipmentCharge{}
+	res.Bind(&updated)
+	as.Equal(2, updated.Quantity)
+	as.Equal("Pulled a day early", updated.Description.String)
+
+	// Charges cannot change once the order has been invoiced
+	order.Status = models.OrderStatusInvoiced.String()
+	as.Nil(as.DB.Update(order))
+	res = as.setupRequest(mane, chargePath).Put(charge)
+	as.Equal(http.StatusConflict, res.Code)
+	res = as.setupRequest(mane, chargePath).Delete()
+	as.Equal(http.StatusConflict, res.Code)
+
+	order.Status = models.OrderStatusDelivered.String()
+	as.Nil(as.DB.Update(order))
+	res = as.setupRequest(mane, chargePath).Delete()
+	as.Equal(http.StatusNoContent, res.Code)
+	res = as.setupRequest(mane, chargePath).Get()
+	as.Equal(http.StatusNotFound, res.Code)
+}
@@ -0,0 +1,50 @@
+package actions
+
+import (
+	"net/http"
+
+	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/nulls"
+	"github.com/gobuffalo/pop/v6"
+)
+
+// shipmentsHistory gets the status timeline of a Shipment. This function is mapped to
+// the path GET /shipments/{shipment_id}/history
+func shipmentsHistory(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	shipment := &models.Shipment{}
+	var loggedInUser = loggedInUser(c)
+	q := tx.Scope(restrictedScope(c))
+	if loggedInUser.IsDriver() {
+		q = q.Where("driver_id = ?", loggedInUser.ID)
+	}
+	if loggedInUser.IsCustomer() {
+		q = q.Where("customer_id = ?", loggedInUser.CustomerID)
+	}
+	if err := q.Find(shipment, c.Param("shipment_id")); err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	events := &models.ShipmentEvents{}
+	if err := tx.Scope(restrictedScope(c)).Where("shipment_id = ?", shipment.ID).Order("created_at ASC").All(events); err != nil {
+		return err
+	}
+	return c.Render(http.StatusOK, r.JSON(events))
+}
+
+// createShipmentEvent records the change of a shipment in its history.
+// The event belongs to the current driver, or to the previous one when the shipment lost its driver.
+func createShipmentEvent(tx *pop.Connection, shipment *models.Shipment, fromStatus string, previousDriverID nulls.UUID, loggedInUser *models.User) error {
+	driverID := shipment.DriverID
+	if !driverID.Valid {
+		driverID = previousDriverID
+	}
+	verrs, err := tx.ValidateAndCreate(models.NewShipmentEvent(shipment, fromStatus, driverID, loggedInUser.ID))
+	if err != nil {
+		return err
+	}
+	if verrs.HasAny() {
+		return verrs
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package actions
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/nulls"
+	"github.com/golang/mock/gomock"
+)
+
+func (as *ActionSuite) Test_ShipmentsHistory() {
+	as.LoadFixture("Tenant bootstrap")
+	var tests = []struct {
+		username     string
+		responseCode int
+	}{
+		{"klopp", http.StatusOK},
+		{"firmino", http.StatusOK},
+		{"mane", http.StatusOK},
+		// The shipment was rejected and is no longer visible to the driver
+		{"salah", http.StatusNotFound},
+		{"nike", http.StatusOK},
+		{"richarlson", http.StatusNotFound},
+		{"rodriguez", http.StatusNotFound},
+		{"lewin", http.StatusNotFound},
+		{"adidas", http.StatusNotFound},
+		{"coutinho", http.StatusNotFound},
+	}
+	firmino := as.getLoggedInUser("firmino")
+	mane := as.getLoggedInUser("mane")
+	salah := as.getLoggedInUser("salah")
+	efaLiv := as.getCustomer("EFA Liv")
+	order := as.createOrder("ord1", models.OrderStatusOpen, firmino.TenantID, firmino.ID, efaLiv.ID)
+	mockFirebase.EXPECT().SendAll(gomock.Any(), gomock.Any()).AnyTimes()
+
+	res := as.setupRequest(mane, "/shipments").Post(models.Shipment{SerialNumber: "hist1", Type: models.ShipmentTypeInbound.String(), OrderID: nulls.NewUUID(order.ID), DriverID: nulls.NewUUID(salah.ID)})
+	as.Equal(http.StatusCreated, res.Code)
+	var shipment = models.Shipment{}
+	res.Bind(&shipment)
+	as.Equal(models.ShipmentStatusAssigned.String(), shipment.Status)
+	for _, status := range []models.ShipmentStatus{models.ShipmentStatusAccepted, models.ShipmentStatusRejected} {
+		shipment.Status = status.String()
+		res = as.setupRequest(salah, fmt.Sprintf("/shipments/%s", shipment.ID)).Put(shipment)
+		as.Equal(http.StatusOK, res.Code)
+	}
+
+	for _, test := range tests {
+		as.T().Run(test.username, func(t *testing.T) {
+			user := as.getLoggedInUser(test.username)
+			res := as.setupRequest(user, fmt.Sprintf("/shipments/%s/history", shipment.ID)).Get()
+			as.Equal(test.responseCode, res.Code)
+			if res.Code != http.StatusOK {
+				return
+			}
+			var events = models.ShipmentEvents{}
+			res.Bind(&events)
+			as.Equal(3, len(events))
+			as.False(events[0].FromStatus.Valid)
+			as.Equal(models.ShipmentStatusAssigned.String(), events[0].ToStatus)
+			as.Equal(mane.ID, events[0].CreatedBy)
+			as.Equal(models.ShipmentStatusAssigned.String(), events[1].FromStatus.String)
+			as.Equal(models.ShipmentStatusAccepted.String(), events[1].ToStatus)
+			as.Equal(salah.ID, events[1].CreatedBy)
+			as.Equal(models.ShipmentStatusRejected.String(), events[2].ToStatus)
+			// The driver is kept on the event even though the shipment lost its driver
+			as.Equal(salah.ID, events[2].DriverID.UUID)
+			for _, e := range events {
+				as.Equal(shipment.ID, e.ShipmentID)
+				as.Equal(firmino.TenantID, e.TenantID)
+			}
+		})
+	}
+}
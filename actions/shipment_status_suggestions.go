@@ -0,0 +1,211 @@
+package actions
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bigpanther/trober/firebase"
+	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/nulls"
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gobuffalo/validate/v3"
+)
+
+// Following naming logic is implemented in Buffalo:
+// Model: Singular (ShipmentStatusSuggestion)
+// DB Table: Plural (shipment_status_suggestions)
+// Resource: Plural (ShipmentStatusSuggestions)
+// Path: Plural (/status-suggestions)
+
+var errStatusSuggestionReviewed = errors.New("status suggestion has already been reviewed")
+var errStatusSuggestionStale = errors.New("shipment status has changed since the suggestion")
+
+// statusSuggestionsList gets all ShipmentStatusSuggestions, drivers only get their own. Params "shipment_id" and "status"
+// filter the suggestions. This function is mapped to the path GET /status-suggestions
+func statusSuggestionsList(c buffalo.Context) error {
+	var loggedInUser = loggedInUser(c)
+	tx := c.Value("tx").(*pop.Connection)
+	suggestions := &models.ShipmentStatusSuggestions{}
+
+	// Paginate results. Params "page" and "per_page" control pagination.
+	// Default values are "page=1" and "per_page=20".
+	q := tx.PaginateFromParams(c.Params())
+
+	if loggedInUser.IsDriver() {
+		q = q.Where("driver_id = ?", loggedInUser.ID)
+	}
+	if shipmentID := c.Param("shipment_id"); shipmentID != "" {
+		q = q.Where("shipment_id = ?", shipmentID)
+	}
+	if status := c.Param("status"); status != "" {
+		q = q.Where("status = ?", status)
+	}
+	if err := q.Scope(restrictedScope(c)).Order("recorded_at DESC").All(suggestions); err != nil {
+		return err
+	}
+	return c.Render(http.StatusOK, r.JSON(suggestions))
+}
+
+// statusSuggestionsAccept applies the status change of a ShipmentStatusSuggestion. This function is mapped to
+// the path POST /status-suggestions/{status_suggestion_id}/accept
+func statusSuggestionsAccept(c buffalo.Context) error {
+	return reviewStatusSuggestion(c, models.StatusSuggestionStatusAccepted)
+}
+
+// statusSuggestionsDismiss dismisses a ShipmentStatusSuggestion, the shipment keeps its status. This function is mapped to
+// the path POST /status-suggestions/{status_suggestion_id}/dismiss
+func statusSuggestionsDismiss(c buffalo.Context) error {
+	return reviewStatusSuggestion(c, models.StatusSuggestionStatusDismissed)
+}
+
+// reviewStatusSuggestion accepts or dismisses a pending suggestion
+func reviewStatusSuggestion(c buffalo.Context, status models.StatusSuggestionStatus) error {
+	var loggedInUser = loggedInUser(c)
+	tx := c.Value("tx").(*pop.Connection)
+	suggestion := &models.ShipmentStatusSuggestion{}
+	q := tx.Scope(restrictedScope(c))
+	if loggedInUser.IsDriver() {
+		q = q.Where("driver_id = ?", loggedInUser.ID)
+	}
+	if err := q.Find(suggestion, c.Param("status_suggestion_id")); err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	if suggestion.IsReviewed() {
+		return c.Error(http.StatusConflict, errStatusSuggestionReviewed)
+	}
+	if status == models.StatusSuggestionStatusAccepted {
+		shipment := &models.Shipment{}
+		if err := tx.Find(shipment, suggestion.ShipmentID); err != nil {
+			return c.Error(http.StatusNotFound, err)
+		}
+		if shipment.Status != suggestion.FromStatus {
+			return c.Error(http.StatusConflict, errStatusSuggestionStale)
+		}
+		verrs, err := applyShipmentStatus(c, tx, loggedInUser, shipment, models.ShipmentStatus(suggestion.ToStatus))
+		if errors.Is(err, errOrderLocked) {
+			return c.Error(http.StatusConflict, err)
+		}
+		var transitionErr *models.ShipmentStatusTransitionError
+		if errors.As(err, &transitionErr) {
+			return renderShipmentStatusTransitionError(c, err)
+		}
+		if err != nil {
+			return err
+		}
+		if verrs.HasAny() {
+			return c.Render(http.StatusUnprocessableEntity, r.JSON(verrs))
+		}
+	}
+	suggestion.Status = status.String()
+	suggestion.ReviewedBy = nulls.NewUUID(loggedInUser.ID)
+	suggestion.ReviewedAt = nulls.NewTime(time.Now().UTC())
+	suggestion.UpdatedAt = time.Now().UTC()
+	verrs, err := tx.ValidateAndUpdate(suggestion)
+	if err != nil {
+		return err
+	}
+	if verrs.HasAny() {
+		return c.Render(http.StatusUnprocessableEntity, r.JSON(verrs))
+	}
+	return c.Render(http.StatusOK, r.JSON(suggestion))
+}
+
+// applyGeofenceTransitions changes the status of the shipments whose terminal geofence the new points of the driver
+// crossed, or suggests the change to the driver, as the tenant chooses. A change that cannot be applied is suggested.
+func applyGeofenceTransitions(c buffalo.Context, tx *pop.Connection, loggedInUser *models.User, shipments models.Shipments, locations models.DriverLocations) error {
+	tenant := &models.Tenant{}
+	if err := tx.Find(tenant, loggedInUser.TenantID); err != nil {
+		return err
+	}
+	for i := range shipments {
+		var shipment = &shipments[i]
+		if shipment.Terminal == nil {
+			continue
+		}
+		var points = models.DriverLocations{}
+		var earliest time.Time
+		for _, l := range locations {
+			if l.ShipmentID.Valid && l.ShipmentID.UUID == shipment.ID {
+				points = append(points, l)
+				if earliest.IsZero() || l.RecordedAt.Before(earliest) {
+					earliest = l.RecordedAt
+				}
+			}
+		}
+		if len(points) == 0 {
+			continue
+		}
+		previous, err := models.LatestShipmentLocation(tx, shipment.ID, earliest)
+		if err != nil {
+			return err
+		}
+		transition := models.DetectGeofenceTransition(shipment, shipment.Terminal.Geofence(), previous, points)
+		if transition == nil {
+			continue
+		}
+		if tenant.GeofenceTransitionMode(transition.To) == models.GeofenceTransitionModeAuto {
+			verrs, err := applyShipmentStatus(c, tx, loggedInUser, shipment, transition.To)
+			var transitionErr *models.ShipmentStatusTransitionError
+			if err != nil && !errors.Is(err, errOrderLocked) && !errors.As(err, &transitionErr) {
+				return err
+			}
+			if err == nil && !verrs.HasAny() {
+				continue
+			}
+			c.Logger().Warnf("error applying geofence status %s to shipment %s: %v %v", transition.To, shipment.ID, err, verrs)
+			shipment.Status = transition.From.String()
+		}
+		if err := suggestShipmentStatus(tx, loggedInUser, transition); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyShipmentStatus moves a shipment to the status caused by the geofence of its terminal.
+// The position of the driver makes the change for them, anyone else accepting it must be allowed the change by their role.
+func applyShipmentStatus(c buffalo.Context, tx *pop.Connection, loggedInUser *models.User, shipment *models.Shipment, to models.ShipmentStatus) (*validate.Errors, error) {
+	from := models.ShipmentStatus(shipment.Status)
+	if err := models.CheckGeofenceShipmentStatusTransition(from, to); err != nil {
+		return nil, err
+	}
+	if !loggedInUser.IsDriver() {
+		if err := models.CheckShipmentStatusTransition(loggedInUser, from, to); err != nil {
+			return nil, err
+		}
+	}
+	return changeShipmentStatus(c, tx, loggedInUser, shipment, to)
+}
+
+// suggestShipmentStatus records the suggestion of a status change and asks the driver to confirm it.
+// A change already pending for the shipment is not suggested again.
+func suggestShipmentStatus(tx *pop.Connection, loggedInUser *models.User, transition *models.GeofenceTransition) error {
+	exists, err := tx.Where("shipment_id = ?", transition.Shipment.ID).Where("to_status = ?", transition.To.String()).
+		Where("status = ?", models.StatusSuggestionStatusPending.String()).Exists(&models.ShipmentStatusSuggestion{})
+	if err != nil || exists {
+		return err
+	}
+	suggestion := models.NewShipmentStatusSuggestion(transition)
+	verrs, err := tx.ValidateAndCreate(suggestion)
+	if err != nil {
+		return err
+	}
+	if verrs.HasAny() {
+		return verrs
+	}
+	sendNotificationsAsync(
+		models.NotificationEventStatusSuggestion,
+		[]string{firebase.GetDriverTopic(loggedInUser.TenantID.String(), loggedInUser.ID.String())},
+		fmt.Sprintf("Mark your shipment %s? - %s", transition.To, transition.Shipment.SerialNumber),
+		transition.Shipment.SerialNumber,
+		map[string]string{
+			"shipment.id":           transition.Shipment.ID.String(),
+			"shipment.serialNumber": transition.Shipment.SerialNumber,
+			"statusSuggestion.id":   suggestion.ID.String(),
+		},
+	)
+	return nil
+}
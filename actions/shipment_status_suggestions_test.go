@@ -0,0 +1,127 @@
+package actions
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/nulls"
+	"github.com/golang/mock/gomock"
+)
+
+var (
+	deltaport = models.DriverLocation{Latitude: 49.0069, Longitude: -123.1548}
+	tsawwasen = models.DriverLocation{Latitude: 49.0211, Longitude: -123.0825}
+)
+
+func (as *ActionSuite) createGeofencedShipment(serialNumber string, status models.ShipmentStatus) *models.Shipment {
+	salah := as.getLoggedInUser("salah")
+	efaLiv := as.getCustomer("EFA Liv")
+	terminal := as.createTerminal("Deltaport "+serialNumber, models.TerminalTypePort, salah.TenantID, salah.ID)
+	terminal.Latitude = nulls.NewFloat64(deltaport.Latitude)
+	terminal.Longitude = nulls.NewFloat64(deltaport.Longitude)
+	as.Nil(as.DB.Update(terminal))
+	order := as.createOrder(serialNumber, models.OrderStatusAccepted, salah.TenantID, salah.ID, efaLiv.ID)
+	return as.createShipment(models.Shipment{SerialNumber: serialNumber, Status: status.String(), Type: models.ShipmentTypeInbound.String(),
+		CreatedBy: salah.ID, TenantID: salah.TenantID, DriverID: nulls.NewUUID(salah.ID), TerminalID: nulls.NewUUID(terminal.ID)}, order)
+}
+
+func (as *ActionSuite) postDriverLocations(username string, locations ...models.DriverLocation) {
+	var now = time.Now().UTC()
+	var points = models.DriverLocations{}
+	for i, l := range locations {
+		points = append(points, models.DriverLocation{RecordedAt: now.Add(time.Duration(i-len(locations)) * time.Minute), Latitude: l.Latitude, Longitude: l.Longitude})
+	}
+	res := as.setupRequest(as.getLoggedInUser(username), "/self/locations").Post(points)
+	as.Equal(http.StatusCreated, res.Code, res.Body.String())
+}
+
+func (as *ActionSuite) Test_GeofenceSuggestedTransitions() {
+	as.LoadFixture("Tenant bootstrap")
+	mockFirebase.EXPECT().SendAll(gomock.Any(), gomock.Any()).AnyTimes()
+	shipment := as.createGeofencedShipment("arrive", models.ShipmentStatusAccepted)
+	as.postDriverLocations("salah", tsawwasen, deltaport)
+	as.postDriverLocations("salah", deltaport)
+	as.Nil(as.DB.Reload(shipment))
+	as.Equal(models.ShipmentStatusAccepted.String(), shipment.Status)
+
+	var suggestions = models.ShipmentStatusSuggestions{}
+	res := as.setupRequest(as.getLoggedInUser("salah"), "/status-suggestions?status=Pending").Get()
+	as.Equal(http.StatusOK, res.Code)
+	res.Bind(&suggestions)
+	as.Len(suggestions, 1)
+	suggestion := suggestions[0]
+	as.Equal(shipment.ID, suggestion.ShipmentID)
+	as.Equal(models.ShipmentStatusArrived.String(), suggestion.ToStatus)
+
+	var tests = []struct {
+		username     string
+		action       string
+		responseCode int
+	}{
+		{"nike", "accept", http.StatusNotFound},
+		{"lewin", "accept", http.StatusNotFound},
+		{"rodriguez", "dismiss", http.StatusNotFound},
+		{"salah", "accept", http.StatusOK},
+		{"mane", "dismiss", http.StatusConflict},
+	}
+	for _, test := range tests {
+		as.T().Run(test.username+test.action, func(t *testing.T) {
+			res := as.setupRequest(as.getLoggedInUser(test.username), fmt.Sprintf("/status-suggestions/%s/%s", suggestion.ID, test.action)).Post(nil)
+			as.Equal(test.responseCode, res.Code, res.Body.String())
+		})
+	}
+	as.Nil(as.DB.Reload(shipment))
+	as.Equal(models.ShipmentStatusArrived.String(), shipment.Status)
+	count, err := as.DB.Where("shipment_id = ?", shipment.ID).Where("to_status = ?", models.ShipmentStatusArrived.String()).Count(&models.ShipmentEvent{})
+	as.Nil(err)
+	as.Equal(1, count)
+
+	// A suggestion of a shipment whose status has changed can only be dismissed
+	other := as.createGeofencedShipment("stale", models.ShipmentStatusAccepted)
+	as.postDriverLocations("salah", deltaport)
+	res = as.setupRequest(as.getLoggedInUser("mane"), fmt.Sprintf("/status-suggestions?shipment_id=%s", other.ID)).Get()
+	res.Bind(&suggestions)
+	as.Len(suggestions, 1)
+	other.Status = models.ShipmentStatusLoaded.String()
+	as.Nil(as.DB.Update(other))
+	res = as.setupRequest(as.getLoggedInUser("mane"), fmt.Sprintf("/status-suggestions/%s/accept", suggestions[0].ID)).Post(nil)
+	as.Equal(http.StatusConflict, res.Code)
+	res = as.setupRequest(as.getLoggedInUser("mane"), fmt.Sprintf("/status-suggestions/%s/dismiss", suggestions[0].ID)).Post(nil)
+	as.Equal(http.StatusOK, res.Code, res.Body.String())
+}
+
+func (as *ActionSuite) Test_GeofenceAutoTransitions() {
+	as.LoadFixture("Tenant bootstrap")
+	mockFirebase.EXPECT().SendAll(gomock.Any(), gomock.Any()).AnyTimes()
+	klopp := as.getLoggedInUser("klopp")
+	salah := as.getLoggedInUser("salah")
+	var tenant = models.Tenant{}
+	res := as.setupRequest(klopp, fmt.Sprintf("/tenants/%s", salah.TenantID)).Get()
+	res.Bind(&tenant)
+	as.Equal(models.GeofenceTransitionModeSuggest.String(), tenant.GeofenceInTransitMode)
+	tenant.GeofenceInTransitMode = models.GeofenceTransitionModeAuto.String()
+	res = as.setupRequest(klopp, fmt.Sprintf("/tenants/%s", salah.TenantID)).Put(tenant)
+	as.Equal(http.StatusOK, res.Code, res.Body.String())
+
+	loaded := as.createGeofencedShipment("leave", models.ShipmentStatusLoaded)
+	accepted := as.createGeofencedShipment("arrive", models.ShipmentStatusAccepted)
+	as.postDriverLocations("salah", deltaport)
+	as.postDriverLocations("salah", tsawwasen)
+	as.Nil(as.DB.Reload(loaded))
+	as.Equal(models.ShipmentStatusInTransit.String(), loaded.Status)
+	as.Nil(as.DB.Reload(accepted))
+	as.Equal(models.ShipmentStatusAccepted.String(), accepted.Status)
+	count, err := as.DB.Where("shipment_id = ?", loaded.ID).Where("to_status = ?", models.ShipmentStatusInTransit.String()).Count(&models.ShipmentEvent{})
+	as.Nil(err)
+	as.Equal(1, count)
+	count, err = as.DB.Where("shipment_id = ?", loaded.ID).Count(&models.ShipmentStatusSuggestion{})
+	as.Nil(err)
+	as.Equal(0, count)
+	// Arrivals are still suggested
+	count, err = as.DB.Where("shipment_id = ?", accepted.ID).Count(&models.ShipmentStatusSuggestion{})
+	as.Nil(err)
+	as.Equal(1, count)
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/bigpanther/trober/firebase"
@@ -11,6 +12,7 @@ import (
 	"github.com/gobuffalo/buffalo"
 	"github.com/gobuffalo/nulls"
 	"github.com/gobuffalo/pop/v6"
+	"github.com/gobuffalo/validate/v3"
 	"github.com/gofrs/uuid"
 )
 
@@ -20,6 +22,8 @@ import (
 // Resource: Plural (Shipments)
 // Path: Plural (/shipments)
 
+var errInvalidDriver = errors.New("invalid driver association")
+
 // shipmentsList gets all Shipments. This function is mapped to the path
 // GET /shipments
 func shipmentsList(c buffalo.Context) error {
@@ -118,29 +122,59 @@ func shipmentsCreate(c buffalo.Context) error {
 	var loggedInUser = loggedInUser(c)
 	shipment.TenantID = loggedInUser.TenantID
 	shipment.CreatedBy = loggedInUser.ID
+	// The ETA is predicted from the GPS points of the driver
+	shipment.Eta = nulls.Time{}
+	shipment.EtaUpdatedAt = nulls.Time{}
+	shipment.EtaAlertedAt = nulls.Time{}
+	shipment.LfdAlertedThreshold = nulls.Int{}
 
 	order, err := checkOrderID(c, tx, loggedInUser, shipment.OrderID.UUID.String())
 	if err != nil {
 		return c.Error(http.StatusBadRequest, err)
 	}
+	if order.IsLocked() {
+		return c.Error(http.StatusConflict, errOrderLocked)
+	}
 	shipment.CustomerID = nulls.NewUUID(order.CustomerID)
 	if loggedInUser.IsDriver() {
 		shipment.DriverID = nulls.NewUUID(loggedInUser.ID)
-	} else if err := checkDriverID(c, tx, loggedInUser, shipment.DriverID); err != nil {
-		return c.Error(http.StatusBadRequest, err)
 	}
-	if shipment.DriverID.Valid && shipment.Status == models.ShipmentStatusUnassigned.String() {
-		shipment.Status = models.ShipmentStatusAssigned.String()
+	if err := checkDriverID(c, tx, loggedInUser, shipment); err != nil {
+		return renderDriverIDError(c, err)
 	}
 	if shipment.Status == "" {
 		shipment.Status = models.ShipmentStatusUnassigned.String()
 	}
+	if shipment.DriverID.Valid && shipment.Status == models.ShipmentStatusUnassigned.String() {
+		shipment.Status = models.ShipmentStatusAssigned.String()
+	}
+	if err := models.CheckShipmentStatusTransition(loggedInUser, "", models.ShipmentStatus(shipment.Status)); err != nil {
+		return renderShipmentStatusTransitionError(c, err)
+	}
 	if err := checkTerminalID(c, tx, loggedInUser, shipment.TerminalID); err != nil {
 		return c.Error(http.StatusBadRequest, err)
 	}
 	if err := checkCarrierID(c, tx, loggedInUser, shipment.CarrierID); err != nil {
 		return c.Error(http.StatusBadRequest, err)
 	}
+	if err := checkEquipmentID(c, tx, loggedInUser, shipment.TractorID, models.EquipmentTypeTractor); err != nil {
+		return c.Error(http.StatusBadRequest, err)
+	}
+	if err := checkEquipmentID(c, tx, loggedInUser, shipment.ChassisID, models.EquipmentTypeChassis); err != nil {
+		return c.Error(http.StatusBadRequest, err)
+	}
+	if err := checkShipmentLocations(c, tx, shipment); err != nil {
+		return c.Error(http.StatusBadRequest, err)
+	}
+	if err := checkDriverAvailability(c, tx, shipment); err != nil {
+		return renderDriverAvailabilityError(c, err)
+	}
+	if err := checkShipmentEquipment(tx, shipment); err != nil {
+		return renderShipmentEquipmentError(c, err)
+	}
+	if err := reserveTerminal(c, tx, shipment); err != nil {
+		return renderTerminalReservationError(c, err)
+	}
 	verrs, err := tx.ValidateAndCreate(shipment)
 	if err != nil {
 		return err
@@ -148,6 +182,12 @@ func shipmentsCreate(c buffalo.Context) error {
 	if verrs.HasAny() {
 		return c.Render(http.StatusUnprocessableEntity, r.JSON(verrs))
 	}
+	if err := createShipmentEvent(tx, shipment, "", nulls.UUID{}, loggedInUser); err != nil {
+		return err
+	}
+	if err := syncOrderStatus(c, tx, shipment.OrderID); err != nil {
+		return err
+	}
 	return c.Render(http.StatusCreated, r.JSON(shipment))
 
 }
@@ -165,31 +205,21 @@ func shipmentsUpdate(c buffalo.Context) error {
 	if err := q.Find(shipment, c.Param("shipment_id")); err != nil {
 		return c.Error(http.StatusNotFound, err)
 	}
+	if err := checkOrderNotLocked(tx, shipment.OrderID); err != nil {
+		return c.Error(http.StatusConflict, err)
+	}
 	newShipment := &models.Shipment{}
 	if err := c.Bind(newShipment); err != nil {
 		c.Logger().Errorf("error binding shipment: %v\n", err)
 		return err
 	}
-	if loggedInUser.IsDriver() {
-		newShipment.DriverID = nulls.NewUUID(loggedInUser.ID)
-	}
-	switch models.ShipmentStatus(newShipment.Status) {
-	case models.ShipmentStatusUnassigned:
-		fallthrough
-	case models.ShipmentStatusInTransit:
-		fallthrough
-	case models.ShipmentStatusArrived:
-		if !loggedInUser.IsBackOffice() {
-			return c.Error(http.StatusBadRequest, fmt.Errorf("invalid status: %s", newShipment.Status))
-		}
-	case models.ShipmentStatusRejected:
-		newShipment.DriverID = nulls.UUID{}
-	}
 	if loggedInUser.IsDriver() {
 		//readonly fields
 		newShipment.ReservationTime = shipment.ReservationTime
 		newShipment.TerminalID = shipment.TerminalID
 		newShipment.CarrierID = shipment.CarrierID
+		newShipment.TractorID = shipment.TractorID
+		newShipment.ChassisID = shipment.ChassisID
 		newShipment.OrderID = shipment.OrderID
 		newShipment.CustomerID = shipment.CustomerID
 		newShipment.DriverID = shipment.DriverID
@@ -198,8 +228,28 @@ func shipmentsUpdate(c buffalo.Context) error {
 		newShipment.SerialNumber = shipment.SerialNumber
 		newShipment.Origin = shipment.Origin
 		newShipment.Destination = shipment.Destination
+		newShipment.OriginLocationID = shipment.OriginLocationID
+		newShipment.DestinationLocationID = shipment.DestinationLocationID
+		newShipment.Miles = shipment.Miles
+	}
+	if err := models.CheckShipmentStatusTransition(loggedInUser, models.ShipmentStatus(shipment.Status), models.ShipmentStatus(newShipment.Status)); err != nil {
+		return renderShipmentStatusTransitionError(c, err)
 	}
-	shouldNotifyCustomer := shipment.Status != newShipment.Status && newShipment.Status == models.ShipmentStatusDelivered.String()
+	// Shipments are delivered with their proof of delivery
+	if shipment.Status != newShipment.Status && newShipment.Status == models.ShipmentStatusDelivered.String() {
+		exists, err := tx.Where("shipment_id = ?", shipment.ID).Exists(&models.DeliveryProof{})
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return c.Error(http.StatusConflict, errDeliveryProofRequired)
+		}
+	}
+	// Rejected and unassigned shipments have no driver
+	if newShipment.IsRejected() || newShipment.Status == models.ShipmentStatusUnassigned.String() {
+		newShipment.DriverID = nulls.UUID{}
+	}
+	fromStatus, previousDriverID, previousOrderID := shipment.Status, shipment.DriverID, shipment.OrderID
 	var changed bool
 	if shipment.OrderID != newShipment.OrderID || newShipment.CustomerID != shipment.CustomerID {
 		changed = true
@@ -207,12 +257,17 @@ func shipmentsUpdate(c buffalo.Context) error {
 		if err != nil {
 			return c.Error(http.StatusBadRequest, err)
 		}
+		if order.IsLocked() {
+			return c.Error(http.StatusConflict, errOrderLocked)
+		}
 		newShipment.CustomerID = nulls.NewUUID(order.CustomerID)
 	}
 	if shipment.DriverID != newShipment.DriverID {
 		changed = true
-		if err := checkDriverID(c, tx, loggedInUser, newShipment.DriverID); err != nil {
-			return c.Error(http.StatusBadRequest, err)
+	}
+	if shipment.DriverID != newShipment.DriverID || shipment.TerminalID != newShipment.TerminalID || shipment.ReservationTime != newShipment.ReservationTime {
+		if err := checkDriverID(c, tx, loggedInUser, newShipment); err != nil {
+			return renderDriverIDError(c, err)
 		}
 	}
 	if shipment.TerminalID != newShipment.TerminalID {
@@ -228,7 +283,33 @@ func shipmentsUpdate(c buffalo.Context) error {
 			return c.Error(http.StatusBadRequest, err)
 		}
 	}
-	if changed || shipment.SerialNumber != newShipment.SerialNumber || shipment.Status != newShipment.Status || shipment.Type != newShipment.Type || shipment.ReservationTime != newShipment.ReservationTime || shipment.Origin != newShipment.Origin || shipment.Destination != newShipment.Destination {
+	if shipment.TractorID != newShipment.TractorID {
+		changed = true
+		if err := checkEquipmentID(c, tx, loggedInUser, newShipment.TractorID, models.EquipmentTypeTractor); err != nil {
+			return c.Error(http.StatusBadRequest, err)
+		}
+	}
+	if shipment.ChassisID != newShipment.ChassisID {
+		changed = true
+		if err := checkEquipmentID(c, tx, loggedInUser, newShipment.ChassisID, models.EquipmentTypeChassis); err != nil {
+			return c.Error(http.StatusBadRequest, err)
+		}
+	}
+	if shipment.OriginLocationID != newShipment.OriginLocationID || shipment.DestinationLocationID != newShipment.DestinationLocationID {
+		changed = true
+		if err := checkShipmentLocations(c, tx, newShipment); err != nil {
+			return c.Error(http.StatusBadRequest, err)
+		}
+	}
+	if shipment.DriverID != newShipment.DriverID || shipment.ReservationTime != newShipment.ReservationTime {
+		newShipment.ID = shipment.ID
+		if err := checkDriverAvailability(c, tx, newShipment); err != nil {
+			return renderDriverAvailabilityError(c, err)
+		}
+	}
+	shouldCheckEquipment := shipment.TractorID != newShipment.TractorID || shipment.ChassisID != newShipment.ChassisID || (!shipment.IsActive() && newShipment.IsActive())
+	shouldReserveTerminal := shipment.TerminalID != newShipment.TerminalID || shipment.ReservationTime != newShipment.ReservationTime || shipment.Type != newShipment.Type
+	if changed || shipment.SerialNumber != newShipment.SerialNumber || shipment.Status != newShipment.Status || shipment.Type != newShipment.Type || shipment.ReservationTime != newShipment.ReservationTime || shipment.Origin != newShipment.Origin || shipment.Destination != newShipment.Destination || shipment.Miles != newShipment.Miles {
 		shipment.UpdatedAt = time.Now().UTC()
 		shipment.Status = newShipment.Status
 		shipment.DriverID = newShipment.DriverID
@@ -236,6 +317,8 @@ func shipmentsUpdate(c buffalo.Context) error {
 		shipment.SerialNumber = newShipment.SerialNumber
 		shipment.TerminalID = newShipment.TerminalID
 		shipment.CarrierID = newShipment.CarrierID
+		shipment.TractorID = newShipment.TractorID
+		shipment.ChassisID = newShipment.ChassisID
 		shipment.OrderID = newShipment.OrderID
 		shipment.CustomerID = newShipment.CustomerID
 		shipment.DriverID = newShipment.DriverID
@@ -244,9 +327,22 @@ func shipmentsUpdate(c buffalo.Context) error {
 		shipment.SerialNumber = newShipment.SerialNumber
 		shipment.Origin = newShipment.Origin
 		shipment.Destination = newShipment.Destination
+		shipment.OriginLocationID = newShipment.OriginLocationID
+		shipment.DestinationLocationID = newShipment.DestinationLocationID
+		shipment.Miles = newShipment.Miles
 	} else {
 		return c.Render(http.StatusOK, r.JSON(shipment))
 	}
+	if shouldReserveTerminal {
+		if err := reserveTerminal(c, tx, shipment); err != nil {
+			return renderTerminalReservationError(c, err)
+		}
+	}
+	if shouldCheckEquipment {
+		if err := checkShipmentEquipment(tx, shipment); err != nil {
+			return renderShipmentEquipmentError(c, err)
+		}
+	}
 	verrs, err := tx.ValidateAndUpdate(shipment)
 	if err != nil {
 		return err
@@ -254,9 +350,52 @@ func shipmentsUpdate(c buffalo.Context) error {
 	if verrs.HasAny() {
 		return c.Render(http.StatusUnprocessableEntity, r.JSON(verrs))
 	}
+	if err := createShipmentEvent(tx, shipment, fromStatus, previousDriverID, loggedInUser); err != nil {
+		return err
+	}
+	if err := syncOrderStatus(c, tx, shipment.OrderID); err != nil {
+		return err
+	}
+	if previousOrderID != shipment.OrderID {
+		if err := syncOrderStatus(c, tx, previousOrderID); err != nil {
+			return err
+		}
+	}
+	notifyShipmentUpdated(loggedInUser, shipment, fromStatus, previousDriverID)
+	return c.Render(http.StatusOK, r.JSON(shipment))
+}
+
+// changeShipmentStatus moves a shipment to a status with the same history and notifications as shipmentsUpdate,
+// once the caller has checked the transition
+func changeShipmentStatus(c buffalo.Context, tx *pop.Connection, loggedInUser *models.User, shipment *models.Shipment, to models.ShipmentStatus) (*validate.Errors, error) {
+	if err := checkOrderNotLocked(tx, shipment.OrderID); err != nil {
+		return nil, err
+	}
+	fromStatus := shipment.Status
+	shipment.Status = to.String()
+	shipment.UpdatedAt = time.Now().UTC()
+	verrs, err := tx.ValidateAndUpdate(shipment)
+	if err != nil || verrs.HasAny() {
+		return verrs, err
+	}
+	if err := createShipmentEvent(tx, shipment, fromStatus, shipment.DriverID, loggedInUser); err != nil {
+		return nil, err
+	}
+	if err := syncOrderStatus(c, tx, shipment.OrderID); err != nil {
+		return nil, err
+	}
+	notifyShipmentUpdated(loggedInUser, shipment, fromStatus, shipment.DriverID)
+	return verrs, nil
+}
+
+// notifyShipmentUpdated notifies the customer of a delivered shipment, back office of the changes made by the driver
+// and the driver of the changes made by back office
+func notifyShipmentUpdated(loggedInUser *models.User, shipment *models.Shipment, fromStatus string, previousDriverID nulls.UUID) {
+	shouldNotifyCustomer := fromStatus != shipment.Status && shipment.Status == models.ShipmentStatusDelivered.String()
 	if shouldNotifyCustomer {
 		if shipment.CustomerID.Valid {
 			sendNotificationsAsync(
+				models.NotificationEventShipmentDelivered,
 				[]string{firebase.GetCustomerTopic(loggedInUser.TenantID.String(), shipment.CustomerID.UUID.String())},
 				fmt.Sprintf("Your shipment has been delivered - %s", shipment.SerialNumber),
 				shipment.SerialNumber,
@@ -269,6 +408,7 @@ func shipmentsUpdate(c buffalo.Context) error {
 	}
 	if loggedInUser.IsDriver() {
 		sendNotificationsAsync(
+			models.NotificationEventShipmentUpdated,
 			[]string{firebase.GetBackOfficeTopic(loggedInUser)},
 			fmt.Sprintf("Shipment updated by driver - %s: %s", shipment.SerialNumber, shipment.Status),
 			shipment.SerialNumber,
@@ -280,12 +420,10 @@ func shipmentsUpdate(c buffalo.Context) error {
 		)
 	}
 	if loggedInUser.IsAtLeastBackOffice() {
-		if shipment.DriverID.Valid && (shipment.Status != models.ShipmentStatusAssigned.String() || shipment.Status != models.ShipmentStatusAccepted.String()) {
-			message := fmt.Sprintf("You have been assigned a pickup - %s", shipment.SerialNumber)
-			if shipment.Status != models.ShipmentStatusAccepted.String() {
-				message = fmt.Sprintf("Your assignment has been updated - %s", shipment.SerialNumber)
-			}
+		if shipment.DriverID.Valid {
+			event, message := driverNotification(shipment, fromStatus, previousDriverID)
 			sendNotificationsAsync(
+				event,
 				[]string{firebase.GetDriverTopic(loggedInUser.TenantID.String(), shipment.DriverID.UUID.String())},
 				message,
 				shipment.SerialNumber,
@@ -296,7 +434,44 @@ func shipmentsUpdate(c buffalo.Context) error {
 			)
 		}
 	}
-	return c.Render(http.StatusOK, r.JSON(shipment))
+}
+
+// driverNotification returns the event and message sent to the driver of a shipment changed by back office.
+// The driver is told of a new assignment when the shipment is handed to them or moved to Assigned, and of an update otherwise.
+func driverNotification(shipment *models.Shipment, fromStatus string, previousDriverID nulls.UUID) (models.NotificationEvent, string) {
+	assigned := shipment.Status == models.ShipmentStatusAssigned.String() && fromStatus != shipment.Status
+	if previousDriverID != shipment.DriverID || assigned {
+		return models.NotificationEventShipmentAssigned, fmt.Sprintf("You have been assigned a pickup - %s", shipment.SerialNumber)
+	}
+	return models.NotificationEventShipmentUpdated, fmt.Sprintf("Your assignment has been updated - %s", shipment.SerialNumber)
+}
+
+type shipmentTransitions struct {
+	Status  models.ShipmentStatus   `json:"status"`
+	Allowed []models.ShipmentStatus `json:"allowed"`
+}
+
+// shipmentsTransitions lists the statuses the logged in user can move a Shipment to.
+// This function is mapped to the path GET /shipments/{shipment_id}/transitions
+func shipmentsTransitions(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	shipment := &models.Shipment{}
+	var loggedInUser = loggedInUser(c)
+	q := tx.Scope(restrictedScope(c))
+	if loggedInUser.IsDriver() {
+		q = q.Where("driver_id = ?", loggedInUser.ID)
+	}
+	if loggedInUser.IsCustomer() {
+		q = q.Where("customer_id = ?", loggedInUser.CustomerID)
+	}
+	if err := q.Find(shipment, c.Param("shipment_id")); err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	var status = models.ShipmentStatus(shipment.Status)
+	return c.Render(http.StatusOK, r.JSON(shipmentTransitions{
+		Status:  status,
+		Allowed: models.NextShipmentStatuses(loggedInUser, status),
+	}))
 }
 
 // shipmentsDestroy deletes a Shipment from the DB. This function is mapped
@@ -309,10 +484,16 @@ func shipmentsDestroy(c buffalo.Context) error {
 	if err := tx.Scope(restrictedScope(c)).Find(shipment, c.Param("shipment_id")); err != nil {
 		return c.Error(http.StatusNotFound, err)
 	}
+	if err := checkOrderNotLocked(tx, shipment.OrderID); err != nil {
+		return c.Error(http.StatusConflict, err)
+	}
 
 	if err := tx.Destroy(shipment); err != nil {
 		return err
 	}
+	if err := syncOrderStatus(c, tx, shipment.OrderID); err != nil {
+		return err
+	}
 	c.Response().WriteHeader(http.StatusNoContent)
 	return nil
 }
@@ -333,18 +514,96 @@ func checkOrderID(c buffalo.Context, tx *pop.Connection, loggedInUser *models.Us
 	return order, nil
 }
 
-func checkDriverID(c buffalo.Context, tx *pop.Connection, loggedInUser *models.User, ID nulls.UUID) error {
-	if !ID.Valid {
+// checkDriverID checks the driver of a shipment belongs to the same tenant, and holds the credentials the terminal
+// of the shipment requires at its reservation time
+func checkDriverID(c buffalo.Context, tx *pop.Connection, loggedInUser *models.User, shipment *models.Shipment) error {
+	if !shipment.DriverID.Valid {
 		return nil
 	}
 	driver := &models.User{}
 	// User must belong to the same tenant
-	err := tx.Scope(restrictedScope(c)).Find(driver, ID)
+	err := tx.Scope(restrictedScope(c)).Find(driver, shipment.DriverID)
 	if err != nil || driver.ID == uuid.Nil {
-		return errors.New("invalid driver association")
+		return errInvalidDriver
+	}
+	if !shipment.TerminalID.Valid {
+		return nil
+	}
+	terminal := &models.Terminal{}
+	// An invalid terminal is reported by checkTerminalID
+	if err := tx.Scope(restrictedScope(c)).Find(terminal, shipment.TerminalID); err != nil || len(terminal.RequiredCredentials) == 0 {
+		return nil
+	}
+	compliances, err := models.LoadDriverCompliances(tx, driver.ID)
+	if err != nil {
+		return err
+	}
+	missing := compliances[driver.ID].MissingCredentials(terminal.RequiredCredentials, shipment.ComplianceTime(time.Now().UTC()))
+	if len(missing) > 0 {
+		return fmt.Errorf("%w for %s: %s", models.ErrDriverNotCompliant, terminal.Name, strings.Join(missing, "; "))
 	}
 	return nil
 }
+
+// isDriverIDError checks if the driver cannot be associated with a shipment
+func isDriverIDError(err error) bool {
+	return errors.Is(err, errInvalidDriver) || errors.Is(err, models.ErrDriverNotCompliant)
+}
+
+// renderDriverIDError renders a conflict when the driver lacks a credential, a bad request for an invalid driver
+// and an internal error otherwise
+func renderDriverIDError(c buffalo.Context, err error) error {
+	if errors.Is(err, models.ErrDriverNotCompliant) {
+		return c.Error(http.StatusConflict, err)
+	}
+	if errors.Is(err, errInvalidDriver) {
+		return c.Error(http.StatusBadRequest, err)
+	}
+	return err
+}
+
+// checkDriverAvailability checks the driver of a shipment works at its reservation time.
+// Back office can assign anyway with the param override_availability=true, the response then carries a warning.
+func checkDriverAvailability(c buffalo.Context, tx *pop.Connection, shipment *models.Shipment) error {
+	if !shipment.DriverID.Valid || !shipment.ReservationTime.Valid {
+		return nil
+	}
+	at := shipment.ReservationTime.Time
+	availabilities, err := models.LoadDriverAvailabilities(tx, at, shipment.DriverID.UUID)
+	if err != nil {
+		return err
+	}
+	reasons, err := availabilities[shipment.DriverID.UUID].Check(at, func(start time.Time, end time.Time) (int, error) {
+		return models.CountDriverReservations(tx, shipment.DriverID.UUID, start, end, shipment.ID)
+	})
+	if err != nil {
+		return err
+	}
+	if len(reasons) == 0 {
+		return nil
+	}
+	return overridable(c, "override_availability", shipment, fmt.Errorf("%w: %s", models.ErrDriverUnavailable, strings.Join(reasons, "; ")))
+}
+
+// overridable lets back office go ahead with a shipment despite an error when the param is true,
+// the response then carries the error as a warning
+func overridable(c buffalo.Context, param string, shipment *models.Shipment, err error) error {
+	if c.Param(param) == "true" && !loggedInUser(c).IsDriver() {
+		c.Logger().Warnf("saving shipment %s anyway: %v", shipment.SerialNumber, err)
+		c.Response().Header().Add("Warning", fmt.Sprintf("199 - %q", err.Error()))
+		return nil
+	}
+	return err
+}
+
+// renderDriverAvailabilityError renders a conflict when the driver is not available, an internal error otherwise
+func renderDriverAvailabilityError(c buffalo.Context, err error) error {
+	if errors.Is(err, models.ErrDriverUnavailable) {
+		return c.Error(http.StatusConflict, err)
+	}
+	return err
+}
+
 func checkTerminalID(c buffalo.Context, tx *pop.Connection, loggedInUser *models.User, ID nulls.UUID) error {
 	if !ID.Valid {
 		return nil
@@ -357,6 +616,26 @@ func checkTerminalID(c buffalo.Context, tx *pop.Connection, loggedInUser *models
 	}
 	return nil
 }
+
+// checkShipmentLocations ensures the stored locations of the origin and the destination of a shipment belong to
+// the tenant. An origin or a destination without text takes the name of its location.
+func checkShipmentLocations(c buffalo.Context, tx *pop.Connection, shipment *models.Shipment) error {
+	origin, err := checkLocationID(c, tx, shipment.OriginLocationID)
+	if err != nil {
+		return err
+	}
+	if origin != nil && strings.TrimSpace(shipment.Origin.String) == "" {
+		shipment.Origin = nulls.NewString(origin.Name)
+	}
+	destination, err := checkLocationID(c, tx, shipment.DestinationLocationID)
+	if err != nil {
+		return err
+	}
+	if destination != nil && strings.TrimSpace(shipment.Destination.String) == "" {
+		shipment.Destination = nulls.NewString(destination.Name)
+	}
+	return nil
+}
 func checkCarrierID(c buffalo.Context, tx *pop.Connection, loggedInUser *models.User, ID nulls.UUID) error {
 	if !ID.Valid {
 		return nil
@@ -369,3 +648,19 @@ func checkCarrierID(c buffalo.Context, tx *pop.Connection, loggedInUser *models.
 	}
 	return nil
 }
+
+type shipmentStatusTransitionError struct {
+	models.CustomError
+	Allowed []models.ShipmentStatus `json:"allowed"`
+}
+
+func renderShipmentStatusTransitionError(c buffalo.Context, err error) error {
+	var transitionErr *models.ShipmentStatusTransitionError
+	if !errors.As(err, &transitionErr) {
+		return c.Error(http.StatusBadRequest, err)
+	}
+	return c.Render(http.StatusConflict, r.JSON(shipmentStatusTransitionError{
+		CustomError: models.NewCustomError(err.Error(), http.StatusText(http.StatusConflict), err),
+		Allowed:     transitionErr.Allowed,
+	}))
+}
@@ -189,7 +189,7 @@ func (as *ActionSuite) Test_ShipmentsCreate() {
 	for _, test := range tests {
 		as.T().Run(test.username, func(t *testing.T) {
 			user := as.getLoggedInUser(test.username)
-			newShipment := models.Shipment{SerialNumber: user.Username, Type: models.ShipmentTypeInbound.String(), Status: models.ShipmentStatusDelivered.String(), TenantID: firmino.TenantID, OrderID: nulls.NewUUID(order.ID)}
+			newShipment := models.Shipment{SerialNumber: user.Username, Type: models.ShipmentTypeInbound.String(), Status: models.ShipmentStatusUnassigned.String(), TenantID: firmino.TenantID, OrderID: nulls.NewUUID(order.ID)}
 			req := as.setupRequest(user, "/shipments")
 			res := req.Post(newShipment)
 			as.Equal(test.responseCode, res.Code)
@@ -197,7 +197,12 @@ func (as *ActionSuite) Test_ShipmentsCreate() {
 				var shipment = models.Shipment{}
 				res.Bind(&shipment)
 				as.Equal(newShipment.SerialNumber, shipment.SerialNumber)
-				as.Equal(models.ShipmentStatusDelivered.String(), shipment.Status)
+				if user.IsDriver() {
+					as.Equal(models.ShipmentStatusAssigned.String(), shipment.Status)
+					as.Equal(user.ID, shipment.DriverID.UUID)
+				} else {
+					as.Equal(models.ShipmentStatusUnassigned.String(), shipment.Status)
+				}
 				as.Equal(models.ShipmentTypeInbound.String(), shipment.Type)
 				as.Equal(user.TenantID, shipment.TenantID)
 				as.Equal(order.ID, shipment.OrderID.UUID)
@@ -206,6 +211,40 @@ func (as *ActionSuite) Test_ShipmentsCreate() {
 	}
 }
 
+func (as *ActionSuite) Test_ShipmentsCreateInvalidStatus() {
+	as.LoadFixture("Tenant bootstrap")
+	var tests = []struct {
+		username     string
+		status       models.ShipmentStatus
+		responseCode int
+	}{
+		{"mane", models.ShipmentStatusAssigned, http.StatusCreated},
+		{"mane", models.ShipmentStatusDelivered, http.StatusConflict},
+		{"mane", models.ShipmentStatusLoaded, http.StatusConflict},
+		{"salah", models.ShipmentStatusAccepted, http.StatusCreated},
+		{"salah", models.ShipmentStatusDelivered, http.StatusConflict},
+		{"salah", models.ShipmentStatusInTransit, http.StatusConflict},
+	}
+	var firmino = as.getLoggedInUser("firmino")
+	efaLiv := as.getCustomer("EFA Liv")
+	order := as.createOrder("ord1", models.OrderStatusOpen, firmino.TenantID, firmino.ID, efaLiv.ID)
+	for _, test := range tests {
+		as.T().Run(fmt.Sprintf("%s-%s", test.username, test.status), func(t *testing.T) {
+			user := as.getLoggedInUser(test.username)
+			newShipment := models.Shipment{SerialNumber: user.Username, Type: models.ShipmentTypeInbound.String(), Status: test.status.String(), OrderID: nulls.NewUUID(order.ID)}
+			req := as.setupRequest(user, "/shipments")
+			res := req.Post(newShipment)
+			as.Equal(test.responseCode, res.Code)
+			if res.Code == http.StatusConflict {
+				var transitionErr = shipmentStatusTransitionError{}
+				res.Bind(&transitionErr)
+				as.NotEmpty(transitionErr.Allowed)
+				as.NotContains(transitionErr.Allowed, test.status)
+			}
+		})
+	}
+}
+
 func (as *ActionSuite) Test_ShipmentsUpdate() {
 	as.LoadFixture("Tenant bootstrap")
 	var tests = []struct {
@@ -233,7 +272,7 @@ func (as *ActionSuite) Test_ShipmentsUpdate() {
 			newShipment := as.createShipment(models.Shipment{SerialNumber: "s1", Status: models.ShipmentStatusAssigned.String(), CreatedBy: firmino.ID, TenantID: firmino.TenantID, Type: models.ShipmentTypeInbound.String(), DriverID: nulls.NewUUID(salah.ID)}, order)
 			req := as.setupRequest(user, fmt.Sprintf("/shipments/%s", newShipment.ID))
 			// Try to update ID and tenant ID. Expect these calls to be excluded at update
-			updatedShipment := models.Shipment{SerialNumber: fmt.Sprintf("not%s", test.username), Status: models.ShipmentStatusDelivered.String(), Type: models.ShipmentTypeInbound.String(), ID: user.ID, TenantID: user.ID}
+			updatedShipment := models.Shipment{SerialNumber: fmt.Sprintf("not%s", test.username), Status: models.ShipmentStatusAccepted.String(), Type: models.ShipmentTypeInbound.String(), ID: user.ID, TenantID: user.ID, DriverID: nulls.NewUUID(salah.ID)}
 			res := req.Put(updatedShipment)
 			as.Equal(test.responseCode, res.Code)
 			var dbShipment = *newShipment
@@ -257,6 +296,239 @@ func (as *ActionSuite) Test_ShipmentsUpdate() {
 	}
 }
 
+func (as *ActionSuite) Test_ShipmentsUpdateStatusTransitions() {
+	as.LoadFixture("Tenant bootstrap")
+	var tests = []struct {
+		username     string
+		from         models.ShipmentStatus
+		to           models.ShipmentStatus
+		responseCode int
+	}{
+		{"salah", models.ShipmentStatusAssigned, models.ShipmentStatusAccepted, http.StatusOK},
+		{"salah", models.ShipmentStatusAssigned, models.ShipmentStatusDelivered, http.StatusConflict},
+		{"salah", models.ShipmentStatusAccepted, models.ShipmentStatusLoaded, http.StatusOK},
+		{"salah", models.ShipmentStatusAccepted, models.ShipmentStatusArrived, http.StatusConflict},
+		{"salah", models.ShipmentStatusDelivered, models.ShipmentStatusLoaded, http.StatusConflict},
+		{"salah", models.ShipmentStatusAssigned, models.ShipmentStatusUnassigned, http.StatusConflict},
+		{"mane", models.ShipmentStatusAssigned, models.ShipmentStatusUnassigned, http.StatusOK},
+		{"mane", models.ShipmentStatusAccepted, models.ShipmentStatusArrived, http.StatusOK},
+		{"mane", models.ShipmentStatusLoaded, models.ShipmentStatusInTransit, http.StatusOK},
+		{"mane", models.ShipmentStatusDelivered, models.ShipmentStatusLoaded, http.StatusConflict},
+		{"firmino", models.ShipmentStatusAssigned, models.ShipmentStatusDelivered, http.StatusConflict},
+	}
+	var firmino = as.getLoggedInUser("firmino")
+	efaLiv := as.getCustomer("EFA Liv")
+	order := as.createOrder("order", models.OrderStatusOpen, firmino.TenantID, firmino.ID, efaLiv.ID)
+	salah := as.getLoggedInUser("salah")
+	mockFirebase.EXPECT().SendAll(gomock.Any(), gomock.Any()).AnyTimes()
+	for _, test := range tests {
+		as.T().Run(fmt.Sprintf("%s-%s-%s", test.username, test.from, test.to), func(t *testing.T) {
+			user := as.getLoggedInUser(test.username)
+			newShipment := as.createShipment(models.Shipment{SerialNumber: "s1", Status: test.from.String(), CreatedBy: firmino.ID, TenantID: firmino.TenantID, Type: models.ShipmentTypeInbound.String(), DriverID: nulls.NewUUID(salah.ID)}, order)
+			req := as.setupRequest(user, fmt.Sprintf("/shipments/%s", newShipment.ID))
+			updatedShipment := *newShipment
+			updatedShipment.Status = test.to.String()
+			res := req.Put(updatedShipment)
+			as.Equal(test.responseCode, res.Code)
+			var dbShipment = *newShipment
+			as.Nil(as.DB.Reload(&dbShipment))
+			if res.Code == http.StatusOK {
+				as.Equal(test.to.String(), dbShipment.Status)
+			} else {
+				as.Equal(test.from.String(), dbShipment.Status)
+				var transitionErr = shipmentStatusTransitionError{}
+				res.Bind(&transitionErr)
+				as.Equal(models.NextShipmentStatuses(user, test.from), transitionErr.Allowed)
+			}
+		})
+	}
+}
+
+func (as *ActionSuite) Test_ShipmentsUpdateClearsDriver() {
+	as.LoadFixture("Tenant bootstrap")
+	var tests = []struct {
+		username string
+		status   models.ShipmentStatus
+	}{
+		{"salah", models.ShipmentStatusRejected},
+		{"mane", models.ShipmentStatusUnassigned},
+	}
+	var firmino = as.getLoggedInUser("firmino")
+	efaLiv := as.getCustomer("EFA Liv")
+	order := as.createOrder("order", models.OrderStatusOpen, firmino.TenantID, firmino.ID, efaLiv.ID)
+	salah := as.getLoggedInUser("salah")
+	mockFirebase.EXPECT().SendAll(gomock.Any(), gomock.Any()).AnyTimes()
+	for _, test := range tests {
+		as.T().Run(test.status.String(), func(t *testing.T) {
+			user := as.getLoggedInUser(test.username)
+			newShipment := as.createShipment(models.Shipment{SerialNumber: "s1", Status: models.ShipmentStatusAssigned.String(), CreatedBy: firmino.ID, TenantID: firmino.TenantID, Type: models.ShipmentTypeInbound.String(), DriverID: nulls.NewUUID(salah.ID)}, order)
+			updatedShipment := *newShipment
+			updatedShipment.Status = test.status.String()
+			res := as.setupRequest(user, fmt.Sprintf("/shipments/%s", newShipment.ID)).Put(updatedShipment)
+			as.Equal(http.StatusOK, res.Code)
+			as.Nil(as.DB.Reload(newShipment))
+			as.Equal(test.status.String(), newShipment.Status)
+			as.False(newShipment.DriverID.Valid)
+		})
+	}
+}
+
+func (as *ActionSuite) Test_ShipmentsTransitions() {
+	as.LoadFixture("Tenant bootstrap")
+	var tests = []struct {
+		username     string
+		responseCode int
+		allowed      []models.ShipmentStatus
+	}{
+		{"mane", http.StatusOK, []models.ShipmentStatus{models.ShipmentStatusUnassigned, models.ShipmentStatusAccepted, models.ShipmentStatusRejected}},
+		{"firmino", http.StatusOK, []models.ShipmentStatus{models.ShipmentStatusUnassigned, models.ShipmentStatusAccepted, models.ShipmentStatusRejected}},
+		{"salah", http.StatusOK, []models.ShipmentStatus{models.ShipmentStatusAccepted, models.ShipmentStatusRejected}},
+		{"nike", http.StatusOK, []models.ShipmentStatus{}},
+		{"lewin", http.StatusNotFound, nil},
+		{"rodriguez", http.StatusNotFound, nil},
+		{"adidas", http.StatusNotFound, nil},
+	}
+	var firmino = as.getLoggedInUser("firmino")
+	efaLiv := as.getCustomer("EFA Liv")
+	order := as.createOrder("order", models.OrderStatusOpen, firmino.TenantID, firmino.ID, efaLiv.ID)
+	salah := as.getLoggedInUser("salah")
+	newShipment := as.createShipment(models.Shipment{SerialNumber: "s1", Status: models.ShipmentStatusAssigned.String(), CreatedBy: firmino.ID, TenantID: firmino.TenantID, Type: models.ShipmentTypeInbound.String(), DriverID: nulls.NewUUID(salah.ID)}, order)
+	for _, test := range tests {
+		as.T().Run(test.username, func(t *testing.T) {
+			user := as.getLoggedInUser(test.username)
+			res := as.setupRequest(user, fmt.Sprintf("/shipments/%s/transitions", newShipment.ID)).Get()
+			as.Equal(test.responseCode, res.Code)
+			if res.Code == http.StatusOK {
+				var transitions = shipmentTransitions{}
+				res.Bind(&transitions)
+				as.Equal(models.ShipmentStatusAssigned, transitions.Status)
+				as.Equal(test.allowed, transitions.Allowed)
+			}
+		})
+	}
+}
+
+func (as *ActionSuite) Test_ShipmentsUpdateDeliveredRequiresProof() {
+	as.LoadFixture("Tenant bootstrap")
+	var firmino = as.getLoggedInUser("firmino")
+	efaLiv := as.getCustomer("EFA Liv")
+	order := as.createOrder("order", models.OrderStatusOpen, firmino.TenantID, firmino.ID, efaLiv.ID)
+	salah := as.getLoggedInUser("salah")
+	mockFirebase.EXPECT().SendAll(gomock.Any(), gomock.Any()).AnyTimes()
+	for _, username := range []string{"salah", "mane"} {
+		as.T().Run(username, func(t *testing.T) {
+			shipment := as.createShipment(models.Shipment{SerialNumber: "s1", Status: models.ShipmentStatusLoaded.String(), CreatedBy: firmino.ID, TenantID: firmino.TenantID, Type: models.ShipmentTypeInbound.String(), DriverID: nulls.NewUUID(salah.ID)}, order)
+			updatedShipment := *shipment
+			updatedShipment.Status = models.ShipmentStatusDelivered.String()
+			res := as.setupRequest(as.getLoggedInUser(username), fmt.Sprintf("/shipments/%s", shipment.ID)).Put(updatedShipment)
+			as.Equal(http.StatusConflict, res.Code)
+			as.Contains(res.Body.String(), "/pod")
+			as.Nil(as.DB.Reload(shipment))
+			as.Equal(models.ShipmentStatusLoaded.String(), shipment.Status)
+		})
+	}
+}
+
+func (as *ActionSuite) Test_ShipmentsUpdateOrderStatus() {
+	as.LoadFixture("Tenant bootstrap")
+	var firmino = as.getLoggedInUser("firmino")
+	efaLiv := as.getCustomer("EFA Liv")
+	order := as.createOrder("order", models.OrderStatusOpen, firmino.TenantID, firmino.ID, efaLiv.ID)
+	salah := as.getLoggedInUser("salah")
+	mockFirebase.EXPECT().SendAll(gomock.Any(), gomock.Any()).AnyTimes()
+	var shipments = []*models.Shipment{
+		as.createShipment(models.Shipment{SerialNumber: "s1", Status: models.ShipmentStatusAssigned.String(), CreatedBy: firmino.ID, TenantID: firmino.TenantID, Type: models.ShipmentTypeInbound.String(), DriverID: nulls.NewUUID(salah.ID)}, order),
+		as.createShipment(models.Shipment{SerialNumber: "s2", Status: models.ShipmentStatusAssigned.String(), CreatedBy: firmino.ID, TenantID: firmino.TenantID, Type: models.ShipmentTypeInbound.String(), DriverID: nulls.NewUUID(salah.ID)}, order),
+	}
+	var steps = []struct {
+		shipment      *models.Shipment
+		status        models.ShipmentStatus
+		expectedOrder models.OrderStatus
+	}{
+		{shipments[0], models.ShipmentStatusAccepted, models.OrderStatusInProgress},
+		{shipments[0], models.ShipmentStatusLoaded, models.OrderStatusInProgress},
+		{shipments[0], models.ShipmentStatusDelivered, models.OrderStatusInProgress},
+		{shipments[1], models.ShipmentStatusRejected, models.OrderStatusDelivered},
+	}
+	for i, step := range steps {
+		as.T().Run(fmt.Sprint(i), func(t *testing.T) {
+			if step.status == models.ShipmentStatusDelivered {
+				as.deliverWithProof(salah, step.shipment)
+			} else {
+				updatedShipment := *step.shipment
+				updatedShipment.Status = step.status.String()
+				res := as.setupRequest(salah, fmt.Sprintf("/shipments/%s", step.shipment.ID)).Put(updatedShipment)
+				as.Equal(http.StatusOK, res.Code)
+			}
+			as.Nil(as.DB.Reload(order))
+			as.Equal(step.expectedOrder.String(), order.Status)
+		})
+	}
+}
+
+func (as *ActionSuite) Test_ShipmentsLockedOrder() {
+	as.LoadFixture("Tenant bootstrap")
+	var firmino = as.getLoggedInUser("firmino")
+	mane := as.getLoggedInUser("mane")
+	efaLiv := as.getCustomer("EFA Liv")
+	order := as.createOrder("order", models.OrderStatusInvoiced, firmino.TenantID, firmino.ID, efaLiv.ID)
+	openOrder := as.createOrder("open", models.OrderStatusOpen, firmino.TenantID, firmino.ID, efaLiv.ID)
+	shipment := as.createShipment(models.Shipment{SerialNumber: "s1", Status: models.ShipmentStatusDelivered.String(), CreatedBy: firmino.ID, TenantID: firmino.TenantID, Type: models.ShipmentTypeInbound.String()}, order)
+	openShipment := as.createShipment(models.Shipment{SerialNumber: "s2", Status: models.ShipmentStatusUnassigned.String(), CreatedBy: firmino.ID, TenantID: firmino.TenantID, Type: models.ShipmentTypeInbound.String()}, openOrder)
+
+	updatedShipment := *shipment
+	updatedShipment.SerialNumber = "s1-updated"
+	res := as.setupRequest(mane, fmt.Sprintf("/shipments/%s", shipment.ID)).Put(updatedShipment)
+	as.Equal(http.StatusConflict, res.Code)
+
+	movedShipment := *openShipment
+	movedShipment.OrderID = nulls.NewUUID(order.ID)
+	res = as.setupRequest(mane, fmt.Sprintf("/shipments/%s", openShipment.ID)).Put(movedShipment)
+	as.Equal(http.StatusConflict, res.Code)
+
+	res = as.setupRequest(mane, "/shipments").Post(models.Shipment{SerialNumber: "s3", Type: models.ShipmentTypeInbound.String(), OrderID: nulls.NewUUID(order.ID)})
+	as.Equal(http.StatusConflict, res.Code)
+
+	res = as.setupRequest(mane, fmt.Sprintf("/shipments/%s", shipment.ID)).Delete()
+	as.Equal(http.StatusConflict, res.Code)
+
+	as.Nil(as.DB.Reload(shipment))
+	as.Equal("s1", shipment.SerialNumber)
+	as.Nil(as.DB.Reload(openShipment))
+	as.Equal(openOrder.ID, openShipment.OrderID.UUID)
+	as.Nil(as.DB.Reload(order))
+	as.Equal(models.OrderStatusInvoiced.String(), order.Status)
+}
+
+func (as *ActionSuite) Test_ShipmentsDriverNotification() {
+	as.LoadFixture("Tenant bootstrap")
+	salah := as.getLoggedInUser("salah")
+	lewin := as.getLoggedInUser("lewin")
+	var tests = []struct {
+		name             string
+		from             models.ShipmentStatus
+		to               models.ShipmentStatus
+		previousDriverID nulls.UUID
+		event            models.NotificationEvent
+	}{
+		{"assigned", models.ShipmentStatusUnassigned, models.ShipmentStatusAssigned, nulls.UUID{}, models.NotificationEventShipmentAssigned},
+		{"reassigned", models.ShipmentStatusAssigned, models.ShipmentStatusAssigned, nulls.NewUUID(lewin.ID), models.NotificationEventShipmentAssigned},
+		{"reassigned accepted", models.ShipmentStatusAccepted, models.ShipmentStatusAccepted, nulls.NewUUID(lewin.ID), models.NotificationEventShipmentAssigned},
+		{"moved back to assigned", models.ShipmentStatusAccepted, models.ShipmentStatusAssigned, nulls.NewUUID(salah.ID), models.NotificationEventShipmentAssigned},
+		{"same assignment", models.ShipmentStatusAssigned, models.ShipmentStatusAssigned, nulls.NewUUID(salah.ID), models.NotificationEventShipmentUpdated},
+		{"accepted", models.ShipmentStatusAssigned, models.ShipmentStatusAccepted, nulls.NewUUID(salah.ID), models.NotificationEventShipmentUpdated},
+		{"in transit", models.ShipmentStatusAccepted, models.ShipmentStatusInTransit, nulls.NewUUID(salah.ID), models.NotificationEventShipmentUpdated},
+	}
+	for _, test := range tests {
+		as.T().Run(test.name, func(t *testing.T) {
+			shipment := &models.Shipment{SerialNumber: "s1", Status: test.to.String(), DriverID: nulls.NewUUID(salah.ID)}
+			event, message := driverNotification(shipment, test.from.String(), test.previousDriverID)
+			as.Equal(test.event, event)
+			as.Contains(message, shipment.SerialNumber)
+		})
+	}
+}
+
 func (as *ActionSuite) Test_ShipmentsDestroy() {
 	as.LoadFixture("Tenant bootstrap")
 	var tests = []struct {
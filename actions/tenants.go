@@ -10,6 +10,7 @@ import (
 	"github.com/gobuffalo/buffalo"
 	"github.com/gobuffalo/nulls"
 	"github.com/gobuffalo/pop/v6"
+	"github.com/gofrs/uuid"
 )
 
 // Following naming logic is implemented in Buffalo:
@@ -84,6 +85,16 @@ func tenantsCreate(c buffalo.Context) error {
 		return err
 	}
 	tenant.CreatedBy = nulls.NewUUID(loggedInUser(c).ID)
+	// The currency and the geofence modes have defaults when they are not given
+	if tenant.Currency == "" {
+		tenant.Currency = models.DefaultCurrency
+	}
+	if tenant.GeofenceArrivedMode == "" {
+		tenant.GeofenceArrivedMode = models.GeofenceTransitionModeSuggest.String()
+	}
+	if tenant.GeofenceInTransitMode == "" {
+		tenant.GeofenceInTransitMode = models.GeofenceTransitionModeSuggest.String()
+	}
 
 	tx := c.Value("tx").(*pop.Connection)
 
@@ -116,11 +127,26 @@ func tenantsUpdate(c buffalo.Context) error {
 
 		return err
 	}
-	if newTenant.Name != tenant.Name || newTenant.Type != tenant.Type || newTenant.Code != tenant.Code {
+	// The currency and the geofence modes are kept when they are not given
+	if newTenant.Currency == "" {
+		newTenant.Currency = tenant.Currency
+	}
+	if newTenant.GeofenceArrivedMode == "" {
+		newTenant.GeofenceArrivedMode = tenant.GeofenceArrivedMode
+	}
+	if newTenant.GeofenceInTransitMode == "" {
+		newTenant.GeofenceInTransitMode = tenant.GeofenceInTransitMode
+	}
+	if newTenant.Name != tenant.Name || newTenant.Type != tenant.Type || newTenant.Code != tenant.Code || newTenant.Currency != tenant.Currency || newTenant.LocationRetentionDays != tenant.LocationRetentionDays ||
+		newTenant.GeofenceArrivedMode != tenant.GeofenceArrivedMode || newTenant.GeofenceInTransitMode != tenant.GeofenceInTransitMode {
 		tenant.UpdatedAt = time.Now().UTC()
 		tenant.Name = newTenant.Name
 		tenant.Type = newTenant.Type
 		tenant.Code = newTenant.Code
+		tenant.Currency = newTenant.Currency
+		tenant.LocationRetentionDays = newTenant.LocationRetentionDays
+		tenant.GeofenceArrivedMode = newTenant.GeofenceArrivedMode
+		tenant.GeofenceInTransitMode = newTenant.GeofenceInTransitMode
 	} else {
 		return c.Render(http.StatusOK, r.JSON(tenant))
 	}
@@ -155,3 +181,12 @@ func tenantsDestroy(c buffalo.Context) error {
 	c.Response().WriteHeader(http.StatusNoContent)
 	return nil
 }
+
+// tenantCurrency returns the default currency of a tenant
+func tenantCurrency(tx *pop.Connection, tenantID uuid.UUID) (string, error) {
+	tenant := &models.Tenant{}
+	if err := tx.Find(tenant, tenantID); err != nil {
+		return "", err
+	}
+	return tenant.Currency, nil
+}
@@ -41,7 +41,7 @@ func (as *ActionSuite) Test_TenantsList() {
 func (as *ActionSuite) Test_TenantsListOrder() {
 	as.LoadFixture("Tenant bootstrap")
 	var username = "klopp"
-	newTenant := &models.Tenant{Name: "Test", Type: "Production", Code: "someC"}
+	newTenant := &models.Tenant{Name: "Test", Type: "Production", Code: "someC", Currency: models.DefaultCurrency, GeofenceArrivedMode: models.GeofenceTransitionModeSuggest.String(), GeofenceInTransitMode: models.GeofenceTransitionModeSuggest.String()}
 	v, err := as.DB.ValidateAndCreate(newTenant)
 	as.Nil(err)
 	as.Equal(0, len(v.Errors))
@@ -58,7 +58,7 @@ func (as *ActionSuite) Test_TenantsListOrder() {
 func (as *ActionSuite) Test_TenantsListPagination() {
 	as.LoadFixture("Tenant bootstrap")
 	var username = "klopp"
-	newTenant := &models.Tenant{Name: "Test", Type: "Production", Code: "someC"}
+	newTenant := &models.Tenant{Name: "Test", Type: "Production", Code: "someC", Currency: models.DefaultCurrency, GeofenceArrivedMode: models.GeofenceTransitionModeSuggest.String(), GeofenceInTransitMode: models.GeofenceTransitionModeSuggest.String()}
 	v, err := as.DB.ValidateAndCreate(newTenant)
 	as.Nil(err)
 	as.Equal(0, len(v.Errors))
@@ -89,7 +89,7 @@ func (as *ActionSuite) Test_TenantsListFilter() {
 			if i%2 == 0 {
 				tenantType = "Production"
 			}
-			newTenant := &models.Tenant{Name: fmt.Sprintf("%s - %d", p, i), Type: tenantType, Code: "someC"}
+			newTenant := &models.Tenant{Name: fmt.Sprintf("%s - %d", p, i), Type: tenantType, Code: "someC", Currency: models.DefaultCurrency, GeofenceArrivedMode: models.GeofenceTransitionModeSuggest.String(), GeofenceInTransitMode: models.GeofenceTransitionModeSuggest.String()}
 			v, err := as.DB.ValidateAndCreate(newTenant)
 			as.Nil(err)
 			as.Equal(0, len(v.Errors))
@@ -182,6 +182,8 @@ func (as *ActionSuite) Test_TenantsCreate() {
 				var tenant = models.Tenant{}
 				res.Bind(&tenant)
 				as.Equal("Test", tenant.Name)
+				as.Equal(models.DefaultCurrency, tenant.Currency)
+				as.Equal(models.GeofenceTransitionModeSuggest.String(), tenant.GeofenceArrivedMode)
 				tenant = models.Tenant{}
 				var err = as.DB.Where("name=?", "Test").First(&tenant)
 				as.Nil(err)
@@ -204,7 +206,7 @@ func (as *ActionSuite) Test_TenantsUpdate() {
 		{"adidas", http.StatusNotFound},
 		{"klopp", http.StatusOK},
 	}
-	newTenant := &models.Tenant{Name: "Test", Type: "Production", Code: "someC"}
+	newTenant := &models.Tenant{Name: "Test", Type: "Production", Code: "someC", Currency: models.DefaultCurrency, GeofenceArrivedMode: models.GeofenceTransitionModeSuggest.String(), GeofenceInTransitMode: models.GeofenceTransitionModeSuggest.String()}
 	v, err := as.DB.ValidateAndCreate(newTenant)
 	as.Nil(err)
 	as.Equal(0, len(v.Errors))
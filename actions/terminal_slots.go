@@ -0,0 +1,152 @@
+package actions
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/nulls"
+	"github.com/gobuffalo/pop/v6"
+)
+
+// Following naming logic is implemented in Buffalo:
+// Model: Singular (TerminalSlot)
+// DB Table: Plural (terminal_slots)
+// Resource: Plural (TerminalSlots)
+// Path: Plural (/terminals/{terminal_id}/slots)
+
+// terminalSlotsList gets the appointment slots of a Terminal on a day with their remaining capacity.
+// Param "date" defaults to today in the timezone of the terminal. This function is mapped to the path GET /terminals/{terminal_id}/slots
+func terminalSlotsList(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	terminal, err := findTerminal(c, tx)
+	if err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	date := time.Now().In(terminal.Location())
+	if d := c.Param("date"); d != "" {
+		parsed, err := time.ParseInLocation(reportDateFormat, d, terminal.Location())
+		if err != nil {
+			return c.Error(http.StatusBadRequest, fmt.Errorf("invalid date %q", d))
+		}
+		date = parsed
+	}
+	availabilities, err := models.LoadTerminalSlotAvailabilities(tx, terminal.ID, date, terminal.Location())
+	if err != nil {
+		return err
+	}
+	return c.Render(http.StatusOK, r.JSON(availabilities))
+}
+
+// terminalSlotsCreate adds an appointment slot to a Terminal. This function is mapped to the
+// path POST /terminals/{terminal_id}/slots
+func terminalSlotsCreate(c buffalo.Context) error {
+	var loggedInUser = loggedInUser(c)
+	tx := c.Value("tx").(*pop.Connection)
+	terminal, err := findTerminal(c, tx)
+	if err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	terminalSlot := &models.TerminalSlot{}
+	if err := c.Bind(terminalSlot); err != nil {
+		c.Logger().Errorf("error binding terminal slot: %v\n", err)
+		return err
+	}
+	terminalSlot.CreatedBy = loggedInUser.ID
+	terminalSlot.TenantID = terminal.TenantID
+	terminalSlot.TerminalID = terminal.ID
+	verrs, err := tx.ValidateAndCreate(terminalSlot)
+	if err != nil {
+		return err
+	}
+	if verrs.HasAny() {
+		return c.Render(http.StatusUnprocessableEntity, r.JSON(verrs))
+	}
+	return c.Render(http.StatusCreated, r.JSON(terminalSlot))
+}
+
+// terminalSlotsUpdate changes an appointment slot of a Terminal. Reservations already made keep the slot.
+// This function is mapped to the path PUT /terminals/{terminal_id}/slots/{slot_id}
+func terminalSlotsUpdate(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	terminalSlot := &models.TerminalSlot{}
+	if err := tx.Scope(restrictedScope(c)).Where("terminal_id = ?", c.Param("terminal_id")).Find(terminalSlot, c.Param("slot_id")); err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	newTerminalSlot := &models.TerminalSlot{}
+	if err := c.Bind(newTerminalSlot); err != nil {
+		c.Logger().Errorf("error binding terminal slot: %v\n", err)
+		return err
+	}
+	terminalSlot.UpdatedAt = time.Now().UTC()
+	terminalSlot.Weekday = newTerminalSlot.Weekday
+	terminalSlot.StartTime = newTerminalSlot.StartTime
+	terminalSlot.EndTime = newTerminalSlot.EndTime
+	terminalSlot.Capacity = newTerminalSlot.Capacity
+	terminalSlot.Size = newTerminalSlot.Size
+	terminalSlot.Type = newTerminalSlot.Type
+	verrs, err := tx.ValidateAndUpdate(terminalSlot)
+	if err != nil {
+		return err
+	}
+	if verrs.HasAny() {
+		return c.Render(http.StatusUnprocessableEntity, r.JSON(verrs))
+	}
+	return c.Render(http.StatusOK, r.JSON(terminalSlot))
+}
+
+// terminalSlotsDestroy deletes an appointment slot of a Terminal, its reservations are released.
+// This function is mapped to the path DELETE /terminals/{terminal_id}/slots/{slot_id}
+func terminalSlotsDestroy(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	terminalSlot := &models.TerminalSlot{}
+	if err := tx.Scope(restrictedScope(c)).Where("terminal_id = ?", c.Param("terminal_id")).Find(terminalSlot, c.Param("slot_id")); err != nil {
+		return c.Error(http.StatusNotFound, err)
+	}
+	if err := tx.Destroy(terminalSlot); err != nil {
+		return err
+	}
+	c.Response().WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// findTerminal gets the Terminal of the path param "terminal_id"
+func findTerminal(c buffalo.Context, tx *pop.Connection) (*models.Terminal, error) {
+	terminal := &models.Terminal{}
+	if err := tx.Scope(restrictedScope(c)).Find(terminal, c.Param("terminal_id")); err != nil {
+		return nil, err
+	}
+	return terminal, nil
+}
+
+// reserveTerminal checks the gate of the terminal of a shipment is open at its reservation time and holds an
+// appointment slot for it. Back office can book outside of the gate hours with the param override_gate_hours=true.
+func reserveTerminal(c buffalo.Context, tx *pop.Connection, shipment *models.Shipment) error {
+	if !shipment.TerminalID.Valid || !shipment.ReservationTime.Valid {
+		shipment.TerminalSlotID = nulls.UUID{}
+		return nil
+	}
+	terminal := &models.Terminal{}
+	if err := tx.Eager("Hours", "Holidays").Find(terminal, shipment.TerminalID.UUID); err != nil {
+		return err
+	}
+	if reasons := terminal.CheckGateHours(shipment.ReservationTime.Time); len(reasons) > 0 {
+		err := overridable(c, "override_gate_hours", shipment, fmt.Errorf("%w: %s", models.ErrTerminalClosed, strings.Join(reasons, "; ")))
+		if err != nil {
+			return err
+		}
+	}
+	return models.ReserveTerminalSlot(tx, shipment, terminal.Location())
+}
+
+// renderTerminalReservationError renders a conflict when the terminal cannot take the reservation, an internal error otherwise
+func renderTerminalReservationError(c buffalo.Context, err error) error {
+	if errors.Is(err, models.ErrTerminalClosed) || errors.Is(err, models.ErrTerminalSlotFull) || errors.Is(err, models.ErrNoTerminalSlot) {
+		return c.Error(http.StatusConflict, err)
+	}
+	return err
+}
@@ -0,0 +1,126 @@
+package actions
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/nulls"
+	"github.com/gofrs/uuid"
+)
+
+func (as *ActionSuite) createTerminalSlot(user *models.User, terminalID uuid.UUID, slot models.TerminalSlot) *models.TerminalSlot {
+	res := as.setupRequest(user, fmt.Sprintf("/terminals/%s/slots", terminalID)).Post(slot)
+	as.Equal(http.StatusCreated, res.Code, res.Body.String())
+	var created = &models.TerminalSlot{}
+	res.Bind(created)
+	return created
+}
+
+func (as *ActionSuite) Test_TerminalSlotsCreate() {
+	as.LoadFixture("Tenant bootstrap")
+	firmino := as.getLoggedInUser("firmino")
+	terminal := as.createTerminal("Deltaport", models.TerminalTypePort, firmino.TenantID, firmino.ID)
+	var tests = []struct {
+		username     string
+		responseCode int
+	}{
+		{"firmino", http.StatusCreated},
+		{"mane", http.StatusCreated},
+		{"rodriguez", http.StatusNotFound},
+		{"salah", http.StatusNotFound},
+		{"nike", http.StatusNotFound},
+		{"coutinho", http.StatusNotFound},
+	}
+	for _, test := range tests {
+		as.T().Run(test.username, func(t *testing.T) {
+			user := as.getLoggedInUser(test.username)
+			res := as.setupRequest(user, fmt.Sprintf("/terminals/%s/slots", terminal.ID)).Post(models.TerminalSlot{Weekday: int(time.Monday), StartTime: "08:00", EndTime: "10:00", Capacity: 2})
+			as.Equal(test.responseCode, res.Code)
+			if res.Code != http.StatusCreated {
+				return
+			}
+			var slot = models.TerminalSlot{}
+			res.Bind(&slot)
+			as.Equal(terminal.ID, slot.TerminalID)
+			as.Equal(firmino.TenantID, slot.TenantID)
+		})
+	}
+	res := as.setupRequest(firmino, fmt.Sprintf("/terminals/%s/slots", terminal.ID)).Post(models.TerminalSlot{Weekday: int(time.Monday), StartTime: "10:00", EndTime: "09:00", Capacity: 2})
+	as.Equal(http.StatusUnprocessableEntity, res.Code)
+}
+
+func (as *ActionSuite) Test_TerminalSlotsReservation() {
+	as.LoadFixture("Tenant bootstrap")
+	mane := as.getLoggedInUser("mane")
+	efaLiv := as.getCustomer("EFA Liv")
+	order := as.createOrder("ord1", models.OrderStatusOpen, mane.TenantID, mane.ID, efaLiv.ID)
+	terminal := as.createTerminal("Deltaport", models.TerminalTypePort, mane.TenantID, mane.ID)
+	open := as.createTerminal("Fraser Surrey", models.TerminalTypePort, mane.TenantID, mane.ID)
+	var monday = time.Date(2026, 11, 2, 0, 0, 0, 0, time.UTC)
+	morning := as.createTerminalSlot(mane, terminal.ID, models.TerminalSlot{Weekday: int(time.Monday), StartTime: "08:00", EndTime: "10:00", Capacity: 1})
+	as.createTerminalSlot(mane, terminal.ID, models.TerminalSlot{Weekday: int(time.Monday), StartTime: "10:00", EndTime: "12:00", Capacity: 2, Type: nulls.NewString(models.Outbound.String())})
+
+	var newShipment = func(serialNumber string, terminalID uuid.UUID, shipmentType models.ShipmentType, at time.Time) models.Shipment {
+		return models.Shipment{SerialNumber: serialNumber, Status: models.ShipmentStatusUnassigned.String(), Type: shipmentType.String(), OrderID: nulls.NewUUID(order.ID), TerminalID: nulls.NewUUID(terminalID), ReservationTime: nulls.NewTime(at)}
+	}
+	var tests = []struct {
+		name         string
+		shipment     models.Shipment
+		responseCode int
+	}{
+		{"reserve", newShipment("s1", terminal.ID, models.ShipmentTypeInbound, monday.Add(9*time.Hour)), http.StatusCreated},
+		{"slot full", newShipment("s2", terminal.ID, models.ShipmentTypeInbound, monday.Add(8*time.Hour)), http.StatusConflict},
+		{"slot of another type", newShipment("s3", terminal.ID, models.ShipmentTypeInbound, monday.Add(11*time.Hour)), http.StatusConflict},
+		{"slot of the type", newShipment("s4", terminal.ID, models.Outbound, monday.Add(11*time.Hour)), http.StatusCreated},
+		{"no slot", newShipment("s5", terminal.ID, models.ShipmentTypeInbound, monday.Add(14*time.Hour)), http.StatusConflict},
+	}
+	var created = map[string]models.Shipment{}
+	for _, test := range tests {
+		as.T().Run(test.name, func(t *testing.T) {
+			res := as.setupRequest(mane, "/shipments").Post(test.shipment)
+			as.Equal(test.responseCode, res.Code, res.Body.String())
+			if res.Code == http.StatusCreated {
+				var shipment = models.Shipment{}
+				res.Bind(&shipment)
+				as.True(shipment.TerminalSlotID.Valid)
+				created[shipment.SerialNumber] = shipment
+			}
+		})
+	}
+	res := as.setupRequest(mane, "/shipments").Post(newShipment("s6", open.ID, models.ShipmentTypeInbound, monday.Add(14*time.Hour)))
+	as.Equal(http.StatusCreated, res.Code)
+	var shipment = models.Shipment{}
+	res.Bind(&shipment)
+	as.False(shipment.TerminalSlotID.Valid)
+
+	res = as.setupRequest(as.getLoggedInUser("nike"), fmt.Sprintf("/terminals/%s/slots?date=%s", terminal.ID, monday.Format(reportDateFormat))).Get()
+	as.Equal(http.StatusOK, res.Code)
+	var slots = models.TerminalSlotAvailabilities{}
+	res.Bind(&slots)
+	as.Len(slots, 2)
+	as.Equal(morning.ID, slots[0].Slot.ID)
+	as.Equal(1, slots[0].Reserved)
+	as.Equal(0, slots[0].Remaining)
+	as.Equal(1, slots[1].Remaining)
+	res = as.setupRequest(mane, fmt.Sprintf("/terminals/%s/slots?date=%s", terminal.ID, monday.AddDate(0, 0, 1).Format(reportDateFormat))).Get()
+	as.Equal(http.StatusOK, res.Code)
+	res.Bind(&slots)
+	as.Len(slots, 0)
+	res = as.setupRequest(mane, fmt.Sprintf("/terminals/%s/slots?date=tomorrow", terminal.ID)).Get()
+	as.Equal(http.StatusBadRequest, res.Code)
+	res = as.setupRequest(as.getLoggedInUser("rodriguez"), fmt.Sprintf("/terminals/%s/slots", terminal.ID)).Get()
+	as.Equal(http.StatusNotFound, res.Code)
+
+	// Moving the reservation releases the slot
+	res = as.setupRequest(mane, fmt.Sprintf("/shipments/%s", shipment.ID)).Put(newShipment("s6", terminal.ID, models.ShipmentTypeInbound, monday.Add(9*time.Hour)))
+	as.Equal(http.StatusConflict, res.Code)
+	var moved = created["s1"]
+	moved.ReservationTime = nulls.NewTime(monday.AddDate(0, 0, 7).Add(9 * time.Hour))
+	res = as.setupRequest(mane, fmt.Sprintf("/shipments/%s", moved.ID)).Put(moved)
+	as.Equal(http.StatusOK, res.Code, res.Body.String())
+	res = as.setupRequest(mane, fmt.Sprintf("/shipments/%s", shipment.ID)).Put(newShipment("s6", terminal.ID, models.ShipmentTypeInbound, monday.Add(9*time.Hour)))
+	as.Equal(http.StatusOK, res.Code, res.Body.String())
+}
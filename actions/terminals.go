@@ -3,11 +3,14 @@ package actions
 import (
 	"fmt"
 	"net/http"
+	"reflect"
+	"strconv"
 	"time"
 
 	"github.com/bigpanther/trober/models"
 	"github.com/gobuffalo/buffalo"
 	"github.com/gobuffalo/pop/v6"
+	"github.com/gofrs/uuid"
 )
 
 // Following naming logic is implemented in Buffalo:
@@ -47,6 +50,39 @@ func terminalsList(c buffalo.Context) error {
 
 }
 
+// defaultNearbyRadiusKm is how far terminals are searched for when no radius is given
+const defaultNearbyRadiusKm = 50
+
+// terminalsNearby gets the Terminals within a radius in km of a position, the closest first. Params "lat" and "lng"
+// are required, "radius" defaults to 50. This function is mapped to the path GET /terminals/nearby
+func terminalsNearby(c buffalo.Context) error {
+	tx := c.Value("tx").(*pop.Connection)
+	lat, err := strconv.ParseFloat(c.Param("lat"), 64)
+	if err != nil {
+		return c.Error(http.StatusBadRequest, fmt.Errorf("invalid lat %q", c.Param("lat")))
+	}
+	lng, err := strconv.ParseFloat(c.Param("lng"), 64)
+	if err != nil {
+		return c.Error(http.StatusBadRequest, fmt.Errorf("invalid lng %q", c.Param("lng")))
+	}
+	var at = models.GeoPoint{Lat: lat, Lng: lng}
+	if !at.IsValid() {
+		return c.Error(http.StatusBadRequest, fmt.Errorf("invalid position %v,%v", lat, lng))
+	}
+	var radius float64 = defaultNearbyRadiusKm
+	if v := c.Param("radius"); v != "" {
+		radius, err = strconv.ParseFloat(v, 64)
+		if err != nil || radius <= 0 {
+			return c.Error(http.StatusBadRequest, fmt.Errorf("invalid radius %q", v))
+		}
+	}
+	terminals := models.Terminals{}
+	if err := tx.Scope(restrictedScope(c)).Where("latitude IS NOT NULL").Where("longitude IS NOT NULL").All(&terminals); err != nil {
+		return err
+	}
+	return c.Render(http.StatusOK, r.JSON(models.FindNearbyTerminals(terminals, at, radius)))
+}
+
 // terminalsShow gets the data for one Terminal. This function is mapped to
 // the path GET /terminals/{terminal_id}
 func terminalsShow(c buffalo.Context) error {
@@ -55,13 +91,13 @@ func terminalsShow(c buffalo.Context) error {
 
 	terminal := &models.Terminal{}
 
-	if err := tx.Scope(restrictedScope(c)).Find(terminal, c.Param("terminal_id")); err != nil {
+	if err := tx.Eager("Hours", "Holidays").Scope(restrictedScope(c)).Find(terminal, c.Param("terminal_id")); err != nil {
 		return c.Error(http.StatusNotFound, err)
 	}
 	return c.Render(http.StatusOK, r.JSON(terminal))
 }
 
-// terminalsCreate adds a Terminal to the DB. This function is mapped to the
+// terminalsCreate adds a Terminal with its gate hours and holidays to the DB. This function is mapped to the
 // path POST /terminals
 func terminalsCreate(c buffalo.Context) error {
 	var loggedInUser = loggedInUser(c)
@@ -79,8 +115,16 @@ func terminalsCreate(c buffalo.Context) error {
 
 	terminal.TenantID = loggedInUser.TenantID
 	terminal.CreatedBy = loggedInUser.ID
+	if terminal.Timezone == "" {
+		terminal.Timezone = time.UTC.String()
+	}
+	if err := setDemurrageRateCurrency(tx, terminal.TenantID, terminal); err != nil {
+		return err
+	}
+	resetTerminalHours(terminal)
+	resetTerminalHolidays(terminal)
 
-	verrs, err := tx.ValidateAndCreate(terminal)
+	verrs, err := tx.Eager("Hours", "Holidays").ValidateAndCreate(terminal)
 	if err != nil {
 		return err
 	}
@@ -93,14 +137,15 @@ func terminalsCreate(c buffalo.Context) error {
 
 }
 
-// terminalsUpdate changes a Terminal in the DB. This function is mapped to
+// terminalsUpdate changes a Terminal in the DB. The gate hours and the holidays are replaced when sent,
+// reservations already made are not checked again. This function is mapped to
 // the path PUT /terminals/{terminal_id}
 func terminalsUpdate(c buffalo.Context) error {
 
 	tx := c.Value("tx").(*pop.Connection)
 
 	terminal := &models.Terminal{}
-	if err := tx.Scope(restrictedScope(c)).Find(terminal, c.Param("terminal_id")); err != nil {
+	if err := tx.Eager("Hours", "Holidays").Scope(restrictedScope(c)).Find(terminal, c.Param("terminal_id")); err != nil {
 		return c.Error(http.StatusNotFound, err)
 	}
 	newTerminal := &models.Terminal{}
@@ -110,10 +155,39 @@ func terminalsUpdate(c buffalo.Context) error {
 
 		return err
 	}
-	if newTerminal.Name != terminal.Name || newTerminal.Type != terminal.Type {
+	if newTerminal.Timezone == "" {
+		newTerminal.Timezone = terminal.Timezone
+	}
+	if err := setDemurrageRateCurrency(tx, terminal.TenantID, newTerminal); err != nil {
+		return err
+	}
+	var scheduleChanged = newTerminal.Hours != nil || newTerminal.Holidays != nil
+	var locationChanged = newTerminal.AddressLine != terminal.AddressLine || newTerminal.City != terminal.City || newTerminal.Region != terminal.Region ||
+		newTerminal.PostalCode != terminal.PostalCode || newTerminal.Country != terminal.Country || newTerminal.Latitude != terminal.Latitude ||
+		newTerminal.Longitude != terminal.Longitude || newTerminal.GeofenceRadiusMeters != terminal.GeofenceRadiusMeters ||
+		!reflect.DeepEqual(newTerminal.GeofencePolygon, terminal.GeofencePolygon)
+	if scheduleChanged || locationChanged || newTerminal.Name != terminal.Name || newTerminal.Type != terminal.Type || newTerminal.FreeTimeDays != terminal.FreeTimeDays || newTerminal.DemurrageRate != terminal.DemurrageRate ||
+		newTerminal.ImportFreeTimeDays != terminal.ImportFreeTimeDays || newTerminal.ExportFreeTimeDays != terminal.ExportFreeTimeDays || newTerminal.Timezone != terminal.Timezone || newTerminal.GateCutoffMinutes != terminal.GateCutoffMinutes ||
+		!reflect.DeepEqual(newTerminal.RequiredCredentials, terminal.RequiredCredentials) {
 		terminal.UpdatedAt = time.Now().UTC()
 		terminal.Name = newTerminal.Name
 		terminal.Type = newTerminal.Type
+		terminal.FreeTimeDays = newTerminal.FreeTimeDays
+		terminal.ImportFreeTimeDays = newTerminal.ImportFreeTimeDays
+		terminal.ExportFreeTimeDays = newTerminal.ExportFreeTimeDays
+		terminal.DemurrageRate = newTerminal.DemurrageRate
+		terminal.Timezone = newTerminal.Timezone
+		terminal.GateCutoffMinutes = newTerminal.GateCutoffMinutes
+		terminal.AddressLine = newTerminal.AddressLine
+		terminal.City = newTerminal.City
+		terminal.Region = newTerminal.Region
+		terminal.PostalCode = newTerminal.PostalCode
+		terminal.Country = newTerminal.Country
+		terminal.Latitude = newTerminal.Latitude
+		terminal.Longitude = newTerminal.Longitude
+		terminal.GeofenceRadiusMeters = newTerminal.GeofenceRadiusMeters
+		terminal.GeofencePolygon = newTerminal.GeofencePolygon
+		terminal.RequiredCredentials = newTerminal.RequiredCredentials
 	} else {
 		return c.Render(http.StatusOK, r.JSON(terminal))
 	}
@@ -125,6 +199,34 @@ func terminalsUpdate(c buffalo.Context) error {
 	if verrs.HasAny() {
 		return c.Render(http.StatusUnprocessableEntity, r.JSON(verrs))
 	}
+	if newTerminal.Hours != nil {
+		if err := tx.Destroy(&terminal.Hours); err != nil {
+			return err
+		}
+		terminal.Hours = newTerminal.Hours
+		resetTerminalHours(terminal)
+		verrs, err = tx.ValidateAndCreate(&terminal.Hours)
+		if err != nil {
+			return err
+		}
+		if verrs.HasAny() {
+			return c.Render(http.StatusUnprocessableEntity, r.JSON(verrs))
+		}
+	}
+	if newTerminal.Holidays != nil {
+		if err := tx.Destroy(&terminal.Holidays); err != nil {
+			return err
+		}
+		terminal.Holidays = newTerminal.Holidays
+		resetTerminalHolidays(terminal)
+		verrs, err = tx.ValidateAndCreate(&terminal.Holidays)
+		if err != nil {
+			return err
+		}
+		if verrs.HasAny() {
+			return c.Render(http.StatusUnprocessableEntity, r.JSON(verrs))
+		}
+	}
 	return c.Render(http.StatusOK, r.JSON(terminal))
 }
 
@@ -147,3 +249,32 @@ func terminalsDestroy(c buffalo.Context) error {
 	return nil
 
 }
+
+// resetTerminalHours makes the gate hours of a terminal new rows of the terminal, the ones it had are replaced
+func resetTerminalHours(terminal *models.Terminal) {
+	for i := range terminal.Hours {
+		terminal.Hours[i].ID = uuid.Nil
+		terminal.Hours[i].TerminalID = terminal.ID
+	}
+}
+
+// resetTerminalHolidays makes the holidays of a terminal new rows of the terminal, the ones it had are replaced
+func resetTerminalHolidays(terminal *models.Terminal) {
+	for i := range terminal.Holidays {
+		terminal.Holidays[i].ID = uuid.Nil
+		terminal.Holidays[i].TerminalID = terminal.ID
+	}
+}
+
+// setDemurrageRateCurrency sets the currency of a demurrage rate given without one to the currency of the tenant
+func setDemurrageRateCurrency(tx *pop.Connection, tenantID uuid.UUID, terminal *models.Terminal) error {
+	if !terminal.DemurrageRate.Valid || terminal.DemurrageRate.Money.Currency != "" {
+		return nil
+	}
+	currency, err := tenantCurrency(tx, tenantID)
+	if err != nil {
+		return err
+	}
+	terminal.DemurrageRate = terminal.DemurrageRate.WithDefaultCurrency(currency)
+	return nil
+}
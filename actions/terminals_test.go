@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/nulls"
 )
 
 func (as *ActionSuite) Test_TerminalsList() {
@@ -200,7 +202,7 @@ func (as *ActionSuite) Test_TerminalsUpdate() {
 			newTerminal := as.createTerminal(user.Username, terminalType, firmino.TenantID, firmino.ID)
 			req := as.setupRequest(user, fmt.Sprintf("/terminals/%s", newTerminal.ID))
 			// Try to update ID and tenant ID. Expect these calls to be excluded at update
-			updatedTerminal := models.Terminal{Name: fmt.Sprintf("not%s", test.username), Type: models.TerminalTypeRail.String(), ID: user.ID, TenantID: user.ID}
+			updatedTerminal := models.Terminal{Name: fmt.Sprintf("not%s", test.username), Type: models.TerminalTypeRail.String(), ID: user.ID, TenantID: user.ID, FreeTimeDays: nulls.NewInt(4), DemurrageRate: models.NewNullMoney(17500, "")}
 			res := req.Put(updatedTerminal)
 			as.Equal(test.responseCode, res.Code)
 			var dbTerminal = *newTerminal
@@ -211,6 +213,9 @@ func (as *ActionSuite) Test_TerminalsUpdate() {
 				res.Bind(&terminal)
 				as.Equal(updatedTerminal.Name, terminal.Name)
 				as.Equal(updatedTerminal.Type, terminal.Type)
+				as.Equal(updatedTerminal.FreeTimeDays, terminal.FreeTimeDays)
+				// The rate is in the currency of the tenant when none is given
+				as.Equal(models.NewNullMoney(17500, models.DefaultCurrency), terminal.DemurrageRate)
 				as.Equal(newTerminal.ID, terminal.ID)
 				as.Equal(dbTerminal.Name, terminal.Name)
 			} else {
@@ -265,3 +270,135 @@ func (as *ActionSuite) Test_TerminalsDestroy() {
 		})
 	}
 }
+
+func (as *ActionSuite) Test_TerminalsSchedule() {
+	as.LoadFixture("Tenant bootstrap")
+	mane := as.getLoggedInUser("mane")
+	var christmas = time.Date(2026, 12, 25, 0, 0, 0, 0, time.UTC)
+	newTerminal := models.Terminal{Name: "Deltaport", Type: models.TerminalTypePort.String(), ImportFreeTimeDays: nulls.NewInt(5), GateCutoffMinutes: nulls.NewInt(30),
+		Hours:    models.TerminalHours{{Weekday: int(time.Monday), OpenTime: "07:00", CloseTime: "17:00"}},
+		Holidays: models.TerminalHolidays{{Date: christmas, Name: "Christmas"}},
+	}
+	res := as.setupRequest(mane, "/terminals").Post(newTerminal)
+	as.Equal(http.StatusCreated, res.Code, res.Body.String())
+	var terminal = models.Terminal{}
+	res.Bind(&terminal)
+	as.Equal("UTC", terminal.Timezone)
+	as.Equal(newTerminal.ImportFreeTimeDays, terminal.ImportFreeTimeDays)
+	as.Len(terminal.Hours, 1)
+	as.Len(terminal.Holidays, 1)
+
+	res = as.setupRequest(mane, fmt.Sprintf("/terminals/%s", terminal.ID)).Get()
+	as.Equal(http.StatusOK, res.Code)
+	res.Bind(&terminal)
+	as.Len(terminal.Hours, 1)
+	as.Equal("07:00", terminal.Hours[0].OpenTime)
+	as.Equal("Christmas", terminal.Holidays[0].Name)
+
+	// Hours are replaced, holidays are kept when not sent
+	var update = models.Terminal{Name: terminal.Name, Type: terminal.Type, Timezone: "America/Vancouver",
+		Hours: models.TerminalHours{{Weekday: int(time.Monday), OpenTime: "06:00", CloseTime: "14:00"}, {Weekday: int(time.Tuesday), OpenTime: "06:00", CloseTime: "14:00"}}}
+	res = as.setupRequest(mane, fmt.Sprintf("/terminals/%s", terminal.ID)).Put(update)
+	as.Equal(http.StatusOK, res.Code, res.Body.String())
+	res.Bind(&terminal)
+	as.Equal("America/Vancouver", terminal.Timezone)
+	as.Len(terminal.Hours, 2)
+	as.Len(terminal.Holidays, 1)
+	count, err := as.DB.Where("terminal_id = ?", terminal.ID).Count(&models.TerminalHours{})
+	as.Nil(err)
+	as.Equal(2, count)
+
+	update.Hours = models.TerminalHours{{Weekday: int(time.Monday), OpenTime: "14:00", CloseTime: "06:00"}}
+	res = as.setupRequest(mane, fmt.Sprintf("/terminals/%s", terminal.ID)).Put(update)
+	as.Equal(http.StatusUnprocessableEntity, res.Code)
+	update.Hours = nil
+	update.Timezone = "Mars/Olympus"
+	res = as.setupRequest(mane, fmt.Sprintf("/terminals/%s", terminal.ID)).Put(update)
+	as.Equal(http.StatusUnprocessableEntity, res.Code)
+}
+
+func (as *ActionSuite) Test_ShipmentsGateHours() {
+	as.LoadFixture("Tenant bootstrap")
+	mane := as.getLoggedInUser("mane")
+	efaLiv := as.getCustomer("EFA Liv")
+	order := as.createOrder("ord1", models.OrderStatusOpen, mane.TenantID, mane.ID, efaLiv.ID)
+	var monday = time.Date(2026, 11, 2, 0, 0, 0, 0, time.UTC)
+	newTerminal := models.Terminal{Name: "Deltaport", Type: models.TerminalTypePort.String(), GateCutoffMinutes: nulls.NewInt(60),
+		Hours:    models.TerminalHours{{Weekday: int(time.Monday), OpenTime: "07:00", CloseTime: "17:00"}, {Weekday: int(time.Tuesday), OpenTime: "07:00", CloseTime: "17:00"}},
+		Holidays: models.TerminalHolidays{{Date: monday.AddDate(0, 0, 1), Name: "Founders Day"}},
+	}
+	res := as.setupRequest(mane, "/terminals").Post(newTerminal)
+	as.Equal(http.StatusCreated, res.Code, res.Body.String())
+	var terminal = models.Terminal{}
+	res.Bind(&terminal)
+
+	var newShipment = func(serialNumber string, at time.Time) models.Shipment {
+		return models.Shipment{SerialNumber: serialNumber, Status: models.ShipmentStatusUnassigned.String(), Type: models.ShipmentTypeInbound.String(), OrderID: nulls.NewUUID(order.ID), TerminalID: nulls.NewUUID(terminal.ID), ReservationTime: nulls.NewTime(at)}
+	}
+	var tests = []struct {
+		name         string
+		shipment     models.Shipment
+		override     bool
+		responseCode int
+	}{
+		{"open", newShipment("s1", monday.Add(9*time.Hour)), false, http.StatusCreated},
+		{"before opening", newShipment("s2", monday.Add(6*time.Hour)), false, http.StatusConflict},
+		{"after cut-off", newShipment("s3", monday.Add(16*time.Hour+30*time.Minute)), false, http.StatusConflict},
+		{"holiday", newShipment("s4", monday.AddDate(0, 0, 1).Add(9*time.Hour)), false, http.StatusConflict},
+		{"closed day", newShipment("s5", monday.AddDate(0, 0, 2).Add(9*time.Hour)), false, http.StatusConflict},
+		{"override", newShipment("s6", monday.Add(6*time.Hour)), true, http.StatusCreated},
+	}
+	for _, test := range tests {
+		as.T().Run(test.name, func(t *testing.T) {
+			path := "/shipments"
+			if test.override {
+				path = "/shipments?override_gate_hours=true"
+			}
+			res := as.setupRequest(mane, path).Post(test.shipment)
+			as.Equal(test.responseCode, res.Code, res.Body.String())
+			as.Equal(test.override, res.Header().Get("Warning") != "")
+		})
+	}
+}
+
+func (as *ActionSuite) Test_TerminalsNearby() {
+	as.LoadFixture("Tenant bootstrap")
+	mane := as.getLoggedInUser("mane")
+	var newTerminal = func(name string, lat float64, lng float64) models.Terminal {
+		return models.Terminal{Name: name, Type: models.TerminalTypePort.String(), Latitude: nulls.NewFloat64(lat), Longitude: nulls.NewFloat64(lng)}
+	}
+	for _, terminal := range []models.Terminal{newTerminal("Deltaport", 49.0069, -123.1548), newTerminal("Vanterm", 49.2866, -123.0805), newTerminal("Harbor Island", 47.5815, -122.3466), {Name: "Nowhere", Type: models.TerminalTypeRail.String()}} {
+		res := as.setupRequest(mane, "/terminals").Post(terminal)
+		as.Equal(http.StatusCreated, res.Code, res.Body.String())
+	}
+	var tests = []struct {
+		username     string
+		query        string
+		responseCode int
+		names        []string
+	}{
+		{"mane", "?lat=49.05&lng=-123.15", http.StatusOK, []string{"Deltaport", "Vanterm"}},
+		{"salah", "?lat=49.05&lng=-123.15&radius=10", http.StatusOK, []string{"Deltaport"}},
+		{"nike", "?lat=47.6&lng=-122.3&radius=500", http.StatusOK, []string{"Harbor Island", "Deltaport", "Vanterm"}},
+		{"rodriguez", "?lat=49.05&lng=-123.15", http.StatusOK, []string{}},
+		{"mane", "?lat=49.05", http.StatusBadRequest, nil},
+		{"mane", "?lat=99&lng=-123.15", http.StatusBadRequest, nil},
+		{"mane", "?lat=49.05&lng=-123.15&radius=-1", http.StatusBadRequest, nil},
+	}
+	for _, test := range tests {
+		as.T().Run(test.username+test.query, func(t *testing.T) {
+			res := as.setupRequest(as.getLoggedInUser(test.username), "/terminals/nearby"+test.query).Get()
+			as.Equal(test.responseCode, res.Code, res.Body.String())
+			if res.Code != http.StatusOK {
+				return
+			}
+			var nearby = models.NearbyTerminals{}
+			res.Bind(&nearby)
+			var names = []string{}
+			for _, n := range nearby {
+				names = append(names, n.Terminal.Name)
+			}
+			as.Equal(test.names, names)
+		})
+	}
+}
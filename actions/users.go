@@ -207,3 +207,17 @@ func checkEscalation(c buffalo.Context, self *models.User, user *models.User) er
 	}
 	return nil
 }
+
+// checkDriverUserID ensures the user is a driver of the tenant, when one is given
+func checkDriverUserID(c buffalo.Context, tx *pop.Connection, loggedInUser *models.User, ID nulls.UUID) error {
+	if !ID.Valid {
+		return nil
+	}
+	driver := &models.User{}
+	// User must be a driver of the same tenant
+	err := tx.Scope(restrictedScope(c)).Where("tenant_id = ?", loggedInUser.TenantID).Find(driver, ID)
+	if err != nil || !driver.IsDriver() {
+		return errors.New("invalid driver association")
+	}
+	return nil
+}
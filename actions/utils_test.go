@@ -72,10 +72,10 @@ func (as *ActionSuite) createOrder(serialNumber string, orderStatus models.Order
 	newOrder.Erd = nulls.NewTime(time.Now().Add(time.Duration(time.Hour * time.Duration(4))))
 	newOrder.Shipline = nulls.NewString("Costco")
 	newOrder.ShipmentCount = 2 //readonly, not persisted
-	newOrder.PickupCharges = nulls.NewInt(0)
-	newOrder.DropoffCharges = nulls.NewInt(1)
-	newOrder.PickupCost = nulls.NewInt(1000)
-	newOrder.DropoffCost = nulls.NewInt(10000)
+	newOrder.PickupCharges = models.NewNullMoney(0, models.DefaultCurrency)
+	newOrder.DropoffCharges = models.NewNullMoney(1, models.DefaultCurrency)
+	newOrder.PickupCost = models.NewNullMoney(1000, models.DefaultCurrency)
+	newOrder.DropoffCost = models.NewNullMoney(10000, models.DefaultCurrency)
 	newOrder.SoNumber = nulls.NewString("SO123")
 	newOrder.Rld = nulls.NewString("RLD")
 	newOrder.Type = models.ShipmentTypeInbound.String()
@@ -2,21 +2,46 @@ package actions
 
 import (
 	"context"
+	"fmt"
 	"log"
 
 	"firebase.google.com/go/v4/messaging"
 	"github.com/bigpanther/trober/firebase"
+	"github.com/bigpanther/trober/mailer"
+	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/buffalo/mail"
 	"github.com/gobuffalo/buffalo/worker"
 )
 
+// sendNotifications finds the users of the topics of a notification, then pushes it to the ones who want it on
+// their devices and queues the email to the others who want it by email
 func sendNotifications(f firebase.Firebase) func(args worker.Args) error {
 	return func(args worker.Args) error {
+		var event = models.NotificationEvent(args["event"].(string))
 		var tos = args["topics"].([]string)
 		msgTitle := args["message.title"].(string)
 		msgBody := args["message.body"].(string)
 		msgData := args["message.data"].(map[string]string)
+		pushTopics, emails, err := notificationRoutes(models.DB, event, tos)
+		if err != nil {
+			return fmt.Errorf("error routing %s: %w", event, err)
+		}
+		if len(emails) > 0 {
+			app.Worker.Perform(worker.Job{
+				Queue:   "default",
+				Handler: "sendEmails",
+				Args: worker.Args{
+					"emails":        emails,
+					"message.title": msgTitle,
+					"message.body":  msgBody,
+				},
+			})
+		}
+		if len(pushTopics) == 0 {
+			return nil
+		}
 		var messages []*messaging.Message
-		for _, to := range tos {
+		for _, to := range pushTopics {
 			message := &messaging.Message{
 				Data: msgData,
 				Notification: &messaging.Notification{
@@ -32,6 +57,27 @@ func sendNotifications(f firebase.Firebase) func(args worker.Args) error {
 	}
 }
 
+func sendEmails(m mail.Sender) func(args worker.Args) error {
+	return func(args worker.Args) error {
+		var tos = args["emails"].([]string)
+		msgTitle := args["message.title"].(string)
+		msgBody := args["message.body"].(string)
+		for _, to := range tos {
+			// One email per user so recipients do not see each other
+			message := mail.Message{
+				From:    mailer.From(),
+				To:      []string{to},
+				Subject: msgTitle,
+				Bodies:  []mail.Body{{Content: msgBody, ContentType: "text/plain"}},
+			}
+			if err := m.Send(message); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
 func testWorker(args worker.Args) error {
 	log.Println(args)
 	return nil
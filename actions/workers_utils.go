@@ -1,12 +1,26 @@
 package actions
 
-import "github.com/gobuffalo/buffalo/worker"
+import (
+	"github.com/bigpanther/trober/firebase"
+	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/buffalo/worker"
+	"github.com/gobuffalo/envy"
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gofrs/uuid"
+)
 
-func sendNotificationsAsync(topics []string, messageTitle string, messageBody string, data map[string]string) {
+// roleTopicsEnabled also sends push notifications to the topics of the roles, for the devices that subscribed
+// to them before each user had a topic. It is off unless NOTIFICATION_ROLE_TOPICS is true, during the transition.
+var roleTopicsEnabled = envy.Get("NOTIFICATION_ROLE_TOPICS", "false") == "true"
+
+// sendNotificationsAsync sends an event to the users of the topics on the channel each of them prefers for it.
+// The users and their preferences are looked up by the job, out of the request.
+func sendNotificationsAsync(event models.NotificationEvent, topics []string, messageTitle string, messageBody string, data map[string]string) {
 	app.Worker.Perform(worker.Job{
 		Queue:   "default",
 		Handler: "sendNotifications",
 		Args: worker.Args{
+			"event":         event.String(),
 			"topics":        topics,
 			"message.title": messageTitle,
 			"message.body":  messageBody,
@@ -14,3 +28,86 @@ func sendNotificationsAsync(topics []string, messageTitle string, messageBody st
 		},
 	})
 }
+
+// notificationRoutes returns the push topics and the email addresses of the users of the topics,
+// on the channel each of them prefers for the event
+func notificationRoutes(tx *pop.Connection, event models.NotificationEvent, topics []string) ([]string, []string, error) {
+	recipients, err := notificationRecipients(tx, topics)
+	if err != nil {
+		return nil, nil, err
+	}
+	var ids = make([]uuid.UUID, len(recipients))
+	for i, u := range recipients {
+		ids[i] = u.ID
+	}
+	channels, err := models.LoadNotificationChannels(tx, event, ids...)
+	if err != nil {
+		return nil, nil, err
+	}
+	pushTopics, emails := routeNotification(recipients, channels, roleTopicsEnabled)
+	return pushTopics, emails, nil
+}
+
+// routeNotification returns the topics of the recipients who get a notification by push and the addresses of the
+// ones who get it by email, the others opted out. With roleTopics, the topics of their roles are added once each,
+// as long as every recipient on the topic of a role wants the notification by push.
+func routeNotification(recipients models.Users, channels map[uuid.UUID]models.NotificationChannel, roleTopics bool) ([]string, []string) {
+	var pushTopics, emails = []string{}, []string{}
+	var seen = map[string]bool{}
+	for i := range recipients {
+		if channels[recipients[i].ID] != models.NotificationChannelPush {
+			// A shared topic would reach the users who opted out of push
+			seen[firebase.GetRoleTopic(&recipients[i])] = true
+		}
+	}
+	for i := range recipients {
+		switch channels[recipients[i].ID] {
+		case models.NotificationChannelPush:
+			pushTopics = append(pushTopics, firebase.GetTopic(&recipients[i]))
+			if t := firebase.GetRoleTopic(&recipients[i]); roleTopics && !seen[t] {
+				seen[t] = true
+				pushTopics = append(pushTopics, t)
+			}
+		case models.NotificationChannelEmail:
+			emails = append(emails, recipients[i].Email)
+		}
+	}
+	return pushTopics, emails
+}
+
+// notificationRecipients returns the users the topics are for, each of them once
+func notificationRecipients(tx *pop.Connection, topics []string) (models.Users, error) {
+	var recipients = models.Users{}
+	var seen = map[uuid.UUID]bool{}
+	for _, topic := range topics {
+		audience, ok := firebase.ParseTopic(topic)
+		if !ok {
+			app.Logger.Errorf("unknown notification topic %s", topic)
+			continue
+		}
+		q := tx.Q()
+		if audience.Role != "" {
+			q = q.Where("role = ?", audience.Role)
+		}
+		if audience.TenantID != uuid.Nil {
+			q = q.Where("tenant_id = ?", audience.TenantID)
+		}
+		if audience.ID != uuid.Nil {
+			q = q.Where("id = ?", audience.ID)
+		}
+		if audience.CustomerID.Valid {
+			q = q.Where("customer_id = ?", audience.CustomerID.UUID)
+		}
+		users := models.Users{}
+		if err := q.All(&users); err != nil {
+			return nil, err
+		}
+		for _, u := range users {
+			if !seen[u.ID] {
+				seen[u.ID] = true
+				recipients = append(recipients, u)
+			}
+		}
+	}
+	return recipients, nil
+}
@@ -0,0 +1,38 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/gobuffalo/envy"
+)
+
+// ErrNotFound is returned when there is no blob for a key
+var ErrNotFound = errors.New("blob not found")
+
+var errMissingDir = errors.New("missing BLOBSTORE_DIR")
+
+// defaultDir keeps the blobs of development and tests out of the way of the sources
+const defaultDir = "tmp/blobs"
+
+// Store keeps the files uploaded to the app, e.g. the signature and photos of a proof of delivery.
+// Keys are slash separated paths.
+type Store interface {
+	Put(c context.Context, key string, r io.Reader) error
+	Get(c context.Context, key string) (io.ReadCloser, error)
+	Delete(c context.Context, key string) error
+}
+
+// New returns the Store of the directory of BLOBSTORE_DIR. In production the directory must be set,
+// e.g. to a mounted volume, elsewhere it defaults to tmp/blobs.
+func New(production bool) (Store, error) {
+	dir := envy.Get("BLOBSTORE_DIR", "")
+	if dir == "" {
+		if production {
+			return nil, errMissingDir
+		}
+		dir = defaultDir
+	}
+	return NewLocal(dir)
+}
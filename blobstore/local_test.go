@@ -0,0 +1,79 @@
+package blobstore
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gobuffalo/envy"
+)
+
+func TestLocalStore(t *testing.T) {
+	var root = t.TempDir()
+	s, err := NewLocal(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ctx = context.Background()
+	if _, err := s.Get(ctx, "tenant/missing"); err != ErrNotFound {
+		t.Fatalf("Get of a missing blob should return ErrNotFound, got %v", err)
+	}
+	if err := s.Put(ctx, "tenant/shipment/signature", strings.NewReader("first")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put(ctx, "tenant/shipment/signature", strings.NewReader("second")); err != nil {
+		t.Fatal(err)
+	}
+	rc, err := s.Get(ctx, "tenant/shipment/signature")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil || string(b) != "second" {
+		t.Fatalf("Get should return the latest blob, got %q %v", b, err)
+	}
+	if err := s.Delete(ctx, "tenant/shipment/signature"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete(ctx, "tenant/shipment/signature"); err != nil {
+		t.Fatalf("Delete of a missing blob should not fail, got %v", err)
+	}
+	if _, err := s.Get(ctx, "tenant/shipment/signature"); err != ErrNotFound {
+		t.Fatalf("Get of a deleted blob should return ErrNotFound, got %v", err)
+	}
+}
+
+func TestLocalStoreKeysStayInRoot(t *testing.T) {
+	var root = t.TempDir()
+	s, err := NewLocal(filepath.Join(root, "blobs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put(context.Background(), "../../escaped", strings.NewReader("data")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "blobs", "escaped")); err != nil {
+		t.Fatalf("blob should be kept under the root: %v", err)
+	}
+}
+
+func TestNew(t *testing.T) {
+	envy.Temp(func() {
+		envy.Set("BLOBSTORE_DIR", "")
+		if _, err := New(true); err != errMissingDir {
+			t.Fatalf("New in production without BLOBSTORE_DIR should return errMissingDir, got %v", err)
+		}
+		var dir = filepath.Join(t.TempDir(), "blobs")
+		envy.Set("BLOBSTORE_DIR", dir)
+		if _, err := New(true); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := os.Stat(dir); err != nil {
+			t.Fatalf("New should create BLOBSTORE_DIR: %v", err)
+		}
+	})
+}
@@ -4,7 +4,10 @@ import (
 	"log"
 
 	"github.com/bigpanther/trober/actions"
+	"github.com/bigpanther/trober/blobstore"
 	"github.com/bigpanther/trober/firebase"
+	"github.com/bigpanther/trober/mailer"
+	"github.com/gobuffalo/buffalo/mail"
 )
 
 // main is the starting point for your Buffalo application.
@@ -27,7 +30,18 @@ func main() {
 	if err != nil {
 		log.Fatal("failed it initialize connection to firebase", err)
 	}
-	app := actions.App(f)
+	b, err := blobstore.New(isProd)
+	if err != nil {
+		log.Fatal("failed to initialize the blob store", err)
+	}
+	var m mail.Sender = mailer.NewFake()
+	if isProd {
+		m, err = mailer.New()
+		if err != nil {
+			log.Fatal("failed to initialize the mailer", err)
+		}
+	}
+	app := actions.App(f, b, m)
 	if err := app.Serve(); err != nil {
 		log.Fatal(err)
 	}
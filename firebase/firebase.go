@@ -9,11 +9,14 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 
 	firebase "firebase.google.com/go/v4"
 	"firebase.google.com/go/v4/auth"
 	"firebase.google.com/go/v4/messaging"
 	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/nulls"
+	"github.com/gofrs/uuid"
 	"google.golang.org/api/option"
 )
 
@@ -117,6 +120,15 @@ func (client *firebaseSdkClient) SubscribeToTopics(c context.Context, user *mode
 		}
 		log.Println(user.ID, " role=", user.Role, " subscribed to topic", t, r)
 	}
+	// The device now gets the notifications of the user on its own topic, not on the one of its role
+	var legacy = GetRoleTopic(user)
+	r, err := client.messagingClient.UnsubscribeFromTopic(c, []string{token}, legacy)
+	if err != nil {
+		log.Println(user.ID, " role=", user.Role, " unsubscription failed to topic", legacy, r)
+		// Don't fail here
+		return nil
+	}
+	log.Println(user.ID, " role=", user.Role, " unsubscribed to topic", legacy, r)
 	return nil
 }
 
@@ -125,7 +137,7 @@ func (client *firebaseSdkClient) UnSubscribeToTopics(c context.Context, user *mo
 	if token == "" {
 		return errMissingToken
 	}
-	topics := []string{GetSuperAdminTopic(), GetAdminTopic(user),
+	topics := []string{GetTopic(user), GetSuperAdminTopic(), GetAdminTopic(user),
 		GetBackOfficeTopic(user), fmt.Sprintf("%s_driver_%s", user.TenantID, user.ID),
 	}
 	if user.IsCustomer() {
@@ -143,8 +155,15 @@ func (client *firebaseSdkClient) UnSubscribeToTopics(c context.Context, user *mo
 	return nil
 }
 
-// GetTopic returns the topic name for the user
+// GetTopic returns the topic name for the user, the devices of the user subscribe to it.
+// The other topics name the audience of a notification, they are resolved into users before sending.
 func GetTopic(user *models.User) string {
+	return fmt.Sprintf("%s_user_%s", user.TenantID, user.ID)
+}
+
+// GetRoleTopic returns the topic of the role of the user, which the devices of the user subscribed to before
+// each user had a topic. The devices that have not registered again since then still listen on it.
+func GetRoleTopic(user *models.User) string {
 	if user.IsSuperAdmin() {
 		return GetSuperAdminTopic()
 	}
@@ -163,6 +182,46 @@ func GetTopic(user *models.User) string {
 	return fmt.Sprintf("%s_none_%s", user.TenantID, user.ID)
 }
 
+// ParseTopic returns the user a topic is for, with only the fields that match the users of the topic set.
+// The result is false for a topic that is not an audience.
+func ParseTopic(topic string) (*models.User, bool) {
+	if topic == GetSuperAdminTopic() {
+		return &models.User{Role: models.UserRoleSuperAdmin.String()}, true
+	}
+	parts := strings.Split(topic, "_")
+	if len(parts) < 2 {
+		return nil, false
+	}
+	tenantID, err := uuid.FromString(parts[0])
+	if err != nil {
+		return nil, false
+	}
+	var user = &models.User{TenantID: tenantID}
+	switch {
+	case len(parts) == 2 && parts[1] == "admin":
+		user.Role = models.UserRoleAdmin.String()
+	case len(parts) == 2 && parts[1] == "backoffice":
+		user.Role = models.UserRoleBackOffice.String()
+	case len(parts) == 3 && parts[1] == "customer":
+		customerID, err := uuid.FromString(parts[2])
+		if err != nil {
+			return nil, false
+		}
+		user.Role = models.UserRoleCustomer.String()
+		user.CustomerID = nulls.NewUUID(customerID)
+	case len(parts) == 3 && (parts[1] == "driver" || parts[1] == "user"):
+		if user.ID, err = uuid.FromString(parts[2]); err != nil {
+			return nil, false
+		}
+		if parts[1] == "driver" {
+			user.Role = models.UserRoleDriver.String()
+		}
+	default:
+		return nil, false
+	}
+	return user, true
+}
+
 // GetSuperAdminTopic returns the topic for superuser
 func GetSuperAdminTopic() string {
 	return fmt.Sprint("superadmin")
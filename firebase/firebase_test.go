@@ -0,0 +1,68 @@
+package firebase
+
+import (
+	"testing"
+
+	"github.com/bigpanther/trober/models"
+	"github.com/gobuffalo/nulls"
+	"github.com/gofrs/uuid"
+)
+
+func TestParseTopic(t *testing.T) {
+	var tenantID = uuid.Must(uuid.NewV4())
+	var id = uuid.Must(uuid.NewV4())
+	var user = &models.User{ID: id, TenantID: tenantID}
+	var tests = []struct {
+		topic    string
+		expected *models.User
+	}{
+		{GetSuperAdminTopic(), &models.User{Role: models.UserRoleSuperAdmin.String()}},
+		{GetAdminTopic(user), &models.User{TenantID: tenantID, Role: models.UserRoleAdmin.String()}},
+		{GetBackOfficeTopic(user), &models.User{TenantID: tenantID, Role: models.UserRoleBackOffice.String()}},
+		{GetCustomerTopic(tenantID.String(), id.String()), &models.User{TenantID: tenantID, Role: models.UserRoleCustomer.String(), CustomerID: nulls.NewUUID(id)}},
+		{GetDriverTopic(tenantID.String(), id.String()), &models.User{ID: id, TenantID: tenantID, Role: models.UserRoleDriver.String()}},
+		{GetTopic(user), &models.User{ID: id, TenantID: tenantID}},
+		{"news", nil},
+		{tenantID.String() + "_none_" + id.String(), nil},
+		{tenantID.String() + "_driver_unknown", nil},
+		{"unknown_admin", nil},
+	}
+	for _, test := range tests {
+		t.Run(test.topic, func(t *testing.T) {
+			u, ok := ParseTopic(test.topic)
+			if ok != (test.expected != nil) {
+				t.Fatalf("ParseTopic(%q) should be %v", test.topic, test.expected != nil)
+			}
+			if !ok {
+				return
+			}
+			if u.ID != test.expected.ID || u.TenantID != test.expected.TenantID || u.Role != test.expected.Role || u.CustomerID != test.expected.CustomerID {
+				t.Fatalf("ParseTopic(%q) returned %+v, expected %+v", test.topic, u, test.expected)
+			}
+		})
+	}
+}
+
+func TestGetRoleTopic(t *testing.T) {
+	var tenantID = uuid.Must(uuid.NewV4())
+	var id = uuid.Must(uuid.NewV4())
+	var customerID = uuid.Must(uuid.NewV4())
+	var tests = []struct {
+		user     *models.User
+		expected string
+	}{
+		{&models.User{ID: id, TenantID: tenantID, Role: models.UserRoleSuperAdmin.String()}, "superadmin"},
+		{&models.User{ID: id, TenantID: tenantID, Role: models.UserRoleAdmin.String()}, tenantID.String() + "_admin"},
+		{&models.User{ID: id, TenantID: tenantID, Role: models.UserRoleBackOffice.String()}, tenantID.String() + "_backoffice"},
+		{&models.User{ID: id, TenantID: tenantID, Role: models.UserRoleCustomer.String(), CustomerID: nulls.NewUUID(customerID)}, tenantID.String() + "_customer_" + customerID.String()},
+		{&models.User{ID: id, TenantID: tenantID, Role: models.UserRoleDriver.String()}, tenantID.String() + "_driver_" + id.String()},
+		{&models.User{ID: id, TenantID: tenantID, Role: models.UserRoleNone.String()}, tenantID.String() + "_none_" + id.String()},
+	}
+	for _, test := range tests {
+		t.Run(test.user.Role, func(t *testing.T) {
+			if topic := GetRoleTopic(test.user); topic != test.expected {
+				t.Fatalf("GetRoleTopic(%s) returned %q, expected %q", test.user.Role, topic, test.expected)
+			}
+		})
+	}
+}
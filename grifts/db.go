@@ -14,9 +14,12 @@ var _ = grift.Namespace("db", func() {
 	grift.Desc("seed", "Seeds a database")
 	grift.Add("seed", func(c *grift.Context) error {
 		tenant := &models.Tenant{
-			Name: "system",
-			Type: "System",
-			Code: "6mapg",
+			Name:                  "system",
+			Type:                  "System",
+			Code:                  "6mapg",
+			Currency:              models.DefaultCurrency,
+			GeofenceArrivedMode:   models.GeofenceTransitionModeSuggest.String(),
+			GeofenceInTransitMode: models.GeofenceTransitionModeSuggest.String(),
 		}
 		err := models.DB.Create(tenant)
 		if err != nil {
@@ -45,9 +48,12 @@ var _ = grift.Namespace("db", func() {
 
 func demoCreate() error {
 	tenant := &models.Tenant{
-		Name: "Acme Enterprises",
-		Type: "Test",
-		Code: "7acme",
+		Name:                  "Acme Enterprises",
+		Type:                  "Test",
+		Code:                  "7acme",
+		Currency:              models.DefaultCurrency,
+		GeofenceArrivedMode:   models.GeofenceTransitionModeSuggest.String(),
+		GeofenceInTransitMode: models.GeofenceTransitionModeSuggest.String(),
 	}
 	err := models.DB.Create(tenant)
 	if err != nil {
@@ -218,6 +224,15 @@ func demoDrop() error {
 	if err != nil {
 		return err
 	}
+	invoices := &models.Invoices{}
+	err = models.DB.Where("tenant_id=?", tenant.ID).All(invoices)
+	if err != nil {
+		return err
+	}
+	err = models.DB.Destroy(invoices)
+	if err != nil {
+		return err
+	}
 	orders := &models.Orders{}
 	err = models.DB.Where("tenant_id=?", tenant.ID).All(orders)
 	if err != nil {
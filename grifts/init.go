@@ -4,7 +4,10 @@ import (
 	"log"
 
 	"github.com/bigpanther/trober/actions"
+	"github.com/bigpanther/trober/blobstore"
 	"github.com/bigpanther/trober/firebase"
+	"github.com/bigpanther/trober/mailer"
+	"github.com/gobuffalo/buffalo/mail"
 
 	"github.com/gobuffalo/buffalo"
 )
@@ -23,5 +26,16 @@ func init() {
 	if err != nil {
 		log.Fatal("failed it initialize connection to firebase", err)
 	}
-	buffalo.Grifts(actions.App(f))
+	b, err := blobstore.New(isProd)
+	if err != nil {
+		log.Fatal("failed to initialize the blob store", err)
+	}
+	var m mail.Sender = mailer.NewFake()
+	if isProd {
+		m, err = mailer.New()
+		if err != nil {
+			log.Fatal("failed to initialize the mailer", err)
+		}
+	}
+	buffalo.Grifts(actions.App(f, b, m))
 }
@@ -0,0 +1,40 @@
+package mailer
+
+import (
+	"sync"
+
+	"github.com/gobuffalo/buffalo/mail"
+)
+
+// Fake keeps the emails instead of sending them, for development and tests
+type Fake struct {
+	mu       sync.Mutex
+	messages []mail.Message
+}
+
+// NewFake returns a sender that keeps the emails
+func NewFake() *Fake {
+	return &Fake{}
+}
+
+// Send keeps the email
+func (f *Fake) Send(m mail.Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.messages = append(f.messages, m)
+	return nil
+}
+
+// Messages returns the emails sent so far
+func (f *Fake) Messages() []mail.Message {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]mail.Message{}, f.messages...)
+}
+
+// Reset forgets the emails sent so far
+func (f *Fake) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.messages = nil
+}
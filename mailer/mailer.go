@@ -0,0 +1,25 @@
+package mailer
+
+import (
+	"errors"
+
+	"github.com/gobuffalo/buffalo/mail"
+	"github.com/gobuffalo/envy"
+)
+
+var errMissingHost = errors.New("missing SMTP_HOST")
+
+// New returns a sender of the emails of the app through the SMTP server of SMTP_HOST and SMTP_PORT,
+// authenticated with SMTP_USER and SMTP_PASSWORD
+func New() (mail.Sender, error) {
+	host := envy.Get("SMTP_HOST", "")
+	if host == "" {
+		return nil, errMissingHost
+	}
+	return mail.NewSMTPSender(host, envy.Get("SMTP_PORT", "587"), envy.Get("SMTP_USER", ""), envy.Get("SMTP_PASSWORD", ""))
+}
+
+// From returns the address the emails of the app are sent from
+func From() string {
+	return envy.Get("MAIL_FROM", "no-reply@bigpanther.ca")
+}
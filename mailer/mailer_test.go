@@ -0,0 +1,42 @@
+package mailer
+
+import (
+	"testing"
+
+	"github.com/gobuffalo/buffalo/mail"
+	"github.com/gobuffalo/envy"
+)
+
+func TestNew(t *testing.T) {
+	envy.Temp(func() {
+		envy.Set("SMTP_HOST", "")
+		if _, err := New(); err != errMissingHost {
+			t.Fatalf("New without SMTP_HOST should return errMissingHost, got %v", err)
+		}
+		envy.Set("SMTP_HOST", "localhost")
+		envy.Set("SMTP_PORT", "port")
+		if _, err := New(); err == nil {
+			t.Fatal("New with an invalid SMTP_PORT should fail")
+		}
+		envy.Set("SMTP_PORT", "2525")
+		if _, err := New(); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestFake(t *testing.T) {
+	var f mail.Sender = NewFake()
+	if err := f.Send(mail.Message{To: []string{"mane@bigpanther.ca"}, Subject: "LFD tomorrow"}); err != nil {
+		t.Fatal(err)
+	}
+	var fake = f.(*Fake)
+	messages := fake.Messages()
+	if len(messages) != 1 || messages[0].Subject != "LFD tomorrow" {
+		t.Fatalf("Fake should keep the message, got %v", messages)
+	}
+	fake.Reset()
+	if len(fake.Messages()) != 0 {
+		t.Fatal("Reset should forget the messages")
+	}
+}
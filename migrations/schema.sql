@@ -54,278 +54,1774 @@ CREATE TABLE public.customers (
 
 ALTER TABLE public.customers OWNER TO postgres;
 
+--
+-- Name: delivery_proof_files; Type: TABLE; Schema: public; Owner: postgres
+--
+
+CREATE TABLE public.delivery_proof_files (
+    id uuid NOT NULL,
+    tenant_id uuid NOT NULL,
+    delivery_proof_id uuid NOT NULL,
+    kind character varying(10) NOT NULL,
+    content_type character varying(50) NOT NULL,
+    size integer NOT NULL,
+    blob_key character varying(255) NOT NULL,
+    created_at timestamp without time zone NOT NULL,
+    updated_at timestamp without time zone NOT NULL
+);
+
+
+ALTER TABLE public.delivery_proof_files OWNER TO postgres;
+
+--
+-- Name: delivery_proofs; Type: TABLE; Schema: public; Owner: postgres
+--
+
+CREATE TABLE public.delivery_proofs (
+    id uuid NOT NULL,
+    created_by uuid NOT NULL,
+    tenant_id uuid NOT NULL,
+    shipment_id uuid NOT NULL,
+    receiver_name character varying(255) NOT NULL,
+    seal_number character varying(50),
+    delivered_at timestamp without time zone NOT NULL,
+    latitude numeric(9,6),
+    longitude numeric(9,6),
+    created_at timestamp without time zone NOT NULL,
+    updated_at timestamp without time zone NOT NULL
+);
+
+
+ALTER TABLE public.delivery_proofs OWNER TO postgres;
+
+--
+-- Name: documents; Type: TABLE; Schema: public; Owner: postgres
+--
+
+CREATE TABLE public.documents (
+    id uuid NOT NULL,
+    created_by uuid NOT NULL,
+    tenant_id uuid NOT NULL,
+    order_id uuid,
+    shipment_id uuid,
+    type character varying(20) NOT NULL,
+    name character varying(255) NOT NULL,
+    content_type character varying(50) NOT NULL,
+    size integer NOT NULL,
+    checksum character varying(64) NOT NULL,
+    blob_key character varying(255) NOT NULL,
+    created_at timestamp without time zone NOT NULL,
+    updated_at timestamp without time zone NOT NULL
+);
+
+
+ALTER TABLE public.documents OWNER TO postgres;
+
+--
+-- Name: driver_compliances; Type: TABLE; Schema: public; Owner: postgres
+--
+
+CREATE TABLE public.driver_compliances (
+    id uuid NOT NULL,
+    created_by uuid NOT NULL,
+    tenant_id uuid NOT NULL,
+    driver_id uuid NOT NULL,
+    licence_class character varying(10),
+    licence_expiry date,
+    medical_certificate_expiry date,
+    twic_expiry date,
+    port_pass_expiry date,
+    created_at timestamp without time zone NOT NULL,
+    updated_at timestamp without time zone NOT NULL
+);
+
+
+ALTER TABLE public.driver_compliances OWNER TO postgres;
+
+--
+-- Name: driver_locations; Type: TABLE; Schema: public; Owner: postgres
+--
+
+CREATE TABLE public.driver_locations (
+    id uuid NOT NULL,
+    tenant_id uuid NOT NULL,
+    driver_id uuid NOT NULL,
+    shipment_id uuid,
+    recorded_at timestamp without time zone NOT NULL,
+    latitude numeric(9,6) NOT NULL,
+    longitude numeric(9,6) NOT NULL,
+    accuracy numeric(10,2),
+    speed numeric(10,2),
+    heading numeric(5,2),
+    created_at timestamp without time zone NOT NULL,
+    updated_at timestamp without time zone NOT NULL
+);
+
+
+ALTER TABLE public.driver_locations OWNER TO postgres;
+
+--
+-- Name: driver_pay_rules; Type: TABLE; Schema: public; Owner: postgres
+--
+
+CREATE TABLE public.driver_pay_rules (
+    id uuid NOT NULL,
+    created_by uuid NOT NULL,
+    tenant_id uuid NOT NULL,
+    driver_id uuid,
+    type character varying(20) NOT NULL,
+    currency character varying(3) NOT NULL,
+    rate integer DEFAULT 0 NOT NULL,
+    percent numeric(5,2) DEFAULT 0 NOT NULL,
+    created_at timestamp without time zone NOT NULL,
+    updated_at timestamp without time zone NOT NULL
+);
+
+
+ALTER TABLE public.driver_pay_rules OWNER TO postgres;
+
+--
+-- Name: driver_settlement_lines; Type: TABLE; Schema: public; Owner: postgres
+--
+
+CREATE TABLE public.driver_settlement_lines (
+    id uuid NOT NULL,
+    driver_settlement_id uuid NOT NULL,
+    shipment_id uuid,
+    type character varying(15) NOT NULL,
+    description character varying(255) NOT NULL,
+    amount jsonb NOT NULL,
+    created_at timestamp without time zone NOT NULL,
+    updated_at timestamp without time zone NOT NULL
+);
+
+
+ALTER TABLE public.driver_settlement_lines OWNER TO postgres;
+
+--
+-- Name: driver_settlements; Type: TABLE; Schema: public; Owner: postgres
+--
+
+CREATE TABLE public.driver_settlements (
+    id uuid NOT NULL,
+    created_by uuid NOT NULL,
+    tenant_id uuid NOT NULL,
+    driver_id uuid NOT NULL,
+    period_start timestamp without time zone NOT NULL,
+    period_end timestamp without time zone NOT NULL,
+    status character varying(15) NOT NULL,
+    earnings jsonb NOT NULL,
+    deductions jsonb NOT NULL,
+    advances jsonb NOT NULL,
+    net_pay jsonb NOT NULL,
+    finalized_at timestamp without time zone,
+    finalized_by uuid,
+    created_at timestamp without time zone NOT NULL,
+    updated_at timestamp without time zone NOT NULL
+);
+
+
+ALTER TABLE public.driver_settlements OWNER TO postgres;
+
+--
+-- Name: driver_shifts; Type: TABLE; Schema: public; Owner: postgres
+--
+
+CREATE TABLE public.driver_shifts (
+    id uuid NOT NULL,
+    created_by uuid NOT NULL,
+    tenant_id uuid NOT NULL,
+    driver_id uuid NOT NULL,
+    weekday integer NOT NULL,
+    start_time character varying(5) NOT NULL,
+    end_time character varying(5) NOT NULL,
+    timezone character varying(64) NOT NULL,
+    capacity integer,
+    created_at timestamp without time zone NOT NULL,
+    updated_at timestamp without time zone NOT NULL
+);
+
+
+ALTER TABLE public.driver_shifts OWNER TO postgres;
+
+--
+-- Name: equipment; Type: TABLE; Schema: public; Owner: postgres
+--
+
+CREATE TABLE public.equipment (
+    id uuid NOT NULL,
+    created_by uuid NOT NULL,
+    tenant_id uuid NOT NULL,
+    type character varying(10) NOT NULL,
+    unit_number character varying(50) NOT NULL,
+    plate character varying(20),
+    owner character varying(10) NOT NULL,
+    status character varying(15) NOT NULL,
+    created_at timestamp without time zone NOT NULL,
+    updated_at timestamp without time zone NOT NULL
+);
+
+
+ALTER TABLE public.equipment OWNER TO postgres;
+
+--
+-- Name: invoice_lines; Type: TABLE; Schema: public; Owner: postgres
+--
+
+CREATE TABLE public.invoice_lines (
+    id uuid NOT NULL,
+    tenant_id uuid NOT NULL,
+    invoice_id uuid NOT NULL,
+    order_id uuid,
+    type character varying(15) NOT NULL,
+    description character varying(255) NOT NULL,
+    amount jsonb NOT NULL,
+    created_at timestamp without time zone NOT NULL,
+    updated_at timestamp without time zone NOT NULL
+);
+
+
+ALTER TABLE public.invoice_lines OWNER TO postgres;
+
+--
+-- Name: invoice_orders; Type: TABLE; Schema: public; Owner: postgres
+--
+
+CREATE TABLE public.invoice_orders (
+    id uuid NOT NULL,
+    tenant_id uuid NOT NULL,
+    invoice_id uuid NOT NULL,
+    order_id uuid NOT NULL,
+    created_at timestamp without time zone NOT NULL,
+    updated_at timestamp without time zone NOT NULL
+);
+
+
+ALTER TABLE public.invoice_orders OWNER TO postgres;
+
+--
+-- Name: invoices; Type: TABLE; Schema: public; Owner: postgres
+--
+
+CREATE TABLE public.invoices (
+    id uuid NOT NULL,
+    created_by uuid NOT NULL,
+    tenant_id uuid NOT NULL,
+    customer_id uuid NOT NULL,
+    number integer NOT NULL,
+    status character varying(15) NOT NULL,
+    due_date timestamp without time zone,
+    paid_at timestamp without time zone,
+    payment_reference character varying(255),
+    total jsonb NOT NULL,
+    created_at timestamp without time zone NOT NULL,
+    updated_at timestamp without time zone NOT NULL
+);
+
+
+ALTER TABLE public.invoices OWNER TO postgres;
+
+--
+-- Name: locations; Type: TABLE; Schema: public; Owner: postgres
+--
+
+CREATE TABLE public.locations (
+    id uuid NOT NULL,
+    created_by uuid NOT NULL,
+    tenant_id uuid NOT NULL,
+    customer_id uuid,
+    name character varying(100) NOT NULL,
+    address_line character varying(255),
+    city character varying(100),
+    region character varying(100),
+    postal_code character varying(20),
+    country character varying(2),
+    latitude numeric(9,6),
+    longitude numeric(9,6),
+    geofence_radius_meters integer,
+    geofence_polygon jsonb,
+    created_at timestamp without time zone NOT NULL,
+    updated_at timestamp without time zone NOT NULL
+);
+
+
+ALTER TABLE public.locations OWNER TO postgres;
+
+--
+-- Name: maintenance_items; Type: TABLE; Schema: public; Owner: postgres
+--
+
+CREATE TABLE public.maintenance_items (
+    id uuid NOT NULL,
+    created_by uuid NOT NULL,
+    tenant_id uuid NOT NULL,
+    equipment_id uuid NOT NULL,
+    type character varying(15) NOT NULL,
+    description character varying(255),
+    due_date date NOT NULL,
+    completed_at timestamp without time zone,
+    created_at timestamp without time zone NOT NULL,
+    updated_at timestamp without time zone NOT NULL,
+    last_alerted_days integer
+);
+
+
+ALTER TABLE public.maintenance_items OWNER TO postgres;
+
+--
+-- Name: notification_preferences; Type: TABLE; Schema: public; Owner: postgres
+--
+
+CREATE TABLE public.notification_preferences (
+    id uuid NOT NULL,
+    tenant_id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    event character varying(50) NOT NULL,
+    channel character varying(20) NOT NULL,
+    created_at timestamp without time zone NOT NULL,
+    updated_at timestamp without time zone NOT NULL
+);
+
+
+ALTER TABLE public.notification_preferences OWNER TO postgres;
+
 --
 -- Name: orders; Type: TABLE; Schema: public; Owner: postgres
 --
 
-CREATE TABLE public.orders (
-    id uuid NOT NULL,
-    created_by uuid NOT NULL,
-    tenant_id uuid NOT NULL,
-    customer_id uuid NOT NULL,
-    serial_number character varying(255) NOT NULL,
-    status character varying(255) NOT NULL,
-    created_at timestamp without time zone NOT NULL,
-    updated_at timestamp without time zone NOT NULL,
-    carrier_id uuid,
-    terminal_id uuid,
-    eta timestamp without time zone,
-    so_number character varying(255),
-    shipline character varying(255),
-    pickup_charges integer,
-    pickup_cost integer,
-    dropoff_charges integer,
-    dropoff_cost integer,
-    rld character varying(255),
-    erd timestamp without time zone,
-    docco timestamp without time zone,
-    lfd timestamp without time zone,
-    container_status character varying(255),
-    type character varying(255)
-);
+CREATE TABLE public.orders (
+    id uuid NOT NULL,
+    created_by uuid NOT NULL,
+    tenant_id uuid NOT NULL,
+    customer_id uuid NOT NULL,
+    serial_number character varying(255) NOT NULL,
+    status character varying(255) NOT NULL,
+    created_at timestamp without time zone NOT NULL,
+    updated_at timestamp without time zone NOT NULL,
+    carrier_id uuid,
+    terminal_id uuid,
+    eta timestamp without time zone,
+    so_number character varying(255),
+    shipline character varying(255),
+    pickup_charges jsonb,
+    pickup_cost jsonb,
+    dropoff_charges jsonb,
+    dropoff_cost jsonb,
+    rld character varying(255),
+    erd timestamp without time zone,
+    docco timestamp without time zone,
+    lfd timestamp without time zone,
+    container_status character varying(255),
+    type character varying(255)
+);
+
+
+ALTER TABLE public.orders OWNER TO postgres;
+
+--
+-- Name: rate_card_accessorials; Type: TABLE; Schema: public; Owner: postgres
+--
+
+CREATE TABLE public.rate_card_accessorials (
+    id uuid NOT NULL,
+    rate_card_id uuid NOT NULL,
+    type character varying(15) NOT NULL,
+    rate jsonb NOT NULL,
+    created_at timestamp without time zone NOT NULL,
+    updated_at timestamp without time zone NOT NULL
+);
+
+
+ALTER TABLE public.rate_card_accessorials OWNER TO postgres;
+
+--
+-- Name: rate_card_lanes; Type: TABLE; Schema: public; Owner: postgres
+--
+
+CREATE TABLE public.rate_card_lanes (
+    id uuid NOT NULL,
+    rate_card_id uuid NOT NULL,
+    terminal_id uuid NOT NULL,
+    destination character varying(255) NOT NULL,
+    size character varying(15),
+    pickup_rate jsonb NOT NULL,
+    dropoff_rate jsonb NOT NULL,
+    created_at timestamp without time zone NOT NULL,
+    updated_at timestamp without time zone NOT NULL
+);
+
+
+ALTER TABLE public.rate_card_lanes OWNER TO postgres;
+
+--
+-- Name: rate_cards; Type: TABLE; Schema: public; Owner: postgres
+--
+
+CREATE TABLE public.rate_cards (
+    id uuid NOT NULL,
+    created_by uuid NOT NULL,
+    tenant_id uuid NOT NULL,
+    customer_id uuid NOT NULL,
+    name character varying(255) NOT NULL,
+    currency character varying(3) NOT NULL,
+    fuel_surcharge_percent numeric(5,2) DEFAULT 0 NOT NULL,
+    effective_from timestamp without time zone NOT NULL,
+    effective_to timestamp without time zone,
+    created_at timestamp without time zone NOT NULL,
+    updated_at timestamp without time zone NOT NULL
+);
+
+
+ALTER TABLE public.rate_cards OWNER TO postgres;
+
+--
+-- Name: schema_migration; Type: TABLE; Schema: public; Owner: postgres
+--
+
+CREATE TABLE public.schema_migration (
+    version character varying(14) NOT NULL
+);
+
+
+ALTER TABLE public.schema_migration OWNER TO postgres;
+
+--
+-- Name: shipment_charges; Type: TABLE; Schema: public; Owner: postgres
+--
+
+CREATE TABLE public.shipment_charges (
+    id uuid NOT NULL,
+    created_by uuid NOT NULL,
+    tenant_id uuid NOT NULL,
+    shipment_id uuid NOT NULL,
+    type character varying(15) NOT NULL,
+    description character varying(255),
+    quantity integer NOT NULL,
+    customer_rate jsonb NOT NULL,
+    cost_rate jsonb NOT NULL,
+    created_at timestamp without time zone NOT NULL,
+    updated_at timestamp without time zone NOT NULL
+);
+
+
+ALTER TABLE public.shipment_charges OWNER TO postgres;
+
+--
+-- Name: shipment_events; Type: TABLE; Schema: public; Owner: postgres
+--
+
+CREATE TABLE public.shipment_events (
+    id uuid NOT NULL,
+    created_by uuid NOT NULL,
+    tenant_id uuid NOT NULL,
+    shipment_id uuid NOT NULL,
+    from_status character varying(15),
+    to_status character varying(15) NOT NULL,
+    driver_id uuid,
+    created_at timestamp without time zone NOT NULL,
+    updated_at timestamp without time zone NOT NULL
+);
+
+
+ALTER TABLE public.shipment_events OWNER TO postgres;
+
+--
+-- Name: shipment_status_suggestions; Type: TABLE; Schema: public; Owner: postgres
+--
+
+CREATE TABLE public.shipment_status_suggestions (
+    id uuid NOT NULL,
+    tenant_id uuid NOT NULL,
+    shipment_id uuid NOT NULL,
+    driver_id uuid NOT NULL,
+    terminal_id uuid,
+    from_status character varying(15) NOT NULL,
+    to_status character varying(15) NOT NULL,
+    status character varying(10) NOT NULL,
+    latitude numeric(9,6) NOT NULL,
+    longitude numeric(9,6) NOT NULL,
+    recorded_at timestamp without time zone NOT NULL,
+    reviewed_by uuid,
+    reviewed_at timestamp without time zone,
+    created_at timestamp without time zone NOT NULL,
+    updated_at timestamp without time zone NOT NULL
+);
+
+
+ALTER TABLE public.shipment_status_suggestions OWNER TO postgres;
+
+--
+-- Name: shipments; Type: TABLE; Schema: public; Owner: postgres
+--
+
+CREATE TABLE public.shipments (
+    id uuid NOT NULL,
+    created_by uuid NOT NULL,
+    tenant_id uuid NOT NULL,
+    terminal_id uuid,
+    order_id uuid,
+    serial_number character varying(20),
+    origin character varying(20),
+    destination character varying(20),
+    lfd timestamp without time zone,
+    reservation_time timestamp without time zone,
+    size character varying(15),
+    type character varying(15),
+    status character varying(15) NOT NULL,
+    driver_id uuid,
+    created_at timestamp without time zone NOT NULL,
+    updated_at timestamp without time zone NOT NULL,
+    carrier_id uuid,
+    customer_id uuid,
+    miles integer,
+    terminal_slot_id uuid,
+    origin_location_id uuid,
+    destination_location_id uuid,
+    eta timestamp without time zone,
+    eta_updated_at timestamp without time zone,
+    eta_alerted_at timestamp without time zone,
+    tractor_id uuid,
+    chassis_id uuid,
+    lfd_alerted_threshold integer
+);
+
+
+ALTER TABLE public.shipments OWNER TO postgres;
+
+--
+-- Name: tenants; Type: TABLE; Schema: public; Owner: postgres
+--
+
+CREATE TABLE public.tenants (
+    id uuid NOT NULL,
+    created_by uuid,
+    name character varying(50) NOT NULL,
+    type character varying(15) NOT NULL,
+    created_at timestamp without time zone NOT NULL,
+    updated_at timestamp without time zone NOT NULL,
+    code character varying(20) NOT NULL,
+    currency character varying(3) DEFAULT 'CAD'::character varying NOT NULL,
+    location_retention_days integer,
+    geofence_arrived_mode character varying(10) DEFAULT 'Suggest'::character varying NOT NULL,
+    geofence_in_transit_mode character varying(10) DEFAULT 'Suggest'::character varying NOT NULL
+);
+
+
+ALTER TABLE public.tenants OWNER TO postgres;
+
+--
+-- Name: terminal_holidays; Type: TABLE; Schema: public; Owner: postgres
+--
+
+CREATE TABLE public.terminal_holidays (
+    id uuid NOT NULL,
+    terminal_id uuid NOT NULL,
+    date date NOT NULL,
+    name character varying(255) NOT NULL,
+    created_at timestamp without time zone NOT NULL,
+    updated_at timestamp without time zone NOT NULL
+);
+
+
+ALTER TABLE public.terminal_holidays OWNER TO postgres;
+
+--
+-- Name: terminal_hours; Type: TABLE; Schema: public; Owner: postgres
+--
+
+CREATE TABLE public.terminal_hours (
+    id uuid NOT NULL,
+    terminal_id uuid NOT NULL,
+    weekday integer NOT NULL,
+    open_time character varying(5) NOT NULL,
+    close_time character varying(5) NOT NULL,
+    created_at timestamp without time zone NOT NULL,
+    updated_at timestamp without time zone NOT NULL
+);
+
+
+ALTER TABLE public.terminal_hours OWNER TO postgres;
+
+--
+-- Name: terminal_slots; Type: TABLE; Schema: public; Owner: postgres
+--
+
+CREATE TABLE public.terminal_slots (
+    id uuid NOT NULL,
+    created_by uuid NOT NULL,
+    tenant_id uuid NOT NULL,
+    terminal_id uuid NOT NULL,
+    weekday integer NOT NULL,
+    start_time character varying(5) NOT NULL,
+    end_time character varying(5) NOT NULL,
+    capacity integer NOT NULL,
+    size character varying(15),
+    type character varying(15),
+    created_at timestamp without time zone NOT NULL,
+    updated_at timestamp without time zone NOT NULL
+);
+
+
+ALTER TABLE public.terminal_slots OWNER TO postgres;
+
+--
+-- Name: terminals; Type: TABLE; Schema: public; Owner: postgres
+--
+
+CREATE TABLE public.terminals (
+    id uuid NOT NULL,
+    created_by uuid NOT NULL,
+    name character varying(50) NOT NULL,
+    type character varying(15) NOT NULL,
+    tenant_id uuid NOT NULL,
+    created_at timestamp without time zone NOT NULL,
+    updated_at timestamp without time zone NOT NULL,
+    free_time_days integer,
+    demurrage_rate jsonb,
+    timezone character varying(64) DEFAULT 'UTC'::character varying NOT NULL,
+    import_free_time_days integer,
+    export_free_time_days integer,
+    gate_cutoff_minutes integer,
+    address_line character varying(255),
+    city character varying(100),
+    region character varying(100),
+    postal_code character varying(20),
+    country character varying(2),
+    latitude numeric(9,6),
+    longitude numeric(9,6),
+    geofence_radius_meters integer,
+    geofence_polygon jsonb,
+    required_credentials jsonb
+);
+
+
+ALTER TABLE public.terminals OWNER TO postgres;
+
+--
+-- Name: time_off_requests; Type: TABLE; Schema: public; Owner: postgres
+--
+
+CREATE TABLE public.time_off_requests (
+    id uuid NOT NULL,
+    created_by uuid NOT NULL,
+    tenant_id uuid NOT NULL,
+    driver_id uuid NOT NULL,
+    starts_at timestamp without time zone NOT NULL,
+    ends_at timestamp without time zone NOT NULL,
+    reason character varying(255),
+    status character varying(15) NOT NULL,
+    reviewed_by uuid,
+    reviewed_at timestamp without time zone,
+    created_at timestamp without time zone NOT NULL,
+    updated_at timestamp without time zone NOT NULL
+);
+
+
+ALTER TABLE public.time_off_requests OWNER TO postgres;
+
+--
+-- Name: users; Type: TABLE; Schema: public; Owner: postgres
+--
+
+CREATE TABLE public.users (
+    id uuid NOT NULL,
+    created_by uuid,
+    name character varying(50) NOT NULL,
+    username character varying(50) NOT NULL,
+    role character varying(15) NOT NULL,
+    tenant_id uuid NOT NULL,
+    customer_id uuid,
+    created_at timestamp without time zone NOT NULL,
+    updated_at timestamp without time zone NOT NULL,
+    email character varying(50) NOT NULL,
+    device_id character varying(255)
+);
+
+
+ALTER TABLE public.users OWNER TO postgres;
+
+--
+-- Name: carriers carriers_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.carriers
+    ADD CONSTRAINT carriers_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: customers customers_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.customers
+    ADD CONSTRAINT customers_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: delivery_proof_files delivery_proof_files_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.delivery_proof_files
+    ADD CONSTRAINT delivery_proof_files_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: delivery_proofs delivery_proofs_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.delivery_proofs
+    ADD CONSTRAINT delivery_proofs_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: documents documents_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.documents
+    ADD CONSTRAINT documents_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: driver_compliances driver_compliances_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.driver_compliances
+    ADD CONSTRAINT driver_compliances_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: driver_locations driver_locations_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.driver_locations
+    ADD CONSTRAINT driver_locations_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: driver_pay_rules driver_pay_rules_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.driver_pay_rules
+    ADD CONSTRAINT driver_pay_rules_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: driver_settlement_lines driver_settlement_lines_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.driver_settlement_lines
+    ADD CONSTRAINT driver_settlement_lines_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: driver_settlements driver_settlements_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.driver_settlements
+    ADD CONSTRAINT driver_settlements_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: driver_shifts driver_shifts_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.driver_shifts
+    ADD CONSTRAINT driver_shifts_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: equipment equipment_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.equipment
+    ADD CONSTRAINT equipment_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: invoice_lines invoice_lines_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.invoice_lines
+    ADD CONSTRAINT invoice_lines_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: invoice_orders invoice_orders_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.invoice_orders
+    ADD CONSTRAINT invoice_orders_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: invoices invoices_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.invoices
+    ADD CONSTRAINT invoices_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: locations locations_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.locations
+    ADD CONSTRAINT locations_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: maintenance_items maintenance_items_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.maintenance_items
+    ADD CONSTRAINT maintenance_items_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: notification_preferences notification_preferences_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.notification_preferences
+    ADD CONSTRAINT notification_preferences_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: orders orders_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.orders
+    ADD CONSTRAINT orders_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: rate_card_accessorials rate_card_accessorials_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.rate_card_accessorials
+    ADD CONSTRAINT rate_card_accessorials_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: rate_card_lanes rate_card_lanes_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.rate_card_lanes
+    ADD CONSTRAINT rate_card_lanes_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: rate_cards rate_cards_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.rate_cards
+    ADD CONSTRAINT rate_cards_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: shipment_charges shipment_charges_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.shipment_charges
+    ADD CONSTRAINT shipment_charges_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: shipment_events shipment_events_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.shipment_events
+    ADD CONSTRAINT shipment_events_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: shipment_status_suggestions shipment_status_suggestions_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.shipment_status_suggestions
+    ADD CONSTRAINT shipment_status_suggestions_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: shipments shipments_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.shipments
+    ADD CONSTRAINT shipments_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: tenants tenants_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.tenants
+    ADD CONSTRAINT tenants_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: terminal_holidays terminal_holidays_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.terminal_holidays
+    ADD CONSTRAINT terminal_holidays_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: terminal_hours terminal_hours_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.terminal_hours
+    ADD CONSTRAINT terminal_hours_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: terminal_slots terminal_slots_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.terminal_slots
+    ADD CONSTRAINT terminal_slots_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: terminals terminals_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.terminals
+    ADD CONSTRAINT terminals_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: time_off_requests time_off_requests_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.time_off_requests
+    ADD CONSTRAINT time_off_requests_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: users users_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.users
+    ADD CONSTRAINT users_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: delivery_proof_files_delivery_proof_id_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX delivery_proof_files_delivery_proof_id_idx ON public.delivery_proof_files USING btree (delivery_proof_id);
+
+
+--
+-- Name: delivery_proofs_shipment_id_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE UNIQUE INDEX delivery_proofs_shipment_id_idx ON public.delivery_proofs USING btree (shipment_id);
+
+
+--
+-- Name: documents_order_id_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX documents_order_id_idx ON public.documents USING btree (order_id);
+
+
+--
+-- Name: documents_shipment_id_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX documents_shipment_id_idx ON public.documents USING btree (shipment_id);
+
+
+--
+-- Name: driver_compliances_driver_id_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE UNIQUE INDEX driver_compliances_driver_id_idx ON public.driver_compliances USING btree (driver_id);
+
+
+--
+-- Name: driver_locations_driver_id_recorded_at_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX driver_locations_driver_id_recorded_at_idx ON public.driver_locations USING btree (driver_id, recorded_at);
+
+
+--
+-- Name: driver_locations_shipment_id_recorded_at_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX driver_locations_shipment_id_recorded_at_idx ON public.driver_locations USING btree (shipment_id, recorded_at);
+
+
+--
+-- Name: driver_locations_tenant_id_recorded_at_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX driver_locations_tenant_id_recorded_at_idx ON public.driver_locations USING btree (tenant_id, recorded_at);
+
+
+--
+-- Name: driver_pay_rules_tenant_id_driver_id_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX driver_pay_rules_tenant_id_driver_id_idx ON public.driver_pay_rules USING btree (tenant_id, driver_id);
+
+
+--
+-- Name: driver_settlement_lines_shipment_id_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX driver_settlement_lines_shipment_id_idx ON public.driver_settlement_lines USING btree (shipment_id);
+
+
+--
+-- Name: driver_settlements_driver_id_period_start_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX driver_settlements_driver_id_period_start_idx ON public.driver_settlements USING btree (driver_id, period_start);
+
+
+--
+-- Name: driver_shifts_driver_id_weekday_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX driver_shifts_driver_id_weekday_idx ON public.driver_shifts USING btree (driver_id, weekday);
+
+
+--
+-- Name: equipment_tenant_id_type_unit_number_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE UNIQUE INDEX equipment_tenant_id_type_unit_number_idx ON public.equipment USING btree (tenant_id, type, unit_number);
+
+
+--
+-- Name: invoice_orders_invoice_id_order_id_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE UNIQUE INDEX invoice_orders_invoice_id_order_id_idx ON public.invoice_orders USING btree (invoice_id, order_id);
+
+
+--
+-- Name: invoice_orders_order_id_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX invoice_orders_order_id_idx ON public.invoice_orders USING btree (order_id);
+
+
+--
+-- Name: invoices_tenant_id_number_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE UNIQUE INDEX invoices_tenant_id_number_idx ON public.invoices USING btree (tenant_id, number);
+
+
+--
+-- Name: locations_tenant_id_name_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX locations_tenant_id_name_idx ON public.locations USING btree (tenant_id, name);
+
+
+--
+-- Name: maintenance_items_equipment_id_due_date_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX maintenance_items_equipment_id_due_date_idx ON public.maintenance_items USING btree (equipment_id, due_date);
+
+
+--
+-- Name: notification_preferences_user_id_event_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE UNIQUE INDEX notification_preferences_user_id_event_idx ON public.notification_preferences USING btree (user_id, event);
+
+
+--
+-- Name: orders_tenant_id_serial_number_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX orders_tenant_id_serial_number_idx ON public.orders USING btree (tenant_id, serial_number);
+
+
+--
+-- Name: rate_cards_customer_id_effective_from_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX rate_cards_customer_id_effective_from_idx ON public.rate_cards USING btree (customer_id, effective_from);
+
+
+--
+-- Name: schema_migration_version_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE UNIQUE INDEX schema_migration_version_idx ON public.schema_migration USING btree (version);
+
+
+--
+-- Name: shipment_charges_shipment_id_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX shipment_charges_shipment_id_idx ON public.shipment_charges USING btree (shipment_id);
+
+
+--
+-- Name: shipment_events_shipment_id_created_at_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX shipment_events_shipment_id_created_at_idx ON public.shipment_events USING btree (shipment_id, created_at);
+
+
+--
+-- Name: shipment_status_suggestions_driver_id_status_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX shipment_status_suggestions_driver_id_status_idx ON public.shipment_status_suggestions USING btree (driver_id, status);
+
+
+--
+-- Name: shipment_status_suggestions_shipment_id_status_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX shipment_status_suggestions_shipment_id_status_idx ON public.shipment_status_suggestions USING btree (shipment_id, status);
+
+
+--
+-- Name: shipments_chassis_id_status_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX shipments_chassis_id_status_idx ON public.shipments USING btree (chassis_id, status);
+
+
+--
+-- Name: shipments_tenant_id_serial_number_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX shipments_tenant_id_serial_number_idx ON public.shipments USING btree (tenant_id, serial_number);
+
+
+--
+-- Name: shipments_terminal_slot_id_reservation_time_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX shipments_terminal_slot_id_reservation_time_idx ON public.shipments USING btree (terminal_slot_id, reservation_time);
+
+
+--
+-- Name: terminal_holidays_terminal_id_date_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX terminal_holidays_terminal_id_date_idx ON public.terminal_holidays USING btree (terminal_id, date);
+
+
+--
+-- Name: terminal_hours_terminal_id_weekday_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX terminal_hours_terminal_id_weekday_idx ON public.terminal_hours USING btree (terminal_id, weekday);
+
+
+--
+-- Name: terminal_slots_terminal_id_weekday_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX terminal_slots_terminal_id_weekday_idx ON public.terminal_slots USING btree (terminal_id, weekday);
+
+
+--
+-- Name: time_off_requests_driver_id_starts_at_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX time_off_requests_driver_id_starts_at_idx ON public.time_off_requests USING btree (driver_id, starts_at);
+
+
+--
+-- Name: time_off_requests_tenant_id_status_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX time_off_requests_tenant_id_status_idx ON public.time_off_requests USING btree (tenant_id, status);
+
+
+--
+-- Name: users_tenant_id_email_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE UNIQUE INDEX users_tenant_id_email_idx ON public.users USING btree (tenant_id, email);
+
+
+--
+-- Name: users_tenant_id_username_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE UNIQUE INDEX users_tenant_id_username_idx ON public.users USING btree (tenant_id, username);
+
+
+--
+-- Name: carriers fk_carriers_created_by; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.carriers
+    ADD CONSTRAINT fk_carriers_created_by FOREIGN KEY (created_by) REFERENCES public.users(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
+
+
+--
+-- Name: carriers fk_carriers_tenant_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.carriers
+    ADD CONSTRAINT fk_carriers_tenant_id FOREIGN KEY (tenant_id) REFERENCES public.tenants(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
+
+
+--
+-- Name: customers fk_customers_created_by; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.customers
+    ADD CONSTRAINT fk_customers_created_by FOREIGN KEY (created_by) REFERENCES public.users(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
+
+
+--
+-- Name: customers fk_customers_tenant_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.customers
+    ADD CONSTRAINT fk_customers_tenant_id FOREIGN KEY (tenant_id) REFERENCES public.tenants(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
+
+
+--
+-- Name: delivery_proof_files fk_delivery_proof_files_delivery_proof_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.delivery_proof_files
+    ADD CONSTRAINT fk_delivery_proof_files_delivery_proof_id FOREIGN KEY (delivery_proof_id) REFERENCES public.delivery_proofs(id) ON UPDATE RESTRICT ON DELETE CASCADE;
+
+
+--
+-- Name: delivery_proof_files fk_delivery_proof_files_tenant_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.delivery_proof_files
+    ADD CONSTRAINT fk_delivery_proof_files_tenant_id FOREIGN KEY (tenant_id) REFERENCES public.tenants(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
+
+
+--
+-- Name: delivery_proofs fk_delivery_proofs_created_by; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.delivery_proofs
+    ADD CONSTRAINT fk_delivery_proofs_created_by FOREIGN KEY (created_by) REFERENCES public.users(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
+
+
+--
+-- Name: delivery_proofs fk_delivery_proofs_shipment_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.delivery_proofs
+    ADD CONSTRAINT fk_delivery_proofs_shipment_id FOREIGN KEY (shipment_id) REFERENCES public.shipments(id) ON UPDATE RESTRICT ON DELETE CASCADE;
+
+
+--
+-- Name: delivery_proofs fk_delivery_proofs_tenant_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.delivery_proofs
+    ADD CONSTRAINT fk_delivery_proofs_tenant_id FOREIGN KEY (tenant_id) REFERENCES public.tenants(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
+
+
+--
+-- Name: documents fk_documents_created_by; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.documents
+    ADD CONSTRAINT fk_documents_created_by FOREIGN KEY (created_by) REFERENCES public.users(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
+
+
+--
+-- Name: documents fk_documents_order_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.documents
+    ADD CONSTRAINT fk_documents_order_id FOREIGN KEY (order_id) REFERENCES public.orders(id) ON UPDATE RESTRICT ON DELETE CASCADE;
+
+
+--
+-- Name: documents fk_documents_shipment_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.documents
+    ADD CONSTRAINT fk_documents_shipment_id FOREIGN KEY (shipment_id) REFERENCES public.shipments(id) ON UPDATE RESTRICT ON DELETE CASCADE;
+
+
+--
+-- Name: documents fk_documents_tenant_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.documents
+    ADD CONSTRAINT fk_documents_tenant_id FOREIGN KEY (tenant_id) REFERENCES public.tenants(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
+
+
+--
+-- Name: driver_compliances fk_driver_compliances_created_by; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.driver_compliances
+    ADD CONSTRAINT fk_driver_compliances_created_by FOREIGN KEY (created_by) REFERENCES public.users(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
+
+
+--
+-- Name: driver_compliances fk_driver_compliances_driver_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.driver_compliances
+    ADD CONSTRAINT fk_driver_compliances_driver_id FOREIGN KEY (driver_id) REFERENCES public.users(id) ON UPDATE RESTRICT ON DELETE CASCADE;
+
+
+--
+-- Name: driver_compliances fk_driver_compliances_tenant_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.driver_compliances
+    ADD CONSTRAINT fk_driver_compliances_tenant_id FOREIGN KEY (tenant_id) REFERENCES public.tenants(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
+
+
+--
+-- Name: driver_locations fk_driver_locations_driver_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.driver_locations
+    ADD CONSTRAINT fk_driver_locations_driver_id FOREIGN KEY (driver_id) REFERENCES public.users(id) ON UPDATE RESTRICT ON DELETE CASCADE;
+
+
+--
+-- Name: driver_locations fk_driver_locations_shipment_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.driver_locations
+    ADD CONSTRAINT fk_driver_locations_shipment_id FOREIGN KEY (shipment_id) REFERENCES public.shipments(id) ON UPDATE RESTRICT ON DELETE CASCADE;
+
+
+--
+-- Name: driver_locations fk_driver_locations_tenant_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.driver_locations
+    ADD CONSTRAINT fk_driver_locations_tenant_id FOREIGN KEY (tenant_id) REFERENCES public.tenants(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
+
+
+--
+-- Name: driver_pay_rules fk_driver_pay_rules_created_by; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.driver_pay_rules
+    ADD CONSTRAINT fk_driver_pay_rules_created_by FOREIGN KEY (created_by) REFERENCES public.users(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
+
+
+--
+-- Name: driver_pay_rules fk_driver_pay_rules_driver_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.driver_pay_rules
+    ADD CONSTRAINT fk_driver_pay_rules_driver_id FOREIGN KEY (driver_id) REFERENCES public.users(id) ON UPDATE RESTRICT ON DELETE CASCADE;
+
+
+--
+-- Name: driver_pay_rules fk_driver_pay_rules_tenant_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.driver_pay_rules
+    ADD CONSTRAINT fk_driver_pay_rules_tenant_id FOREIGN KEY (tenant_id) REFERENCES public.tenants(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
+
+
+--
+-- Name: driver_settlement_lines fk_driver_settlement_lines_driver_settlement_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.driver_settlement_lines
+    ADD CONSTRAINT fk_driver_settlement_lines_driver_settlement_id FOREIGN KEY (driver_settlement_id) REFERENCES public.driver_settlements(id) ON UPDATE RESTRICT ON DELETE CASCADE;
+
+
+--
+-- Name: driver_settlement_lines fk_driver_settlement_lines_shipment_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.driver_settlement_lines
+    ADD CONSTRAINT fk_driver_settlement_lines_shipment_id FOREIGN KEY (shipment_id) REFERENCES public.shipments(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
+
+
+--
+-- Name: driver_settlements fk_driver_settlements_created_by; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.driver_settlements
+    ADD CONSTRAINT fk_driver_settlements_created_by FOREIGN KEY (created_by) REFERENCES public.users(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
+
+
+--
+-- Name: driver_settlements fk_driver_settlements_driver_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.driver_settlements
+    ADD CONSTRAINT fk_driver_settlements_driver_id FOREIGN KEY (driver_id) REFERENCES public.users(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
+
+
+--
+-- Name: driver_settlements fk_driver_settlements_finalized_by; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.driver_settlements
+    ADD CONSTRAINT fk_driver_settlements_finalized_by FOREIGN KEY (finalized_by) REFERENCES public.users(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
+
+
+--
+-- Name: driver_settlements fk_driver_settlements_tenant_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.driver_settlements
+    ADD CONSTRAINT fk_driver_settlements_tenant_id FOREIGN KEY (tenant_id) REFERENCES public.tenants(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
+
+
+--
+-- Name: driver_shifts fk_driver_shifts_created_by; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.driver_shifts
+    ADD CONSTRAINT fk_driver_shifts_created_by FOREIGN KEY (created_by) REFERENCES public.users(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
+
+
+--
+-- Name: driver_shifts fk_driver_shifts_driver_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.driver_shifts
+    ADD CONSTRAINT fk_driver_shifts_driver_id FOREIGN KEY (driver_id) REFERENCES public.users(id) ON UPDATE RESTRICT ON DELETE CASCADE;
+
+
+--
+-- Name: driver_shifts fk_driver_shifts_tenant_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.driver_shifts
+    ADD CONSTRAINT fk_driver_shifts_tenant_id FOREIGN KEY (tenant_id) REFERENCES public.tenants(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
+
+
+--
+-- Name: equipment fk_equipment_created_by; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.equipment
+    ADD CONSTRAINT fk_equipment_created_by FOREIGN KEY (created_by) REFERENCES public.users(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
+
+
+--
+-- Name: equipment fk_equipment_tenant_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.equipment
+    ADD CONSTRAINT fk_equipment_tenant_id FOREIGN KEY (tenant_id) REFERENCES public.tenants(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
+
+
+--
+-- Name: invoice_lines fk_invoice_lines_invoice_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.invoice_lines
+    ADD CONSTRAINT fk_invoice_lines_invoice_id FOREIGN KEY (invoice_id) REFERENCES public.invoices(id) ON UPDATE RESTRICT ON DELETE CASCADE;
+
+
+--
+-- Name: invoice_lines fk_invoice_lines_order_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.invoice_lines
+    ADD CONSTRAINT fk_invoice_lines_order_id FOREIGN KEY (order_id) REFERENCES public.orders(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
+
+
+--
+-- Name: invoice_lines fk_invoice_lines_tenant_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.invoice_lines
+    ADD CONSTRAINT fk_invoice_lines_tenant_id FOREIGN KEY (tenant_id) REFERENCES public.tenants(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
+
+
+--
+-- Name: invoice_orders fk_invoice_orders_invoice_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.invoice_orders
+    ADD CONSTRAINT fk_invoice_orders_invoice_id FOREIGN KEY (invoice_id) REFERENCES public.invoices(id) ON UPDATE RESTRICT ON DELETE CASCADE;
+
+
+--
+-- Name: invoice_orders fk_invoice_orders_order_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.invoice_orders
+    ADD CONSTRAINT fk_invoice_orders_order_id FOREIGN KEY (order_id) REFERENCES public.orders(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
+
+
+--
+-- Name: invoice_orders fk_invoice_orders_tenant_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.invoice_orders
+    ADD CONSTRAINT fk_invoice_orders_tenant_id FOREIGN KEY (tenant_id) REFERENCES public.tenants(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
 
 
-ALTER TABLE public.orders OWNER TO postgres;
+--
+-- Name: invoices fk_invoices_created_by; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.invoices
+    ADD CONSTRAINT fk_invoices_created_by FOREIGN KEY (created_by) REFERENCES public.users(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
+
 
 --
--- Name: schema_migration; Type: TABLE; Schema: public; Owner: postgres
+-- Name: invoices fk_invoices_customer_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
 --
 
-CREATE TABLE public.schema_migration (
-    version character varying(14) NOT NULL
-);
+ALTER TABLE ONLY public.invoices
+    ADD CONSTRAINT fk_invoices_customer_id FOREIGN KEY (customer_id) REFERENCES public.customers(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
 
 
-ALTER TABLE public.schema_migration OWNER TO postgres;
+--
+-- Name: invoices fk_invoices_tenant_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.invoices
+    ADD CONSTRAINT fk_invoices_tenant_id FOREIGN KEY (tenant_id) REFERENCES public.tenants(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
+
 
 --
--- Name: shipments; Type: TABLE; Schema: public; Owner: postgres
+-- Name: locations fk_locations_created_by; Type: FK CONSTRAINT; Schema: public; Owner: postgres
 --
 
-CREATE TABLE public.shipments (
-    id uuid NOT NULL,
-    created_by uuid NOT NULL,
-    tenant_id uuid NOT NULL,
-    terminal_id uuid,
-    order_id uuid,
-    serial_number character varying(20),
-    origin character varying(20),
-    destination character varying(20),
-    lfd timestamp without time zone,
-    reservation_time timestamp without time zone,
-    size character varying(15),
-    type character varying(15),
-    status character varying(15) NOT NULL,
-    driver_id uuid,
-    created_at timestamp without time zone NOT NULL,
-    updated_at timestamp without time zone NOT NULL,
-    carrier_id uuid,
-    customer_id uuid
-);
+ALTER TABLE ONLY public.locations
+    ADD CONSTRAINT fk_locations_created_by FOREIGN KEY (created_by) REFERENCES public.users(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
 
 
-ALTER TABLE public.shipments OWNER TO postgres;
+--
+-- Name: locations fk_locations_customer_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.locations
+    ADD CONSTRAINT fk_locations_customer_id FOREIGN KEY (customer_id) REFERENCES public.customers(id) ON UPDATE RESTRICT ON DELETE CASCADE;
+
 
 --
--- Name: tenants; Type: TABLE; Schema: public; Owner: postgres
+-- Name: locations fk_locations_tenant_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
 --
 
-CREATE TABLE public.tenants (
-    id uuid NOT NULL,
-    created_by uuid,
-    name character varying(50) NOT NULL,
-    type character varying(15) NOT NULL,
-    created_at timestamp without time zone NOT NULL,
-    updated_at timestamp without time zone NOT NULL,
-    code character varying(20) NOT NULL
-);
+ALTER TABLE ONLY public.locations
+    ADD CONSTRAINT fk_locations_tenant_id FOREIGN KEY (tenant_id) REFERENCES public.tenants(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
 
 
-ALTER TABLE public.tenants OWNER TO postgres;
+--
+-- Name: maintenance_items fk_maintenance_items_created_by; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.maintenance_items
+    ADD CONSTRAINT fk_maintenance_items_created_by FOREIGN KEY (created_by) REFERENCES public.users(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
+
 
 --
--- Name: terminals; Type: TABLE; Schema: public; Owner: postgres
+-- Name: maintenance_items fk_maintenance_items_equipment_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
 --
 
-CREATE TABLE public.terminals (
-    id uuid NOT NULL,
-    created_by uuid NOT NULL,
-    name character varying(50) NOT NULL,
-    type character varying(15) NOT NULL,
-    tenant_id uuid NOT NULL,
-    created_at timestamp without time zone NOT NULL,
-    updated_at timestamp without time zone NOT NULL
-);
+ALTER TABLE ONLY public.maintenance_items
+    ADD CONSTRAINT fk_maintenance_items_equipment_id FOREIGN KEY (equipment_id) REFERENCES public.equipment(id) ON UPDATE RESTRICT ON DELETE CASCADE;
 
 
-ALTER TABLE public.terminals OWNER TO postgres;
+--
+-- Name: maintenance_items fk_maintenance_items_tenant_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.maintenance_items
+    ADD CONSTRAINT fk_maintenance_items_tenant_id FOREIGN KEY (tenant_id) REFERENCES public.tenants(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
+
 
 --
--- Name: users; Type: TABLE; Schema: public; Owner: postgres
+-- Name: notification_preferences fk_notification_preferences_tenant_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
 --
 
-CREATE TABLE public.users (
-    id uuid NOT NULL,
-    created_by uuid,
-    name character varying(50) NOT NULL,
-    username character varying(50) NOT NULL,
-    role character varying(15) NOT NULL,
-    tenant_id uuid NOT NULL,
-    customer_id uuid,
-    created_at timestamp without time zone NOT NULL,
-    updated_at timestamp without time zone NOT NULL,
-    email character varying(50) NOT NULL,
-    device_id character varying(255)
-);
+ALTER TABLE ONLY public.notification_preferences
+    ADD CONSTRAINT fk_notification_preferences_tenant_id FOREIGN KEY (tenant_id) REFERENCES public.tenants(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
 
 
-ALTER TABLE public.users OWNER TO postgres;
+--
+-- Name: notification_preferences fk_notification_preferences_user_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.notification_preferences
+    ADD CONSTRAINT fk_notification_preferences_user_id FOREIGN KEY (user_id) REFERENCES public.users(id) ON UPDATE RESTRICT ON DELETE CASCADE;
+
 
 --
--- Name: carriers carriers_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+-- Name: orders fk_orders_created_by; Type: FK CONSTRAINT; Schema: public; Owner: postgres
 --
 
-ALTER TABLE ONLY public.carriers
-    ADD CONSTRAINT carriers_pkey PRIMARY KEY (id);
+ALTER TABLE ONLY public.orders
+    ADD CONSTRAINT fk_orders_created_by FOREIGN KEY (created_by) REFERENCES public.users(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
 
 
 --
--- Name: customers customers_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+-- Name: orders fk_orders_customer_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
 --
 
-ALTER TABLE ONLY public.customers
-    ADD CONSTRAINT customers_pkey PRIMARY KEY (id);
+ALTER TABLE ONLY public.orders
+    ADD CONSTRAINT fk_orders_customer_id FOREIGN KEY (customer_id) REFERENCES public.customers(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
 
 
 --
--- Name: orders orders_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+-- Name: orders fk_orders_tenant_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
 --
 
 ALTER TABLE ONLY public.orders
-    ADD CONSTRAINT orders_pkey PRIMARY KEY (id);
+    ADD CONSTRAINT fk_orders_tenant_id FOREIGN KEY (tenant_id) REFERENCES public.tenants(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
 
 
 --
--- Name: shipments shipments_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+-- Name: rate_card_accessorials fk_rate_card_accessorials_rate_card_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
 --
 
-ALTER TABLE ONLY public.shipments
-    ADD CONSTRAINT shipments_pkey PRIMARY KEY (id);
+ALTER TABLE ONLY public.rate_card_accessorials
+    ADD CONSTRAINT fk_rate_card_accessorials_rate_card_id FOREIGN KEY (rate_card_id) REFERENCES public.rate_cards(id) ON UPDATE RESTRICT ON DELETE CASCADE;
 
 
 --
--- Name: tenants tenants_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+-- Name: rate_card_lanes fk_rate_card_lanes_rate_card_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
 --
 
-ALTER TABLE ONLY public.tenants
-    ADD CONSTRAINT tenants_pkey PRIMARY KEY (id);
+ALTER TABLE ONLY public.rate_card_lanes
+    ADD CONSTRAINT fk_rate_card_lanes_rate_card_id FOREIGN KEY (rate_card_id) REFERENCES public.rate_cards(id) ON UPDATE RESTRICT ON DELETE CASCADE;
 
 
 --
--- Name: terminals terminals_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+-- Name: rate_card_lanes fk_rate_card_lanes_terminal_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
 --
 
-ALTER TABLE ONLY public.terminals
-    ADD CONSTRAINT terminals_pkey PRIMARY KEY (id);
+ALTER TABLE ONLY public.rate_card_lanes
+    ADD CONSTRAINT fk_rate_card_lanes_terminal_id FOREIGN KEY (terminal_id) REFERENCES public.terminals(id) ON UPDATE RESTRICT ON DELETE CASCADE;
 
 
 --
--- Name: users users_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+-- Name: rate_cards fk_rate_cards_created_by; Type: FK CONSTRAINT; Schema: public; Owner: postgres
 --
 
-ALTER TABLE ONLY public.users
-    ADD CONSTRAINT users_pkey PRIMARY KEY (id);
+ALTER TABLE ONLY public.rate_cards
+    ADD CONSTRAINT fk_rate_cards_created_by FOREIGN KEY (created_by) REFERENCES public.users(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
 
 
 --
--- Name: orders_tenant_id_serial_number_idx; Type: INDEX; Schema: public; Owner: postgres
+-- Name: rate_cards fk_rate_cards_customer_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
 --
 
-CREATE INDEX orders_tenant_id_serial_number_idx ON public.orders USING btree (tenant_id, serial_number);
+ALTER TABLE ONLY public.rate_cards
+    ADD CONSTRAINT fk_rate_cards_customer_id FOREIGN KEY (customer_id) REFERENCES public.customers(id) ON UPDATE RESTRICT ON DELETE CASCADE;
 
 
 --
--- Name: schema_migration_version_idx; Type: INDEX; Schema: public; Owner: postgres
+-- Name: rate_cards fk_rate_cards_tenant_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
 --
 
-CREATE UNIQUE INDEX schema_migration_version_idx ON public.schema_migration USING btree (version);
+ALTER TABLE ONLY public.rate_cards
+    ADD CONSTRAINT fk_rate_cards_tenant_id FOREIGN KEY (tenant_id) REFERENCES public.tenants(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
 
 
 --
--- Name: shipments_tenant_id_serial_number_idx; Type: INDEX; Schema: public; Owner: postgres
+-- Name: shipment_charges fk_shipment_charges_created_by; Type: FK CONSTRAINT; Schema: public; Owner: postgres
 --
 
-CREATE INDEX shipments_tenant_id_serial_number_idx ON public.shipments USING btree (tenant_id, serial_number);
+ALTER TABLE ONLY public.shipment_charges
+    ADD CONSTRAINT fk_shipment_charges_created_by FOREIGN KEY (created_by) REFERENCES public.users(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
 
 
 --
--- Name: users_tenant_id_email_idx; Type: INDEX; Schema: public; Owner: postgres
+-- Name: shipment_charges fk_shipment_charges_shipment_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
 --
 
-CREATE UNIQUE INDEX users_tenant_id_email_idx ON public.users USING btree (tenant_id, email);
+ALTER TABLE ONLY public.shipment_charges
+    ADD CONSTRAINT fk_shipment_charges_shipment_id FOREIGN KEY (shipment_id) REFERENCES public.shipments(id) ON UPDATE RESTRICT ON DELETE CASCADE;
 
 
 --
--- Name: users_tenant_id_username_idx; Type: INDEX; Schema: public; Owner: postgres
+-- Name: shipment_charges fk_shipment_charges_tenant_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
 --
 
-CREATE UNIQUE INDEX users_tenant_id_username_idx ON public.users USING btree (tenant_id, username);
+ALTER TABLE ONLY public.shipment_charges
+    ADD CONSTRAINT fk_shipment_charges_tenant_id FOREIGN KEY (tenant_id) REFERENCES public.tenants(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
 
 
 --
--- Name: carriers fk_carriers_created_by; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+-- Name: shipment_events fk_shipment_events_created_by; Type: FK CONSTRAINT; Schema: public; Owner: postgres
 --
 
-ALTER TABLE ONLY public.carriers
-    ADD CONSTRAINT fk_carriers_created_by FOREIGN KEY (created_by) REFERENCES public.users(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
+ALTER TABLE ONLY public.shipment_events
+    ADD CONSTRAINT fk_shipment_events_created_by FOREIGN KEY (created_by) REFERENCES public.users(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
 
 
 --
--- Name: carriers fk_carriers_tenant_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+-- Name: shipment_events fk_shipment_events_driver_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
 --
 
-ALTER TABLE ONLY public.carriers
-    ADD CONSTRAINT fk_carriers_tenant_id FOREIGN KEY (tenant_id) REFERENCES public.tenants(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
+ALTER TABLE ONLY public.shipment_events
+    ADD CONSTRAINT fk_shipment_events_driver_id FOREIGN KEY (driver_id) REFERENCES public.users(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
 
 
 --
--- Name: customers fk_customers_created_by; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+-- Name: shipment_events fk_shipment_events_shipment_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
 --
 
-ALTER TABLE ONLY public.customers
-    ADD CONSTRAINT fk_customers_created_by FOREIGN KEY (created_by) REFERENCES public.users(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
+ALTER TABLE ONLY public.shipment_events
+    ADD CONSTRAINT fk_shipment_events_shipment_id FOREIGN KEY (shipment_id) REFERENCES public.shipments(id) ON UPDATE RESTRICT ON DELETE CASCADE;
 
 
 --
--- Name: customers fk_customers_tenant_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+-- Name: shipment_events fk_shipment_events_tenant_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
 --
 
-ALTER TABLE ONLY public.customers
-    ADD CONSTRAINT fk_customers_tenant_id FOREIGN KEY (tenant_id) REFERENCES public.tenants(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
+ALTER TABLE ONLY public.shipment_events
+    ADD CONSTRAINT fk_shipment_events_tenant_id FOREIGN KEY (tenant_id) REFERENCES public.tenants(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
 
 
 --
--- Name: orders fk_orders_created_by; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+-- Name: shipment_status_suggestions fk_shipment_status_suggestions_driver_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
 --
 
-ALTER TABLE ONLY public.orders
-    ADD CONSTRAINT fk_orders_created_by FOREIGN KEY (created_by) REFERENCES public.users(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
+ALTER TABLE ONLY public.shipment_status_suggestions
+    ADD CONSTRAINT fk_shipment_status_suggestions_driver_id FOREIGN KEY (driver_id) REFERENCES public.users(id) ON UPDATE RESTRICT ON DELETE CASCADE;
 
 
 --
--- Name: orders fk_orders_customer_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+-- Name: shipment_status_suggestions fk_shipment_status_suggestions_reviewed_by; Type: FK CONSTRAINT; Schema: public; Owner: postgres
 --
 
-ALTER TABLE ONLY public.orders
-    ADD CONSTRAINT fk_orders_customer_id FOREIGN KEY (customer_id) REFERENCES public.customers(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
+ALTER TABLE ONLY public.shipment_status_suggestions
+    ADD CONSTRAINT fk_shipment_status_suggestions_reviewed_by FOREIGN KEY (reviewed_by) REFERENCES public.users(id) ON UPDATE RESTRICT ON DELETE SET NULL;
 
 
 --
--- Name: orders fk_orders_tenant_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+-- Name: shipment_status_suggestions fk_shipment_status_suggestions_shipment_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
 --
 
-ALTER TABLE ONLY public.orders
-    ADD CONSTRAINT fk_orders_tenant_id FOREIGN KEY (tenant_id) REFERENCES public.tenants(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
+ALTER TABLE ONLY public.shipment_status_suggestions
+    ADD CONSTRAINT fk_shipment_status_suggestions_shipment_id FOREIGN KEY (shipment_id) REFERENCES public.shipments(id) ON UPDATE RESTRICT ON DELETE CASCADE;
+
+
+--
+-- Name: shipment_status_suggestions fk_shipment_status_suggestions_tenant_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.shipment_status_suggestions
+    ADD CONSTRAINT fk_shipment_status_suggestions_tenant_id FOREIGN KEY (tenant_id) REFERENCES public.tenants(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
+
+
+--
+-- Name: shipment_status_suggestions fk_shipment_status_suggestions_terminal_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.shipment_status_suggestions
+    ADD CONSTRAINT fk_shipment_status_suggestions_terminal_id FOREIGN KEY (terminal_id) REFERENCES public.terminals(id) ON UPDATE RESTRICT ON DELETE SET NULL;
 
 
 --
@@ -336,6 +1832,14 @@ ALTER TABLE ONLY public.shipments
     ADD CONSTRAINT fk_shipments_carrier_id FOREIGN KEY (carrier_id) REFERENCES public.carriers(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
 
 
+--
+-- Name: shipments fk_shipments_chassis_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.shipments
+    ADD CONSTRAINT fk_shipments_chassis_id FOREIGN KEY (chassis_id) REFERENCES public.equipment(id) ON UPDATE RESTRICT ON DELETE SET NULL;
+
+
 --
 -- Name: shipments fk_shipments_created_by; Type: FK CONSTRAINT; Schema: public; Owner: postgres
 --
@@ -344,6 +1848,14 @@ ALTER TABLE ONLY public.shipments
     ADD CONSTRAINT fk_shipments_created_by FOREIGN KEY (created_by) REFERENCES public.users(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
 
 
+--
+-- Name: shipments fk_shipments_destination_location_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.shipments
+    ADD CONSTRAINT fk_shipments_destination_location_id FOREIGN KEY (destination_location_id) REFERENCES public.locations(id) ON UPDATE RESTRICT ON DELETE SET NULL;
+
+
 --
 -- Name: shipments fk_shipments_driver_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
 --
@@ -360,6 +1872,14 @@ ALTER TABLE ONLY public.shipments
     ADD CONSTRAINT fk_shipments_order_id FOREIGN KEY (order_id) REFERENCES public.orders(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
 
 
+--
+-- Name: shipments fk_shipments_origin_location_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.shipments
+    ADD CONSTRAINT fk_shipments_origin_location_id FOREIGN KEY (origin_location_id) REFERENCES public.locations(id) ON UPDATE RESTRICT ON DELETE SET NULL;
+
+
 --
 -- Name: shipments fk_shipments_tenant_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
 --
@@ -376,6 +1896,22 @@ ALTER TABLE ONLY public.shipments
     ADD CONSTRAINT fk_shipments_terminal_id FOREIGN KEY (terminal_id) REFERENCES public.terminals(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
 
 
+--
+-- Name: shipments fk_shipments_terminal_slot_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.shipments
+    ADD CONSTRAINT fk_shipments_terminal_slot_id FOREIGN KEY (terminal_slot_id) REFERENCES public.terminal_slots(id) ON UPDATE RESTRICT ON DELETE SET NULL;
+
+
+--
+-- Name: shipments fk_shipments_tractor_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.shipments
+    ADD CONSTRAINT fk_shipments_tractor_id FOREIGN KEY (tractor_id) REFERENCES public.equipment(id) ON UPDATE RESTRICT ON DELETE SET NULL;
+
+
 --
 -- Name: tenants fk_tenants_created_by; Type: FK CONSTRAINT; Schema: public; Owner: postgres
 --
@@ -384,6 +1920,46 @@ ALTER TABLE ONLY public.tenants
     ADD CONSTRAINT fk_tenants_created_by FOREIGN KEY (created_by) REFERENCES public.users(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
 
 
+--
+-- Name: terminal_holidays fk_terminal_holidays_terminal_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.terminal_holidays
+    ADD CONSTRAINT fk_terminal_holidays_terminal_id FOREIGN KEY (terminal_id) REFERENCES public.terminals(id) ON UPDATE RESTRICT ON DELETE CASCADE;
+
+
+--
+-- Name: terminal_hours fk_terminal_hours_terminal_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.terminal_hours
+    ADD CONSTRAINT fk_terminal_hours_terminal_id FOREIGN KEY (terminal_id) REFERENCES public.terminals(id) ON UPDATE RESTRICT ON DELETE CASCADE;
+
+
+--
+-- Name: terminal_slots fk_terminal_slots_created_by; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.terminal_slots
+    ADD CONSTRAINT fk_terminal_slots_created_by FOREIGN KEY (created_by) REFERENCES public.users(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
+
+
+--
+-- Name: terminal_slots fk_terminal_slots_tenant_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.terminal_slots
+    ADD CONSTRAINT fk_terminal_slots_tenant_id FOREIGN KEY (tenant_id) REFERENCES public.tenants(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
+
+
+--
+-- Name: terminal_slots fk_terminal_slots_terminal_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.terminal_slots
+    ADD CONSTRAINT fk_terminal_slots_terminal_id FOREIGN KEY (terminal_id) REFERENCES public.terminals(id) ON UPDATE RESTRICT ON DELETE CASCADE;
+
+
 --
 -- Name: terminals fk_terminals_created_by; Type: FK CONSTRAINT; Schema: public; Owner: postgres
 --
@@ -400,6 +1976,38 @@ ALTER TABLE ONLY public.terminals
     ADD CONSTRAINT fk_terminals_tenant_id FOREIGN KEY (tenant_id) REFERENCES public.tenants(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
 
 
+--
+-- Name: time_off_requests fk_time_off_requests_created_by; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.time_off_requests
+    ADD CONSTRAINT fk_time_off_requests_created_by FOREIGN KEY (created_by) REFERENCES public.users(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
+
+
+--
+-- Name: time_off_requests fk_time_off_requests_driver_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.time_off_requests
+    ADD CONSTRAINT fk_time_off_requests_driver_id FOREIGN KEY (driver_id) REFERENCES public.users(id) ON UPDATE RESTRICT ON DELETE CASCADE;
+
+
+--
+-- Name: time_off_requests fk_time_off_requests_reviewed_by; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.time_off_requests
+    ADD CONSTRAINT fk_time_off_requests_reviewed_by FOREIGN KEY (reviewed_by) REFERENCES public.users(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
+
+
+--
+-- Name: time_off_requests fk_time_off_requests_tenant_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.time_off_requests
+    ADD CONSTRAINT fk_time_off_requests_tenant_id FOREIGN KEY (tenant_id) REFERENCES public.tenants(id) ON UPDATE RESTRICT ON DELETE RESTRICT;
+
+
 --
 -- Name: users fk_users_created_by; Type: FK CONSTRAINT; Schema: public; Owner: postgres
 --
@@ -0,0 +1,117 @@
+package models
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gobuffalo/validate/v3"
+	"github.com/gobuffalo/validate/v3/validators"
+	"github.com/gofrs/uuid"
+)
+
+// MaxDeliveryProofFileSize is the largest signature or photo of a proof of delivery, in bytes
+const MaxDeliveryProofFileSize = 5 << 20
+
+// MaxDeliveryProofPhotos is how many photos a proof of delivery can have
+const MaxDeliveryProofPhotos = 10
+
+// deliveryProofContentTypes are the images a proof of delivery accepts
+var deliveryProofContentTypes = []string{"image/jpeg", "image/png"}
+
+// DeliveryProof is used by pop to map your delivery_proofs database table to your go code.
+// It is what the driver captures when a shipment is delivered: who received it, their signature, photos and the seal
+// of the container, when and where. The images are kept in a blob store, Files describe them.
+type DeliveryProof struct {
+	ID           uuid.UUID          `json:"id" db:"id"`
+	CreatedAt    time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time          `json:"updated_at" db:"updated_at"`
+	CreatedBy    uuid.UUID          `json:"created_by" db:"created_by"`
+	TenantID     uuid.UUID          `json:"tenant_id" db:"tenant_id"`
+	ShipmentID   uuid.UUID          `json:"shipment_id" db:"shipment_id"`
+	ReceiverName string             `json:"receiver_name" db:"receiver_name"`
+	SealNumber   nulls.String       `json:"seal_number" db:"seal_number"`
+	DeliveredAt  time.Time          `json:"delivered_at" db:"delivered_at"`
+	Latitude     nulls.Float64      `json:"latitude" db:"latitude"`
+	Longitude    nulls.Float64      `json:"longitude" db:"longitude"`
+	Tenant       *Tenant            `belongs_to:"tenant" json:"-"`
+	Shipment     *Shipment          `belongs_to:"shipment" json:"-"`
+	Files        DeliveryProofFiles `has_many:"delivery_proof_files" order_by:"created_at asc" json:"files,omitempty"`
+}
+
+// DeliveryProofs is not required by pop and may be deleted
+type DeliveryProofs []DeliveryProof
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (p *DeliveryProof) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.UUIDIsPresent{Field: p.ShipmentID, Name: "ShipmentID"},
+		&validators.StringIsPresent{Field: p.ReceiverName, Name: "ReceiverName"},
+		&validators.TimeIsPresent{Field: p.DeliveredAt, Name: "DeliveredAt"},
+		&validators.FuncValidator{Fn: func() bool {
+			return p.DeliveredAt.Before(time.Now().Add(maxLocationClockSkew))
+		}, Field: p.DeliveredAt.String(), Name: "DeliveredAt"},
+		&validators.FuncValidator{Fn: func() bool {
+			// Value can be null
+			return validCoordinates(p.Latitude, p.Longitude)
+		}, Field: fmt.Sprintf("%v,%v", p.Latitude.Float64, p.Longitude.Float64), Name: "Latitude"},
+	), nil
+}
+
+// DeliveryProofFile is used by pop to map your delivery_proof_files database table to your go code.
+// It is a signature or a photo of a proof of delivery, BlobKey is where the blob store keeps it.
+type DeliveryProofFile struct {
+	ID              uuid.UUID `json:"id" db:"id"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+	TenantID        uuid.UUID `json:"tenant_id" db:"tenant_id"`
+	DeliveryProofID uuid.UUID `json:"delivery_proof_id" db:"delivery_proof_id"`
+	Kind            string    `json:"kind" db:"kind"`
+	ContentType     string    `json:"content_type" db:"content_type"`
+	Size            int       `json:"size" db:"size"`
+	BlobKey         string    `json:"-" db:"blob_key"`
+}
+
+// DeliveryProofFiles is not required by pop and may be deleted
+type DeliveryProofFiles []DeliveryProofFile
+
+// NewDeliveryProofFile returns the file of an image of a proof of delivery and the key it is kept with.
+// The content type is sniffed from the image.
+func NewDeliveryProofFile(p *DeliveryProof, kind DeliveryProofFileKind, data []byte) *DeliveryProofFile {
+	var id = uuid.Must(uuid.NewV4())
+	return &DeliveryProofFile{
+		ID:              id,
+		TenantID:        p.TenantID,
+		DeliveryProofID: p.ID,
+		Kind:            kind.String(),
+		ContentType:     http.DetectContentType(data),
+		Size:            len(data),
+		BlobKey:         fmt.Sprintf("%s/shipments/%s/pod/%s", p.TenantID, p.ShipmentID, id),
+	}
+}
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (f *DeliveryProofFile) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.UUIDIsPresent{Field: f.DeliveryProofID, Name: "DeliveryProofID"},
+		&validators.StringIsPresent{Field: f.BlobKey, Name: "BlobKey"},
+		&validators.FuncValidator{Fn: func() bool {
+			return IsValidDeliveryProofFileKind(f.Kind)
+		}, Field: f.Kind, Name: "Kind"},
+		&validators.FuncValidator{Fn: func() bool {
+			for _, t := range deliveryProofContentTypes {
+				if f.ContentType == t {
+					return true
+				}
+			}
+			return false
+		}, Field: f.ContentType, Name: "ContentType"},
+		&validators.FuncValidator{Fn: func() bool {
+			return f.Size > 0 && f.Size <= MaxDeliveryProofFileSize
+		}, Field: fmt.Sprint(f.Size), Name: "Size"},
+	), nil
+}
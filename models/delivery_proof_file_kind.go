@@ -0,0 +1,29 @@
+package models
+
+// AUTOGENERATED BY: HSM GEN
+
+// DeliveryProofFileKind represents the DeliveryProofFileKind enum
+type DeliveryProofFileKind string
+
+const (
+	// DeliveryProofFileKindSignature represents Signature DeliveryProofFileKind
+	DeliveryProofFileKindSignature DeliveryProofFileKind = "Signature"
+	// DeliveryProofFileKindPhoto represents Photo DeliveryProofFileKind
+	DeliveryProofFileKindPhoto DeliveryProofFileKind = "Photo"
+)
+
+var allowedDeliveryProofFileKind [2]DeliveryProofFileKind = [2]DeliveryProofFileKind{
+	DeliveryProofFileKindSignature,
+	DeliveryProofFileKindPhoto,
+}
+
+// String returns the string representation of
+func (k DeliveryProofFileKind) String() string {
+	return string(k)
+}
+
+// IsValidDeliveryProofFileKind validates if the input is a DeliveryProofFileKind
+func IsValidDeliveryProofFileKind(s string) bool {
+	t := DeliveryProofFileKind(s)
+	return DeliveryProofFileKindSignature == t || DeliveryProofFileKindPhoto == t
+}
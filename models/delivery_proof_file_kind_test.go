@@ -0,0 +1,20 @@
+package models_test
+
+// AUTOGENERATED BY: HSM GEN
+
+import (
+	"testing"
+
+	m "github.com/bigpanther/trober/models"
+)
+
+func TestIsValidDeliveryProofFileKind(t *testing.T) {
+	var validVal = "Signature"
+	var inValidVal = "_someInvalidval_"
+	if !m.IsValidDeliveryProofFileKind(validVal) {
+		t.Fatalf("IsValidDeliveryProofFileKind(%q) should be true", validVal)
+	}
+	if m.IsValidDeliveryProofFileKind(inValidVal) {
+		t.Fatalf("IsValidDeliveryProofFileKind(%q) should be false", inValidVal)
+	}
+}
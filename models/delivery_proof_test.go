@@ -0,0 +1,63 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/gofrs/uuid"
+)
+
+// pngHeader is enough of a png image for its content type to be sniffed
+var pngHeader = []byte("\x89PNG\x0D\x0A\x1A\x0A\x00\x00\x00\x0DIHDR")
+
+func (ms *ModelSuite) Test_DeliveryProof() {
+	var shipmentID = uuid.Must(uuid.NewV4())
+	var tests = []struct {
+		proof                    *DeliveryProof
+		expectedValidationErrors int
+	}{
+		{&DeliveryProof{}, 3},
+		{&DeliveryProof{ShipmentID: shipmentID, ReceiverName: "Jordan", DeliveredAt: time.Now().UTC()}, 0},
+		{&DeliveryProof{ShipmentID: shipmentID, ReceiverName: "Jordan", DeliveredAt: time.Now().UTC(), SealNumber: nulls.NewString("SEAL1"), Latitude: nulls.NewFloat64(deltaport.Lat), Longitude: nulls.NewFloat64(deltaport.Lng)}, 0},
+		{&DeliveryProof{ShipmentID: shipmentID, ReceiverName: "Jordan", DeliveredAt: time.Now().UTC().Add(time.Hour)}, 1},
+		{&DeliveryProof{ShipmentID: shipmentID, ReceiverName: "Jordan", DeliveredAt: time.Now().UTC(), Latitude: nulls.NewFloat64(deltaport.Lat)}, 1},
+		{&DeliveryProof{ShipmentID: shipmentID, ReceiverName: "Jordan", DeliveredAt: time.Now().UTC(), Latitude: nulls.NewFloat64(91), Longitude: nulls.NewFloat64(0)}, 1},
+	}
+	for i, test := range tests {
+		ms.T().Run(fmt.Sprint(i), func(t *testing.T) {
+			v, err := test.proof.Validate(ms.DB)
+			ms.Nil(err)
+			ms.Equal(test.expectedValidationErrors, len(v.Errors))
+		})
+	}
+}
+
+func (ms *ModelSuite) Test_DeliveryProofFile() {
+	var proof = &DeliveryProof{ID: uuid.Must(uuid.NewV4()), TenantID: uuid.Must(uuid.NewV4()), ShipmentID: uuid.Must(uuid.NewV4())}
+	var file = NewDeliveryProofFile(proof, DeliveryProofFileKindSignature, pngHeader)
+	ms.Equal(proof.ID, file.DeliveryProofID)
+	ms.Equal(proof.TenantID, file.TenantID)
+	ms.Equal("image/png", file.ContentType)
+	ms.Equal(len(pngHeader), file.Size)
+	ms.Equal(fmt.Sprintf("%s/shipments/%s/pod/%s", proof.TenantID, proof.ShipmentID, file.ID), file.BlobKey)
+	var tests = []struct {
+		file                     *DeliveryProofFile
+		expectedValidationErrors int
+	}{
+		{&DeliveryProofFile{}, 5},
+		{file, 0},
+		{NewDeliveryProofFile(proof, DeliveryProofFileKindPhoto, []byte("not an image")), 1},
+		{NewDeliveryProofFile(proof, DeliveryProofFileKind("Video"), pngHeader), 1},
+		{NewDeliveryProofFile(proof, DeliveryProofFileKindPhoto, []byte(string(pngHeader)+strings.Repeat("x", MaxDeliveryProofFileSize))), 1},
+	}
+	for i, test := range tests {
+		ms.T().Run(fmt.Sprint(i), func(t *testing.T) {
+			v, err := test.file.Validate(ms.DB)
+			ms.Nil(err)
+			ms.Equal(test.expectedValidationErrors, len(v.Errors))
+		})
+	}
+}
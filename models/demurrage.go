@@ -0,0 +1,130 @@
+package models
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/gobuffalo/nulls"
+)
+
+// containerAtTerminalStatuses are the statuses of a shipment whose container has not been picked up yet
+var containerAtTerminalStatuses = []ShipmentStatus{
+	ShipmentStatusUnassigned,
+	ShipmentStatusAssigned,
+	ShipmentStatusAccepted,
+	ShipmentStatusRejected,
+	ShipmentStatusArrived,
+}
+
+// ContainerAtTerminalStatuses returns the shipment statuses that accrue demurrage, as query arguments
+func ContainerAtTerminalStatuses() []interface{} {
+	var statuses = make([]interface{}, len(containerAtTerminalStatuses))
+	for i, s := range containerAtTerminalStatuses {
+		statuses[i] = s.String()
+	}
+	return statuses
+}
+
+// DemurrageExposure is the demurrage a shipment is projected to incur at its terminal
+type DemurrageExposure struct {
+	Shipment           Shipment  `json:"shipment"`
+	Lfd                time.Time `json:"lfd"`
+	HoursRemaining     int       `json:"hours_remaining"`
+	DaysRemaining      int       `json:"days_remaining"`
+	ChargeableDays     int       `json:"chargeable_days"`
+	DailyRate          NullMoney `json:"daily_rate"`
+	ProjectedDemurrage NullMoney `json:"projected_demurrage"`
+	remaining          time.Duration
+}
+
+// LastFreeDay returns the last free day of a shipment. The shipment LFD takes precedence over the order LFD,
+// which in turn takes precedence over the order ETA plus the free time of the terminal for the type of the shipment.
+// The Order and Terminal of the shipment must be loaded, holidays of the terminal are not counted when loaded.
+func (c *Shipment) LastFreeDay() (time.Time, bool) {
+	if c.Lfd.Valid {
+		return c.Lfd.Time, true
+	}
+	if c.Order == nil {
+		return time.Time{}, false
+	}
+	if c.Order.Lfd.Valid {
+		return c.Order.Lfd.Time, true
+	}
+	if c.Order.Eta.Valid && c.Terminal != nil {
+		if days, ok := c.Terminal.FreeTimeDaysFor(c.Type); ok {
+			return c.Terminal.AddFreeDays(c.Order.Eta.Time, days), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// IsAtTerminal checks if the container of a shipment has not been picked up yet
+func (c *Shipment) IsAtTerminal() bool {
+	for _, s := range containerAtTerminalStatuses {
+		if ShipmentStatus(c.Status) == s {
+			return true
+		}
+	}
+	return false
+}
+
+// NewDemurrageExposure computes the exposure of a shipment at the given time.
+// The container is expected to be picked up now, or at its reservation time if that is later.
+// It returns false when the container has left the terminal or its last free day is unknown.
+func NewDemurrageExposure(s *Shipment, now time.Time) (*DemurrageExposure, bool) {
+	if !s.IsAtTerminal() {
+		return nil, false
+	}
+	lfd, ok := s.LastFreeDay()
+	if !ok {
+		return nil, false
+	}
+	e := &DemurrageExposure{Shipment: *s, Lfd: lfd, remaining: lfd.Sub(now)}
+	e.HoursRemaining = int(math.Floor(e.remaining.Hours()))
+	e.DaysRemaining = int(math.Floor(e.remaining.Hours() / 24))
+	pickup := now
+	if s.ReservationTime.Valid && s.ReservationTime.Time.After(now) {
+		pickup = s.ReservationTime.Time
+	}
+	if pickup.After(lfd) {
+		e.ChargeableDays = int(math.Ceil(pickup.Sub(lfd).Hours() / 24))
+	}
+	if s.Terminal != nil && s.Terminal.DemurrageRate.Valid {
+		e.DailyRate = s.Terminal.DemurrageRate
+		e.ProjectedDemurrage = NullMoney{Money: e.DailyRate.Money.Multiply(e.ChargeableDays), Valid: true}
+	}
+	return e, true
+}
+
+// DemurrageExposures returns the exposure of the shipments whose last free day is at most the given duration away,
+// including the ones already past it, soonest first.
+func DemurrageExposures(shipments Shipments, now time.Time, within time.Duration) []DemurrageExposure {
+	var exposures = []DemurrageExposure{}
+	for i := range shipments {
+		e, ok := NewDemurrageExposure(&shipments[i], now)
+		if ok && e.remaining <= within {
+			exposures = append(exposures, *e)
+		}
+	}
+	sort.SliceStable(exposures, func(i, j int) bool {
+		return exposures[i].Lfd.Before(exposures[j].Lfd)
+	})
+	return exposures
+}
+
+// DueThreshold returns the closest of the thresholds, sorted in increasing order, the time remaining until the
+// last free day is at or below, when the shipment has not been alerted for it or a closer one yet.
+// An alert for a threshold the last free day has since moved away from does not count.
+func (e *DemurrageExposure) DueThreshold(thresholds []time.Duration) (time.Duration, bool) {
+	alerted := e.Shipment.LfdAlertedThreshold
+	if alerted.Valid && e.remaining > time.Duration(alerted.Int)*time.Second {
+		alerted = nulls.Int{}
+	}
+	for _, t := range thresholds {
+		if e.remaining <= t {
+			return t, !alerted.Valid || t < time.Duration(alerted.Int)*time.Second
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,120 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gobuffalo/nulls"
+)
+
+func (ms *ModelSuite) Test_ShipmentLastFreeDay() {
+	var now = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	var terminal = &Terminal{FreeTimeDays: nulls.NewInt(4)}
+	var scheduled = &Terminal{FreeTimeDays: nulls.NewInt(4), ImportFreeTimeDays: nulls.NewInt(5), ExportFreeTimeDays: nulls.NewInt(2),
+		Holidays: TerminalHolidays{{Date: time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC), Name: "Founders Day"}}}
+	var tests = []struct {
+		shipment *Shipment
+		lfd      time.Time
+		ok       bool
+	}{
+		{&Shipment{}, time.Time{}, false},
+		{&Shipment{Lfd: nulls.NewTime(now)}, now, true},
+		{&Shipment{Lfd: nulls.NewTime(now), Order: &Order{Lfd: nulls.NewTime(now.AddDate(0, 0, 1))}}, now, true},
+		{&Shipment{Order: &Order{Lfd: nulls.NewTime(now.AddDate(0, 0, 1))}}, now.AddDate(0, 0, 1), true},
+		{&Shipment{Order: &Order{Eta: nulls.NewTime(now)}, Terminal: terminal}, now.AddDate(0, 0, 4), true},
+		{&Shipment{Order: &Order{Eta: nulls.NewTime(now)}, Terminal: &Terminal{}}, time.Time{}, false},
+		{&Shipment{Type: ShipmentTypeInbound.String(), Order: &Order{Eta: nulls.NewTime(now)}, Terminal: scheduled}, now.AddDate(0, 0, 6), true},
+		{&Shipment{Type: Outbound.String(), Order: &Order{Eta: nulls.NewTime(now)}, Terminal: scheduled}, now.AddDate(0, 0, 3), true},
+		{&Shipment{Type: ShipmentTypeInbound.String(), Order: &Order{Eta: nulls.NewTime(now)}, Terminal: terminal}, now.AddDate(0, 0, 4), true},
+	}
+	for i, test := range tests {
+		ms.T().Run(fmt.Sprint(i), func(t *testing.T) {
+			lfd, ok := test.shipment.LastFreeDay()
+			ms.Equal(test.ok, ok)
+			ms.Equal(test.lfd, lfd)
+		})
+	}
+}
+
+func (ms *ModelSuite) Test_DemurrageExposure() {
+	var now = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	var terminal = &Terminal{DemurrageRate: NewNullMoney(15000, "CAD")}
+	var tests = []struct {
+		shipment           *Shipment
+		ok                 bool
+		hoursRemaining     int
+		daysRemaining      int
+		chargeableDays     int
+		projectedDemurrage NullMoney
+	}{
+		{&Shipment{Status: ShipmentStatusAssigned.String()}, false, 0, 0, 0, NullMoney{}},
+		{&Shipment{Status: ShipmentStatusLoaded.String(), Lfd: nulls.NewTime(now)}, false, 0, 0, 0, NullMoney{}},
+		{&Shipment{Status: ShipmentStatusAssigned.String(), Lfd: nulls.NewTime(now.Add(30 * time.Hour)), Terminal: terminal}, true, 30, 1, 0, NewNullMoney(0, "CAD")},
+		{&Shipment{Status: ShipmentStatusUnassigned.String(), Lfd: nulls.NewTime(now.Add(-36 * time.Hour)), Terminal: terminal}, true, -36, -2, 2, NewNullMoney(30000, "CAD")},
+		{&Shipment{Status: ShipmentStatusAccepted.String(), Lfd: nulls.NewTime(now.Add(12 * time.Hour)), ReservationTime: nulls.NewTime(now.Add(60 * time.Hour)), Terminal: terminal}, true, 12, 0, 2, NewNullMoney(30000, "CAD")},
+		{&Shipment{Status: ShipmentStatusArrived.String(), Lfd: nulls.NewTime(now.Add(-time.Hour))}, true, -1, -1, 1, NullMoney{}},
+	}
+	for i, test := range tests {
+		ms.T().Run(fmt.Sprint(i), func(t *testing.T) {
+			e, ok := NewDemurrageExposure(test.shipment, now)
+			ms.Equal(test.ok, ok)
+			if !ok {
+				return
+			}
+			ms.Equal(test.hoursRemaining, e.HoursRemaining)
+			ms.Equal(test.daysRemaining, e.DaysRemaining)
+			ms.Equal(test.chargeableDays, e.ChargeableDays)
+			ms.Equal(test.projectedDemurrage, e.ProjectedDemurrage)
+		})
+	}
+}
+
+func (ms *ModelSuite) Test_DemurrageExposures() {
+	var now = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	var shipments = Shipments{
+		{SerialNumber: "later", Status: ShipmentStatusAssigned.String(), Lfd: nulls.NewTime(now.Add(72 * time.Hour))},
+		{SerialNumber: "soon", Status: ShipmentStatusAssigned.String(), Lfd: nulls.NewTime(now.Add(24 * time.Hour))},
+		{SerialNumber: "past", Status: ShipmentStatusAssigned.String(), Lfd: nulls.NewTime(now.Add(-24 * time.Hour))},
+		{SerialNumber: "gone", Status: ShipmentStatusDelivered.String(), Lfd: nulls.NewTime(now.Add(-24 * time.Hour))},
+		{SerialNumber: "unknown", Status: ShipmentStatusAssigned.String()},
+	}
+	exposures := DemurrageExposures(shipments, now, 48*time.Hour)
+	ms.Equal(2, len(exposures))
+	ms.Equal("past", exposures[0].Shipment.SerialNumber)
+	ms.Equal("soon", exposures[1].Shipment.SerialNumber)
+}
+
+func (ms *ModelSuite) Test_DemurrageExposureDueThreshold() {
+	var now = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	var thresholds = []time.Duration{0, 24 * time.Hour, 48 * time.Hour}
+	var tests = []struct {
+		remaining time.Duration
+		alerted   nulls.Int
+		threshold time.Duration
+		due       bool
+	}{
+		{50 * time.Hour, nulls.Int{}, 0, false},
+		{48 * time.Hour, nulls.Int{}, 48 * time.Hour, true},
+		{47 * time.Hour, nulls.Int{}, 48 * time.Hour, true},
+		{47 * time.Hour, nulls.NewInt(48 * 3600), 48 * time.Hour, false},
+		{23*time.Hour + 59*time.Minute, nulls.NewInt(48 * 3600), 24 * time.Hour, true},
+		{12 * time.Hour, nulls.NewInt(24 * 3600), 24 * time.Hour, false},
+		{-30 * time.Minute, nulls.NewInt(24 * 3600), 0, true},
+		{-2 * time.Hour, nulls.Int{}, 0, true},
+		{-2 * time.Hour, nulls.NewInt(0), 0, false},
+		// The last free day moved out after the alerts
+		{36 * time.Hour, nulls.NewInt(0), 48 * time.Hour, true},
+		{72 * time.Hour, nulls.NewInt(0), 0, false},
+	}
+	for i, test := range tests {
+		ms.T().Run(fmt.Sprint(i), func(t *testing.T) {
+			s := &Shipment{Status: ShipmentStatusAssigned.String(), Lfd: nulls.NewTime(now.Add(test.remaining)), LfdAlertedThreshold: test.alerted}
+			e, ok := NewDemurrageExposure(s, now)
+			ms.True(ok)
+			threshold, due := e.DueThreshold(thresholds)
+			ms.Equal(test.due, due)
+			ms.Equal(test.threshold, threshold)
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"sort"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// DefaultReservationWindow is how long a driver is considered busy with a reservation
+const DefaultReservationWindow = 2 * time.Hour
+
+// DispatchBoard is the work of every driver of a tenant for a day
+type DispatchBoard struct {
+	Date    time.Time            `json:"date"`
+	Drivers DispatchBoardDrivers `json:"drivers"`
+}
+
+// DispatchBoardDriver is a driver with the shipments reserved for the day, ordered by reservation time
+type DispatchBoardDriver struct {
+	Driver       User                 `json:"driver"`
+	Shipments    Shipments            `json:"shipments"`
+	StatusCounts map[string]int       `json:"status_counts"`
+	Conflicts    ReservationConflicts `json:"conflicts"`
+}
+
+// DispatchBoardDrivers is a list of DispatchBoardDriver
+type DispatchBoardDrivers []DispatchBoardDriver
+
+// ReservationConflict flags two shipments of a driver whose reservations overlap
+type ReservationConflict struct {
+	ShipmentID      uuid.UUID `json:"shipment_id"`
+	OtherShipmentID uuid.UUID `json:"other_shipment_id"`
+}
+
+// ReservationConflicts is a list of ReservationConflict
+type ReservationConflicts []ReservationConflict
+
+// NewDispatchBoard groups the shipments of the day by driver. Every driver is on the board,
+// including the ones without shipments. A reservation keeps the driver busy for the window.
+func NewDispatchBoard(date time.Time, drivers Users, shipments Shipments, window time.Duration) *DispatchBoard {
+	var byDriver = map[uuid.UUID]Shipments{}
+	for _, s := range shipments {
+		if s.DriverID.Valid {
+			byDriver[s.DriverID.UUID] = append(byDriver[s.DriverID.UUID], s)
+		}
+	}
+	var board = &DispatchBoard{Date: date, Drivers: DispatchBoardDrivers{}}
+	for _, d := range drivers {
+		var driverShipments = byDriver[d.ID]
+		if driverShipments == nil {
+			driverShipments = Shipments{}
+		}
+		sortByReservationTime(driverShipments)
+		var counts = map[string]int{}
+		for _, s := range driverShipments {
+			counts[s.Status]++
+		}
+		board.Drivers = append(board.Drivers, DispatchBoardDriver{
+			Driver:       d,
+			Shipments:    driverShipments,
+			StatusCounts: counts,
+			Conflicts:    FindReservationConflicts(driverShipments, window),
+		})
+	}
+	return board
+}
+
+// FindReservationConflicts returns the pairs of shipments whose reservations are less than the window apart.
+// Shipments without a reservation time never conflict.
+func FindReservationConflicts(shipments Shipments, window time.Duration) ReservationConflicts {
+	var reserved = Shipments{}
+	for _, s := range shipments {
+		if s.ReservationTime.Valid {
+			reserved = append(reserved, s)
+		}
+	}
+	sortByReservationTime(reserved)
+	var conflicts = ReservationConflicts{}
+	for i := range reserved {
+		end := reserved[i].ReservationTime.Time.Add(window)
+		for j := i + 1; j < len(reserved) && reserved[j].ReservationTime.Time.Before(end); j++ {
+			conflicts = append(conflicts, ReservationConflict{ShipmentID: reserved[i].ID, OtherShipmentID: reserved[j].ID})
+		}
+	}
+	return conflicts
+}
+
+// sortByReservationTime orders shipments by reservation time, the ones without a reservation last
+func sortByReservationTime(shipments Shipments) {
+	sort.SliceStable(shipments, func(i, j int) bool {
+		a, b := shipments[i].ReservationTime, shipments[j].ReservationTime
+		if !a.Valid || !b.Valid {
+			return a.Valid && !b.Valid
+		}
+		return a.Time.Before(b.Time)
+	})
+}
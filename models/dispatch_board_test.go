@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/gofrs/uuid"
+)
+
+func (ms *ModelSuite) Test_FindReservationConflicts() {
+	var day = time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	var shipment = func(hour int, minute int) Shipment {
+		return Shipment{ID: uuid.Must(uuid.NewV4()), ReservationTime: nulls.NewTime(day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute))}
+	}
+	a, b, c, d := shipment(8, 0), shipment(9, 30), shipment(10, 0), shipment(14, 0)
+	var unreserved = Shipment{ID: uuid.Must(uuid.NewV4())}
+
+	conflicts := FindReservationConflicts(Shipments{d, unreserved, c, b, a}, DefaultReservationWindow)
+	ms.Equal(ReservationConflicts{
+		{ShipmentID: a.ID, OtherShipmentID: b.ID},
+		{ShipmentID: b.ID, OtherShipmentID: c.ID},
+	}, conflicts)
+	// Reservations exactly one window apart do not overlap
+	ms.Equal(0, len(FindReservationConflicts(Shipments{a, c}, DefaultReservationWindow)))
+	ms.Equal(3, len(FindReservationConflicts(Shipments{a, b, c}, 3*time.Hour)))
+}
+
+func (ms *ModelSuite) Test_NewDispatchBoard() {
+	var day = time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	var salah = User{ID: uuid.Must(uuid.NewV4()), Name: "salah"}
+	var mane = User{ID: uuid.Must(uuid.NewV4()), Name: "mane"}
+	var shipments = Shipments{
+		{ID: uuid.Must(uuid.NewV4()), DriverID: nulls.NewUUID(salah.ID), Status: ShipmentStatusAccepted.String(), ReservationTime: nulls.NewTime(day.Add(11 * time.Hour))},
+		{ID: uuid.Must(uuid.NewV4()), DriverID: nulls.NewUUID(salah.ID), Status: ShipmentStatusAssigned.String()},
+		{ID: uuid.Must(uuid.NewV4()), DriverID: nulls.NewUUID(salah.ID), Status: ShipmentStatusAssigned.String(), ReservationTime: nulls.NewTime(day.Add(10 * time.Hour))},
+		{ID: uuid.Must(uuid.NewV4()), Status: ShipmentStatusUnassigned.String(), ReservationTime: nulls.NewTime(day.Add(10 * time.Hour))},
+	}
+	board := NewDispatchBoard(day, Users{salah, mane}, shipments, DefaultReservationWindow)
+	ms.Equal(2, len(board.Drivers))
+
+	ms.Equal(salah.ID, board.Drivers[0].Driver.ID)
+	ms.Equal(3, len(board.Drivers[0].Shipments))
+	ms.Equal(shipments[2].ID, board.Drivers[0].Shipments[0].ID)
+	ms.Equal(shipments[0].ID, board.Drivers[0].Shipments[1].ID)
+	ms.Equal(shipments[1].ID, board.Drivers[0].Shipments[2].ID)
+	ms.Equal(map[string]int{ShipmentStatusAssigned.String(): 2, ShipmentStatusAccepted.String(): 1}, board.Drivers[0].StatusCounts)
+	ms.Equal(ReservationConflicts{{ShipmentID: shipments[2].ID, OtherShipmentID: shipments[0].ID}}, board.Drivers[0].Conflicts)
+
+	ms.Equal(mane.ID, board.Drivers[1].Driver.ID)
+	ms.Equal(0, len(board.Drivers[1].Shipments))
+	ms.Equal(0, len(board.Drivers[1].Conflicts))
+}
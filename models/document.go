@@ -0,0 +1,87 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gobuffalo/validate/v3"
+	"github.com/gobuffalo/validate/v3/validators"
+	"github.com/gofrs/uuid"
+)
+
+// MaxDocumentSize is the largest document that can be attached, in bytes
+const MaxDocumentSize = 10 << 20
+
+// documentContentTypes are the files that can be attached
+var documentContentTypes = []string{"application/pdf", "image/jpeg", "image/png"}
+
+// Document is used by pop to map your documents database table to your go code.
+// It is a file attached to an order or a shipment, e.g. a bill of lading or a customs release.
+// Checksum is the hex encoded SHA-256 of the file, BlobKey is where the blob store keeps it.
+type Document struct {
+	ID          uuid.UUID  `json:"id" db:"id"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+	CreatedBy   uuid.UUID  `json:"created_by" db:"created_by"`
+	TenantID    uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	OrderID     nulls.UUID `json:"order_id" db:"order_id"`
+	ShipmentID  nulls.UUID `json:"shipment_id" db:"shipment_id"`
+	Type        string     `json:"type" db:"type"`
+	Name        string     `json:"name" db:"name"`
+	ContentType string     `json:"content_type" db:"content_type"`
+	Size        int        `json:"size" db:"size"`
+	Checksum    string     `json:"checksum" db:"checksum"`
+	BlobKey     string     `json:"-" db:"blob_key"`
+	Tenant      *Tenant    `belongs_to:"tenant" json:"-"`
+	Order       *Order     `belongs_to:"order" json:"-"`
+	Shipment    *Shipment  `belongs_to:"shipment" json:"-"`
+}
+
+// Documents is not required by pop and may be deleted
+type Documents []Document
+
+// SetContent describes the file of the document and the key it is kept with. The content type is sniffed from the file.
+func (d *Document) SetContent(data []byte) {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.Must(uuid.NewV4())
+	}
+	var sum = sha256.Sum256(data)
+	d.ContentType = http.DetectContentType(data)
+	d.Size = len(data)
+	d.Checksum = hex.EncodeToString(sum[:])
+	d.BlobKey = fmt.Sprintf("%s/documents/%s", d.TenantID, d.ID)
+}
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (d *Document) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.StringIsPresent{Field: d.Name, Name: "Name"},
+		&validators.StringLengthInRange{Field: d.Name, Name: "Name", Max: 255},
+		&validators.StringIsPresent{Field: d.Checksum, Name: "Checksum"},
+		&validators.StringIsPresent{Field: d.BlobKey, Name: "BlobKey"},
+		&validators.FuncValidator{Fn: func() bool {
+			// A document belongs to either an order or a shipment
+			return d.OrderID.Valid != d.ShipmentID.Valid
+		}, Field: "", Name: "OrderID"},
+		&validators.FuncValidator{Fn: func() bool {
+			return IsValidDocumentType(d.Type)
+		}, Field: d.Type, Name: "Type"},
+		&validators.FuncValidator{Fn: func() bool {
+			for _, t := range documentContentTypes {
+				if d.ContentType == t {
+					return true
+				}
+			}
+			return false
+		}, Field: d.ContentType, Name: "ContentType"},
+		&validators.FuncValidator{Fn: func() bool {
+			return d.Size > 0 && d.Size <= MaxDocumentSize
+		}, Field: fmt.Sprint(d.Size), Name: "Size"},
+	), nil
+}
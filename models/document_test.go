@@ -0,0 +1,48 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/gofrs/uuid"
+)
+
+func (ms *ModelSuite) Test_Document() {
+	var pdf = []byte("%PDF-1.7\n%bill of lading")
+	var sum = sha256.Sum256(pdf)
+	var document = &Document{TenantID: uuid.Must(uuid.NewV4()), OrderID: nulls.NewUUID(uuid.Must(uuid.NewV4())), Type: DocumentTypeBillOfLading.String(), Name: "bol.pdf"}
+	document.SetContent(pdf)
+	ms.NotEqual(uuid.Nil, document.ID)
+	ms.Equal("application/pdf", document.ContentType)
+	ms.Equal(len(pdf), document.Size)
+	ms.Equal(hex.EncodeToString(sum[:]), document.Checksum)
+	ms.Equal(fmt.Sprintf("%s/documents/%s", document.TenantID, document.ID), document.BlobKey)
+
+	var newDocument = func(d Document, data []byte) *Document {
+		d.SetContent(data)
+		return &d
+	}
+	var tests = []struct {
+		document                 *Document
+		expectedValidationErrors int
+	}{
+		{&Document{}, 7},
+		{document, 0},
+		{newDocument(Document{ShipmentID: document.OrderID, Type: "Invoice", Name: "invoice.png"}, pngHeader), 0},
+		{newDocument(Document{OrderID: document.OrderID, ShipmentID: document.OrderID, Type: "Invoice", Name: "invoice.pdf"}, pdf), 1},
+		{newDocument(Document{OrderID: document.OrderID, Type: "Receipt", Name: "receipt.pdf"}, pdf), 1},
+		{newDocument(Document{OrderID: document.OrderID, Type: "Other", Name: "notes.txt"}, []byte("notes")), 1},
+		{newDocument(Document{OrderID: document.OrderID, Type: "Other", Name: "large.pdf"}, []byte(string(pdf)+strings.Repeat("x", MaxDocumentSize))), 1},
+	}
+	for i, test := range tests {
+		ms.T().Run(fmt.Sprint(i), func(t *testing.T) {
+			v, err := test.document.Validate(ms.DB)
+			ms.Nil(err)
+			ms.Equal(test.expectedValidationErrors, len(v.Errors), v.String())
+		})
+	}
+}
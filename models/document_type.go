@@ -0,0 +1,38 @@
+package models
+
+// AUTOGENERATED BY: HSM GEN
+
+// DocumentType represents the DocumentType enum
+type DocumentType string
+
+const (
+	// DocumentTypeBillOfLading represents BillOfLading DocumentType
+	DocumentTypeBillOfLading DocumentType = "BillOfLading"
+	// DocumentTypeDeliveryOrder represents DeliveryOrder DocumentType
+	DocumentTypeDeliveryOrder DocumentType = "DeliveryOrder"
+	// DocumentTypeCustomsRelease represents CustomsRelease DocumentType
+	DocumentTypeCustomsRelease DocumentType = "CustomsRelease"
+	// DocumentTypeInvoice represents Invoice DocumentType
+	DocumentTypeInvoice DocumentType = "Invoice"
+	// DocumentTypeOther represents Other DocumentType
+	DocumentTypeOther DocumentType = "Other"
+)
+
+var allowedDocumentType [5]DocumentType = [5]DocumentType{
+	DocumentTypeBillOfLading,
+	DocumentTypeDeliveryOrder,
+	DocumentTypeCustomsRelease,
+	DocumentTypeInvoice,
+	DocumentTypeOther,
+}
+
+// String returns the string representation of
+func (t DocumentType) String() string {
+	return string(t)
+}
+
+// IsValidDocumentType validates if the input is a DocumentType
+func IsValidDocumentType(s string) bool {
+	t := DocumentType(s)
+	return DocumentTypeBillOfLading == t || DocumentTypeDeliveryOrder == t || DocumentTypeCustomsRelease == t || DocumentTypeInvoice == t || DocumentTypeOther == t
+}
@@ -0,0 +1,20 @@
+package models_test
+
+// AUTOGENERATED BY: HSM GEN
+
+import (
+	"testing"
+
+	m "github.com/bigpanther/trober/models"
+)
+
+func TestIsValidDocumentType(t *testing.T) {
+	var validVal = "CustomsRelease"
+	var inValidVal = "_someInvalidval_"
+	if !m.IsValidDocumentType(validVal) {
+		t.Fatalf("IsValidDocumentType(%q) should be true", validVal)
+	}
+	if m.IsValidDocumentType(inValidVal) {
+		t.Fatalf("IsValidDocumentType(%q) should be false", inValidVal)
+	}
+}
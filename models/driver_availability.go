@@ -0,0 +1,109 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gofrs/uuid"
+)
+
+// ErrDriverUnavailable is returned when a shipment is reserved outside the availability of its driver
+var ErrDriverUnavailable = errors.New("driver is not available")
+
+// DriverAvailability is when a driver works, the weekly shifts and the approved time off.
+// A driver without shifts is available any time outside of the time off.
+type DriverAvailability struct {
+	Shifts  DriverShifts    `json:"shifts"`
+	TimeOff TimeOffRequests `json:"time_off"`
+}
+
+// LoadDriverAvailabilities returns the availability of the drivers, with the time off ending after the given time
+func LoadDriverAvailabilities(tx *pop.Connection, since time.Time, driverIDs ...uuid.UUID) (map[uuid.UUID]*DriverAvailability, error) {
+	var availabilities = map[uuid.UUID]*DriverAvailability{}
+	if len(driverIDs) == 0 {
+		return availabilities, nil
+	}
+	var ids = make([]interface{}, len(driverIDs))
+	for i, id := range driverIDs {
+		ids[i] = id
+		availabilities[id] = &DriverAvailability{Shifts: DriverShifts{}, TimeOff: TimeOffRequests{}}
+	}
+	shifts := DriverShifts{}
+	if err := tx.Where("driver_id IN (?)", ids...).Order("weekday ASC, start_time ASC").All(&shifts); err != nil {
+		return nil, err
+	}
+	for _, s := range shifts {
+		availabilities[s.DriverID].Shifts = append(availabilities[s.DriverID].Shifts, s)
+	}
+	timeOff := TimeOffRequests{}
+	if err := tx.Where("driver_id IN (?)", ids...).Where("status = ?", TimeOffRequestStatusApproved.String()).
+		Where("ends_at > ?", since).Order("starts_at ASC").All(&timeOff); err != nil {
+		return nil, err
+	}
+	for _, t := range timeOff {
+		availabilities[t.DriverID].TimeOff = append(availabilities[t.DriverID].TimeOff, t)
+	}
+	return availabilities, nil
+}
+
+// Check returns why the driver cannot take a shipment reserved at the given time, nothing when the driver is available.
+// The load returns how many shipments the driver has reserved between two times, to enforce the capacity of the shift.
+func (a *DriverAvailability) Check(at time.Time, load func(start time.Time, end time.Time) (int, error)) ([]string, error) {
+	var reasons = []string{}
+	if a == nil {
+		return reasons, nil
+	}
+	for _, t := range a.TimeOff {
+		if t.Covers(at) {
+			reasons = append(reasons, fmt.Sprintf("On time off from %s to %s", t.StartsAt.Format(time.RFC3339), t.EndsAt.Format(time.RFC3339)))
+		}
+	}
+	if len(a.Shifts) == 0 {
+		return reasons, nil
+	}
+	var shift *DriverShift
+	for i := range a.Shifts {
+		if a.Shifts[i].Covers(at) {
+			shift = &a.Shifts[i]
+			break
+		}
+	}
+	if shift == nil {
+		return append(reasons, "Outside of the shifts of the driver"), nil
+	}
+	if shift.Capacity.Valid {
+		start, end := shift.Day(at)
+		count, err := load(start, end)
+		if err != nil {
+			return nil, err
+		}
+		if count >= shift.Capacity.Int {
+			reasons = append(reasons, fmt.Sprintf("Already has %d shipments on %s, the capacity is %d", count, start.Format("2006-01-02"), shift.Capacity.Int))
+		}
+	}
+	return reasons, nil
+}
+
+// CountDriverReservations returns how many shipments the driver is working on are reserved between two times,
+// other than the given shipment
+func CountDriverReservations(tx *pop.Connection, driverID uuid.UUID, start time.Time, end time.Time, excludeID uuid.UUID) (int, error) {
+	var statuses = make([]interface{}, len(driverActiveStatuses))
+	for i, s := range driverActiveStatuses {
+		statuses[i] = s.String()
+	}
+	return tx.Where("driver_id = ?", driverID).Where("id <> ?", excludeID).Where("status IN (?)", statuses...).
+		Where("reservation_time >= ?", start).Where("reservation_time < ?", end).Count(&Shipments{})
+}
+
+// countReservations returns how many of the shipments other than the given one are reserved between two times
+func countReservations(shipments Shipments, start time.Time, end time.Time, excludeID uuid.UUID) int {
+	var count int
+	for _, s := range shipments {
+		if s.ID != excludeID && s.ReservationTime.Valid && !s.ReservationTime.Time.Before(start) && s.ReservationTime.Time.Before(end) {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/gofrs/uuid"
+)
+
+func (ms *ModelSuite) Test_DriverAvailabilityCheck() {
+	var monday = time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	var shifts = DriverShifts{
+		{Weekday: int(time.Monday), StartTime: "08:00", EndTime: "16:00", Timezone: "UTC", Capacity: nulls.NewInt(2)},
+		{Weekday: int(time.Tuesday), StartTime: "08:00", EndTime: "16:00", Timezone: "UTC"},
+	}
+	var timeOff = TimeOffRequests{
+		{StartsAt: monday.AddDate(0, 0, 7), EndsAt: monday.AddDate(0, 0, 9), Status: TimeOffRequestStatusApproved.String()},
+	}
+	var none = func(start time.Time, end time.Time) (int, error) { return 0, nil }
+	var full = func(start time.Time, end time.Time) (int, error) {
+		ms.True(start.Equal(monday))
+		ms.True(end.Equal(monday.AddDate(0, 0, 1)))
+		return 2, nil
+	}
+	var tests = []struct {
+		name         string
+		availability *DriverAvailability
+		at           time.Time
+		load         func(start time.Time, end time.Time) (int, error)
+		reasons      int
+	}{
+		{"unknown driver", nil, monday, none, 0},
+		{"no shifts", &DriverAvailability{}, monday.Add(3 * time.Hour), none, 0},
+		{"no shifts on time off", &DriverAvailability{TimeOff: timeOff}, monday.AddDate(0, 0, 7).Add(10 * time.Hour), none, 1},
+		{"during a shift", &DriverAvailability{Shifts: shifts, TimeOff: timeOff}, monday.Add(10 * time.Hour), none, 0},
+		{"outside of the shifts", &DriverAvailability{Shifts: shifts, TimeOff: timeOff}, monday.Add(18 * time.Hour), none, 1},
+		{"shift at capacity", &DriverAvailability{Shifts: shifts, TimeOff: timeOff}, monday.Add(10 * time.Hour), full, 1},
+		{"shift without capacity", &DriverAvailability{Shifts: shifts, TimeOff: timeOff}, monday.Add(34 * time.Hour), full, 0},
+		{"shift on time off", &DriverAvailability{Shifts: shifts, TimeOff: timeOff}, monday.AddDate(0, 0, 7).Add(10 * time.Hour), none, 1},
+		{"outside of the shifts on time off", &DriverAvailability{Shifts: shifts, TimeOff: timeOff}, monday.AddDate(0, 0, 7).Add(18 * time.Hour), none, 2},
+	}
+	for _, test := range tests {
+		ms.T().Run(test.name, func(t *testing.T) {
+			reasons, err := test.availability.Check(test.at, test.load)
+			ms.Nil(err)
+			ms.Len(reasons, test.reasons)
+		})
+	}
+	var errLoad = errors.New("load failed")
+	_, err := (&DriverAvailability{Shifts: shifts}).Check(monday.Add(10*time.Hour), func(start time.Time, end time.Time) (int, error) {
+		return 0, errLoad
+	})
+	ms.Equal(errLoad, err)
+}
+
+func (ms *ModelSuite) Test_DriverWorkloadScoreAvailability() {
+	var monday = time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	var driver = User{ID: uuid.Must(uuid.NewV4()), Name: "salah"}
+	var shifts = DriverShifts{{Weekday: int(time.Monday), StartTime: "08:00", EndTime: "16:00", Timezone: "UTC", Capacity: nulls.NewInt(1)}}
+	var shipment = Shipment{ID: uuid.Must(uuid.NewV4()), SerialNumber: "S1", ReservationTime: nulls.NewTime(monday.Add(9 * time.Hour))}
+
+	var available = DriverWorkload{Driver: driver, Availability: &DriverAvailability{Shifts: shifts}}
+	ms.False(available.Score(&shipment, DefaultReservationWindow).HasConflict)
+
+	var late = shipment
+	late.ReservationTime = nulls.NewTime(monday.Add(20 * time.Hour))
+	candidate := available.Score(&late, DefaultReservationWindow)
+	ms.True(candidate.HasConflict)
+	ms.Equal(candidateBaseScore-reservationConflictPenalty, candidate.Score)
+
+	var busy = DriverWorkload{Driver: driver, Availability: &DriverAvailability{Shifts: shifts}, Active: Shipments{
+		{ID: uuid.Must(uuid.NewV4()), SerialNumber: "S2", ReservationTime: nulls.NewTime(monday.Add(14 * time.Hour))},
+	}}
+	ms.True(busy.Score(&shipment, DefaultReservationWindow).HasConflict)
+}
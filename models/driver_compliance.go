@@ -0,0 +1,205 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gobuffalo/validate/v3"
+	"github.com/gobuffalo/validate/v3/validators"
+	"github.com/gofrs/uuid"
+)
+
+// ErrDriverNotCompliant is returned when a driver lacks a credential the terminal of a shipment requires
+var ErrDriverNotCompliant = errors.New("driver is not compliant")
+
+const complianceDateFormat = "2006-01-02"
+
+// DriverCompliance is used by pop to map your driver_compliances database table to your go code.
+// It is the licence, medical certificate, TWIC and port pass of a driver, one per driver.
+// A credential is valid until the end of its expiry day (UTC), a credential without expiry is missing.
+type DriverCompliance struct {
+	ID                       uuid.UUID    `json:"id" db:"id"`
+	CreatedAt                time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt                time.Time    `json:"updated_at" db:"updated_at"`
+	CreatedBy                uuid.UUID    `json:"created_by" db:"created_by"`
+	TenantID                 uuid.UUID    `json:"tenant_id" db:"tenant_id"`
+	DriverID                 uuid.UUID    `json:"driver_id" db:"driver_id"`
+	LicenceClass             nulls.String `json:"licence_class" db:"licence_class"`
+	LicenceExpiry            nulls.Time   `json:"licence_expiry" db:"licence_expiry"`
+	MedicalCertificateExpiry nulls.Time   `json:"medical_certificate_expiry" db:"medical_certificate_expiry"`
+	TwicExpiry               nulls.Time   `json:"twic_expiry" db:"twic_expiry"`
+	PortPassExpiry           nulls.Time   `json:"port_pass_expiry" db:"port_pass_expiry"`
+	Tenant                   *Tenant      `belongs_to:"tenant" json:"-"`
+	Driver                   *User        `belongs_to:"user" json:"driver,omitempty"`
+}
+
+// DriverCompliances is not required by pop and may be deleted
+type DriverCompliances []DriverCompliance
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (d *DriverCompliance) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	for _, expiry := range []*nulls.Time{&d.LicenceExpiry, &d.MedicalCertificateExpiry, &d.TwicExpiry, &d.PortPassExpiry} {
+		if expiry.Valid {
+			expiry.Time = expiry.Time.UTC().Truncate(24 * time.Hour)
+		}
+	}
+	return validate.Validate(
+		&validators.UUIDIsPresent{Field: d.DriverID, Name: "DriverID"},
+		&validators.FuncValidator{Fn: func() bool {
+			// Value can be null
+			return !d.LicenceExpiry.Valid || d.LicenceClass.Valid && d.LicenceClass.String != ""
+		}, Field: d.LicenceClass.String, Name: "LicenceClass"},
+	), nil
+}
+
+// Expiry returns the expiry day of a credential of the driver, null when the driver does not hold it
+func (d *DriverCompliance) Expiry(credential DriverCredential) nulls.Time {
+	if d == nil {
+		return nulls.Time{}
+	}
+	switch credential {
+	case DriverCredentialLicence:
+		return d.LicenceExpiry
+	case DriverCredentialMedicalCertificate:
+		return d.MedicalCertificateExpiry
+	case DriverCredentialTwic:
+		return d.TwicExpiry
+	case DriverCredentialPortPass:
+		return d.PortPassExpiry
+	}
+	return nulls.Time{}
+}
+
+// MissingCredentials returns why the driver cannot work at the given time where the credentials are required,
+// empty when the driver holds all of them. A nil compliance holds no credential.
+func (d *DriverCompliance) MissingCredentials(required DriverCredentials, at time.Time) []string {
+	var reasons = []string{}
+	for _, credential := range required {
+		expiry := d.Expiry(DriverCredential(credential))
+		if !expiry.Valid {
+			reasons = append(reasons, fmt.Sprintf("No %s on file", credential))
+			continue
+		}
+		if !at.Before(expiry.Time.AddDate(0, 0, 1)) {
+			reasons = append(reasons, fmt.Sprintf("%s expired on %s", credential, expiry.Time.Format(complianceDateFormat)))
+		}
+	}
+	return reasons
+}
+
+// ComplianceTime returns when the driver must hold the credentials for the shipment, at its reservation or else now
+func (c *Shipment) ComplianceTime(now time.Time) time.Time {
+	if c.ReservationTime.Valid {
+		return c.ReservationTime.Time
+	}
+	return now
+}
+
+// LoadDriverCompliances returns the compliance of each of the drivers that has one
+func LoadDriverCompliances(tx *pop.Connection, driverIDs ...uuid.UUID) (map[uuid.UUID]*DriverCompliance, error) {
+	var compliances = map[uuid.UUID]*DriverCompliance{}
+	if len(driverIDs) == 0 {
+		return compliances, nil
+	}
+	var ids = make([]interface{}, len(driverIDs))
+	for i, id := range driverIDs {
+		ids[i] = id
+	}
+	all := DriverCompliances{}
+	if err := tx.Where("driver_id IN (?)", ids...).All(&all); err != nil {
+		return nil, err
+	}
+	for i := range all {
+		compliances[all[i].DriverID] = &all[i]
+	}
+	return compliances, nil
+}
+
+// ComplianceExpiration is a credential of a driver that expires, or expired, by the end of a compliance report
+type ComplianceExpiration struct {
+	Driver     User      `json:"driver"`
+	Credential string    `json:"credential"`
+	ExpiresOn  time.Time `json:"expires_on"`
+	Expired    bool      `json:"expired"`
+}
+
+// ComplianceExpirations is a compliance report
+type ComplianceExpirations []ComplianceExpiration
+
+// NewComplianceExpirations returns the credentials of the drivers expiring before the end of the given day,
+// soonest first. The driver of each compliance must be loaded.
+func NewComplianceExpirations(compliances DriverCompliances, now time.Time, until time.Time) ComplianceExpirations {
+	var expirations = ComplianceExpirations{}
+	for i := range compliances {
+		d := &compliances[i]
+		if d.Driver == nil {
+			continue
+		}
+		for _, credential := range allowedDriverCredential {
+			expiry := d.Expiry(credential)
+			if !expiry.Valid || expiry.Time.After(until) {
+				continue
+			}
+			expirations = append(expirations, ComplianceExpiration{
+				Driver:     *d.Driver,
+				Credential: credential.String(),
+				ExpiresOn:  expiry.Time,
+				Expired:    !now.Before(expiry.Time.AddDate(0, 0, 1)),
+			})
+		}
+	}
+	sort.SliceStable(expirations, func(i, j int) bool {
+		if !expirations[i].ExpiresOn.Equal(expirations[j].ExpiresOn) {
+			return expirations[i].ExpiresOn.Before(expirations[j].ExpiresOn)
+		}
+		return expirations[i].Driver.Name < expirations[j].Driver.Name
+	})
+	return expirations
+}
+
+// DriverCredentials are the credentials a terminal requires from the drivers it lets in.
+// It is stored as a json array, null when empty.
+type DriverCredentials []string
+
+// IsValid checks if every credential is a DriverCredential
+func (d DriverCredentials) IsValid() bool {
+	for _, credential := range d {
+		if !IsValidDriverCredential(credential) {
+			return false
+		}
+	}
+	return true
+}
+
+// Value implements the driver.Valuer interface
+func (d DriverCredentials) Value() (driver.Value, error) {
+	if len(d) == 0 {
+		return nil, nil
+	}
+	b, err := json.Marshal([]string(d))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan implements the sql.Scanner interface
+func (d *DriverCredentials) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*d = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, (*[]string)(d))
+	case string:
+		return json.Unmarshal([]byte(v), (*[]string)(d))
+	}
+	return fmt.Errorf("cannot scan %T into DriverCredentials", value)
+}
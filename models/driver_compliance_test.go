@@ -0,0 +1,125 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/gofrs/uuid"
+)
+
+func (ms *ModelSuite) Test_DriverCompliance() {
+	var driverID = uuid.Must(uuid.NewV4())
+	var expiry = nulls.NewTime(time.Date(2027, 3, 31, 18, 0, 0, 0, time.UTC))
+	var tests = []struct {
+		compliance               *DriverCompliance
+		expectedValidationErrors int
+	}{
+		{&DriverCompliance{}, 1},
+		{&DriverCompliance{DriverID: driverID}, 0},
+		{&DriverCompliance{DriverID: driverID, LicenceClass: nulls.NewString("1"), LicenceExpiry: expiry, PortPassExpiry: expiry}, 0},
+		{&DriverCompliance{DriverID: driverID, LicenceExpiry: expiry}, 1},
+		{&DriverCompliance{DriverID: driverID, LicenceClass: nulls.NewString(""), LicenceExpiry: expiry}, 1},
+	}
+	for i, test := range tests {
+		ms.T().Run(fmt.Sprint(i), func(t *testing.T) {
+			v, err := test.compliance.Validate(ms.DB)
+			ms.Nil(err)
+			ms.Equal(test.expectedValidationErrors, len(v.Errors))
+		})
+	}
+	// Credentials expire at the end of the day
+	ms.Equal(time.Date(2027, 3, 31, 0, 0, 0, 0, time.UTC), tests[2].compliance.PortPassExpiry.Time)
+}
+
+func (ms *ModelSuite) Test_DriverComplianceMissingCredentials() {
+	var day = time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	var compliance = &DriverCompliance{
+		LicenceClass:             nulls.NewString("1"),
+		LicenceExpiry:            nulls.NewTime(day.AddDate(1, 0, 0)),
+		MedicalCertificateExpiry: nulls.NewTime(day),
+		PortPassExpiry:           nulls.NewTime(day.AddDate(0, 0, -1)),
+	}
+	var tests = []struct {
+		name       string
+		compliance *DriverCompliance
+		required   DriverCredentials
+		at         time.Time
+		missing    []string
+	}{
+		{"nothing required", compliance, nil, day, []string{}},
+		{"valid", compliance, DriverCredentials{"Licence", "MedicalCertificate"}, day.Add(23 * time.Hour), []string{}},
+		{"expires at the end of the day", compliance, DriverCredentials{"MedicalCertificate"}, day.AddDate(0, 0, 1), []string{"MedicalCertificate expired on 2026-10-18"}},
+		{"expired", compliance, DriverCredentials{"Licence", "PortPass"}, day, []string{"PortPass expired on 2026-10-17"}},
+		{"missing", compliance, DriverCredentials{"Twic"}, day, []string{"No Twic on file"}},
+		{"no record", nil, DriverCredentials{"Licence"}, day, []string{"No Licence on file"}},
+	}
+	for _, test := range tests {
+		ms.T().Run(test.name, func(t *testing.T) {
+			ms.Equal(test.missing, test.compliance.MissingCredentials(test.required, test.at))
+		})
+	}
+}
+
+func (ms *ModelSuite) Test_NewComplianceExpirations() {
+	var now = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	var today = now.Truncate(24 * time.Hour)
+	var salah = User{ID: uuid.Must(uuid.NewV4()), Name: "salah"}
+	var lewin = User{ID: uuid.Must(uuid.NewV4()), Name: "lewin"}
+	var compliances = DriverCompliances{
+		{DriverID: salah.ID, Driver: &salah, LicenceExpiry: nulls.NewTime(today.AddDate(0, 0, 10)), PortPassExpiry: nulls.NewTime(today.AddDate(0, 0, -2)), TwicExpiry: nulls.NewTime(today.AddDate(1, 0, 0))},
+		{DriverID: lewin.ID, Driver: &lewin, MedicalCertificateExpiry: nulls.NewTime(today.AddDate(0, 0, 10)), TwicExpiry: nulls.NewTime(today)},
+	}
+	expirations := NewComplianceExpirations(compliances, now, today.AddDate(0, 0, 30))
+	ms.Equal(4, len(expirations))
+	ms.Equal("PortPass", expirations[0].Credential)
+	ms.True(expirations[0].Expired)
+	ms.Equal("Twic", expirations[1].Credential)
+	ms.Equal(lewin.ID, expirations[1].Driver.ID)
+	ms.False(expirations[1].Expired)
+	ms.Equal(lewin.ID, expirations[2].Driver.ID)
+	ms.Equal(salah.ID, expirations[3].Driver.ID)
+	ms.Equal(1, len(NewComplianceExpirations(compliances, now, today.AddDate(0, 0, -1))))
+}
+
+func (ms *ModelSuite) Test_DriverCredentials() {
+	var credentials = DriverCredentials{"Twic", "PortPass"}
+	ms.True(credentials.IsValid())
+	ms.False(DriverCredentials{"Passport"}.IsValid())
+	v, err := credentials.Value()
+	ms.Nil(err)
+	ms.Equal(`["Twic","PortPass"]`, v)
+	v, err = DriverCredentials{}.Value()
+	ms.Nil(err)
+	ms.Nil(v)
+	var scanned DriverCredentials
+	ms.Nil(scanned.Scan([]byte(`["Licence"]`)))
+	ms.Equal(DriverCredentials{"Licence"}, scanned)
+	ms.Nil(scanned.Scan(nil))
+	ms.Nil(scanned)
+	ms.NotNil(scanned.Scan(42))
+}
+
+func (ms *ModelSuite) Test_DriverWorkloadScoreCompliance() {
+	var at = time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+	var terminal = Terminal{ID: uuid.Must(uuid.NewV4()), Name: "Deltaport", RequiredCredentials: DriverCredentials{"PortPass"}}
+	var shipment = Shipment{ID: uuid.Must(uuid.NewV4()), TerminalID: nulls.NewUUID(terminal.ID), Terminal: &terminal, ReservationTime: nulls.NewTime(at)}
+	var tests = []struct {
+		name        string
+		workload    DriverWorkload
+		hasConflict bool
+		reasons     int
+	}{
+		{"no record", DriverWorkload{}, true, 2},
+		{"valid", DriverWorkload{Compliance: &DriverCompliance{PortPassExpiry: nulls.NewTime(at)}}, false, 1},
+		{"expires before the reservation", DriverWorkload{Compliance: &DriverCompliance{PortPassExpiry: nulls.NewTime(at.AddDate(0, 0, -1))}}, true, 2},
+	}
+	for _, test := range tests {
+		ms.T().Run(test.name, func(t *testing.T) {
+			candidate := test.workload.Score(&shipment, DefaultReservationWindow)
+			ms.Equal(test.hasConflict, candidate.HasConflict)
+			ms.Equal(test.reasons, len(candidate.Reasons))
+		})
+	}
+}
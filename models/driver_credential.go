@@ -0,0 +1,35 @@
+package models
+
+// AUTOGENERATED BY: HSM GEN
+
+// DriverCredential represents the DriverCredential enum
+type DriverCredential string
+
+const (
+	// DriverCredentialLicence represents Licence DriverCredential
+	DriverCredentialLicence DriverCredential = "Licence"
+	// DriverCredentialMedicalCertificate represents MedicalCertificate DriverCredential
+	DriverCredentialMedicalCertificate DriverCredential = "MedicalCertificate"
+	// DriverCredentialTwic represents Twic DriverCredential
+	DriverCredentialTwic DriverCredential = "Twic"
+	// DriverCredentialPortPass represents PortPass DriverCredential
+	DriverCredentialPortPass DriverCredential = "PortPass"
+)
+
+var allowedDriverCredential [4]DriverCredential = [4]DriverCredential{
+	DriverCredentialLicence,
+	DriverCredentialMedicalCertificate,
+	DriverCredentialTwic,
+	DriverCredentialPortPass,
+}
+
+// String returns the string representation of
+func (k DriverCredential) String() string {
+	return string(k)
+}
+
+// IsValidDriverCredential validates if the input is a DriverCredential
+func IsValidDriverCredential(s string) bool {
+	t := DriverCredential(s)
+	return DriverCredentialLicence == t || DriverCredentialMedicalCertificate == t || DriverCredentialTwic == t || DriverCredentialPortPass == t
+}
@@ -0,0 +1,20 @@
+package models_test
+
+// AUTOGENERATED BY: HSM GEN
+
+import (
+	"testing"
+
+	m "github.com/bigpanther/trober/models"
+)
+
+func TestIsValidDriverCredential(t *testing.T) {
+	var validVal = "Licence"
+	var inValidVal = "_someInvalidval_"
+	if !m.IsValidDriverCredential(validVal) {
+		t.Fatalf("IsValidDriverCredential(%q) should be true", validVal)
+	}
+	if m.IsValidDriverCredential(inValidVal) {
+		t.Fatalf("IsValidDriverCredential(%q) should be false", inValidVal)
+	}
+}
@@ -0,0 +1,158 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gobuffalo/validate/v3"
+	"github.com/gobuffalo/validate/v3/validators"
+	"github.com/gofrs/uuid"
+)
+
+// DefaultLocationRetentionDays is how long the GPS points of the drivers are kept when the tenant does not say
+const DefaultLocationRetentionDays = 30
+
+// maxLocationClockSkew is how far in the future a GPS point can be recorded, to allow for the clock of the device
+const maxLocationClockSkew = 5 * time.Minute
+
+// shipmentTrackedStatuses are the statuses of a shipment the GPS points of its driver are linked to
+var shipmentTrackedStatuses = []ShipmentStatus{
+	ShipmentStatusAccepted,
+	ShipmentStatusArrived,
+	ShipmentStatusLoaded,
+	ShipmentStatusInTransit,
+}
+
+// ShipmentTrackedStatuses returns the shipment statuses the GPS points of the driver are linked to, as query arguments
+func ShipmentTrackedStatuses() []interface{} {
+	var statuses = make([]interface{}, len(shipmentTrackedStatuses))
+	for i, s := range shipmentTrackedStatuses {
+		statuses[i] = s.String()
+	}
+	return statuses
+}
+
+// DriverLocation is used by pop to map your driver_locations database table to your go code.
+// It is a GPS point of a driver, linked to a shipment the driver was working on when it was recorded.
+// Accuracy is in meters, Speed in meters per second and Heading in degrees clockwise from north.
+type DriverLocation struct {
+	ID         uuid.UUID     `json:"id" db:"id"`
+	CreatedAt  time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time     `json:"updated_at" db:"updated_at"`
+	TenantID   uuid.UUID     `json:"tenant_id" db:"tenant_id"`
+	DriverID   uuid.UUID     `json:"driver_id" db:"driver_id"`
+	ShipmentID nulls.UUID    `json:"shipment_id" db:"shipment_id"`
+	RecordedAt time.Time     `json:"recorded_at" db:"recorded_at"`
+	Latitude   float64       `json:"latitude" db:"latitude"`
+	Longitude  float64       `json:"longitude" db:"longitude"`
+	Accuracy   nulls.Float64 `json:"accuracy" db:"accuracy"`
+	Speed      nulls.Float64 `json:"speed" db:"speed"`
+	Heading    nulls.Float64 `json:"heading" db:"heading"`
+	Tenant     *Tenant       `belongs_to:"tenant" json:"-"`
+	Driver     *User         `belongs_to:"user" json:"-"`
+	Shipment   *Shipment     `belongs_to:"shipment" json:"-"`
+}
+
+// DriverLocations is not required by pop and may be deleted
+type DriverLocations []DriverLocation
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (l *DriverLocation) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.UUIDIsPresent{Field: l.DriverID, Name: "DriverID"},
+		&validators.TimeIsPresent{Field: l.RecordedAt, Name: "RecordedAt"},
+		&validators.FuncValidator{Fn: func() bool {
+			return l.RecordedAt.Before(time.Now().Add(maxLocationClockSkew))
+		}, Field: l.RecordedAt.String(), Name: "RecordedAt"},
+		&validators.FuncValidator{Fn: func() bool {
+			return l.Position().IsValid()
+		}, Field: fmt.Sprintf("%v,%v", l.Latitude, l.Longitude), Name: "Latitude"},
+		&validators.FuncValidator{Fn: func() bool {
+			// Value can be null
+			return !l.Accuracy.Valid || l.Accuracy.Float64 >= 0
+		}, Field: fmt.Sprint(l.Accuracy.Float64), Name: "Accuracy"},
+		&validators.FuncValidator{Fn: func() bool {
+			// Value can be null
+			return !l.Speed.Valid || l.Speed.Float64 >= 0
+		}, Field: fmt.Sprint(l.Speed.Float64), Name: "Speed"},
+		&validators.FuncValidator{Fn: func() bool {
+			// Value can be null
+			return !l.Heading.Valid || (l.Heading.Float64 >= 0 && l.Heading.Float64 < 360)
+		}, Field: fmt.Sprint(l.Heading.Float64), Name: "Heading"},
+	), nil
+}
+
+// Position returns where the point was recorded
+func (l *DriverLocation) Position() GeoPoint {
+	return GeoPoint{Lat: l.Latitude, Lng: l.Longitude}
+}
+
+// ShipmentTrack is where a shipment is, the latest GPS point of its driver and the trail of points, oldest first
+type ShipmentTrack struct {
+	ShipmentID uuid.UUID       `json:"shipment_id"`
+	Status     string          `json:"status"`
+	Latest     *DriverLocation `json:"latest"`
+	Trail      DriverLocations `json:"trail"`
+}
+
+// LoadShipmentTrack returns the GPS points of a shipment recorded since the given time, oldest first,
+// keeping the latest ones when there are more than the limit
+func LoadShipmentTrack(tx *pop.Connection, shipment *Shipment, since time.Time, limit int) (*ShipmentTrack, error) {
+	points := DriverLocations{}
+	if err := tx.Where("shipment_id = ?", shipment.ID).Where("recorded_at >= ?", since).
+		Order("recorded_at DESC").Limit(limit).All(&points); err != nil {
+		return nil, err
+	}
+	var track = &ShipmentTrack{ShipmentID: shipment.ID, Status: shipment.Status, Trail: DriverLocations{}}
+	for i := len(points) - 1; i >= 0; i-- {
+		track.Trail = append(track.Trail, points[i])
+	}
+	if len(points) > 0 {
+		track.Latest = &points[0]
+	}
+	return track, nil
+}
+
+// LatestShipmentLocation returns the latest GPS point of a shipment recorded before the given time, nil when there is none
+func LatestShipmentLocation(tx *pop.Connection, shipmentID uuid.UUID, before time.Time) (*DriverLocation, error) {
+	points := DriverLocations{}
+	if err := tx.Where("shipment_id = ?", shipmentID).Where("recorded_at < ?", before).
+		Order("recorded_at DESC").Limit(1).All(&points); err != nil {
+		return nil, err
+	}
+	if len(points) == 0 {
+		return nil, nil
+	}
+	return &points[0], nil
+}
+
+// LatestDriverPositions returns the latest position of each driver recorded since the given time
+func LatestDriverPositions(tx *pop.Connection, since time.Time, driverIDs ...uuid.UUID) (map[uuid.UUID]GeoPoint, error) {
+	var positions = map[uuid.UUID]GeoPoint{}
+	if len(driverIDs) == 0 {
+		return positions, nil
+	}
+	var ids = make([]interface{}, len(driverIDs))
+	for i, id := range driverIDs {
+		ids[i] = id
+	}
+	points := DriverLocations{}
+	if err := tx.Where("driver_id IN (?)", ids...).Where("recorded_at >= ?", since).Order("recorded_at ASC").All(&points); err != nil {
+		return nil, err
+	}
+	// Later points replace the earlier ones
+	for _, p := range points {
+		positions[p.DriverID] = p.Position()
+	}
+	return positions, nil
+}
+
+// PruneDriverLocations deletes the GPS points older than the retention period of their tenant and returns how many
+func PruneDriverLocations(tx *pop.Connection, now time.Time) (int, error) {
+	return tx.RawQuery(`DELETE FROM driver_locations USING tenants WHERE driver_locations.tenant_id = tenants.id
+		AND driver_locations.recorded_at < ?::timestamp - make_interval(days => COALESCE(tenants.location_retention_days, ?))`,
+		now, DefaultLocationRetentionDays).ExecWithCount()
+}
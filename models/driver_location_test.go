@@ -0,0 +1,41 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/gofrs/uuid"
+)
+
+func (ms *ModelSuite) Test_DriverLocation() {
+	var driverID = uuid.Must(uuid.NewV4())
+	var now = time.Now().UTC()
+	var tests = []struct {
+		location                 *DriverLocation
+		expectedValidationErrors int
+	}{
+		{&DriverLocation{}, 2},
+		{&DriverLocation{DriverID: driverID, RecordedAt: now, Latitude: vancouver.Lat, Longitude: vancouver.Lng}, 0},
+		{&DriverLocation{DriverID: driverID, RecordedAt: now.Add(-time.Hour), Latitude: vancouver.Lat, Longitude: vancouver.Lng,
+			Accuracy: nulls.NewFloat64(8), Speed: nulls.NewFloat64(22.5), Heading: nulls.NewFloat64(359.9)}, 0},
+		{&DriverLocation{DriverID: driverID, RecordedAt: now.Add(time.Hour), Latitude: vancouver.Lat, Longitude: vancouver.Lng}, 1},
+		{&DriverLocation{DriverID: driverID, RecordedAt: now, Latitude: 91, Longitude: vancouver.Lng}, 1},
+		{&DriverLocation{DriverID: driverID, RecordedAt: now, Latitude: vancouver.Lat, Longitude: vancouver.Lng, Accuracy: nulls.NewFloat64(-1)}, 1},
+		{&DriverLocation{DriverID: driverID, RecordedAt: now, Latitude: vancouver.Lat, Longitude: vancouver.Lng, Speed: nulls.NewFloat64(-1)}, 1},
+		{&DriverLocation{DriverID: driverID, RecordedAt: now, Latitude: vancouver.Lat, Longitude: vancouver.Lng, Heading: nulls.NewFloat64(360)}, 1},
+	}
+	for i, test := range tests {
+		ms.T().Run(fmt.Sprint(i), func(t *testing.T) {
+			v, err := test.location.Validate(ms.DB)
+			ms.Nil(err)
+			ms.Equal(test.expectedValidationErrors, len(v.Errors))
+		})
+	}
+}
+
+func (ms *ModelSuite) Test_DriverLocationPosition() {
+	var l = DriverLocation{Latitude: seattle.Lat, Longitude: seattle.Lng}
+	ms.Equal(seattle, l.Position())
+}
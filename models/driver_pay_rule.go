@@ -0,0 +1,104 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gobuffalo/validate/v3"
+	"github.com/gobuffalo/validate/v3/validators"
+	"github.com/gofrs/uuid"
+)
+
+// DriverPayRule is used by pop to map your driver_pay_rules database table to your go code.
+// A rule without a driver is the default of the tenant. Rate is in minor units of the currency,
+// per move or per mile, and Percent applies to the linehaul charges of the shipment.
+type DriverPayRule struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+	CreatedBy uuid.UUID  `json:"created_by" db:"created_by"`
+	TenantID  uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	DriverID  nulls.UUID `json:"driver_id" db:"driver_id"`
+	Type      string     `json:"type" db:"type"`
+	Currency  string     `json:"currency" db:"currency"`
+	Rate      int        `json:"rate" db:"rate"`
+	Percent   float64    `json:"percent" db:"percent"`
+	Tenant    *Tenant    `belongs_to:"tenant" json:"-"`
+}
+
+// ErrMissingMiles is returned when a per mile rule pays for a shipment without recorded miles
+var ErrMissingMiles = errors.New("shipment has no miles recorded")
+
+// DriverPayRules is not required by pop and may be deleted
+type DriverPayRules []DriverPayRule
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (r *DriverPayRule) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.FuncValidator{Fn: func() bool {
+			return IsValidDriverPayRuleType(r.Type)
+		}, Field: r.Type, Name: "Type"},
+		&validators.FuncValidator{Fn: func() bool {
+			return IsValidCurrency(r.Currency)
+		}, Field: r.Currency, Name: "Currency"},
+		&validators.IntIsGreaterThan{Field: r.Rate, Name: "Rate", Compared: -1},
+		&validators.FuncValidator{Fn: func() bool {
+			return r.Percent >= 0 && r.Percent <= 100
+		}, Field: fmt.Sprint(r.Percent), Name: "Percent"},
+	), nil
+}
+
+// Exists checks if the driver, or the tenant for a default rule, already has another pay rule.
+// Each driver has at most one rule so that settlements are computed the same way every time.
+func (r *DriverPayRule) Exists(tx *pop.Connection) (bool, error) {
+	q := tx.Where("tenant_id = ?", r.TenantID).Where("id <> ?", r.ID)
+	if r.DriverID.Valid {
+		q = q.Where("driver_id = ?", r.DriverID.UUID)
+	} else {
+		q = q.Where("driver_id IS NULL")
+	}
+	return q.Exists(&DriverPayRules{})
+}
+
+// FindDriverPayRule returns the pay rule of the driver, or the default of the tenant when the driver has none.
+// It returns sql.ErrNoRows when neither exists.
+func FindDriverPayRule(tx *pop.Connection, tenantID uuid.UUID, driverID uuid.UUID) (*DriverPayRule, error) {
+	rule := &DriverPayRule{}
+	err := tx.Where("tenant_id = ?", tenantID).Where("(driver_id = ? OR driver_id IS NULL)", driverID).
+		Order("driver_id NULLS LAST").First(rule)
+	return rule, err
+}
+
+// Earning returns the settlement line paying the driver for a delivered shipment.
+// The linehaul is the share of the order charges of the shipment.
+func (r *DriverPayRule) Earning(s *Shipment, linehaul Money) (*DriverSettlementLine, error) {
+	line := &DriverSettlementLine{
+		ShipmentID: nulls.NewUUID(s.ID),
+		Type:       DriverSettlementLineTypeEarning.String(),
+	}
+	switch DriverPayRuleType(r.Type) {
+	case DriverPayRuleTypePerMove:
+		line.Amount = NewMoney(r.Rate, r.Currency)
+		line.Description = fmt.Sprintf("Move - %s", s.SerialNumber)
+	case DriverPayRuleTypePerMile:
+		if !s.Miles.Valid {
+			return nil, fmt.Errorf("%w: %s", ErrMissingMiles, s.SerialNumber)
+		}
+		line.Amount = NewMoney(r.Rate, r.Currency).Multiply(s.Miles.Int)
+		line.Description = fmt.Sprintf("%d miles - %s", s.Miles.Int, s.SerialNumber)
+	case DriverPayRuleTypePercentOfLinehaul:
+		if linehaul.Currency != "" && linehaul.Currency != r.Currency {
+			return nil, fmt.Errorf("%w: linehaul of %s is in %s and pay in %s", ErrCurrencyMismatch, s.SerialNumber, linehaul.Currency, r.Currency)
+		}
+		line.Amount = NewMoney(int(math.Round(float64(linehaul.Amount)*r.Percent/100)), r.Currency)
+		line.Description = fmt.Sprintf("%g%% of linehaul %d - %s", r.Percent, linehaul.Amount, s.SerialNumber)
+	default:
+		return nil, fmt.Errorf("invalid pay rule type %q", r.Type)
+	}
+	return line, nil
+}
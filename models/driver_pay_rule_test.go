@@ -0,0 +1,62 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/gobuffalo/nulls"
+)
+
+func (ms *ModelSuite) Test_DriverPayRule() {
+	var tests = []struct {
+		driverPayRule            *DriverPayRule
+		expectedValidationErrors int
+	}{
+		{&DriverPayRule{}, 2},
+		{&DriverPayRule{Type: DriverPayRuleTypePerMove.String(), Currency: "CAD", Rate: 5000}, 0},
+		{&DriverPayRule{Type: DriverPayRuleTypePerMile.String(), Currency: "CAD", Rate: -1}, 1},
+		{&DriverPayRule{Type: DriverPayRuleTypePercentOfLinehaul.String(), Currency: "CAD", Percent: 101}, 1},
+		{&DriverPayRule{Type: DriverPayRuleTypePercentOfLinehaul.String(), Currency: "CAD", Percent: 27.5}, 0},
+	}
+	for i, test := range tests {
+		ms.T().Run(fmt.Sprint(i), func(t *testing.T) {
+			v, err := test.driverPayRule.Validate(ms.DB)
+			ms.Nil(err)
+			ms.Equal(test.expectedValidationErrors, len(v.Errors))
+		})
+	}
+}
+
+func (ms *ModelSuite) Test_DriverPayRuleEarning() {
+	var linehaul = NewMoney(30000, "CAD")
+	var tests = []struct {
+		name     string
+		rule     DriverPayRule
+		shipment Shipment
+		linehaul Money
+		amount   int
+		err      error
+	}{
+		{"per move", DriverPayRule{Type: DriverPayRuleTypePerMove.String(), Currency: "CAD", Rate: 7500}, Shipment{SerialNumber: "S1"}, linehaul, 7500, nil},
+		{"per mile", DriverPayRule{Type: DriverPayRuleTypePerMile.String(), Currency: "CAD", Rate: 150}, Shipment{SerialNumber: "S1", Miles: nulls.NewInt(40)}, linehaul, 6000, nil},
+		{"per mile without miles", DriverPayRule{Type: DriverPayRuleTypePerMile.String(), Currency: "CAD", Rate: 150}, Shipment{SerialNumber: "S1"}, linehaul, 0, ErrMissingMiles},
+		{"percent", DriverPayRule{Type: DriverPayRuleTypePercentOfLinehaul.String(), Currency: "CAD", Percent: 25.5}, Shipment{SerialNumber: "S1"}, linehaul, 7650, nil},
+		{"percent without charges", DriverPayRule{Type: DriverPayRuleTypePercentOfLinehaul.String(), Currency: "CAD", Percent: 25.5}, Shipment{SerialNumber: "S1"}, Money{}, 0, nil},
+		{"percent other currency", DriverPayRule{Type: DriverPayRuleTypePercentOfLinehaul.String(), Currency: "USD", Percent: 25}, Shipment{SerialNumber: "S1"}, linehaul, 0, ErrCurrencyMismatch},
+	}
+	for _, test := range tests {
+		ms.T().Run(test.name, func(t *testing.T) {
+			line, err := test.rule.Earning(&test.shipment, test.linehaul)
+			if test.err != nil {
+				ms.True(errors.Is(err, test.err))
+				return
+			}
+			ms.Nil(err)
+			ms.Equal(NewMoney(test.amount, test.rule.Currency), line.Amount)
+			ms.Equal(DriverSettlementLineTypeEarning.String(), line.Type)
+			ms.Equal(nulls.NewUUID(test.shipment.ID), line.ShipmentID)
+			ms.NotEmpty(line.Description)
+		})
+	}
+}
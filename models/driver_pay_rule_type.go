@@ -0,0 +1,32 @@
+package models
+
+// AUTOGENERATED BY: HSM GEN
+
+// DriverPayRuleType represents the DriverPayRuleType enum
+type DriverPayRuleType string
+
+const (
+	// DriverPayRuleTypePerMove represents PerMove DriverPayRuleType
+	DriverPayRuleTypePerMove DriverPayRuleType = "PerMove"
+	// DriverPayRuleTypePerMile represents PerMile DriverPayRuleType
+	DriverPayRuleTypePerMile DriverPayRuleType = "PerMile"
+	// DriverPayRuleTypePercentOfLinehaul represents PercentOfLinehaul DriverPayRuleType
+	DriverPayRuleTypePercentOfLinehaul DriverPayRuleType = "PercentOfLinehaul"
+)
+
+var allowedDriverPayRuleType [3]DriverPayRuleType = [3]DriverPayRuleType{
+	DriverPayRuleTypePerMove,
+	DriverPayRuleTypePerMile,
+	DriverPayRuleTypePercentOfLinehaul,
+}
+
+// String returns the string representation of
+func (k DriverPayRuleType) String() string {
+	return string(k)
+}
+
+// IsValidDriverPayRuleType validates if the input is a DriverPayRuleType
+func IsValidDriverPayRuleType(s string) bool {
+	t := DriverPayRuleType(s)
+	return DriverPayRuleTypePerMove == t || DriverPayRuleTypePerMile == t || DriverPayRuleTypePercentOfLinehaul == t
+}
@@ -0,0 +1,20 @@
+package models_test
+
+// AUTOGENERATED BY: HSM GEN
+
+import (
+	"testing"
+
+	m "github.com/bigpanther/trober/models"
+)
+
+func TestIsValidDriverPayRuleType(t *testing.T) {
+	var validVal = "PerMove"
+	var inValidVal = "_someInvalidval_"
+	if !m.IsValidDriverPayRuleType(validVal) {
+		t.Fatalf("IsValidDriverPayRuleType(%q) should be true", validVal)
+	}
+	if m.IsValidDriverPayRuleType(inValidVal) {
+		t.Fatalf("IsValidDriverPayRuleType(%q) should be false", inValidVal)
+	}
+}
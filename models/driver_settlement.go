@@ -0,0 +1,182 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gobuffalo/validate/v3"
+	"github.com/gobuffalo/validate/v3/validators"
+	"github.com/gofrs/uuid"
+)
+
+// DriverSettlement is used by pop to map your driver_settlements database table to your go code.
+// It is the pay statement of a driver for the shipments delivered from PeriodStart until PeriodEnd, which is exclusive.
+// All amounts are in the currency of the pay rule of the driver. Deductions and advances are subtracted from the earnings.
+type DriverSettlement struct {
+	ID          uuid.UUID             `json:"id" db:"id"`
+	CreatedAt   time.Time             `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time             `json:"updated_at" db:"updated_at"`
+	CreatedBy   uuid.UUID             `json:"created_by" db:"created_by"`
+	TenantID    uuid.UUID             `json:"tenant_id" db:"tenant_id"`
+	DriverID    uuid.UUID             `json:"driver_id" db:"driver_id"`
+	PeriodStart time.Time             `json:"period_start" db:"period_start"`
+	PeriodEnd   time.Time             `json:"period_end" db:"period_end"`
+	Status      string                `json:"status" db:"status"`
+	Earnings    Money                 `json:"earnings" db:"earnings"`
+	Deductions  Money                 `json:"deductions" db:"deductions"`
+	Advances    Money                 `json:"advances" db:"advances"`
+	NetPay      Money                 `json:"net_pay" db:"net_pay"`
+	FinalizedAt nulls.Time            `json:"finalized_at" db:"finalized_at"`
+	FinalizedBy nulls.UUID            `json:"finalized_by" db:"finalized_by"`
+	Tenant      *Tenant               `belongs_to:"tenant" json:"-"`
+	Driver      *User                 `belongs_to:"user" json:"driver,omitempty"`
+	Lines       DriverSettlementLines `has_many:"driver_settlement_lines" order_by:"created_at asc" json:"lines,omitempty"`
+}
+
+// DriverSettlements is not required by pop and may be deleted
+type DriverSettlements []DriverSettlement
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (s *DriverSettlement) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.UUIDIsPresent{Field: s.DriverID, Name: "DriverID"},
+		&validators.TimeIsPresent{Field: s.PeriodStart, Name: "PeriodStart"},
+		&validators.FuncValidator{Fn: func() bool {
+			return s.PeriodEnd.After(s.PeriodStart)
+		}, Field: "PeriodEnd", Name: "PeriodEnd", Message: "%s must be after PeriodStart"},
+		&validators.FuncValidator{Fn: func() bool {
+			return IsValidDriverSettlementStatus(s.Status)
+		}, Field: s.Status, Name: "Status"},
+		&validators.FuncValidator{Fn: func() bool {
+			return IsValidCurrency(s.Currency())
+		}, Field: s.Currency(), Name: "Currency"},
+	), nil
+}
+
+// IsFinalized checks if the settlement has been approved and can no longer change
+func (s *DriverSettlement) IsFinalized() bool {
+	return s.Status == DriverSettlementStatusFinalized.String()
+}
+
+// NewDriverSettlement returns a draft settlement with zero totals in the given currency
+func NewDriverSettlement(currency string) *DriverSettlement {
+	var zero = NewMoney(0, currency)
+	return &DriverSettlement{
+		Status:     DriverSettlementStatusDraft.String(),
+		Earnings:   zero,
+		Deductions: zero,
+		Advances:   zero,
+		NetPay:     zero,
+	}
+}
+
+// Currency returns the currency the driver is paid in
+func (s *DriverSettlement) Currency() string {
+	return s.NetPay.Currency
+}
+
+// UpdateTotals computes the earnings, deductions, advances and net pay from the lines.
+// It returns ErrCurrencyMismatch when a line is not in the currency of the settlement.
+func (s *DriverSettlement) UpdateTotals() error {
+	var zero = NewMoney(0, s.Currency())
+	var earnings, deductions, advances = []Money{zero}, []Money{zero}, []Money{zero}
+	for _, l := range s.Lines {
+		switch DriverSettlementLineType(l.Type) {
+		case DriverSettlementLineTypeEarning:
+			earnings = append(earnings, l.Amount)
+		case DriverSettlementLineTypeDeduction:
+			deductions = append(deductions, l.Amount)
+		case DriverSettlementLineTypeAdvance:
+			advances = append(advances, l.Amount)
+		}
+	}
+	var err error
+	if s.Earnings, err = SumMoney(earnings...); err != nil {
+		return err
+	}
+	if s.Deductions, err = SumMoney(deductions...); err != nil {
+		return err
+	}
+	if s.Advances, err = SumMoney(advances...); err != nil {
+		return err
+	}
+	s.NetPay = NewMoney(s.Earnings.Amount-s.Deductions.Amount-s.Advances.Amount, s.Earnings.Currency)
+	return nil
+}
+
+// UnsettledDeliveries returns the shipments the driver delivered during the period, that are still delivered
+// and not paid on another settlement.
+func UnsettledDeliveries(tx *pop.Connection, tenantID uuid.UUID, driverID uuid.UUID, start time.Time, end time.Time) (Shipments, error) {
+	shipments := Shipments{}
+	err := tx.Eager("Order").Where("tenant_id = ?", tenantID).Where("status = ?", ShipmentStatusDelivered.String()).
+		Where("id IN (SELECT shipment_id FROM shipment_events WHERE to_status = ? AND driver_id = ? AND created_at >= ? AND created_at < ?)",
+			ShipmentStatusDelivered.String(), driverID, start, end).
+		Where("id NOT IN (SELECT shipment_id FROM driver_settlement_lines WHERE shipment_id IS NOT NULL)").
+		Order("serial_number ASC").All(&shipments)
+	return shipments, err
+}
+
+// ShipmentLinehaul returns the share of the pickup and dropoff charges of the order of a shipment,
+// which are split evenly between the shipments of the order. The Order of the shipment must be loaded.
+func ShipmentLinehaul(tx *pop.Connection, s *Shipment) (Money, error) {
+	if s.Order == nil {
+		return Money{}, nil
+	}
+	var charges = []Money{}
+	for _, m := range []NullMoney{s.Order.PickupCharges, s.Order.DropoffCharges} {
+		if m.Valid {
+			charges = append(charges, m.Money)
+		}
+	}
+	total, err := SumMoney(charges...)
+	if err != nil {
+		return Money{}, err
+	}
+	count, err := tx.Where("order_id = ?", s.Order.ID).Count(&Shipments{})
+	if err != nil {
+		return Money{}, err
+	}
+	if count > 1 {
+		total.Amount /= count
+	}
+	return total, nil
+}
+
+// DriverSettlementLine is used by pop to map your driver_settlement_lines database table to your go code.
+// Earnings pay for a delivered shipment, deductions and advances are added by the back office.
+type DriverSettlementLine struct {
+	ID                 uuid.UUID         `json:"id" db:"id"`
+	CreatedAt          time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time         `json:"updated_at" db:"updated_at"`
+	DriverSettlementID uuid.UUID         `json:"driver_settlement_id" db:"driver_settlement_id"`
+	ShipmentID         nulls.UUID        `json:"shipment_id" db:"shipment_id"`
+	Type               string            `json:"type" db:"type"`
+	Description        string            `json:"description" db:"description"`
+	Amount             Money             `json:"amount" db:"amount"`
+	DriverSettlement   *DriverSettlement `belongs_to:"driver_settlement" json:"-"`
+}
+
+// DriverSettlementLines is not required by pop and may be deleted
+type DriverSettlementLines []DriverSettlementLine
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (l *DriverSettlementLine) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.StringIsPresent{Field: l.Description, Name: "Description"},
+		&validators.FuncValidator{Fn: func() bool {
+			return IsValidDriverSettlementLineType(l.Type)
+		}, Field: l.Type, Name: "Type"},
+		&validators.IntIsGreaterThan{Field: l.Amount.Amount, Name: "Amount", Compared: -1},
+		&validators.FuncValidator{Fn: func() bool {
+			return IsValidCurrency(l.Amount.Currency)
+		}, Field: l.Amount.Currency, Name: "Currency"},
+	), nil
+}
+
+// IsAdjustment checks if the line was added by the back office rather than computed from a delivery
+func (l *DriverSettlementLine) IsAdjustment() bool {
+	return l.Type != DriverSettlementLineTypeEarning.String()
+}
@@ -0,0 +1,32 @@
+package models
+
+// AUTOGENERATED BY: HSM GEN
+
+// DriverSettlementLineType represents the DriverSettlementLineType enum
+type DriverSettlementLineType string
+
+const (
+	// DriverSettlementLineTypeEarning represents Earning DriverSettlementLineType
+	DriverSettlementLineTypeEarning DriverSettlementLineType = "Earning"
+	// DriverSettlementLineTypeDeduction represents Deduction DriverSettlementLineType
+	DriverSettlementLineTypeDeduction DriverSettlementLineType = "Deduction"
+	// DriverSettlementLineTypeAdvance represents Advance DriverSettlementLineType
+	DriverSettlementLineTypeAdvance DriverSettlementLineType = "Advance"
+)
+
+var allowedDriverSettlementLineType [3]DriverSettlementLineType = [3]DriverSettlementLineType{
+	DriverSettlementLineTypeEarning,
+	DriverSettlementLineTypeDeduction,
+	DriverSettlementLineTypeAdvance,
+}
+
+// String returns the string representation of
+func (k DriverSettlementLineType) String() string {
+	return string(k)
+}
+
+// IsValidDriverSettlementLineType validates if the input is a DriverSettlementLineType
+func IsValidDriverSettlementLineType(s string) bool {
+	t := DriverSettlementLineType(s)
+	return DriverSettlementLineTypeEarning == t || DriverSettlementLineTypeDeduction == t || DriverSettlementLineTypeAdvance == t
+}
@@ -0,0 +1,20 @@
+package models_test
+
+// AUTOGENERATED BY: HSM GEN
+
+import (
+	"testing"
+
+	m "github.com/bigpanther/trober/models"
+)
+
+func TestIsValidDriverSettlementLineType(t *testing.T) {
+	var validVal = "Earning"
+	var inValidVal = "_someInvalidval_"
+	if !m.IsValidDriverSettlementLineType(validVal) {
+		t.Fatalf("IsValidDriverSettlementLineType(%q) should be true", validVal)
+	}
+	if m.IsValidDriverSettlementLineType(inValidVal) {
+		t.Fatalf("IsValidDriverSettlementLineType(%q) should be false", inValidVal)
+	}
+}
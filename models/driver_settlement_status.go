@@ -0,0 +1,29 @@
+package models
+
+// AUTOGENERATED BY: HSM GEN
+
+// DriverSettlementStatus represents the DriverSettlementStatus enum
+type DriverSettlementStatus string
+
+const (
+	// DriverSettlementStatusDraft represents Draft DriverSettlementStatus
+	DriverSettlementStatusDraft DriverSettlementStatus = "Draft"
+	// DriverSettlementStatusFinalized represents Finalized DriverSettlementStatus
+	DriverSettlementStatusFinalized DriverSettlementStatus = "Finalized"
+)
+
+var allowedDriverSettlementStatus [2]DriverSettlementStatus = [2]DriverSettlementStatus{
+	DriverSettlementStatusDraft,
+	DriverSettlementStatusFinalized,
+}
+
+// String returns the string representation of
+func (k DriverSettlementStatus) String() string {
+	return string(k)
+}
+
+// IsValidDriverSettlementStatus validates if the input is a DriverSettlementStatus
+func IsValidDriverSettlementStatus(s string) bool {
+	t := DriverSettlementStatus(s)
+	return DriverSettlementStatusDraft == t || DriverSettlementStatusFinalized == t
+}
@@ -0,0 +1,20 @@
+package models_test
+
+// AUTOGENERATED BY: HSM GEN
+
+import (
+	"testing"
+
+	m "github.com/bigpanther/trober/models"
+)
+
+func TestIsValidDriverSettlementStatus(t *testing.T) {
+	var validVal = "Draft"
+	var inValidVal = "_someInvalidval_"
+	if !m.IsValidDriverSettlementStatus(validVal) {
+		t.Fatalf("IsValidDriverSettlementStatus(%q) should be true", validVal)
+	}
+	if m.IsValidDriverSettlementStatus(inValidVal) {
+		t.Fatalf("IsValidDriverSettlementStatus(%q) should be false", inValidVal)
+	}
+}
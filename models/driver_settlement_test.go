@@ -0,0 +1,75 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+func (ms *ModelSuite) Test_DriverSettlement() {
+	var driverID = uuid.Must(uuid.NewV4())
+	var start = time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	var tests = []struct {
+		driverSettlement         *DriverSettlement
+		expectedValidationErrors int
+	}{
+		{&DriverSettlement{}, 5},
+		{&DriverSettlement{DriverID: driverID, PeriodStart: start, PeriodEnd: start.AddDate(0, 0, 14), Status: DriverSettlementStatusDraft.String(), NetPay: NewMoney(0, "CAD")}, 0},
+		{&DriverSettlement{DriverID: driverID, PeriodStart: start, PeriodEnd: start, Status: DriverSettlementStatusDraft.String(), NetPay: NewMoney(0, "CAD")}, 1},
+		{&DriverSettlement{DriverID: driverID, PeriodStart: start, PeriodEnd: start.AddDate(0, 0, 14), Status: "Paid", NetPay: NewMoney(0, "CAD")}, 1},
+	}
+	for i, test := range tests {
+		ms.T().Run(fmt.Sprint(i), func(t *testing.T) {
+			v, err := test.driverSettlement.Validate(ms.DB)
+			ms.Nil(err)
+			ms.Equal(test.expectedValidationErrors, len(v.Errors))
+		})
+	}
+}
+
+func (ms *ModelSuite) Test_DriverSettlementLine() {
+	var tests = []struct {
+		line                     *DriverSettlementLine
+		expectedValidationErrors int
+		isAdjustment             bool
+	}{
+		{&DriverSettlementLine{}, 3, true},
+		{&DriverSettlementLine{Type: DriverSettlementLineTypeEarning.String(), Description: "Move - S1", Amount: NewMoney(7500, "CAD")}, 0, false},
+		{&DriverSettlementLine{Type: DriverSettlementLineTypeDeduction.String(), Description: "Fuel card", Amount: NewMoney(-1, "CAD")}, 1, true},
+		{&DriverSettlementLine{Type: DriverSettlementLineTypeAdvance.String(), Description: "Advance", Amount: NewMoney(10000, "")}, 1, true},
+		{&DriverSettlementLine{Type: DriverSettlementLineTypeAdvance.String(), Description: "Advance", Amount: NewMoney(10000, "CAD")}, 0, true},
+	}
+	for i, test := range tests {
+		ms.T().Run(fmt.Sprint(i), func(t *testing.T) {
+			v, err := test.line.Validate(ms.DB)
+			ms.Nil(err)
+			ms.Equal(test.expectedValidationErrors, len(v.Errors))
+			ms.Equal(test.isAdjustment, test.line.IsAdjustment())
+		})
+	}
+}
+
+func (ms *ModelSuite) Test_DriverSettlementUpdateTotals() {
+	var settlement = NewDriverSettlement("CAD")
+	settlement.Lines = DriverSettlementLines{
+		{Type: DriverSettlementLineTypeEarning.String(), Amount: NewMoney(7500, "CAD")},
+		{Type: DriverSettlementLineTypeEarning.String(), Amount: NewMoney(6000, "CAD")},
+		{Type: DriverSettlementLineTypeDeduction.String(), Amount: NewMoney(2500, "CAD")},
+		{Type: DriverSettlementLineTypeAdvance.String(), Amount: NewMoney(10000, "CAD")},
+	}
+	ms.Nil(settlement.UpdateTotals())
+	ms.Equal(NewMoney(13500, "CAD"), settlement.Earnings)
+	ms.Equal(NewMoney(2500, "CAD"), settlement.Deductions)
+	ms.Equal(NewMoney(10000, "CAD"), settlement.Advances)
+	ms.Equal(NewMoney(1000, "CAD"), settlement.NetPay)
+
+	settlement.Lines = append(settlement.Lines, DriverSettlementLine{Type: DriverSettlementLineTypeDeduction.String(), Amount: NewMoney(100, "USD")})
+	ms.True(errors.Is(settlement.UpdateTotals(), ErrCurrencyMismatch))
+
+	var empty = NewDriverSettlement("USD")
+	ms.Nil(empty.UpdateTotals())
+	ms.Equal(NewMoney(0, "USD"), empty.NetPay)
+}
@@ -0,0 +1,107 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gobuffalo/validate/v3"
+	"github.com/gobuffalo/validate/v3/validators"
+	"github.com/gofrs/uuid"
+)
+
+// clockFormat is the format of the times of day of shifts and appointment slots
+const clockFormat = "15:04"
+
+// DriverShift is used by pop to map your driver_shifts database table to your go code.
+// It is a recurring weekly shift of a driver, from StartTime to EndTime in the timezone of the shift.
+// A shift ending before it starts goes past midnight. Capacity limits the shipments reserved on the day.
+type DriverShift struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	CreatedBy uuid.UUID `json:"created_by" db:"created_by"`
+	TenantID  uuid.UUID `json:"tenant_id" db:"tenant_id"`
+	DriverID  uuid.UUID `json:"driver_id" db:"driver_id"`
+	Weekday   int       `json:"weekday" db:"weekday"`
+	StartTime string    `json:"start_time" db:"start_time"`
+	EndTime   string    `json:"end_time" db:"end_time"`
+	Timezone  string    `json:"timezone" db:"timezone"`
+	Capacity  nulls.Int `json:"capacity" db:"capacity"`
+	Tenant    *Tenant   `belongs_to:"tenant" json:"-"`
+}
+
+// DriverShifts is not required by pop and may be deleted
+type DriverShifts []DriverShift
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (s *DriverShift) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.UUIDIsPresent{Field: s.DriverID, Name: "DriverID"},
+		&validators.FuncValidator{Fn: func() bool {
+			return s.Weekday >= int(time.Sunday) && s.Weekday <= int(time.Saturday)
+		}, Field: fmt.Sprint(s.Weekday), Name: "Weekday"},
+		&validators.FuncValidator{Fn: func() bool {
+			_, err := time.Parse(clockFormat, s.StartTime)
+			return err == nil
+		}, Field: s.StartTime, Name: "StartTime"},
+		&validators.FuncValidator{Fn: func() bool {
+			_, err := time.Parse(clockFormat, s.EndTime)
+			return err == nil && s.EndTime != s.StartTime
+		}, Field: s.EndTime, Name: "EndTime"},
+		&validators.FuncValidator{Fn: func() bool {
+			_, err := time.LoadLocation(s.Timezone)
+			return s.Timezone != "" && err == nil
+		}, Field: s.Timezone, Name: "Timezone"},
+		// Value can be null
+		&validators.FuncValidator{Fn: func() bool {
+			return !s.Capacity.Valid || s.Capacity.Int > 0
+		}, Field: fmt.Sprint(s.Capacity.Int), Name: "Capacity"},
+	), nil
+}
+
+// Covers checks if a time falls within the shift
+func (s *DriverShift) Covers(at time.Time) bool {
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return false
+	}
+	from, err := clockMinutes(s.StartTime)
+	if err != nil {
+		return false
+	}
+	to, err := clockMinutes(s.EndTime)
+	if err != nil {
+		return false
+	}
+	local := at.In(loc)
+	minute := local.Hour()*60 + local.Minute()
+	weekday := int(local.Weekday())
+	if from < to {
+		return weekday == s.Weekday && minute >= from && minute < to
+	}
+	// The shift goes past midnight into the next day
+	return (weekday == s.Weekday && minute >= from) || (weekday == (s.Weekday+1)%7 && minute < to)
+}
+
+// Day returns the start and the end of the day of a time, in the timezone of the shift
+func (s *DriverShift) Day(at time.Time) (time.Time, time.Time) {
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	local := at.In(loc)
+	start := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	return start, start.AddDate(0, 0, 1)
+}
+
+// clockMinutes returns the minutes since midnight of a time of day
+func clockMinutes(clock string) (int, error) {
+	t, err := time.Parse(clockFormat, clock)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
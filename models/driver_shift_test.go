@@ -0,0 +1,72 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/gofrs/uuid"
+)
+
+func (ms *ModelSuite) Test_DriverShift() {
+	var driverID = uuid.Must(uuid.NewV4())
+	var tests = []struct {
+		driverShift              *DriverShift
+		expectedValidationErrors int
+	}{
+		{&DriverShift{}, 4},
+		{&DriverShift{DriverID: driverID, Weekday: 1, StartTime: "08:00", EndTime: "16:00", Timezone: "UTC"}, 0},
+		{&DriverShift{DriverID: driverID, Weekday: 5, StartTime: "22:00", EndTime: "06:00", Timezone: "America/Vancouver", Capacity: nulls.NewInt(4)}, 0},
+		{&DriverShift{DriverID: driverID, Weekday: 7, StartTime: "08:00", EndTime: "16:00", Timezone: "UTC"}, 1},
+		{&DriverShift{DriverID: driverID, Weekday: 1, StartTime: "8am", EndTime: "16:00", Timezone: "UTC"}, 1},
+		{&DriverShift{DriverID: driverID, Weekday: 1, StartTime: "08:00", EndTime: "08:00", Timezone: "UTC"}, 1},
+		{&DriverShift{DriverID: driverID, Weekday: 1, StartTime: "08:00", EndTime: "16:00", Timezone: "Mars/Olympus"}, 1},
+		{&DriverShift{DriverID: driverID, Weekday: 1, StartTime: "08:00", EndTime: "16:00", Timezone: "UTC", Capacity: nulls.NewInt(0)}, 1},
+	}
+	for i, test := range tests {
+		ms.T().Run(fmt.Sprint(i), func(t *testing.T) {
+			v, err := test.driverShift.Validate(ms.DB)
+			ms.Nil(err)
+			ms.Equal(test.expectedValidationErrors, len(v.Errors))
+		})
+	}
+}
+
+func (ms *ModelSuite) Test_DriverShiftCovers() {
+	vancouver, err := time.LoadLocation("America/Vancouver")
+	ms.Nil(err)
+	var day = DriverShift{Weekday: int(time.Monday), StartTime: "08:00", EndTime: "16:00", Timezone: "America/Vancouver"}
+	var night = DriverShift{Weekday: int(time.Friday), StartTime: "22:00", EndTime: "06:00", Timezone: "UTC"}
+	var tests = []struct {
+		name   string
+		shift  DriverShift
+		at     time.Time
+		covers bool
+	}{
+		{"start of shift", day, time.Date(2026, 10, 19, 8, 0, 0, 0, vancouver), true},
+		{"during shift", day, time.Date(2026, 10, 19, 12, 30, 0, 0, vancouver), true},
+		{"end of shift", day, time.Date(2026, 10, 19, 16, 0, 0, 0, vancouver), false},
+		{"before shift", day, time.Date(2026, 10, 19, 7, 59, 0, 0, vancouver), false},
+		{"other day", day, time.Date(2026, 10, 20, 12, 0, 0, 0, vancouver), false},
+		{"in the timezone of the shift", day, time.Date(2026, 10, 19, 20, 0, 0, 0, time.UTC), true},
+		{"overnight before midnight", night, time.Date(2026, 10, 23, 23, 0, 0, 0, time.UTC), true},
+		{"overnight after midnight", night, time.Date(2026, 10, 24, 5, 0, 0, 0, time.UTC), true},
+		{"overnight after the end", night, time.Date(2026, 10, 24, 6, 0, 0, 0, time.UTC), false},
+		{"overnight on the start day morning", night, time.Date(2026, 10, 23, 5, 0, 0, 0, time.UTC), false},
+	}
+	for _, test := range tests {
+		ms.T().Run(test.name, func(t *testing.T) {
+			ms.Equal(test.covers, test.shift.Covers(test.at))
+		})
+	}
+}
+
+func (ms *ModelSuite) Test_DriverShiftDay() {
+	vancouver, err := time.LoadLocation("America/Vancouver")
+	ms.Nil(err)
+	var shift = DriverShift{Weekday: int(time.Monday), StartTime: "08:00", EndTime: "16:00", Timezone: "America/Vancouver"}
+	start, end := shift.Day(time.Date(2026, 10, 20, 3, 0, 0, 0, time.UTC))
+	ms.True(start.Equal(time.Date(2026, 10, 19, 0, 0, 0, 0, vancouver)))
+	ms.True(end.Equal(time.Date(2026, 10, 20, 0, 0, 0, 0, vancouver)))
+}
@@ -0,0 +1,370 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gofrs/uuid"
+)
+
+// RecentRejectionsPeriod is how far back the rejections of a driver count against similar shipments
+const RecentRejectionsPeriod = 30 * 24 * time.Hour
+
+// driverPositionMaxAge is how long the latest GPS point of a driver tells where the driver is
+const driverPositionMaxAge = 30 * time.Minute
+
+// Weights of the driver suggestion score. A candidate starts with candidateBaseScore and loses points
+// for its load, its reservation conflicts, its recent rejections of similar work and its distance to the terminal.
+const (
+	candidateBaseScore         = 100
+	activeShipmentPenalty      = 10
+	reservationConflictPenalty = 50
+	similarRejectionPenalty    = 20
+	maxDistancePenalty         = 50
+)
+
+// driverActiveStatuses are the statuses of a shipment a driver is working on
+var driverActiveStatuses = []ShipmentStatus{
+	ShipmentStatusAssigned,
+	ShipmentStatusAccepted,
+	ShipmentStatusArrived,
+	ShipmentStatusLoaded,
+	ShipmentStatusInTransit,
+}
+
+// DriverWorkload is what dispatch knows about a driver when assigning a shipment
+type DriverWorkload struct {
+	Driver     User
+	Active     Shipments
+	Rejections Shipments
+	// DistanceKm is the distance of the driver to the terminal of the shipment, when the location is known.
+	// It is computed from the Position of the driver and the terminal of the shipment when not set.
+	DistanceKm   nulls.Float64
+	Position     *GeoPoint
+	Availability *DriverAvailability
+	Compliance   *DriverCompliance
+}
+
+// DriverCandidate is a driver suggested for a shipment. Candidates with conflicts cannot be auto assigned.
+type DriverCandidate struct {
+	Driver      User     `json:"driver"`
+	Score       int      `json:"score"`
+	HasConflict bool     `json:"has_conflict"`
+	Reasons     []string `json:"reasons"`
+}
+
+// DriverCandidates is a list of DriverCandidate
+type DriverCandidates []DriverCandidate
+
+// ShipmentAssignment is a driver chosen for a shipment
+type ShipmentAssignment struct {
+	Shipment  Shipment        `json:"shipment"`
+	Candidate DriverCandidate `json:"candidate"`
+}
+
+// ShipmentAssignments is a list of ShipmentAssignment
+type ShipmentAssignments []ShipmentAssignment
+
+// LoadDriverWorkloads returns the drivers of a tenant with the shipments they are working on,
+// the shipments they rejected since the given time, where they are when they sent a GPS point recently and their credentials.
+func LoadDriverWorkloads(tx *pop.Connection, tenantID uuid.UUID, since time.Time) ([]DriverWorkload, error) {
+	drivers := Users{}
+	if err := tx.Where("tenant_id = ?", tenantID).Where("role = ?", UserRoleDriver.String()).Order("name ASC").All(&drivers); err != nil {
+		return nil, err
+	}
+	var statuses = make([]interface{}, len(driverActiveStatuses))
+	for i, s := range driverActiveStatuses {
+		statuses[i] = s.String()
+	}
+	active := Shipments{}
+	if err := tx.Where("tenant_id = ?", tenantID).Where("driver_id IS NOT NULL").Where("status IN (?)", statuses...).All(&active); err != nil {
+		return nil, err
+	}
+	events := ShipmentEvents{}
+	if err := tx.Where("tenant_id = ?", tenantID).Where("to_status = ?", ShipmentStatusRejected.String()).
+		Where("driver_id IS NOT NULL").Where("created_at >= ?", since).All(&events); err != nil {
+		return nil, err
+	}
+	var rejected = map[uuid.UUID]Shipment{}
+	if len(events) > 0 {
+		var ids = make([]interface{}, len(events))
+		for i, e := range events {
+			ids[i] = e.ShipmentID
+		}
+		shipments := Shipments{}
+		if err := tx.Where("id IN (?)", ids...).All(&shipments); err != nil {
+			return nil, err
+		}
+		for _, s := range shipments {
+			rejected[s.ID] = s
+		}
+	}
+	var workloads = make([]DriverWorkload, len(drivers))
+	var index = map[uuid.UUID]int{}
+	for i, d := range drivers {
+		workloads[i] = DriverWorkload{Driver: d, Active: Shipments{}, Rejections: Shipments{}}
+		index[d.ID] = i
+	}
+	var driverIDs = make([]uuid.UUID, len(drivers))
+	for i, d := range drivers {
+		driverIDs[i] = d.ID
+	}
+	availabilities, err := LoadDriverAvailabilities(tx, time.Now(), driverIDs...)
+	if err != nil {
+		return nil, err
+	}
+	positions, err := LatestDriverPositions(tx, time.Now().Add(-driverPositionMaxAge), driverIDs...)
+	if err != nil {
+		return nil, err
+	}
+	compliances, err := LoadDriverCompliances(tx, driverIDs...)
+	if err != nil {
+		return nil, err
+	}
+	for i := range workloads {
+		workloads[i].Availability = availabilities[workloads[i].Driver.ID]
+		workloads[i].Compliance = compliances[workloads[i].Driver.ID]
+		if p, ok := positions[workloads[i].Driver.ID]; ok {
+			workloads[i].Position = &p
+		}
+	}
+	for _, s := range active {
+		if i, ok := index[s.DriverID.UUID]; ok {
+			workloads[i].Active = append(workloads[i].Active, s)
+		}
+	}
+	for _, e := range events {
+		if i, ok := index[e.DriverID.UUID]; ok {
+			if s, ok := rejected[e.ShipmentID]; ok {
+				workloads[i].Rejections = append(workloads[i].Rejections, s)
+			}
+		}
+	}
+	return workloads, nil
+}
+
+// Score rates the driver for a shipment, the higher the better, with the reasons behind the score.
+// A reservation keeps the driver busy for the window. A driver without the credentials of the terminal has a conflict.
+func (w *DriverWorkload) Score(s *Shipment, window time.Duration) DriverCandidate {
+	var candidate = DriverCandidate{Driver: w.Driver, Score: candidateBaseScore, Reasons: []string{}}
+	var load int
+	for _, a := range w.Active {
+		if a.ID == s.ID {
+			continue
+		}
+		load++
+		if reservationsOverlap(&a, s, window) {
+			candidate.HasConflict = true
+			candidate.Score -= reservationConflictPenalty
+			candidate.Reasons = append(candidate.Reasons, fmt.Sprintf("Reservation overlaps %s", a.SerialNumber))
+		}
+	}
+	candidate.Score -= load * activeShipmentPenalty
+	candidate.Reasons = append(candidate.Reasons, fmt.Sprintf("%d active shipments", load))
+	var rejections int
+	for _, r := range w.Rejections {
+		if r.ID == s.ID || isSimilarShipment(&r, s) {
+			rejections++
+		}
+	}
+	if rejections > 0 {
+		candidate.Score -= rejections * similarRejectionPenalty
+		candidate.Reasons = append(candidate.Reasons, fmt.Sprintf("Rejected %d similar shipments recently", rejections))
+	}
+	if s.ReservationTime.Valid {
+		// Counting in memory never fails
+		unavailable, _ := w.Availability.Check(s.ReservationTime.Time, func(start time.Time, end time.Time) (int, error) {
+			return countReservations(w.Active, start, end, s.ID), nil
+		})
+		if len(unavailable) > 0 {
+			candidate.HasConflict = true
+			candidate.Score -= reservationConflictPenalty
+			candidate.Reasons = append(candidate.Reasons, unavailable...)
+		}
+	}
+	if s.Terminal != nil {
+		if missing := w.Compliance.MissingCredentials(s.Terminal.RequiredCredentials, s.ComplianceTime(time.Now().UTC())); len(missing) > 0 {
+			candidate.HasConflict = true
+			candidate.Score -= reservationConflictPenalty
+			candidate.Reasons = append(candidate.Reasons, missing...)
+		}
+	}
+	if distance := w.distanceTo(s); distance.Valid {
+		candidate.Score -= int(math.Min(math.Round(distance.Float64), maxDistancePenalty))
+		candidate.Reasons = append(candidate.Reasons, fmt.Sprintf("%.1f km from the terminal", distance.Float64))
+	}
+	return candidate
+}
+
+// distanceTo returns the distance of the driver to the terminal of a shipment, null when either position is unknown.
+// The terminal of the shipment must be loaded.
+func (w *DriverWorkload) distanceTo(s *Shipment) nulls.Float64 {
+	if w.DistanceKm.Valid || w.Position == nil || s.Terminal == nil {
+		return w.DistanceKm
+	}
+	terminal, ok := s.Terminal.Position()
+	if !ok {
+		return nulls.Float64{}
+	}
+	return nulls.NewFloat64(DistanceKm(*w.Position, terminal))
+}
+
+// RankDrivers returns the drivers for a shipment from the best to the worst
+func RankDrivers(s *Shipment, workloads []DriverWorkload, window time.Duration) DriverCandidates {
+	var candidates = DriverCandidates{}
+	for i := range workloads {
+		candidates = append(candidates, workloads[i].Score(s, window))
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].HasConflict != candidates[j].HasConflict {
+			return !candidates[i].HasConflict
+		}
+		return candidates[i].Score > candidates[j].Score
+	})
+	return candidates
+}
+
+// AssignShipments chooses a driver for each shipment. Every round gives each driver at most one shipment,
+// maximizing the total score of the round, then the workloads are updated for the next round.
+// Drivers with a conflict are never chosen, the shipments nobody can take are returned unassigned.
+func AssignShipments(shipments Shipments, workloads []DriverWorkload, window time.Duration) (ShipmentAssignments, Shipments) {
+	var remaining = append(Shipments{}, shipments...)
+	sortByReservationTime(remaining)
+	var assignments = ShipmentAssignments{}
+	for len(remaining) > 0 && len(workloads) > 0 {
+		var candidates = make([][]DriverCandidate, len(remaining))
+		var cost = make([][]int, len(remaining))
+		for i := range remaining {
+			candidates[i] = make([]DriverCandidate, len(workloads))
+			cost[i] = make([]int, len(workloads))
+			for j := range workloads {
+				candidates[i][j] = workloads[j].Score(&remaining[i], window)
+				cost[i][j] = -candidates[i][j].Score
+				if candidates[i][j].HasConflict {
+					cost[i][j] = unassignableCost
+				}
+			}
+		}
+		var next = Shipments{}
+		var assigned bool
+		for i, j := range minCostAssignment(cost) {
+			if j < 0 || candidates[i][j].HasConflict {
+				next = append(next, remaining[i])
+				continue
+			}
+			assigned = true
+			s := remaining[i]
+			s.DriverID = nulls.NewUUID(workloads[j].Driver.ID)
+			assignments = append(assignments, ShipmentAssignment{Shipment: s, Candidate: candidates[i][j]})
+			workloads[j].Active = append(workloads[j].Active, s)
+		}
+		remaining = next
+		if !assigned {
+			break
+		}
+	}
+	return assignments, remaining
+}
+
+// reservationsOverlap checks if two reservations are less than the window apart
+func reservationsOverlap(a *Shipment, b *Shipment, window time.Duration) bool {
+	if !a.ReservationTime.Valid || !b.ReservationTime.Valid {
+		return false
+	}
+	d := a.ReservationTime.Time.Sub(b.ReservationTime.Time)
+	return d < window && d > -window
+}
+
+// isSimilarShipment checks if two shipments are the same kind of work, from the same terminal
+// when both have one, or else of the same type and size
+func isSimilarShipment(a *Shipment, b *Shipment) bool {
+	if a.TerminalID.Valid && b.TerminalID.Valid {
+		return a.TerminalID.UUID == b.TerminalID.UUID
+	}
+	return a.Type == b.Type && a.Size == b.Size
+}
+
+// unassignableCost is the cost of a pair that must not be matched
+const unassignableCost = math.MaxInt32
+
+// minCostAssignment solves the assignment problem with the Hungarian algorithm. It returns the column
+// matched to each row of the cost matrix, or -1 when there are more rows than columns.
+func minCostAssignment(cost [][]int) []int {
+	rows := len(cost)
+	if rows == 0 {
+		return []int{}
+	}
+	cols := len(cost[0])
+	n := rows
+	if cols > n {
+		n = cols
+	}
+	// Square matrix padded with zeros, 1-indexed as in the classic formulation
+	var a = make([][]int, n+1)
+	for i := range a {
+		a[i] = make([]int, n+1)
+	}
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			a[i+1][j+1] = cost[i][j]
+		}
+	}
+	const inf = math.MaxInt64
+	u, v := make([]int, n+1), make([]int, n+1)
+	p, way := make([]int, n+1), make([]int, n+1)
+	for i := 1; i <= n; i++ {
+		p[0] = i
+		j0 := 0
+		minv := make([]int, n+1)
+		used := make([]bool, n+1)
+		for j := range minv {
+			minv[j] = inf
+		}
+		for {
+			used[j0] = true
+			i0, delta, j1 := p[j0], inf, 0
+			for j := 1; j <= n; j++ {
+				if used[j] {
+					continue
+				}
+				if cur := a[i0][j] - u[i0] - v[j]; cur < minv[j] {
+					minv[j], way[j] = cur, j0
+				}
+				if minv[j] < delta {
+					delta, j1 = minv[j], j
+				}
+			}
+			for j := 0; j <= n; j++ {
+				if used[j] {
+					u[p[j]] += delta
+					v[j] -= delta
+				} else {
+					minv[j] -= delta
+				}
+			}
+			j0 = j1
+			if p[j0] == 0 {
+				break
+			}
+		}
+		for j0 != 0 {
+			j1 := way[j0]
+			p[j0] = p[j1]
+			j0 = j1
+		}
+	}
+	var result = make([]int, rows)
+	for i := range result {
+		result[i] = -1
+	}
+	for j := 1; j <= n; j++ {
+		if p[j] > 0 && p[j] <= rows && j <= cols {
+			result[p[j]-1] = j - 1
+		}
+	}
+	return result
+}
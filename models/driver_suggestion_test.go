@@ -0,0 +1,136 @@
+package models
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/gofrs/uuid"
+)
+
+func (ms *ModelSuite) Test_DriverWorkloadScore() {
+	var at = time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+	var terminalID = nulls.NewUUID(uuid.Must(uuid.NewV4()))
+	var shipment = Shipment{ID: uuid.Must(uuid.NewV4()), TerminalID: terminalID, ReservationTime: nulls.NewTime(at)}
+	var busy = Shipment{ID: uuid.Must(uuid.NewV4()), SerialNumber: "BUSY1", ReservationTime: nulls.NewTime(at.Add(time.Hour))}
+	var later = Shipment{ID: uuid.Must(uuid.NewV4()), SerialNumber: "LATER1", ReservationTime: nulls.NewTime(at.Add(5 * time.Hour))}
+	var similar = Shipment{ID: uuid.Must(uuid.NewV4()), TerminalID: terminalID}
+	var other = Shipment{ID: uuid.Must(uuid.NewV4()), TerminalID: nulls.NewUUID(uuid.Must(uuid.NewV4()))}
+	var tests = []struct {
+		name        string
+		workload    DriverWorkload
+		score       int
+		hasConflict bool
+		reasons     int
+	}{
+		{"free", DriverWorkload{}, 100, false, 1},
+		{"loaded", DriverWorkload{Active: Shipments{later, later}}, 80, false, 1},
+		{"conflict", DriverWorkload{Active: Shipments{busy}}, 40, true, 2},
+		{"rejected similar", DriverWorkload{Rejections: Shipments{similar, other}}, 80, false, 2},
+		{"rejected this one", DriverWorkload{Rejections: Shipments{shipment}}, 80, false, 2},
+		{"far", DriverWorkload{DistanceKm: nulls.NewFloat64(12.4)}, 88, false, 2},
+		{"very far", DriverWorkload{DistanceKm: nulls.NewFloat64(400)}, 50, false, 2},
+		{"position without terminal", DriverWorkload{Position: &GeoPoint{Lat: 49.1, Lng: -123.1}}, 100, false, 1},
+	}
+	for _, test := range tests {
+		ms.T().Run(test.name, func(t *testing.T) {
+			candidate := test.workload.Score(&shipment, DefaultReservationWindow)
+			ms.Equal(test.score, candidate.Score)
+			ms.Equal(test.hasConflict, candidate.HasConflict)
+			ms.Equal(test.reasons, len(candidate.Reasons))
+		})
+	}
+}
+
+func (ms *ModelSuite) Test_RankDrivers() {
+	var at = time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+	var shipment = Shipment{ID: uuid.Must(uuid.NewV4()), ReservationTime: nulls.NewTime(at)}
+	var salah = User{ID: uuid.Must(uuid.NewV4()), Name: "salah"}
+	var mane = User{ID: uuid.Must(uuid.NewV4()), Name: "mane"}
+	var firmino = User{ID: uuid.Must(uuid.NewV4()), Name: "firmino"}
+	var workloads = []DriverWorkload{
+		{Driver: salah, Active: Shipments{{ID: uuid.Must(uuid.NewV4()), ReservationTime: nulls.NewTime(at)}}},
+		{Driver: mane, Active: Shipments{{ID: uuid.Must(uuid.NewV4())}, {ID: uuid.Must(uuid.NewV4())}, {ID: uuid.Must(uuid.NewV4())}, {ID: uuid.Must(uuid.NewV4())}, {ID: uuid.Must(uuid.NewV4())}, {ID: uuid.Must(uuid.NewV4())}}},
+		{Driver: firmino, Active: Shipments{{ID: uuid.Must(uuid.NewV4())}}},
+	}
+	candidates := RankDrivers(&shipment, workloads, DefaultReservationWindow)
+	ms.Equal(3, len(candidates))
+	ms.Equal(firmino.ID, candidates[0].Driver.ID)
+	ms.Equal(mane.ID, candidates[1].Driver.ID)
+	// A conflict ranks last even with a better score
+	ms.Equal(salah.ID, candidates[2].Driver.ID)
+	ms.True(candidates[2].HasConflict)
+}
+
+func (ms *ModelSuite) Test_AssignShipments() {
+	var at = time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+	var terminalA = nulls.NewUUID(uuid.Must(uuid.NewV4()))
+	var terminalB = nulls.NewUUID(uuid.Must(uuid.NewV4()))
+	var salah = User{ID: uuid.Must(uuid.NewV4()), Name: "salah"}
+	var mane = User{ID: uuid.Must(uuid.NewV4()), Name: "mane"}
+	var shipments = Shipments{
+		{ID: uuid.Must(uuid.NewV4()), SerialNumber: "A1", TerminalID: terminalA, ReservationTime: nulls.NewTime(at)},
+		{ID: uuid.Must(uuid.NewV4()), SerialNumber: "B1", TerminalID: terminalB, ReservationTime: nulls.NewTime(at)},
+		{ID: uuid.Must(uuid.NewV4()), SerialNumber: "A2", TerminalID: terminalA, ReservationTime: nulls.NewTime(at.Add(30 * time.Minute))},
+		{ID: uuid.Must(uuid.NewV4()), SerialNumber: "B2", TerminalID: terminalB, ReservationTime: nulls.NewTime(at.Add(4 * time.Hour))},
+	}
+	var workloads = []DriverWorkload{
+		// Salah does not like terminal A
+		{Driver: salah, Rejections: Shipments{{ID: uuid.Must(uuid.NewV4()), TerminalID: terminalA}, {ID: uuid.Must(uuid.NewV4()), TerminalID: terminalA}}},
+		{Driver: mane},
+	}
+	assignments, unassigned := AssignShipments(shipments, workloads, DefaultReservationWindow)
+	var drivers = map[string]uuid.UUID{}
+	for _, a := range assignments {
+		ms.Equal(a.Candidate.Driver.ID, a.Shipment.DriverID.UUID)
+		drivers[a.Shipment.SerialNumber] = a.Candidate.Driver.ID
+	}
+	ms.Equal(3, len(drivers))
+	ms.Equal(mane.ID, drivers["A1"])
+	ms.Equal(salah.ID, drivers["B1"])
+	ms.Contains(drivers, "B2")
+	// Both drivers are busy when A2 is reserved
+	ms.Equal(1, len(unassigned))
+	ms.Equal("A2", unassigned[0].SerialNumber)
+	ms.Equal(3, len(workloads[0].Active)+len(workloads[1].Active))
+}
+
+func (ms *ModelSuite) Test_MinCostAssignment() {
+	var tests = []struct {
+		cost     [][]int
+		expected []int
+	}{
+		{[][]int{}, []int{}},
+		{[][]int{{4, 1, 3}, {2, 0, 5}, {3, 2, 2}}, []int{1, 0, 2}},
+		{[][]int{{-10, -20}, {-30, -25}}, []int{1, 0}},
+		{[][]int{{5, 1, 9}}, []int{1}},
+		{[][]int{{5}, {1}, {3}}, []int{-1, 0, -1}},
+	}
+	for _, test := range tests {
+		ms.Equal(test.expected, minCostAssignment(test.cost))
+	}
+}
+
+func (ms *ModelSuite) Test_DriverWorkloadScorePosition() {
+	var terminal = Terminal{ID: uuid.Must(uuid.NewV4()), Latitude: nulls.NewFloat64(deltaport.Lat), Longitude: nulls.NewFloat64(deltaport.Lng)}
+	var shipment = Shipment{ID: uuid.Must(uuid.NewV4()), TerminalID: nulls.NewUUID(terminal.ID), Terminal: &terminal}
+	var tests = []struct {
+		name     string
+		workload DriverWorkload
+		score    int
+		reasons  int
+	}{
+		{"unknown position", DriverWorkload{}, 100, 1},
+		{"at the terminal", DriverWorkload{Position: &deltaport}, 100, 2},
+		{"in vancouver", DriverWorkload{Position: &vancouver}, 100 - int(math.Round(DistanceKm(vancouver, deltaport))), 2},
+		{"distance wins", DriverWorkload{Position: &vancouver, DistanceKm: nulls.NewFloat64(3)}, 97, 2},
+	}
+	for _, test := range tests {
+		ms.T().Run(test.name, func(t *testing.T) {
+			candidate := test.workload.Score(&shipment, DefaultReservationWindow)
+			ms.Equal(test.score, candidate.Score)
+			ms.Equal(test.reasons, len(candidate.Reasons))
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gobuffalo/validate/v3"
+	"github.com/gobuffalo/validate/v3/validators"
+	"github.com/gofrs/uuid"
+)
+
+// ErrChassisInUse is returned when a chassis is already on another active shipment
+var ErrChassisInUse = errors.New("chassis is in use")
+
+// Equipment is used by pop to map your equipment database table to your go code.
+// It is a tractor, a chassis or a genset of the tenant. UnitNumber identifies it within its type,
+// Owner says if the tenant owns it, takes it from a pool or leases it.
+type Equipment struct {
+	ID         uuid.UUID    `json:"id" db:"id"`
+	CreatedAt  time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time    `json:"updated_at" db:"updated_at"`
+	CreatedBy  uuid.UUID    `json:"created_by" db:"created_by"`
+	TenantID   uuid.UUID    `json:"tenant_id" db:"tenant_id"`
+	Type       string       `json:"type" db:"type"`
+	UnitNumber string       `json:"unit_number" db:"unit_number"`
+	Plate      nulls.String `json:"plate" db:"plate"`
+	Owner      string       `json:"owner" db:"owner"`
+	Status     string       `json:"status" db:"status"`
+	Tenant     *Tenant      `belongs_to:"tenant" json:"-"`
+}
+
+// EquipmentList is not required by pop and may be deleted
+type EquipmentList []Equipment
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (e *Equipment) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	if e.Status == "" {
+		e.Status = EquipmentStatusActive.String()
+	}
+	return validate.Validate(
+		&validators.StringIsPresent{Field: e.UnitNumber, Name: "UnitNumber"},
+		&validators.FuncValidator{Fn: func() bool {
+			return IsValidEquipmentType(e.Type)
+		}, Field: e.Type, Name: "Type"},
+		&validators.FuncValidator{Fn: func() bool {
+			return IsValidEquipmentOwner(e.Owner)
+		}, Field: e.Owner, Name: "Owner"},
+		&validators.FuncValidator{Fn: func() bool {
+			return IsValidEquipmentStatus(e.Status)
+		}, Field: e.Status, Name: "Status"},
+	), nil
+}
+
+// CountChassisShipments locks the chassis and returns how many active shipments other than the given one are on it
+func CountChassisShipments(tx *pop.Connection, chassisID uuid.UUID, excludeID uuid.UUID) (int, error) {
+	if err := tx.RawQuery("SELECT id FROM equipment WHERE id = ? FOR UPDATE", chassisID).Exec(); err != nil {
+		return 0, err
+	}
+	var statuses = make([]interface{}, len(driverActiveStatuses))
+	for i, s := range driverActiveStatuses {
+		statuses[i] = s.String()
+	}
+	return tx.Where("chassis_id = ?", chassisID).Where("id <> ?", excludeID).Where("status IN (?)", statuses...).Count(&Shipments{})
+}
+
+// IsActive checks if the shipment is being worked on, the equipment on it is in use
+func (c *Shipment) IsActive() bool {
+	for _, s := range driverActiveStatuses {
+		if c.Status == s.String() {
+			return true
+		}
+	}
+	return false
+}
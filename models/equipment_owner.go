@@ -0,0 +1,32 @@
+package models
+
+// AUTOGENERATED BY: HSM GEN
+
+// EquipmentOwner represents the EquipmentOwner enum
+type EquipmentOwner string
+
+const (
+	// EquipmentOwnerOwn represents Own EquipmentOwner
+	EquipmentOwnerOwn EquipmentOwner = "Own"
+	// EquipmentOwnerPool represents Pool EquipmentOwner
+	EquipmentOwnerPool EquipmentOwner = "Pool"
+	// EquipmentOwnerLeased represents Leased EquipmentOwner
+	EquipmentOwnerLeased EquipmentOwner = "Leased"
+)
+
+var allowedEquipmentOwner [3]EquipmentOwner = [3]EquipmentOwner{
+	EquipmentOwnerOwn,
+	EquipmentOwnerPool,
+	EquipmentOwnerLeased,
+}
+
+// String returns the string representation of
+func (o EquipmentOwner) String() string {
+	return string(o)
+}
+
+// IsValidEquipmentOwner validates if the input is a EquipmentOwner
+func IsValidEquipmentOwner(s string) bool {
+	t := EquipmentOwner(s)
+	return EquipmentOwnerOwn == t || EquipmentOwnerPool == t || EquipmentOwnerLeased == t
+}
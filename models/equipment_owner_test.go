@@ -0,0 +1,20 @@
+package models_test
+
+// AUTOGENERATED BY: HSM GEN
+
+import (
+	"testing"
+
+	m "github.com/bigpanther/trober/models"
+)
+
+func TestIsValidEquipmentOwner(t *testing.T) {
+	var validVal = "Leased"
+	var inValidVal = "_someInvalidval_"
+	if !m.IsValidEquipmentOwner(validVal) {
+		t.Fatalf("IsValidEquipmentOwner(%q) should be true", validVal)
+	}
+	if m.IsValidEquipmentOwner(inValidVal) {
+		t.Fatalf("IsValidEquipmentOwner(%q) should be false", inValidVal)
+	}
+}
@@ -0,0 +1,32 @@
+package models
+
+// AUTOGENERATED BY: HSM GEN
+
+// EquipmentStatus represents the EquipmentStatus enum
+type EquipmentStatus string
+
+const (
+	// EquipmentStatusActive represents Active EquipmentStatus
+	EquipmentStatusActive EquipmentStatus = "Active"
+	// EquipmentStatusOutOfService represents OutOfService EquipmentStatus
+	EquipmentStatusOutOfService EquipmentStatus = "OutOfService"
+	// EquipmentStatusRetired represents Retired EquipmentStatus
+	EquipmentStatusRetired EquipmentStatus = "Retired"
+)
+
+var allowedEquipmentStatus [3]EquipmentStatus = [3]EquipmentStatus{
+	EquipmentStatusActive,
+	EquipmentStatusOutOfService,
+	EquipmentStatusRetired,
+}
+
+// String returns the string representation of
+func (s EquipmentStatus) String() string {
+	return string(s)
+}
+
+// IsValidEquipmentStatus validates if the input is a EquipmentStatus
+func IsValidEquipmentStatus(s string) bool {
+	t := EquipmentStatus(s)
+	return EquipmentStatusActive == t || EquipmentStatusOutOfService == t || EquipmentStatusRetired == t
+}
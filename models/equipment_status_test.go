@@ -0,0 +1,20 @@
+package models_test
+
+// AUTOGENERATED BY: HSM GEN
+
+import (
+	"testing"
+
+	m "github.com/bigpanther/trober/models"
+)
+
+func TestIsValidEquipmentStatus(t *testing.T) {
+	var validVal = "Retired"
+	var inValidVal = "_someInvalidval_"
+	if !m.IsValidEquipmentStatus(validVal) {
+		t.Fatalf("IsValidEquipmentStatus(%q) should be true", validVal)
+	}
+	if m.IsValidEquipmentStatus(inValidVal) {
+		t.Fatalf("IsValidEquipmentStatus(%q) should be false", inValidVal)
+	}
+}
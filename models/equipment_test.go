@@ -0,0 +1,55 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gobuffalo/nulls"
+)
+
+func (ms *ModelSuite) Test_Equipment() {
+	var tests = []struct {
+		equipment                *Equipment
+		expectedValidationErrors int
+	}{
+		{&Equipment{}, 3},
+		{&Equipment{Type: "Tractor", UnitNumber: "T100", Owner: "Own"}, 0},
+		{&Equipment{Type: "Chassis", UnitNumber: "C200", Owner: "Pool", Plate: nulls.NewString("ABC123"), Status: "OutOfService"}, 0},
+		{&Equipment{Type: "Trailer", UnitNumber: "C200", Owner: "Pool"}, 1},
+		{&Equipment{Type: "Chassis", UnitNumber: "C200", Owner: "Rented"}, 1},
+		{&Equipment{Type: "Chassis", UnitNumber: "C200", Owner: "Leased", Status: "Broken"}, 1},
+	}
+	for i, test := range tests {
+		ms.T().Run(fmt.Sprint(i), func(t *testing.T) {
+			v, err := test.equipment.Validate(ms.DB)
+			ms.Nil(err)
+			ms.Equal(test.expectedValidationErrors, len(v.Errors))
+		})
+	}
+	var equipment = &Equipment{Type: "Genset", UnitNumber: "G1", Owner: "Own"}
+	_, err := equipment.Validate(ms.DB)
+	ms.Nil(err)
+	ms.Equal(EquipmentStatusActive.String(), equipment.Status)
+}
+
+func (ms *ModelSuite) Test_ShipmentIsActive() {
+	var tests = []struct {
+		status ShipmentStatus
+		active bool
+	}{
+		{ShipmentStatusUnassigned, false},
+		{ShipmentStatusAssigned, true},
+		{ShipmentStatusAccepted, true},
+		{ShipmentStatusArrived, true},
+		{ShipmentStatusLoaded, true},
+		{ShipmentStatusInTransit, true},
+		{ShipmentStatusDelivered, false},
+		{ShipmentStatusRejected, false},
+	}
+	for _, test := range tests {
+		ms.T().Run(test.status.String(), func(t *testing.T) {
+			var shipment = &Shipment{Status: test.status.String()}
+			ms.Equal(test.active, shipment.IsActive())
+		})
+	}
+}
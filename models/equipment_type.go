@@ -0,0 +1,32 @@
+package models
+
+// AUTOGENERATED BY: HSM GEN
+
+// EquipmentType represents the EquipmentType enum
+type EquipmentType string
+
+const (
+	// EquipmentTypeTractor represents Tractor EquipmentType
+	EquipmentTypeTractor EquipmentType = "Tractor"
+	// EquipmentTypeChassis represents Chassis EquipmentType
+	EquipmentTypeChassis EquipmentType = "Chassis"
+	// EquipmentTypeGenset represents Genset EquipmentType
+	EquipmentTypeGenset EquipmentType = "Genset"
+)
+
+var allowedEquipmentType [3]EquipmentType = [3]EquipmentType{
+	EquipmentTypeTractor,
+	EquipmentTypeChassis,
+	EquipmentTypeGenset,
+}
+
+// String returns the string representation of
+func (t EquipmentType) String() string {
+	return string(t)
+}
+
+// IsValidEquipmentType validates if the input is a EquipmentType
+func IsValidEquipmentType(s string) bool {
+	t := EquipmentType(s)
+	return EquipmentTypeTractor == t || EquipmentTypeChassis == t || EquipmentTypeGenset == t
+}
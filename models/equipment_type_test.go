@@ -0,0 +1,20 @@
+package models_test
+
+// AUTOGENERATED BY: HSM GEN
+
+import (
+	"testing"
+
+	m "github.com/bigpanther/trober/models"
+)
+
+func TestIsValidEquipmentType(t *testing.T) {
+	var validVal = "Genset"
+	var inValidVal = "_someInvalidval_"
+	if !m.IsValidEquipmentType(validVal) {
+		t.Fatalf("IsValidEquipmentType(%q) should be true", validVal)
+	}
+	if m.IsValidEquipmentType(inValidVal) {
+		t.Fatalf("IsValidEquipmentType(%q) should be false", inValidVal)
+	}
+}
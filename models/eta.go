@@ -0,0 +1,155 @@
+package models
+
+import (
+	"math"
+	"time"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gofrs/uuid"
+)
+
+// DefaultLaneSpeedKmh is the average speed of a lane without history, in straight line kilometers per hour.
+// Speeds are measured along the straight line between the places, so they account for the detours of the roads.
+const DefaultLaneSpeedKmh = 40.0
+
+// maxLaneTrips is how many of the latest delivered shipments of a lane its average speed is derived from
+const maxLaneTrips = 50
+
+// LaneTrip is a past trip of a lane, the straight line distance of the lane and the time from InTransit to Delivered
+type LaneTrip struct {
+	DistanceKm float64
+	Duration   time.Duration
+}
+
+// LaneSpeedKmh returns the average speed of the past trips of a lane, DefaultLaneSpeedKmh when there are none
+func LaneSpeedKmh(trips []LaneTrip) float64 {
+	var distance, hours float64
+	for _, t := range trips {
+		if t.DistanceKm <= 0 || t.Duration <= 0 {
+			continue
+		}
+		distance += t.DistanceKm
+		hours += t.Duration.Hours()
+	}
+	if hours == 0 {
+		return DefaultLaneSpeedKmh
+	}
+	return distance / hours
+}
+
+// PredictEta returns when a shipment at a position at the given time reaches its destination at the given speed
+func PredictEta(at time.Time, from GeoPoint, to GeoPoint, speedKmh float64) time.Time {
+	if speedKmh <= 0 {
+		speedKmh = DefaultLaneSpeedKmh
+	}
+	var hours = DistanceKm(from, to) / speedKmh
+	return at.Add(time.Duration(math.Round(hours * float64(time.Hour))))
+}
+
+// EtaSlipped checks if the shipment is predicted to arrive later than its reservation time by more than the threshold
+func (c *Shipment) EtaSlipped(threshold time.Duration) bool {
+	return c.Eta.Valid && c.ReservationTime.Valid && c.Eta.Time.Sub(c.ReservationTime.Time) > threshold
+}
+
+// ShipmentRoute is where a shipment goes from and to, nil when the place has no coordinates.
+// An inbound shipment goes from its terminal, an outbound one to it, unless the shipment references a location.
+type ShipmentRoute struct {
+	Origin      *GeoPoint
+	Destination *GeoPoint
+}
+
+// DistanceKm returns the straight line distance of the route, zero when a place is unknown
+func (r ShipmentRoute) DistanceKm() float64 {
+	if r.Origin == nil || r.Destination == nil {
+		return 0
+	}
+	return DistanceKm(*r.Origin, *r.Destination)
+}
+
+// LoadShipmentRoute returns the positions of the places a shipment goes from and to
+func LoadShipmentRoute(tx *pop.Connection, s *Shipment) (ShipmentRoute, error) {
+	var route ShipmentRoute
+	var terminal *GeoPoint
+	if s.TerminalID.Valid {
+		t := &Terminal{}
+		if err := tx.Find(t, s.TerminalID.UUID); err != nil {
+			return route, err
+		}
+		if p, ok := t.Position(); ok {
+			terminal = &p
+		}
+	}
+	var locationPosition = func(ID uuid.UUID) (*GeoPoint, error) {
+		l := &Location{}
+		if err := tx.Find(l, ID); err != nil {
+			return nil, err
+		}
+		if p, ok := l.Position(); ok {
+			return &p, nil
+		}
+		return nil, nil
+	}
+	var err error
+	if s.OriginLocationID.Valid {
+		if route.Origin, err = locationPosition(s.OriginLocationID.UUID); err != nil {
+			return route, err
+		}
+	} else if s.Type == ShipmentTypeInbound.String() {
+		route.Origin = terminal
+	}
+	if s.DestinationLocationID.Valid {
+		if route.Destination, err = locationPosition(s.DestinationLocationID.UUID); err != nil {
+			return route, err
+		}
+	} else if s.Type == Outbound.String() {
+		route.Destination = terminal
+	}
+	return route, nil
+}
+
+// LoadLaneTrips returns the latest trips of the delivered shipments that went the same way as a shipment,
+// from the same terminal and locations. There are no trips when the route is unknown.
+func LoadLaneTrips(tx *pop.Connection, s *Shipment, route ShipmentRoute) ([]LaneTrip, error) {
+	var trips = []LaneTrip{}
+	var distance = route.DistanceKm()
+	if distance == 0 {
+		return trips, nil
+	}
+	shipments := Shipments{}
+	if err := tx.Where("tenant_id = ?", s.TenantID).Where("id != ?", s.ID).Where("type = ?", s.Type).
+		Where("status = ?", ShipmentStatusDelivered.String()).
+		Where("terminal_id IS NOT DISTINCT FROM ?", s.TerminalID).
+		Where("origin_location_id IS NOT DISTINCT FROM ?", s.OriginLocationID).
+		Where("destination_location_id IS NOT DISTINCT FROM ?", s.DestinationLocationID).
+		Order("updated_at DESC").Limit(maxLaneTrips).All(&shipments); err != nil {
+		return nil, err
+	}
+	if len(shipments) == 0 {
+		return trips, nil
+	}
+	var ids = make([]interface{}, len(shipments))
+	for i, past := range shipments {
+		ids[i] = past.ID
+	}
+	events := ShipmentEvents{}
+	if err := tx.Where("shipment_id IN (?)", ids...).Where("to_status IN (?)", ShipmentStatusInTransit.String(), ShipmentStatusDelivered.String()).
+		Order("created_at ASC").All(&events); err != nil {
+		return nil, err
+	}
+	var departures = map[uuid.UUID]time.Time{}
+	var arrivals = map[uuid.UUID]time.Time{}
+	for _, e := range events {
+		if _, ok := departures[e.ShipmentID]; !ok && e.ToStatus == ShipmentStatusInTransit.String() {
+			departures[e.ShipmentID] = e.CreatedAt
+		}
+		if e.ToStatus == ShipmentStatusDelivered.String() {
+			arrivals[e.ShipmentID] = e.CreatedAt
+		}
+	}
+	for id, departed := range departures {
+		if arrived, ok := arrivals[id]; ok && arrived.After(departed) {
+			trips = append(trips, LaneTrip{DistanceKm: distance, Duration: arrived.Sub(departed)})
+		}
+	}
+	return trips, nil
+}
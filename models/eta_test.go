@@ -0,0 +1,60 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gobuffalo/nulls"
+)
+
+func (ms *ModelSuite) Test_LaneSpeedKmh() {
+	var tests = []struct {
+		name  string
+		trips []LaneTrip
+		speed float64
+	}{
+		{"no history", nil, DefaultLaneSpeedKmh},
+		{"one trip", []LaneTrip{{DistanceKm: 30, Duration: 30 * time.Minute}}, 60},
+		{"weighted by time", []LaneTrip{{DistanceKm: 30, Duration: 30 * time.Minute}, {DistanceKm: 30, Duration: 90 * time.Minute}}, 30},
+		{"invalid trips", []LaneTrip{{DistanceKm: 30, Duration: 0}, {DistanceKm: 0, Duration: time.Hour}}, DefaultLaneSpeedKmh},
+	}
+	for _, test := range tests {
+		ms.T().Run(test.name, func(t *testing.T) {
+			ms.InDelta(test.speed, LaneSpeedKmh(test.trips), 0.001)
+		})
+	}
+}
+
+func (ms *ModelSuite) Test_PredictEta() {
+	var at = time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+	var distance = DistanceKm(vancouver, deltaport)
+	ms.Equal(at, PredictEta(at, deltaport, deltaport, 60))
+	ms.WithinDuration(at.Add(time.Duration(distance/60*float64(time.Hour))), PredictEta(at, vancouver, deltaport, 60), time.Second)
+	ms.Equal(PredictEta(at, vancouver, deltaport, DefaultLaneSpeedKmh), PredictEta(at, vancouver, deltaport, 0))
+}
+
+func (ms *ModelSuite) Test_ShipmentEtaSlipped() {
+	var at = time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+	var tests = []struct {
+		name     string
+		shipment Shipment
+		slipped  bool
+	}{
+		{"no eta", Shipment{ReservationTime: nulls.NewTime(at)}, false},
+		{"no reservation", Shipment{Eta: nulls.NewTime(at)}, false},
+		{"early", Shipment{Eta: nulls.NewTime(at.Add(-time.Hour)), ReservationTime: nulls.NewTime(at)}, false},
+		{"within threshold", Shipment{Eta: nulls.NewTime(at.Add(30 * time.Minute)), ReservationTime: nulls.NewTime(at)}, false},
+		{"late", Shipment{Eta: nulls.NewTime(at.Add(31 * time.Minute)), ReservationTime: nulls.NewTime(at)}, true},
+	}
+	for _, test := range tests {
+		ms.T().Run(test.name, func(t *testing.T) {
+			ms.Equal(test.slipped, test.shipment.EtaSlipped(30*time.Minute))
+		})
+	}
+}
+
+func (ms *ModelSuite) Test_ShipmentRouteDistanceKm() {
+	ms.Equal(0.0, ShipmentRoute{}.DistanceKm())
+	ms.Equal(0.0, ShipmentRoute{Destination: &deltaport}.DistanceKm())
+	ms.InDelta(DistanceKm(vancouver, deltaport), ShipmentRoute{Origin: &vancouver, Destination: &deltaport}.DistanceKm(), 0.001)
+}
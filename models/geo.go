@@ -0,0 +1,143 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/gobuffalo/nulls"
+)
+
+// earthRadiusKm is the mean radius of the earth used by the haversine formula
+const earthRadiusKm = 6371.0
+
+// DefaultGeofenceRadiusMeters is the radius of the geofence of a place with coordinates but no geofence of its own
+const DefaultGeofenceRadiusMeters = 250
+
+// GeoPoint is a position in decimal degrees
+type GeoPoint struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+// NewGeoPoint returns the position of nullable coordinates, false when either is null
+func NewGeoPoint(lat nulls.Float64, lng nulls.Float64) (GeoPoint, bool) {
+	if !lat.Valid || !lng.Valid {
+		return GeoPoint{}, false
+	}
+	return GeoPoint{Lat: lat.Float64, Lng: lng.Float64}, true
+}
+
+// IsValid checks if the position is within the range of latitudes and longitudes
+func (p GeoPoint) IsValid() bool {
+	return p.Lat >= -90 && p.Lat <= 90 && p.Lng >= -180 && p.Lng <= 180
+}
+
+// DistanceKm returns the great-circle distance between two positions in kilometers, using the haversine formula
+func DistanceKm(a GeoPoint, b GeoPoint) float64 {
+	var toRadians = func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat := toRadians(b.Lat - a.Lat)
+	dLng := toRadians(b.Lng - a.Lng)
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(toRadians(a.Lat))*math.Cos(toRadians(b.Lat))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// GeoPolygon is a closed area, the last point connects back to the first one.
+// It is stored as a json array, null when empty.
+type GeoPolygon []GeoPoint
+
+// IsValid checks if the polygon is empty or has at least three valid points
+func (g GeoPolygon) IsValid() bool {
+	if len(g) == 0 {
+		return true
+	}
+	if len(g) < 3 {
+		return false
+	}
+	for _, p := range g {
+		if !p.IsValid() {
+			return false
+		}
+	}
+	return true
+}
+
+// Contains checks if a position is inside the polygon, by casting a ray from it and counting the edges it crosses
+func (g GeoPolygon) Contains(p GeoPoint) bool {
+	var inside bool
+	for i, j := 0, len(g)-1; i < len(g); j, i = i, i+1 {
+		a, b := g[i], g[j]
+		if (a.Lat > p.Lat) != (b.Lat > p.Lat) && p.Lng < (b.Lng-a.Lng)*(p.Lat-a.Lat)/(b.Lat-a.Lat)+a.Lng {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// Value implements the driver.Valuer interface
+func (g GeoPolygon) Value() (driver.Value, error) {
+	if len(g) == 0 {
+		return nil, nil
+	}
+	b, err := json.Marshal([]GeoPoint(g))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan implements the sql.Scanner interface
+func (g *GeoPolygon) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*g = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, (*[]GeoPoint)(g))
+	case string:
+		return json.Unmarshal([]byte(v), (*[]GeoPoint)(g))
+	}
+	return fmt.Errorf("cannot scan %T into GeoPolygon", value)
+}
+
+// Geofence is the area of a place. It is the polygon when there is one, otherwise a circle of the radius
+// around the center, DefaultGeofenceRadiusMeters when there is no radius. A place without coordinates has no area.
+type Geofence struct {
+	Center       *GeoPoint
+	RadiusMeters nulls.Int
+	Polygon      GeoPolygon
+}
+
+// NewGeofence returns the geofence of a place from its stored coordinates, radius and polygon
+func NewGeofence(lat nulls.Float64, lng nulls.Float64, radiusMeters nulls.Int, polygon GeoPolygon) Geofence {
+	var g = Geofence{RadiusMeters: radiusMeters, Polygon: polygon}
+	if center, ok := NewGeoPoint(lat, lng); ok {
+		g.Center = &center
+	}
+	return g
+}
+
+// Contains checks if a position is inside the geofence
+func (g Geofence) Contains(p GeoPoint) bool {
+	if len(g.Polygon) > 0 {
+		return g.Polygon.Contains(p)
+	}
+	if g.Center == nil {
+		return false
+	}
+	var radius = DefaultGeofenceRadiusMeters
+	if g.RadiusMeters.Valid {
+		radius = g.RadiusMeters.Int
+	}
+	return DistanceKm(*g.Center, p)*1000 <= float64(radius)
+}
+
+// validCoordinates checks that nullable coordinates are both set and in range, or both null
+func validCoordinates(lat nulls.Float64, lng nulls.Float64) bool {
+	if !lat.Valid && !lng.Valid {
+		return true
+	}
+	p, ok := NewGeoPoint(lat, lng)
+	return ok && p.IsValid()
+}
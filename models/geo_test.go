@@ -0,0 +1,83 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/gobuffalo/nulls"
+)
+
+var (
+	vancouver = GeoPoint{Lat: 49.2827, Lng: -123.1207}
+	seattle   = GeoPoint{Lat: 47.6062, Lng: -122.3321}
+	deltaport = GeoPoint{Lat: 49.0069, Lng: -123.1548}
+)
+
+func (ms *ModelSuite) Test_DistanceKm() {
+	var tests = []struct {
+		a        GeoPoint
+		b        GeoPoint
+		expected float64
+	}{
+		{vancouver, vancouver, 0},
+		{vancouver, seattle, 195.4},
+		{seattle, vancouver, 195.4},
+		{vancouver, deltaport, 30.8},
+		{GeoPoint{Lat: 0, Lng: 179.5}, GeoPoint{Lat: 0, Lng: -179.5}, 111.2},
+	}
+	for i, test := range tests {
+		ms.T().Run(fmt.Sprint(i), func(t *testing.T) {
+			ms.InDelta(test.expected, DistanceKm(test.a, test.b), 0.5)
+		})
+	}
+}
+
+func (ms *ModelSuite) Test_GeoPolygon() {
+	var square = GeoPolygon{{Lat: 49, Lng: -123.2}, {Lat: 49, Lng: -123.1}, {Lat: 49.1, Lng: -123.1}, {Lat: 49.1, Lng: -123.2}}
+	ms.True(square.IsValid())
+	ms.True(GeoPolygon{}.IsValid())
+	ms.False(square[:2].IsValid())
+	ms.False(GeoPolygon{{Lat: 91}, {Lat: 0}, {Lng: 1}}.IsValid())
+	ms.True(square.Contains(deltaport))
+	ms.False(square.Contains(vancouver))
+	ms.False(GeoPolygon{}.Contains(deltaport))
+
+	v, err := square.Value()
+	ms.Nil(err)
+	var scanned GeoPolygon
+	ms.Nil(scanned.Scan(v))
+	ms.Equal(square, scanned)
+	ms.Nil(scanned.Scan([]byte(`[{"lat":1,"lng":2}]`)))
+	ms.Equal(GeoPolygon{{Lat: 1, Lng: 2}}, scanned)
+	ms.Nil(scanned.Scan(nil))
+	ms.Nil(scanned)
+	v, err = GeoPolygon{}.Value()
+	ms.Nil(err)
+	ms.Nil(v)
+	ms.Error(scanned.Scan(42))
+}
+
+func (ms *ModelSuite) Test_Geofence() {
+	var near = GeoPoint{Lat: deltaport.Lat + 0.001, Lng: deltaport.Lng}
+	var tests = []struct {
+		name     string
+		geofence Geofence
+		at       GeoPoint
+		contains bool
+	}{
+		{"no coordinates", NewGeofence(nulls.Float64{}, nulls.Float64{}, nulls.Int{}, nil), deltaport, false},
+		{"center", NewGeofence(nulls.NewFloat64(deltaport.Lat), nulls.NewFloat64(deltaport.Lng), nulls.Int{}, nil), deltaport, true},
+		{"default radius", NewGeofence(nulls.NewFloat64(deltaport.Lat), nulls.NewFloat64(deltaport.Lng), nulls.Int{}, nil), near, true},
+		{"small radius", NewGeofence(nulls.NewFloat64(deltaport.Lat), nulls.NewFloat64(deltaport.Lng), nulls.NewInt(50), nil), near, false},
+		{"far", NewGeofence(nulls.NewFloat64(deltaport.Lat), nulls.NewFloat64(deltaport.Lng), nulls.NewInt(5000), nil), vancouver, false},
+		{"polygon", NewGeofence(nulls.NewFloat64(vancouver.Lat), nulls.NewFloat64(vancouver.Lng), nulls.Int{}, GeoPolygon{{Lat: 49, Lng: -123.2}, {Lat: 49, Lng: -123.1}, {Lat: 49.1, Lng: -123.15}}), deltaport, true},
+		{"polygon over radius", NewGeofence(nulls.NewFloat64(vancouver.Lat), nulls.NewFloat64(vancouver.Lng), nulls.Int{}, GeoPolygon{{Lat: 49, Lng: -123.2}, {Lat: 49, Lng: -123.1}, {Lat: 49.1, Lng: -123.15}}), vancouver, false},
+	}
+	for _, test := range tests {
+		ms.T().Run(test.name, func(t *testing.T) {
+			ms.Equal(test.contains, test.geofence.Contains(test.at))
+		})
+	}
+	ms.Less(math.Abs(DistanceKm(deltaport, near)*1000-111), 1.0)
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"sort"
+)
+
+// GeofenceTransition is a status change of a shipment caused by its driver entering or leaving the geofence of its terminal.
+// Location is the first point of the driver across the geofence.
+type GeofenceTransition struct {
+	Shipment *Shipment
+	From     ShipmentStatus
+	To       ShipmentStatus
+	Location DriverLocation
+}
+
+// DetectGeofenceTransition returns the status change the points of the driver cause to a shipment, nil when there is none.
+// A shipment accepted by the driver arrives when a point enters the geofence, a loaded shipment is in transit once a point
+// leaves it. Previous is the latest point recorded before the batch, nil when there is none.
+func DetectGeofenceTransition(s *Shipment, fence Geofence, previous *DriverLocation, points DriverLocations) *GeofenceTransition {
+	var from = ShipmentStatus(s.Status)
+	var to ShipmentStatus
+	switch from {
+	case ShipmentStatusAccepted:
+		to = ShipmentStatusArrived
+	case ShipmentStatusLoaded:
+		to = ShipmentStatusInTransit
+	default:
+		return nil
+	}
+	var sorted = make(DriverLocations, len(points))
+	copy(sorted, points)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].RecordedAt.Before(sorted[j].RecordedAt) })
+	var wasInside = previous != nil && fence.Contains(previous.Position())
+	for _, p := range sorted {
+		inside := fence.Contains(p.Position())
+		entered := inside && !wasInside
+		left := !inside && wasInside
+		if (to == ShipmentStatusArrived && entered) || (to == ShipmentStatusInTransit && left) {
+			return &GeofenceTransition{Shipment: s, From: from, To: to, Location: p}
+		}
+		wasInside = inside
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package models
+
+// AUTOGENERATED BY: HSM GEN
+
+// GeofenceTransitionMode represents the GeofenceTransitionMode enum
+type GeofenceTransitionMode string
+
+const (
+	// GeofenceTransitionModeSuggest represents Suggest GeofenceTransitionMode
+	GeofenceTransitionModeSuggest GeofenceTransitionMode = "Suggest"
+	// GeofenceTransitionModeAuto represents Auto GeofenceTransitionMode
+	GeofenceTransitionModeAuto GeofenceTransitionMode = "Auto"
+)
+
+var allowedGeofenceTransitionMode [2]GeofenceTransitionMode = [2]GeofenceTransitionMode{
+	GeofenceTransitionModeSuggest,
+	GeofenceTransitionModeAuto,
+}
+
+// String returns the string representation of
+func (k GeofenceTransitionMode) String() string {
+	return string(k)
+}
+
+// IsValidGeofenceTransitionMode validates if the input is a GeofenceTransitionMode
+func IsValidGeofenceTransitionMode(s string) bool {
+	t := GeofenceTransitionMode(s)
+	return GeofenceTransitionModeSuggest == t || GeofenceTransitionModeAuto == t
+}
@@ -0,0 +1,20 @@
+package models_test
+
+// AUTOGENERATED BY: HSM GEN
+
+import (
+	"testing"
+
+	m "github.com/bigpanther/trober/models"
+)
+
+func TestIsValidGeofenceTransitionMode(t *testing.T) {
+	var validVal = "Suggest"
+	var inValidVal = "_someInvalidval_"
+	if !m.IsValidGeofenceTransitionMode(validVal) {
+		t.Fatalf("IsValidGeofenceTransitionMode(%q) should be true", validVal)
+	}
+	if m.IsValidGeofenceTransitionMode(inValidVal) {
+		t.Fatalf("IsValidGeofenceTransitionMode(%q) should be false", inValidVal)
+	}
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/gofrs/uuid"
+)
+
+func (ms *ModelSuite) Test_DetectGeofenceTransition() {
+	var at = time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+	var fence = NewGeofence(nulls.NewFloat64(deltaport.Lat), nulls.NewFloat64(deltaport.Lng), nulls.NewInt(500), nil)
+	var point = func(p GeoPoint, minutes int) DriverLocation {
+		return DriverLocation{RecordedAt: at.Add(time.Duration(minutes) * time.Minute), Latitude: p.Lat, Longitude: p.Lng}
+	}
+	var outside, inside = point(vancouver, 0), point(deltaport, 0)
+	var tests = []struct {
+		name     string
+		status   ShipmentStatus
+		fence    Geofence
+		previous *DriverLocation
+		points   DriverLocations
+		to       ShipmentStatus
+		minutes  int
+	}{
+		{"arrived", ShipmentStatusAccepted, fence, &outside, DriverLocations{point(vancouver, 1), point(deltaport, 2), point(deltaport, 3)}, ShipmentStatusArrived, 2},
+		{"arrived out of order", ShipmentStatusAccepted, fence, nil, DriverLocations{point(deltaport, 3), point(vancouver, 1), point(deltaport, 2)}, ShipmentStatusArrived, 2},
+		{"arrived on first point", ShipmentStatusAccepted, fence, nil, DriverLocations{point(deltaport, 1)}, ShipmentStatusArrived, 1},
+		{"already inside", ShipmentStatusAccepted, fence, &inside, DriverLocations{point(deltaport, 1)}, "", 0},
+		{"not there yet", ShipmentStatusAccepted, fence, nil, DriverLocations{point(vancouver, 1), point(seattle, 2)}, "", 0},
+		{"left loaded", ShipmentStatusLoaded, fence, &inside, DriverLocations{point(deltaport, 1), point(vancouver, 2)}, ShipmentStatusInTransit, 2},
+		{"left within the batch", ShipmentStatusLoaded, fence, nil, DriverLocations{point(deltaport, 1), point(vancouver, 2)}, ShipmentStatusInTransit, 2},
+		{"loaded outside", ShipmentStatusLoaded, fence, &outside, DriverLocations{point(vancouver, 1)}, "", 0},
+		{"left not loaded", ShipmentStatusArrived, fence, &inside, DriverLocations{point(vancouver, 1)}, "", 0},
+		{"no geofence", ShipmentStatusAccepted, Geofence{}, nil, DriverLocations{point(deltaport, 1)}, "", 0},
+	}
+	for _, test := range tests {
+		ms.T().Run(test.name, func(t *testing.T) {
+			var shipment = &Shipment{ID: uuid.Must(uuid.NewV4()), Status: test.status.String()}
+			transition := DetectGeofenceTransition(shipment, test.fence, test.previous, test.points)
+			if test.to == "" {
+				ms.Nil(transition)
+				return
+			}
+			ms.NotNil(transition)
+			ms.Equal(test.status, transition.From)
+			ms.Equal(test.to, transition.To)
+			ms.Equal(at.Add(time.Duration(test.minutes)*time.Minute), transition.Location.RecordedAt)
+		})
+	}
+}
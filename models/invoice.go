@@ -0,0 +1,77 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gobuffalo/validate/v3"
+	"github.com/gobuffalo/validate/v3/validators"
+	"github.com/gofrs/uuid"
+)
+
+// Invoice is used by pop to map your invoices database table to your go code.
+type Invoice struct {
+	ID               uuid.UUID     `json:"id" db:"id"`
+	CreatedAt        time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time     `json:"updated_at" db:"updated_at"`
+	CreatedBy        uuid.UUID     `json:"created_by" db:"created_by"`
+	TenantID         uuid.UUID     `json:"tenant_id" db:"tenant_id"`
+	CustomerID       uuid.UUID     `json:"customer_id" db:"customer_id"`
+	Number           int           `json:"number" db:"number"`
+	Status           string        `json:"status" db:"status"`
+	DueDate          nulls.Time    `json:"due_date" db:"due_date"`
+	PaidAt           nulls.Time    `json:"paid_at" db:"paid_at"`
+	PaymentReference nulls.String  `json:"payment_reference" db:"payment_reference"`
+	Total            Money         `json:"total" db:"total"`
+	Tenant           *Tenant       `belongs_to:"tenant" json:"-"`
+	Customer         *Customer     `belongs_to:"customer" json:"customer,omitempty"`
+	Lines            InvoiceLines  `has_many:"invoice_lines" json:"lines,omitempty"`
+	Orders           InvoiceOrders `has_many:"invoice_orders" json:"orders,omitempty"`
+}
+
+// Invoices is not required by pop and may be deleted
+type Invoices []Invoice
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (i *Invoice) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.UUIDIsPresent{Field: i.CustomerID, Name: "CustomerID"},
+		&validators.IntIsGreaterThan{Field: i.Number, Name: "Number", Compared: 0},
+		&validators.FuncValidator{Fn: func() bool {
+			return IsValidInvoiceStatus(i.Status)
+		}, Field: i.Status, Name: "Status"},
+	), nil
+}
+
+// IsPaid checks if a payment has been recorded for the invoice
+func (i *Invoice) IsPaid() bool {
+	return i.Status == InvoiceStatusPaid.String()
+}
+
+// OrderIDs returns the distinct orders billed on the invoice, the orders must be loaded
+func (i *Invoice) OrderIDs() []uuid.UUID {
+	var seen = map[uuid.UUID]bool{}
+	var ids = []uuid.UUID{}
+	for _, o := range i.Orders {
+		if !seen[o.OrderID] {
+			seen[o.OrderID] = true
+			ids = append(ids, o.OrderID)
+		}
+	}
+	return ids
+}
+
+// NextInvoiceNumber returns the next sequential invoice number for the tenant.
+// The tenant row is locked until the end of the transaction so concurrent invoices cannot share a number.
+func NextInvoiceNumber(tx *pop.Connection, tenantID uuid.UUID) (int, error) {
+	if err := tx.RawQuery("SELECT id FROM tenants WHERE id = ? FOR UPDATE", tenantID).Exec(); err != nil {
+		return 0, err
+	}
+	var number int
+	if err := tx.RawQuery("SELECT COALESCE(MAX(number), 0) + 1 FROM invoices WHERE tenant_id = ?", tenantID).First(&number); err != nil {
+		return 0, err
+	}
+	return number, nil
+}
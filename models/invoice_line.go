@@ -0,0 +1,75 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gobuffalo/validate/v3"
+	"github.com/gobuffalo/validate/v3/validators"
+	"github.com/gofrs/uuid"
+)
+
+// InvoiceLine is used by pop to map your invoice_lines database table to your go code.
+type InvoiceLine struct {
+	ID          uuid.UUID  `json:"id" db:"id"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+	TenantID    uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	InvoiceID   uuid.UUID  `json:"invoice_id" db:"invoice_id"`
+	OrderID     nulls.UUID `json:"order_id" db:"order_id"`
+	Type        string     `json:"type" db:"type"`
+	Description string     `json:"description" db:"description"`
+	Amount      Money      `json:"amount" db:"amount"`
+	Invoice     *Invoice   `belongs_to:"invoice" json:"-"`
+}
+
+// InvoiceLines is not required by pop and may be deleted
+type InvoiceLines []InvoiceLine
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (l *InvoiceLine) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.StringIsPresent{Field: l.Description, Name: "Description"},
+		&validators.FuncValidator{Fn: func() bool {
+			return IsValidInvoiceLineType(l.Type)
+		}, Field: l.Type, Name: "Type"},
+		&validators.FuncValidator{Fn: func() bool {
+			return IsValidCurrency(l.Amount.Currency)
+		}, Field: l.Amount.Currency, Name: "Currency"},
+	), nil
+}
+
+// InvoiceLinesForOrder returns the lines billing the pickup and dropoff charges of an order
+func InvoiceLinesForOrder(o *Order) InvoiceLines {
+	var lines = InvoiceLines{}
+	if o.PickupCharges.Valid {
+		lines = append(lines, InvoiceLine{
+			TenantID:    o.TenantID,
+			OrderID:     nulls.NewUUID(o.ID),
+			Type:        InvoiceLineTypePickup.String(),
+			Description: "Pickup charges - " + o.SerialNumber,
+			Amount:      o.PickupCharges.Money,
+		})
+	}
+	if o.DropoffCharges.Valid {
+		lines = append(lines, InvoiceLine{
+			TenantID:    o.TenantID,
+			OrderID:     nulls.NewUUID(o.ID),
+			Type:        InvoiceLineTypeDropoff.String(),
+			Description: "Dropoff charges - " + o.SerialNumber,
+			Amount:      o.DropoffCharges.Money,
+		})
+	}
+	return lines
+}
+
+// Total returns the sum of the amounts of the lines, which must all be of the same currency
+func (l InvoiceLines) Total() (Money, error) {
+	var amounts = make([]Money, len(l))
+	for i, line := range l {
+		amounts[i] = line.Amount
+	}
+	return SumMoney(amounts...)
+}
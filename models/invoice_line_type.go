@@ -0,0 +1,32 @@
+package models
+
+// AUTOGENERATED BY: HSM GEN
+
+// InvoiceLineType represents the InvoiceLineType enum
+type InvoiceLineType string
+
+const (
+	// InvoiceLineTypePickup represents Pickup InvoiceLineType
+	InvoiceLineTypePickup InvoiceLineType = "Pickup"
+	// InvoiceLineTypeDropoff represents Dropoff InvoiceLineType
+	InvoiceLineTypeDropoff InvoiceLineType = "Dropoff"
+	// InvoiceLineTypeAccessorial represents Accessorial InvoiceLineType
+	InvoiceLineTypeAccessorial InvoiceLineType = "Accessorial"
+)
+
+var allowedInvoiceLineType [3]InvoiceLineType = [3]InvoiceLineType{
+	InvoiceLineTypePickup,
+	InvoiceLineTypeDropoff,
+	InvoiceLineTypeAccessorial,
+}
+
+// String returns the string representation of
+func (k InvoiceLineType) String() string {
+	return string(k)
+}
+
+// IsValidInvoiceLineType validates if the input is a InvoiceLineType
+func IsValidInvoiceLineType(s string) bool {
+	t := InvoiceLineType(s)
+	return InvoiceLineTypePickup == t || InvoiceLineTypeDropoff == t || InvoiceLineTypeAccessorial == t
+}
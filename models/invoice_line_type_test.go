@@ -0,0 +1,20 @@
+package models_test
+
+// AUTOGENERATED BY: HSM GEN
+
+import (
+	"testing"
+
+	m "github.com/bigpanther/trober/models"
+)
+
+func TestIsValidInvoiceLineType(t *testing.T) {
+	var validVal = "Pickup"
+	var inValidVal = "_someInvalidval_"
+	if !m.IsValidInvoiceLineType(validVal) {
+		t.Fatalf("IsValidInvoiceLineType(%q) should be true", validVal)
+	}
+	if m.IsValidInvoiceLineType(inValidVal) {
+		t.Fatalf("IsValidInvoiceLineType(%q) should be false", inValidVal)
+	}
+}
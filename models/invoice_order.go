@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gobuffalo/validate/v3"
+	"github.com/gobuffalo/validate/v3/validators"
+	"github.com/gofrs/uuid"
+)
+
+// InvoiceOrder is used by pop to map your invoice_orders database table to your go code.
+// It links an invoice to an order it bills, whether or not the order has a line on the invoice.
+type InvoiceOrder struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	TenantID  uuid.UUID `json:"tenant_id" db:"tenant_id"`
+	InvoiceID uuid.UUID `json:"invoice_id" db:"invoice_id"`
+	OrderID   uuid.UUID `json:"order_id" db:"order_id"`
+	Invoice   *Invoice  `belongs_to:"invoice" json:"-"`
+}
+
+// InvoiceOrders is not required by pop and may be deleted
+type InvoiceOrders []InvoiceOrder
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (o *InvoiceOrder) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.UUIDIsPresent{Field: o.OrderID, Name: "OrderID"},
+	), nil
+}
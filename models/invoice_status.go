@@ -0,0 +1,29 @@
+package models
+
+// AUTOGENERATED BY: HSM GEN
+
+// InvoiceStatus represents the InvoiceStatus enum
+type InvoiceStatus string
+
+const (
+	// InvoiceStatusIssued represents Issued InvoiceStatus
+	InvoiceStatusIssued InvoiceStatus = "Issued"
+	// InvoiceStatusPaid represents Paid InvoiceStatus
+	InvoiceStatusPaid InvoiceStatus = "Paid"
+)
+
+var allowedInvoiceStatus [2]InvoiceStatus = [2]InvoiceStatus{
+	InvoiceStatusIssued,
+	InvoiceStatusPaid,
+}
+
+// String returns the string representation of
+func (k InvoiceStatus) String() string {
+	return string(k)
+}
+
+// IsValidInvoiceStatus validates if the input is a InvoiceStatus
+func IsValidInvoiceStatus(s string) bool {
+	t := InvoiceStatus(s)
+	return InvoiceStatusIssued == t || InvoiceStatusPaid == t
+}
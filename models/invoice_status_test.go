@@ -0,0 +1,20 @@
+package models_test
+
+// AUTOGENERATED BY: HSM GEN
+
+import (
+	"testing"
+
+	m "github.com/bigpanther/trober/models"
+)
+
+func TestIsValidInvoiceStatus(t *testing.T) {
+	var validVal = "Issued"
+	var inValidVal = "_someInvalidval_"
+	if !m.IsValidInvoiceStatus(validVal) {
+		t.Fatalf("IsValidInvoiceStatus(%q) should be true", validVal)
+	}
+	if m.IsValidInvoiceStatus(inValidVal) {
+		t.Fatalf("IsValidInvoiceStatus(%q) should be false", inValidVal)
+	}
+}
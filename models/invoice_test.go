@@ -0,0 +1,85 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/gofrs/uuid"
+)
+
+func (ms *ModelSuite) Test_Invoice() {
+	var tests = []struct {
+		invoice                  *Invoice
+		expectedValidationErrors int
+	}{
+		{&Invoice{}, 3},
+		{&Invoice{Status: "invalid"}, 3},
+		{&Invoice{CustomerID: uuid.Must(uuid.NewV4())}, 2},
+		{&Invoice{CustomerID: uuid.Must(uuid.NewV4()), Number: 1}, 1},
+		{&Invoice{CustomerID: uuid.Must(uuid.NewV4()), Number: 1, Status: InvoiceStatusIssued.String()}, 0},
+	}
+	for i, test := range tests {
+		ms.T().Run(fmt.Sprint(i), func(t *testing.T) {
+			v, err := test.invoice.Validate(ms.DB)
+			ms.Nil(err)
+			ms.Equal(test.expectedValidationErrors, len(v.Errors))
+		})
+	}
+}
+
+func (ms *ModelSuite) Test_InvoiceOrderIDs() {
+	var first, second = uuid.Must(uuid.NewV4()), uuid.Must(uuid.NewV4())
+	invoice := &Invoice{Orders: InvoiceOrders{
+		{OrderID: first},
+		{OrderID: second},
+		{OrderID: first},
+	}}
+	ms.Equal([]uuid.UUID{first, second}, invoice.OrderIDs())
+	ms.Equal([]uuid.UUID{}, (&Invoice{}).OrderIDs())
+}
+
+func (ms *ModelSuite) Test_InvoiceLine() {
+	var tests = []struct {
+		line                     *InvoiceLine
+		expectedValidationErrors int
+	}{
+		{&InvoiceLine{}, 3},
+		{&InvoiceLine{Type: "invalid"}, 3},
+		{&InvoiceLine{Description: "Detention"}, 2},
+		{&InvoiceLine{Description: "Detention", Type: InvoiceLineTypeAccessorial.String()}, 1},
+		{&InvoiceLine{Description: "Detention", Type: InvoiceLineTypeAccessorial.String(), Amount: NewMoney(100, "usd")}, 1},
+		{&InvoiceLine{Description: "Detention", Type: InvoiceLineTypeAccessorial.String(), Amount: NewMoney(100, "USD")}, 0},
+	}
+	for i, test := range tests {
+		ms.T().Run(fmt.Sprint(i), func(t *testing.T) {
+			v, err := test.line.Validate(ms.DB)
+			ms.Nil(err)
+			ms.Equal(test.expectedValidationErrors, len(v.Errors))
+		})
+	}
+}
+
+func (ms *ModelSuite) Test_InvoiceLinesForOrder() {
+	order := &Order{ID: uuid.Must(uuid.NewV4()), SerialNumber: "ORD0001", PickupCharges: NewNullMoney(100, "CAD"), DropoffCharges: NewNullMoney(250, "CAD")}
+	lines := InvoiceLinesForOrder(order)
+	ms.Equal(2, len(lines))
+	ms.Equal(InvoiceLineTypePickup.String(), lines[0].Type)
+	ms.Equal("Pickup charges - ORD0001", lines[0].Description)
+	ms.Equal(InvoiceLineTypeDropoff.String(), lines[1].Type)
+	ms.Equal(order.ID, lines[1].OrderID.UUID)
+	total, err := lines.Total()
+	ms.Nil(err)
+	ms.Equal(NewMoney(350, "CAD"), total)
+
+	order.PickupCharges = NullMoney{}
+	lines = InvoiceLinesForOrder(order)
+	ms.Equal(1, len(lines))
+	total, err = lines.Total()
+	ms.Nil(err)
+	ms.Equal(NewMoney(250, "CAD"), total)
+
+	lines = append(lines, InvoiceLine{Amount: NewMoney(100, "USD")})
+	_, err = lines.Total()
+	ms.True(errors.Is(err, ErrCurrencyMismatch))
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gobuffalo/validate/v3"
+	"github.com/gobuffalo/validate/v3/validators"
+	"github.com/gofrs/uuid"
+)
+
+// Location is used by pop to map your locations database table to your go code.
+// It is a stored place shipments can start from or go to, e.g. a yard, a warehouse or a site of a customer.
+// Latitude and Longitude locate the place, GeofenceRadiusMeters or GeofencePolygon is its area.
+type Location struct {
+	ID                   uuid.UUID     `json:"id" db:"id"`
+	CreatedAt            time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt            time.Time     `json:"updated_at" db:"updated_at"`
+	CreatedBy            uuid.UUID     `json:"created_by" db:"created_by"`
+	TenantID             uuid.UUID     `json:"tenant_id" db:"tenant_id"`
+	CustomerID           nulls.UUID    `json:"customer_id" db:"customer_id"`
+	Name                 string        `json:"name" db:"name"`
+	AddressLine          nulls.String  `json:"address_line" db:"address_line"`
+	City                 nulls.String  `json:"city" db:"city"`
+	Region               nulls.String  `json:"region" db:"region"`
+	PostalCode           nulls.String  `json:"postal_code" db:"postal_code"`
+	Country              nulls.String  `json:"country" db:"country"`
+	Latitude             nulls.Float64 `json:"latitude" db:"latitude"`
+	Longitude            nulls.Float64 `json:"longitude" db:"longitude"`
+	GeofenceRadiusMeters nulls.Int     `json:"geofence_radius_meters" db:"geofence_radius_meters"`
+	GeofencePolygon      GeoPolygon    `json:"geofence_polygon" db:"geofence_polygon"`
+	Tenant               *Tenant       `belongs_to:"tenant" json:"-"`
+	Customer             *Customer     `belongs_to:"customer" json:"-"`
+}
+
+// Locations is not required by pop and may be deleted
+type Locations []Location
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (l *Location) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.StringIsPresent{Field: l.Name, Name: "Name"},
+		&validators.FuncValidator{Fn: func() bool {
+			// Value can be null
+			return validCoordinates(l.Latitude, l.Longitude)
+		}, Field: fmt.Sprintf("%v,%v", l.Latitude.Float64, l.Longitude.Float64), Name: "Latitude"},
+		&validators.FuncValidator{Fn: func() bool {
+			// Value can be null
+			return !l.GeofenceRadiusMeters.Valid || l.GeofenceRadiusMeters.Int > 0
+		}, Field: fmt.Sprint(l.GeofenceRadiusMeters.Int), Name: "GeofenceRadiusMeters"},
+		&validators.FuncValidator{Fn: func() bool {
+			return l.GeofencePolygon.IsValid()
+		}, Field: fmt.Sprint(len(l.GeofencePolygon)), Name: "GeofencePolygon"},
+	), nil
+}
+
+// Position returns where the location is, false when it has no coordinates
+func (l *Location) Position() (GeoPoint, bool) {
+	return NewGeoPoint(l.Latitude, l.Longitude)
+}
+
+// Geofence returns the area of the location
+func (l *Location) Geofence() Geofence {
+	return NewGeofence(l.Latitude, l.Longitude, l.GeofenceRadiusMeters, l.GeofencePolygon)
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gobuffalo/nulls"
+)
+
+func (ms *ModelSuite) Test_Location() {
+	var tests = []struct {
+		location                 *Location
+		expectedValidationErrors int
+	}{
+		{&Location{}, 1},
+		{&Location{Name: "Yard"}, 0},
+		{&Location{Name: "Yard", Latitude: nulls.NewFloat64(49.1), Longitude: nulls.NewFloat64(-123.1), GeofenceRadiusMeters: nulls.NewInt(300)}, 0},
+		{&Location{Name: "Yard", Latitude: nulls.NewFloat64(49.1)}, 1},
+		{&Location{Name: "Yard", Latitude: nulls.NewFloat64(99.1), Longitude: nulls.NewFloat64(-123.1)}, 1},
+		{&Location{Name: "Yard", Latitude: nulls.NewFloat64(49.1), Longitude: nulls.NewFloat64(-123.1), GeofenceRadiusMeters: nulls.NewInt(0)}, 1},
+		{&Location{Name: "Yard", GeofencePolygon: GeoPolygon{{Lat: 49, Lng: -123}, {Lat: 49.1, Lng: -123}}}, 1},
+	}
+	for i, test := range tests {
+		ms.T().Run(fmt.Sprint(i), func(t *testing.T) {
+			v, err := test.location.Validate(ms.DB)
+			ms.Nil(err)
+			ms.Equal(test.expectedValidationErrors, len(v.Errors))
+		})
+	}
+}
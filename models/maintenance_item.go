@@ -0,0 +1,121 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gobuffalo/validate/v3"
+	"github.com/gobuffalo/validate/v3/validators"
+	"github.com/gofrs/uuid"
+)
+
+// ErrEquipmentOutOfService is returned when a shipment is dispatched with equipment that cannot be used
+var ErrEquipmentOutOfService = errors.New("equipment is out of service")
+
+// MaintenanceAlertDays are how many days before its due date back office is told about a maintenance item, closest first
+var MaintenanceAlertDays = []int{1, 7, 30}
+
+// blockingMaintenanceItemTypes are the maintenance items equipment cannot be used without once they are past due
+var blockingMaintenanceItemTypes = []MaintenanceItemType{
+	MaintenanceItemTypeInspection,
+	MaintenanceItemTypeRegistration,
+}
+
+const maintenanceDueDateFormat = "2006-01-02"
+
+// MaintenanceItem is used by pop to map your maintenance_items database table to your go code.
+// It is an inspection, a registration or a work order of an equipment, due at the start of its DueDate (UTC).
+// The item stays in the log once completed, the next one is a new item. LastAlertedDays are the days before the due date
+// of the last alert sent for it.
+type MaintenanceItem struct {
+	ID              uuid.UUID    `json:"id" db:"id"`
+	CreatedAt       time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time    `json:"updated_at" db:"updated_at"`
+	CreatedBy       uuid.UUID    `json:"created_by" db:"created_by"`
+	TenantID        uuid.UUID    `json:"tenant_id" db:"tenant_id"`
+	EquipmentID     uuid.UUID    `json:"equipment_id" db:"equipment_id"`
+	Type            string       `json:"type" db:"type"`
+	Description     nulls.String `json:"description" db:"description"`
+	DueDate         time.Time    `json:"due_date" db:"due_date"`
+	CompletedAt     nulls.Time   `json:"completed_at" db:"completed_at"`
+	LastAlertedDays nulls.Int    `json:"last_alerted_days" db:"last_alerted_days"`
+	Tenant          *Tenant      `belongs_to:"tenant" json:"-"`
+	Equipment       *Equipment   `belongs_to:"equipment" json:"equipment,omitempty"`
+}
+
+// MaintenanceItems is not required by pop and may be deleted
+type MaintenanceItems []MaintenanceItem
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (m *MaintenanceItem) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	if !m.DueDate.IsZero() {
+		m.DueDate = m.DueDate.UTC().Truncate(24 * time.Hour)
+	}
+	return validate.Validate(
+		&validators.UUIDIsPresent{Field: m.EquipmentID, Name: "EquipmentID"},
+		&validators.TimeIsPresent{Field: m.DueDate, Name: "DueDate"},
+		&validators.FuncValidator{Fn: func() bool {
+			return IsValidMaintenanceItemType(m.Type)
+		}, Field: m.Type, Name: "Type"},
+	), nil
+}
+
+// IsCompleted checks if the maintenance item was done
+func (m *MaintenanceItem) IsCompleted() bool {
+	return m.CompletedAt.Valid
+}
+
+// IsPastDue checks if the maintenance item is not done at the given time and its due date has come
+func (m *MaintenanceItem) IsPastDue(now time.Time) bool {
+	return !m.IsCompleted() && !now.Before(m.DueDate)
+}
+
+// DueAlertDays returns the closest alert days the time remaining until the due date is at or below at the given time,
+// when the item has not been alerted for them or closer ones yet. An alert for days the due date has since moved
+// away from does not count.
+func (m *MaintenanceItem) DueAlertDays(now time.Time) (int, bool) {
+	if m.IsCompleted() {
+		return 0, false
+	}
+	remaining := m.DueDate.Sub(now)
+	alerted := m.LastAlertedDays
+	if alerted.Valid && remaining > time.Duration(alerted.Int)*24*time.Hour {
+		alerted = nulls.Int{}
+	}
+	for _, d := range MaintenanceAlertDays {
+		if remaining <= time.Duration(d)*24*time.Hour {
+			return d, !alerted.Valid || d < alerted.Int
+		}
+	}
+	return 0, false
+}
+
+// EquipmentOutOfServiceReasons returns why an equipment cannot be used at the given time, empty when it can.
+// Equipment that is not Active, or with an inspection or a registration past due, is out of service.
+func EquipmentOutOfServiceReasons(tx *pop.Connection, e *Equipment, now time.Time) ([]string, error) {
+	var reasons = []string{}
+	if e.Status != EquipmentStatusActive.String() {
+		reasons = append(reasons, fmt.Sprintf("%s %s is %s", strings.ToLower(e.Type), e.UnitNumber, e.Status))
+	}
+	var types = make([]interface{}, len(blockingMaintenanceItemTypes))
+	for i, t := range blockingMaintenanceItemTypes {
+		types[i] = t.String()
+	}
+	items := MaintenanceItems{}
+	if err := tx.Where("equipment_id = ?", e.ID).Where("type IN (?)", types...).Where("completed_at IS NULL").
+		Where("due_date <= ?", now).Order("due_date ASC").All(&items); err != nil {
+		return nil, err
+	}
+	for _, m := range items {
+		if m.IsPastDue(now) {
+			reasons = append(reasons, fmt.Sprintf("%s %s %s was due on %s", strings.ToLower(e.Type), e.UnitNumber,
+				strings.ToLower(m.Type), m.DueDate.Format(maintenanceDueDateFormat)))
+		}
+	}
+	return reasons, nil
+}
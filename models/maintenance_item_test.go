@@ -0,0 +1,79 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/gofrs/uuid"
+)
+
+func (ms *ModelSuite) Test_MaintenanceItem() {
+	var equipmentID = uuid.Must(uuid.NewV4())
+	var due = time.Date(2026, 11, 1, 15, 30, 0, 0, time.UTC)
+	var tests = []struct {
+		item                     *MaintenanceItem
+		expectedValidationErrors int
+	}{
+		{&MaintenanceItem{}, 3},
+		{&MaintenanceItem{EquipmentID: equipmentID, Type: "Inspection", DueDate: due}, 0},
+		{&MaintenanceItem{EquipmentID: equipmentID, Type: "WorkOrder", DueDate: due, Description: nulls.NewString("Brakes"), CompletedAt: nulls.NewTime(due)}, 0},
+		{&MaintenanceItem{EquipmentID: equipmentID, Type: "Oil", DueDate: due}, 1},
+		{&MaintenanceItem{EquipmentID: equipmentID, Type: "Registration"}, 1},
+	}
+	for i, test := range tests {
+		ms.T().Run(fmt.Sprint(i), func(t *testing.T) {
+			v, err := test.item.Validate(ms.DB)
+			ms.Nil(err)
+			ms.Equal(test.expectedValidationErrors, len(v.Errors))
+		})
+	}
+	// Items are due at the start of the day
+	ms.Equal(time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), tests[1].item.DueDate)
+}
+
+func (ms *ModelSuite) Test_MaintenanceItemIsPastDue() {
+	var due = time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	var item = &MaintenanceItem{DueDate: due}
+	ms.False(item.IsPastDue(due.Add(-time.Minute)))
+	ms.True(item.IsPastDue(due))
+	ms.True(item.IsPastDue(due.AddDate(0, 1, 0)))
+	item.CompletedAt = nulls.NewTime(due.AddDate(0, 0, -2))
+	ms.True(item.IsCompleted())
+	ms.False(item.IsPastDue(due.AddDate(0, 1, 0)))
+}
+
+func (ms *ModelSuite) Test_MaintenanceItemDueAlertDays() {
+	var now = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	var tests = []struct {
+		remaining time.Duration
+		alerted   nulls.Int
+		days      int
+		due       bool
+	}{
+		{31 * 24 * time.Hour, nulls.Int{}, 0, false},
+		{30 * 24 * time.Hour, nulls.Int{}, 30, true},
+		{30*24*time.Hour - time.Hour, nulls.Int{}, 30, true},
+		{30*24*time.Hour - time.Hour, nulls.NewInt(30), 30, false},
+		{7*24*time.Hour - time.Minute, nulls.NewInt(30), 7, true},
+		{3 * 24 * time.Hour, nulls.NewInt(7), 7, false},
+		{24*time.Hour - 59*time.Minute, nulls.NewInt(7), 1, true},
+		{12 * time.Hour, nulls.Int{}, 1, true},
+		{12 * time.Hour, nulls.NewInt(1), 1, false},
+		// The due date moved out after the alerts
+		{10 * 24 * time.Hour, nulls.NewInt(1), 30, true},
+		{-time.Minute, nulls.Int{}, 1, true},
+	}
+	for i, test := range tests {
+		ms.T().Run(fmt.Sprint(i), func(t *testing.T) {
+			item := &MaintenanceItem{DueDate: now.Add(test.remaining), LastAlertedDays: test.alerted}
+			days, due := item.DueAlertDays(now)
+			ms.Equal(test.due, due)
+			ms.Equal(test.days, days)
+		})
+	}
+	var completed = &MaintenanceItem{DueDate: now.Add(7 * 24 * time.Hour), CompletedAt: nulls.NewTime(now)}
+	_, due := completed.DueAlertDays(now)
+	ms.False(due)
+}
@@ -0,0 +1,32 @@
+package models
+
+// AUTOGENERATED BY: HSM GEN
+
+// MaintenanceItemType represents the MaintenanceItemType enum
+type MaintenanceItemType string
+
+const (
+	// MaintenanceItemTypeInspection represents Inspection MaintenanceItemType
+	MaintenanceItemTypeInspection MaintenanceItemType = "Inspection"
+	// MaintenanceItemTypeRegistration represents Registration MaintenanceItemType
+	MaintenanceItemTypeRegistration MaintenanceItemType = "Registration"
+	// MaintenanceItemTypeWorkOrder represents WorkOrder MaintenanceItemType
+	MaintenanceItemTypeWorkOrder MaintenanceItemType = "WorkOrder"
+)
+
+var allowedMaintenanceItemType [3]MaintenanceItemType = [3]MaintenanceItemType{
+	MaintenanceItemTypeInspection,
+	MaintenanceItemTypeRegistration,
+	MaintenanceItemTypeWorkOrder,
+}
+
+// String returns the string representation of
+func (t MaintenanceItemType) String() string {
+	return string(t)
+}
+
+// IsValidMaintenanceItemType validates if the input is a MaintenanceItemType
+func IsValidMaintenanceItemType(s string) bool {
+	t := MaintenanceItemType(s)
+	return MaintenanceItemTypeInspection == t || MaintenanceItemTypeRegistration == t || MaintenanceItemTypeWorkOrder == t
+}
@@ -0,0 +1,20 @@
+package models_test
+
+// AUTOGENERATED BY: HSM GEN
+
+import (
+	"testing"
+
+	m "github.com/bigpanther/trober/models"
+)
+
+func TestIsValidMaintenanceItemType(t *testing.T) {
+	var validVal = "WorkOrder"
+	var inValidVal = "_someInvalidval_"
+	if !m.IsValidMaintenanceItemType(validVal) {
+		t.Fatalf("IsValidMaintenanceItemType(%q) should be true", validVal)
+	}
+	if m.IsValidMaintenanceItemType(inValidVal) {
+		t.Fatalf("IsValidMaintenanceItemType(%q) should be false", inValidVal)
+	}
+}
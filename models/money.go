@@ -0,0 +1,170 @@
+package models
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// DefaultCurrency is the currency of tenants that did not choose one
+const DefaultCurrency = "CAD"
+
+var currencyRegex = regexp.MustCompile("^[A-Z]{3}$")
+
+// ErrCurrencyMismatch is returned when amounts in different currencies are added together
+var ErrCurrencyMismatch = errors.New("amounts in different currencies cannot be added")
+
+// IsValidCurrency validates if the input is an ISO-4217 currency code
+func IsValidCurrency(s string) bool {
+	return currencyRegex.MatchString(s)
+}
+
+// Money is an amount in the minor units of an ISO-4217 currency, e.g. cents for CAD.
+// It is stored as a json object so the amount can still be aggregated in SQL.
+type Money struct {
+	Amount   int    `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// NewMoney returns an amount of the given currency
+func NewMoney(amount int, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+func (m Money) String() string {
+	return fmt.Sprintf("%d %s", m.Amount, m.Currency)
+}
+
+// Add returns the sum of two amounts of the same currency.
+// An amount without a currency takes the currency of the other one.
+func (m Money) Add(o Money) (Money, error) {
+	if m.Currency == "" {
+		m.Currency = o.Currency
+	}
+	if o.Currency != "" && o.Currency != m.Currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, o.Currency)
+	}
+	m.Amount += o.Amount
+	return m, nil
+}
+
+// Multiply returns the amount multiplied by a quantity
+func (m Money) Multiply(quantity int) Money {
+	m.Amount *= quantity
+	return m
+}
+
+// SumMoney returns the total of the amounts, which must all be of the same currency
+func SumMoney(amounts ...Money) (Money, error) {
+	var total Money
+	var err error
+	for _, m := range amounts {
+		if total, err = total.Add(m); err != nil {
+			return Money{}, err
+		}
+	}
+	return total, nil
+}
+
+// UnmarshalJSON accepts a Money object, or a bare number of minor units with no currency
+func (m *Money) UnmarshalJSON(b []byte) error {
+	var amount int
+	if err := json.Unmarshal(b, &amount); err == nil {
+		*m = Money{Amount: amount}
+		return nil
+	}
+	type money Money
+	var v money
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*m = Money(v)
+	return nil
+}
+
+// Value implements the driver.Valuer interface
+func (m Money) Value() (driver.Value, error) {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan implements the sql.Scanner interface
+func (m *Money) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, m)
+	case string:
+		return json.Unmarshal([]byte(v), m)
+	}
+	return fmt.Errorf("cannot scan %T into Money", value)
+}
+
+// NullMoney can be used with the standard sql package to represent a
+// Money value that can be NULL in the database.
+type NullMoney struct {
+	Money Money
+	Valid bool
+}
+
+// NewNullMoney returns a new, properly instantiated NullMoney object
+func NewNullMoney(amount int, currency string) NullMoney {
+	return NullMoney{Money: NewMoney(amount, currency), Valid: true}
+}
+
+// Interface implements the nullable interface. It returns nil if the Money is not valid, otherwise it returns the Money value.
+func (m NullMoney) Interface() interface{} {
+	if !m.Valid {
+		return nil
+	}
+	return m.Money
+}
+
+// Value implements the driver.Valuer interface
+func (m NullMoney) Value() (driver.Value, error) {
+	if !m.Valid {
+		return nil, nil
+	}
+	return m.Money.Value()
+}
+
+// Scan implements the sql.Scanner interface
+func (m *NullMoney) Scan(value interface{}) error {
+	if value == nil {
+		*m = NullMoney{}
+		return nil
+	}
+	m.Valid = true
+	return m.Money.Scan(value)
+}
+
+// MarshalJSON marshals the underlying value to a proper JSON representation
+func (m NullMoney) MarshalJSON() ([]byte, error) {
+	if !m.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(m.Money)
+}
+
+// UnmarshalJSON will unmarshal a JSON value into the proper representation of that value
+func (m *NullMoney) UnmarshalJSON(b []byte) error {
+	if bytes.Equal(bytes.TrimSpace(b), []byte("null")) {
+		*m = NullMoney{}
+		return nil
+	}
+	m.Valid = true
+	return json.Unmarshal(b, &m.Money)
+}
+
+// WithDefaultCurrency returns the amount in the given currency when it has none
+func (m NullMoney) WithDefaultCurrency(currency string) NullMoney {
+	if m.Valid && m.Money.Currency == "" {
+		m.Money.Currency = currency
+	}
+	return m
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func (ms *ModelSuite) Test_MoneyAdd() {
+	var tests = []struct {
+		amounts  []Money
+		expected Money
+		err      error
+	}{
+		{[]Money{}, Money{}, nil},
+		{[]Money{NewMoney(100, "CAD"), NewMoney(250, "CAD")}, NewMoney(350, "CAD"), nil},
+		{[]Money{NewMoney(100, ""), NewMoney(250, "USD")}, NewMoney(350, "USD"), nil},
+		{[]Money{NewMoney(100, "CAD"), NewMoney(250, "USD")}, Money{}, ErrCurrencyMismatch},
+	}
+	for i, test := range tests {
+		ms.T().Run(fmt.Sprint(i), func(t *testing.T) {
+			total, err := SumMoney(test.amounts...)
+			ms.True(errors.Is(err, test.err))
+			ms.Equal(test.expected, total)
+		})
+	}
+	ms.Equal(NewMoney(300, "CAD"), NewMoney(100, "CAD").Multiply(3))
+}
+
+func (ms *ModelSuite) Test_MoneyJSON() {
+	var tests = []struct {
+		json     string
+		expected NullMoney
+	}{
+		{`null`, NullMoney{}},
+		{`1250`, NullMoney{Money: NewMoney(1250, ""), Valid: true}},
+		{`{"amount":1250,"currency":"USD"}`, NewNullMoney(1250, "USD")},
+	}
+	for i, test := range tests {
+		ms.T().Run(fmt.Sprint(i), func(t *testing.T) {
+			var m NullMoney
+			ms.Nil(json.Unmarshal([]byte(test.json), &m))
+			ms.Equal(test.expected, m)
+		})
+	}
+	b, err := json.Marshal(NewNullMoney(1250, "USD"))
+	ms.Nil(err)
+	ms.Equal(`{"amount":1250,"currency":"USD"}`, string(b))
+	b, err = json.Marshal(NullMoney{})
+	ms.Nil(err)
+	ms.Equal(`null`, string(b))
+	var m NullMoney
+	ms.NotNil(json.Unmarshal([]byte(`"12.50"`), &m))
+}
+
+func (ms *ModelSuite) Test_MoneySQL() {
+	v, err := NewNullMoney(1250, "CAD").Value()
+	ms.Nil(err)
+	ms.Equal(`{"amount":1250,"currency":"CAD"}`, v)
+	v, err = NullMoney{}.Value()
+	ms.Nil(err)
+	ms.Nil(v)
+
+	var m NullMoney
+	ms.Nil(m.Scan([]byte(`{"amount":1250,"currency":"CAD"}`)))
+	ms.Equal(NewNullMoney(1250, "CAD"), m)
+	ms.Nil(m.Scan(nil))
+	ms.False(m.Valid)
+	ms.NotNil(m.Scan(1250))
+}
@@ -0,0 +1,32 @@
+package models
+
+// AUTOGENERATED BY: HSM GEN
+
+// NotificationChannel represents the NotificationChannel enum
+type NotificationChannel string
+
+const (
+	// NotificationChannelPush represents Push NotificationChannel
+	NotificationChannelPush NotificationChannel = "Push"
+	// NotificationChannelEmail represents Email NotificationChannel
+	NotificationChannelEmail NotificationChannel = "Email"
+	// NotificationChannelNone represents None NotificationChannel
+	NotificationChannelNone NotificationChannel = "None"
+)
+
+var allowedNotificationChannel [3]NotificationChannel = [3]NotificationChannel{
+	NotificationChannelPush,
+	NotificationChannelEmail,
+	NotificationChannelNone,
+}
+
+// String returns the string representation of
+func (k NotificationChannel) String() string {
+	return string(k)
+}
+
+// IsValidNotificationChannel validates if the input is a NotificationChannel
+func IsValidNotificationChannel(s string) bool {
+	t := NotificationChannel(s)
+	return NotificationChannelPush == t || NotificationChannelEmail == t || NotificationChannelNone == t
+}
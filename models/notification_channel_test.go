@@ -0,0 +1,20 @@
+package models_test
+
+// AUTOGENERATED BY: HSM GEN
+
+import (
+	"testing"
+
+	m "github.com/bigpanther/trober/models"
+)
+
+func TestIsValidNotificationChannel(t *testing.T) {
+	var validVal = "Push"
+	var inValidVal = "_someInvalidval_"
+	if !m.IsValidNotificationChannel(validVal) {
+		t.Fatalf("IsValidNotificationChannel(%q) should be true", validVal)
+	}
+	if m.IsValidNotificationChannel(inValidVal) {
+		t.Fatalf("IsValidNotificationChannel(%q) should be false", inValidVal)
+	}
+}
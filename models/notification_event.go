@@ -0,0 +1,53 @@
+package models
+
+// AUTOGENERATED BY: HSM GEN
+
+// NotificationEvent represents the NotificationEvent enum
+type NotificationEvent string
+
+const (
+	// NotificationEventNewUser represents NewUser NotificationEvent
+	NotificationEventNewUser NotificationEvent = "NewUser"
+	// NotificationEventShipmentAssigned represents ShipmentAssigned NotificationEvent
+	NotificationEventShipmentAssigned NotificationEvent = "ShipmentAssigned"
+	// NotificationEventShipmentUpdated represents ShipmentUpdated NotificationEvent
+	NotificationEventShipmentUpdated NotificationEvent = "ShipmentUpdated"
+	// NotificationEventShipmentDelivered represents ShipmentDelivered NotificationEvent
+	NotificationEventShipmentDelivered NotificationEvent = "ShipmentDelivered"
+	// NotificationEventShipmentLate represents ShipmentLate NotificationEvent
+	NotificationEventShipmentLate NotificationEvent = "ShipmentLate"
+	// NotificationEventLfdWarning represents LfdWarning NotificationEvent
+	NotificationEventLfdWarning NotificationEvent = "LfdWarning"
+	// NotificationEventTimeOff represents TimeOff NotificationEvent
+	NotificationEventTimeOff NotificationEvent = "TimeOff"
+	// NotificationEventPayStatement represents PayStatement NotificationEvent
+	NotificationEventPayStatement NotificationEvent = "PayStatement"
+	// NotificationEventStatusSuggestion represents StatusSuggestion NotificationEvent
+	NotificationEventStatusSuggestion NotificationEvent = "StatusSuggestion"
+	// NotificationEventMaintenanceDue represents MaintenanceDue NotificationEvent
+	NotificationEventMaintenanceDue NotificationEvent = "MaintenanceDue"
+)
+
+var allowedNotificationEvent [10]NotificationEvent = [10]NotificationEvent{
+	NotificationEventNewUser,
+	NotificationEventShipmentAssigned,
+	NotificationEventShipmentUpdated,
+	NotificationEventShipmentDelivered,
+	NotificationEventShipmentLate,
+	NotificationEventLfdWarning,
+	NotificationEventTimeOff,
+	NotificationEventPayStatement,
+	NotificationEventStatusSuggestion,
+	NotificationEventMaintenanceDue,
+}
+
+// String returns the string representation of
+func (k NotificationEvent) String() string {
+	return string(k)
+}
+
+// IsValidNotificationEvent validates if the input is a NotificationEvent
+func IsValidNotificationEvent(s string) bool {
+	t := NotificationEvent(s)
+	return NotificationEventNewUser == t || NotificationEventShipmentAssigned == t || NotificationEventShipmentUpdated == t || NotificationEventShipmentDelivered == t || NotificationEventShipmentLate == t || NotificationEventLfdWarning == t || NotificationEventTimeOff == t || NotificationEventPayStatement == t || NotificationEventStatusSuggestion == t || NotificationEventMaintenanceDue == t
+}
@@ -0,0 +1,20 @@
+package models_test
+
+// AUTOGENERATED BY: HSM GEN
+
+import (
+	"testing"
+
+	m "github.com/bigpanther/trober/models"
+)
+
+func TestIsValidNotificationEvent(t *testing.T) {
+	var validVal = "NewUser"
+	var inValidVal = "_someInvalidval_"
+	if !m.IsValidNotificationEvent(validVal) {
+		t.Fatalf("IsValidNotificationEvent(%q) should be true", validVal)
+	}
+	if m.IsValidNotificationEvent(inValidVal) {
+		t.Fatalf("IsValidNotificationEvent(%q) should be false", inValidVal)
+	}
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gobuffalo/validate/v3"
+	"github.com/gobuffalo/validate/v3/validators"
+	"github.com/gofrs/uuid"
+)
+
+// DefaultNotificationChannel is how a user gets an event without a preference for it
+const DefaultNotificationChannel = NotificationChannelPush
+
+// NotificationPreference is used by pop to map your notification_preferences database table to your go code.
+// It is the channel a user gets an event on, a user has one per event at most.
+type NotificationPreference struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	TenantID  uuid.UUID `json:"tenant_id" db:"tenant_id"`
+	UserID    uuid.UUID `json:"user_id" db:"user_id"`
+	Event     string    `json:"event" db:"event"`
+	Channel   string    `json:"channel" db:"channel"`
+	Tenant    *Tenant   `belongs_to:"tenant" json:"-"`
+	User      *User     `belongs_to:"user" json:"-"`
+}
+
+// NotificationPreferences is not required by pop and may be deleted
+type NotificationPreferences []NotificationPreference
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (n *NotificationPreference) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.UUIDIsPresent{Field: n.UserID, Name: "UserID"},
+		&validators.FuncValidator{Fn: func() bool {
+			return IsValidNotificationEvent(n.Event)
+		}, Field: n.Event, Name: "Event"},
+		&validators.FuncValidator{Fn: func() bool {
+			return IsValidNotificationChannel(n.Channel)
+		}, Field: n.Channel, Name: "Channel"},
+	), nil
+}
+
+// NotificationSetting is the channel a user gets an event on, whether the user chose it or not
+type NotificationSetting struct {
+	Event   string `json:"event"`
+	Channel string `json:"channel"`
+}
+
+// NotificationSettings are the channels of all the events of a user
+type NotificationSettings []NotificationSetting
+
+// Settings returns the channel of every event for the user the preferences are of, the default one for the events
+// without a preference
+func (n NotificationPreferences) Settings() NotificationSettings {
+	var settings = make(NotificationSettings, len(allowedNotificationEvent))
+	for i, event := range allowedNotificationEvent {
+		settings[i] = NotificationSetting{Event: event.String(), Channel: n.Channel(event).String()}
+	}
+	return settings
+}
+
+// Channel returns the channel of an event for the user the preferences are of
+func (n NotificationPreferences) Channel(event NotificationEvent) NotificationChannel {
+	for _, p := range n {
+		if p.Event == event.String() {
+			return NotificationChannel(p.Channel)
+		}
+	}
+	return DefaultNotificationChannel
+}
+
+// LoadNotificationChannels returns the channel each of the users gets an event on
+func LoadNotificationChannels(tx *pop.Connection, event NotificationEvent, userIDs ...uuid.UUID) (map[uuid.UUID]NotificationChannel, error) {
+	var channels = map[uuid.UUID]NotificationChannel{}
+	if len(userIDs) == 0 {
+		return channels, nil
+	}
+	var ids = make([]interface{}, len(userIDs))
+	for i, id := range userIDs {
+		ids[i] = id
+		channels[id] = DefaultNotificationChannel
+	}
+	all := NotificationPreferences{}
+	if err := tx.Where("event = ?", event.String()).Where("user_id IN (?)", ids...).All(&all); err != nil {
+		return nil, err
+	}
+	for _, p := range all {
+		channels[p.UserID] = NotificationChannel(p.Channel)
+	}
+	return channels, nil
+}
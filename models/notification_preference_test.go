@@ -0,0 +1,44 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gofrs/uuid"
+)
+
+func (ms *ModelSuite) Test_NotificationPreference() {
+	var userID = uuid.Must(uuid.NewV4())
+	var tests = []struct {
+		preference               *NotificationPreference
+		expectedValidationErrors int
+	}{
+		{&NotificationPreference{}, 3},
+		{&NotificationPreference{UserID: userID, Event: NotificationEventLfdWarning.String(), Channel: NotificationChannelEmail.String()}, 0},
+		{&NotificationPreference{UserID: userID, Event: NotificationEventLfdWarning.String(), Channel: "Fax"}, 1},
+		{&NotificationPreference{UserID: userID, Event: "Anything", Channel: NotificationChannelNone.String()}, 1},
+	}
+	for i, test := range tests {
+		ms.T().Run(fmt.Sprint(i), func(t *testing.T) {
+			v, err := test.preference.Validate(ms.DB)
+			ms.Nil(err)
+			ms.Equal(test.expectedValidationErrors, len(v.Errors))
+		})
+	}
+}
+
+func (ms *ModelSuite) Test_NotificationPreferencesSettings() {
+	var preferences = NotificationPreferences{
+		{Event: NotificationEventShipmentUpdated.String(), Channel: NotificationChannelNone.String()},
+		{Event: NotificationEventLfdWarning.String(), Channel: NotificationChannelEmail.String()},
+	}
+	ms.Equal(NotificationChannelNone, preferences.Channel(NotificationEventShipmentUpdated))
+	ms.Equal(NotificationChannelEmail, preferences.Channel(NotificationEventLfdWarning))
+	ms.Equal(DefaultNotificationChannel, preferences.Channel(NotificationEventShipmentDelivered))
+	settings := preferences.Settings()
+	ms.Equal(len(allowedNotificationEvent), len(settings))
+	for _, s := range settings {
+		ms.Equal(preferences.Channel(NotificationEvent(s.Event)).String(), s.Channel)
+	}
+	ms.Equal(DefaultNotificationChannel.String(), NotificationPreferences{}.Settings()[0].Channel)
+}
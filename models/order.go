@@ -30,10 +30,10 @@ type Order struct {
 	Eta              nulls.Time   `json:"eta" db:"eta"`
 	SoNumber         nulls.String `json:"so_number" db:"so_number"`
 	Shipline         nulls.String `json:"shipline" db:"shipline"`
-	PickupCharges    nulls.Int    `json:"pickup_charges" db:"pickup_charges"`
-	PickupCost       nulls.Int    `json:"pickup_cost" db:"pickup_cost"`
-	DropoffCharges   nulls.Int    `json:"dropoff_charges" db:"dropoff_charges"`
-	DropoffCost      nulls.Int    `json:"dropoff_cost" db:"dropoff_cost"`
+	PickupCharges    NullMoney    `json:"pickup_charges" db:"pickup_charges"`
+	PickupCost       NullMoney    `json:"pickup_cost" db:"pickup_cost"`
+	DropoffCharges   NullMoney    `json:"dropoff_charges" db:"dropoff_charges"`
+	DropoffCost      NullMoney    `json:"dropoff_cost" db:"dropoff_cost"`
 	Rld              nulls.String `json:"rld" db:"rld"`
 	Erd              nulls.Time   `json:"erd" db:"erd"`
 	Docco            nulls.Time   `json:"docco" db:"docco"`
@@ -57,5 +57,79 @@ func (o *Order) Validate(tx *pop.Connection) (*validate.Errors, error) {
 		&validators.FuncValidator{Fn: func() bool {
 			return IsValidShipmentType(o.Type)
 		}, Field: o.Type, Name: "Type"},
+		&validators.FuncValidator{Fn: func() bool {
+			for _, m := range o.amounts() {
+				if !IsValidCurrency(m.Currency) {
+					return false
+				}
+			}
+			// Charges and costs are added up in totals
+			_, err := SumMoney(o.amounts()...)
+			return err == nil
+		}, Field: "Currency", Name: "Currency", Message: "%s of the charges and costs must be valid and the same"},
 	), nil
 }
+
+// amounts returns the charges and costs of the order that are set
+func (o *Order) amounts() []Money {
+	var amounts = []Money{}
+	for _, m := range []NullMoney{o.PickupCharges, o.PickupCost, o.DropoffCharges, o.DropoffCost} {
+		if m.Valid {
+			amounts = append(amounts, m.Money)
+		}
+	}
+	return amounts
+}
+
+// SetDefaultCurrency sets the currency of the charges and costs that were given without one
+func (o *Order) SetDefaultCurrency(currency string) {
+	o.PickupCharges = o.PickupCharges.WithDefaultCurrency(currency)
+	o.PickupCost = o.PickupCost.WithDefaultCurrency(currency)
+	o.DropoffCharges = o.DropoffCharges.WithDefaultCurrency(currency)
+	o.DropoffCost = o.DropoffCost.WithDefaultCurrency(currency)
+}
+
+// IsLocked checks if the order has been billed and its shipments can no longer change
+func (o *Order) IsLocked() bool {
+	return o.Status == OrderStatusInvoiced.String() || o.Status == OrderStatusPaymentReceived.String()
+}
+
+// CanSetStatus checks if the status of the order can be changed by hand to the given one. Only Open, Accepted
+// and Cancelled are set by hand, the other statuses are rolled up from the shipments or set by invoicing.
+func (o *Order) CanSetStatus(status string) bool {
+	var manual = func(s OrderStatus) bool {
+		return s == OrderStatusOpen || s == OrderStatusAccepted || s == OrderStatusCancelled
+	}
+	return manual(OrderStatus(o.Status)) && manual(OrderStatus(status))
+}
+
+// RollUpStatus derives the status of the order from its shipments.
+// The first shipment that is being worked on moves the order to InProgress, and the order is Delivered
+// once every shipment is either Delivered or Rejected. Orders only move forward, so cancelled, delivered
+// and billed orders keep their status.
+func (o *Order) RollUpStatus(shipments Shipments) OrderStatus {
+	var status = OrderStatus(o.Status)
+	if status != OrderStatusOpen && status != OrderStatusAccepted && status != OrderStatusInProgress {
+		return status
+	}
+	var started, delivered bool
+	var done = len(shipments) > 0
+	for _, s := range shipments {
+		switch ShipmentStatus(s.Status) {
+		case ShipmentStatusDelivered:
+			started, delivered = true, true
+		case ShipmentStatusRejected:
+		case ShipmentStatusAccepted, ShipmentStatusArrived, ShipmentStatusLoaded, ShipmentStatusInTransit:
+			started, done = true, false
+		default:
+			done = false
+		}
+	}
+	if done && delivered {
+		return OrderStatusDelivered
+	}
+	if started {
+		return OrderStatusInProgress
+	}
+	return status
+}
@@ -15,6 +15,9 @@ func (ms *ModelSuite) TestOrder() {
 		{&Order{SerialNumber: "ORD0001"}, 2},
 		{&Order{SerialNumber: "ORD0001", Status: OrderStatusAccepted.String()}, 1},
 		{&Order{SerialNumber: "ORD0001", Status: OrderStatusAccepted.String(), Type: ShipmentTypeInbound.String()}, 0},
+		{&Order{SerialNumber: "ORD0001", Status: OrderStatusAccepted.String(), Type: ShipmentTypeInbound.String(), PickupCharges: NewNullMoney(100, "CAD"), PickupCost: NewNullMoney(80, "CAD")}, 0},
+		{&Order{SerialNumber: "ORD0001", Status: OrderStatusAccepted.String(), Type: ShipmentTypeInbound.String(), PickupCharges: NewNullMoney(100, "")}, 1},
+		{&Order{SerialNumber: "ORD0001", Status: OrderStatusAccepted.String(), Type: ShipmentTypeInbound.String(), PickupCharges: NewNullMoney(100, "CAD"), DropoffCharges: NewNullMoney(100, "USD")}, 1},
 	}
 	for i, test := range tests {
 		ms.T().Run(fmt.Sprint(i), func(t *testing.T) {
@@ -24,3 +27,74 @@ func (ms *ModelSuite) TestOrder() {
 		})
 	}
 }
+
+func (ms *ModelSuite) Test_OrderSetDefaultCurrency() {
+	o := &Order{PickupCharges: NullMoney{Money: NewMoney(100, ""), Valid: true}, PickupCost: NewNullMoney(80, "USD")}
+	o.SetDefaultCurrency("CAD")
+	ms.Equal(NewNullMoney(100, "CAD"), o.PickupCharges)
+	ms.Equal(NewNullMoney(80, "USD"), o.PickupCost)
+	ms.False(o.DropoffCharges.Valid)
+}
+
+func (ms *ModelSuite) Test_OrderRollUpStatus() {
+	var shipments = func(statuses ...ShipmentStatus) Shipments {
+		var s = Shipments{}
+		for _, status := range statuses {
+			s = append(s, Shipment{Status: status.String()})
+		}
+		return s
+	}
+	var tests = []struct {
+		status    OrderStatus
+		shipments Shipments
+		expected  OrderStatus
+	}{
+		{OrderStatusOpen, shipments(), OrderStatusOpen},
+		{OrderStatusOpen, shipments(ShipmentStatusUnassigned, ShipmentStatusAssigned), OrderStatusOpen},
+		{OrderStatusOpen, shipments(ShipmentStatusUnassigned, ShipmentStatusAccepted), OrderStatusInProgress},
+		{OrderStatusAccepted, shipments(ShipmentStatusLoaded), OrderStatusInProgress},
+		{OrderStatusInProgress, shipments(ShipmentStatusDelivered, ShipmentStatusAssigned), OrderStatusInProgress},
+		{OrderStatusInProgress, shipments(ShipmentStatusDelivered, ShipmentStatusRejected), OrderStatusDelivered},
+		{OrderStatusOpen, shipments(ShipmentStatusDelivered, ShipmentStatusDelivered), OrderStatusDelivered},
+		{OrderStatusOpen, shipments(ShipmentStatusRejected), OrderStatusOpen},
+		{OrderStatusCancelled, shipments(ShipmentStatusDelivered), OrderStatusCancelled},
+		{OrderStatusInvoiced, shipments(ShipmentStatusAccepted), OrderStatusInvoiced},
+		{OrderStatusDelivered, shipments(ShipmentStatusAssigned), OrderStatusDelivered},
+	}
+	for i, test := range tests {
+		ms.T().Run(fmt.Sprint(i), func(t *testing.T) {
+			o := &Order{Status: test.status.String()}
+			ms.Equal(test.expected, o.RollUpStatus(test.shipments))
+		})
+	}
+}
+
+func (ms *ModelSuite) Test_OrderIsLocked() {
+	ms.False((&Order{Status: OrderStatusDelivered.String()}).IsLocked())
+	ms.True((&Order{Status: OrderStatusInvoiced.String()}).IsLocked())
+	ms.True((&Order{Status: OrderStatusPaymentReceived.String()}).IsLocked())
+}
+
+func (ms *ModelSuite) Test_OrderCanSetStatus() {
+	var tests = []struct {
+		from     OrderStatus
+		to       OrderStatus
+		expected bool
+	}{
+		{OrderStatusOpen, OrderStatusAccepted, true},
+		{OrderStatusAccepted, OrderStatusCancelled, true},
+		{OrderStatusCancelled, OrderStatusOpen, true},
+		{OrderStatusOpen, OrderStatusDelivered, false},
+		{OrderStatusAccepted, OrderStatusInvoiced, false},
+		{OrderStatusInProgress, OrderStatusOpen, false},
+		{OrderStatusInvoiced, OrderStatusOpen, false},
+		{OrderStatusPaymentReceived, OrderStatusCancelled, false},
+		{OrderStatusOpen, "Closed", false},
+	}
+	for i, test := range tests {
+		ms.T().Run(fmt.Sprint(i), func(t *testing.T) {
+			o := &Order{Status: test.from.String()}
+			ms.Equal(test.expected, o.CanSetStatus(test.to.String()))
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package models
+
+import (
+	"fmt"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/gobuffalo/pop/v6"
+)
+
+// ProfitabilityRow is the revenue, cost and margin of a group of orders in one currency.
+// Amounts are in the minor units of the currency.
+type ProfitabilityRow struct {
+	GroupID       string        `json:"group_id" db:"group_id"`
+	GroupName     string        `json:"group_name" db:"group_name"`
+	Currency      string        `json:"currency" db:"currency"`
+	Orders        int           `json:"orders" db:"orders"`
+	Revenue       int           `json:"revenue" db:"revenue"`
+	Cost          int           `json:"cost" db:"cost"`
+	Margin        int           `json:"margin" db:"margin"`
+	MarginPercent nulls.Float64 `json:"margin_percent" db:"margin_percent"`
+}
+
+// ProfitabilityRows is a profitability report
+type ProfitabilityRows []ProfitabilityRow
+
+// profitabilityGroupColumns are the id and name expressions, and the join providing the name, of each grouping
+var profitabilityGroupColumns = map[ProfitabilityGroup][3]string{
+	ProfitabilityGroupCustomer: {"amounts.customer_id::text", "COALESCE(g.name, '')", "LEFT JOIN customers g ON g.id = amounts.customer_id"},
+	ProfitabilityGroupTerminal: {"COALESCE(amounts.terminal_id::text, '')", "COALESCE(g.name, '')", "LEFT JOIN terminals g ON g.id = amounts.terminal_id"},
+	ProfitabilityGroupCarrier:  {"COALESCE(amounts.carrier_id::text, '')", "COALESCE(g.name, '')", "LEFT JOIN carriers g ON g.id = amounts.carrier_id"},
+	ProfitabilityGroupLane:     {"amounts.lane", "amounts.lane", ""},
+}
+
+// profitabilitySQL computes the report from the orders selected by the first placeholder.
+// Order charges and costs are shared evenly between the shipments of the order, so that lanes, which are
+// set on shipments, add up to the order total. Shipment charges count towards their own shipment.
+const profitabilitySQL = `WITH scoped AS (%s),
+moves AS (
+	SELECT o.id AS order_id, o.customer_id, o.terminal_id, o.carrier_id, s.id AS shipment_id,
+		COALESCE(s.origin, '') || ' -> ' || COALESCE(s.destination, '') AS lane,
+		GREATEST(COUNT(s.id) OVER (PARTITION BY o.id), 1) AS share
+	FROM orders o
+	LEFT JOIN shipments s ON s.order_id = o.id
+	WHERE o.id IN (SELECT id FROM scoped)
+),
+amounts AS (
+	SELECT m.order_id, m.customer_id, m.terminal_id, m.carrier_id, m.lane, a.currency, a.revenue / m.share AS revenue, a.cost / m.share AS cost
+	FROM moves m
+	JOIN orders o ON o.id = m.order_id
+	CROSS JOIN LATERAL (VALUES
+		(o.pickup_charges->>'currency', (o.pickup_charges->>'amount')::numeric, 0::numeric),
+		(o.dropoff_charges->>'currency', (o.dropoff_charges->>'amount')::numeric, 0::numeric),
+		(o.pickup_cost->>'currency', 0::numeric, (o.pickup_cost->>'amount')::numeric),
+		(o.dropoff_cost->>'currency', 0::numeric, (o.dropoff_cost->>'amount')::numeric)
+	) AS a(currency, revenue, cost)
+	WHERE a.currency IS NOT NULL
+	UNION ALL
+	SELECT m.order_id, m.customer_id, m.terminal_id, m.carrier_id, m.lane, c.customer_rate->>'currency', c.quantity * (c.customer_rate->>'amount')::numeric, c.quantity * (c.cost_rate->>'amount')::numeric
+	FROM moves m
+	JOIN shipment_charges c ON c.shipment_id = m.shipment_id
+)
+SELECT %s AS group_id, %s AS group_name, amounts.currency,
+	COUNT(DISTINCT amounts.order_id)::int AS orders,
+	ROUND(SUM(amounts.revenue))::int AS revenue,
+	ROUND(SUM(amounts.cost))::int AS cost,
+	ROUND(SUM(amounts.revenue) - SUM(amounts.cost))::int AS margin,
+	CASE WHEN SUM(amounts.revenue) = 0 THEN NULL ELSE ROUND((SUM(amounts.revenue) - SUM(amounts.cost)) * 100 / SUM(amounts.revenue), 2)::float END AS margin_percent
+FROM amounts %s
+GROUP BY 1, 2, 3
+ORDER BY margin DESC, group_name, currency`
+
+// Profitability returns the revenue, cost and margin of the orders selected by the query, grouped as requested.
+// Amounts in different currencies are never added up, each currency of a group gets its own row.
+func Profitability(tx *pop.Connection, orders *pop.Query, groupBy ProfitabilityGroup) (ProfitabilityRows, error) {
+	columns, ok := profitabilityGroupColumns[groupBy]
+	if !ok {
+		return nil, fmt.Errorf("invalid profitability group %q", groupBy)
+	}
+	// The scoped orders query is already bound, so the report itself must not add placeholders
+	scoped, args := orders.Select("orders.id").ToSQL(pop.NewModel(&Order{}, tx.Context()))
+	rows := ProfitabilityRows{}
+	err := tx.RawQuery(fmt.Sprintf(profitabilitySQL, scoped, columns[0], columns[1], columns[2]), args...).All(&rows)
+	return rows, err
+}
@@ -0,0 +1,35 @@
+package models
+
+// AUTOGENERATED BY: HSM GEN
+
+// ProfitabilityGroup represents the ProfitabilityGroup enum
+type ProfitabilityGroup string
+
+const (
+	// ProfitabilityGroupCustomer represents Customer ProfitabilityGroup
+	ProfitabilityGroupCustomer ProfitabilityGroup = "Customer"
+	// ProfitabilityGroupTerminal represents Terminal ProfitabilityGroup
+	ProfitabilityGroupTerminal ProfitabilityGroup = "Terminal"
+	// ProfitabilityGroupCarrier represents Carrier ProfitabilityGroup
+	ProfitabilityGroupCarrier ProfitabilityGroup = "Carrier"
+	// ProfitabilityGroupLane represents Lane ProfitabilityGroup
+	ProfitabilityGroupLane ProfitabilityGroup = "Lane"
+)
+
+var allowedProfitabilityGroup [4]ProfitabilityGroup = [4]ProfitabilityGroup{
+	ProfitabilityGroupCustomer,
+	ProfitabilityGroupTerminal,
+	ProfitabilityGroupCarrier,
+	ProfitabilityGroupLane,
+}
+
+// String returns the string representation of
+func (k ProfitabilityGroup) String() string {
+	return string(k)
+}
+
+// IsValidProfitabilityGroup validates if the input is a ProfitabilityGroup
+func IsValidProfitabilityGroup(s string) bool {
+	t := ProfitabilityGroup(s)
+	return ProfitabilityGroupCustomer == t || ProfitabilityGroupTerminal == t || ProfitabilityGroupCarrier == t || ProfitabilityGroupLane == t
+}
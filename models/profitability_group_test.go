@@ -0,0 +1,20 @@
+package models_test
+
+// AUTOGENERATED BY: HSM GEN
+
+import (
+	"testing"
+
+	m "github.com/bigpanther/trober/models"
+)
+
+func TestIsValidProfitabilityGroup(t *testing.T) {
+	var validVal = "Customer"
+	var inValidVal = "_someInvalidval_"
+	if !m.IsValidProfitabilityGroup(validVal) {
+		t.Fatalf("IsValidProfitabilityGroup(%q) should be true", validVal)
+	}
+	if m.IsValidProfitabilityGroup(inValidVal) {
+		t.Fatalf("IsValidProfitabilityGroup(%q) should be false", inValidVal)
+	}
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"github.com/gofrs/uuid"
+)
+
+// Quote is the price of a prospective order from the rate card of its customer. It is not persisted.
+type Quote struct {
+	RateCardID uuid.UUID  `json:"rate_card_id"`
+	CustomerID uuid.UUID  `json:"customer_id"`
+	Currency   string     `json:"currency"`
+	Lines      QuoteLines `json:"lines"`
+	Total      Money      `json:"total"`
+}
+
+// QuoteLine is a priced item of a quote
+type QuoteLine struct {
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	Quantity    int    `json:"quantity"`
+	Rate        Money  `json:"rate"`
+	Amount      Money  `json:"amount"`
+}
+
+// QuoteLines is a list of QuoteLine
+type QuoteLines []QuoteLine
+
+// add appends a line to the quote and adds its amount to the total
+func (q *Quote) add(lineType QuoteLineType, description string, quantity int, rate Money) error {
+	line := QuoteLine{
+		Type:        lineType.String(),
+		Description: description,
+		Quantity:    quantity,
+		Rate:        rate,
+		Amount:      rate.Multiply(quantity),
+	}
+	total, err := q.Total.Add(line.Amount)
+	if err != nil {
+		return err
+	}
+	q.Lines = append(q.Lines, line)
+	q.Total = total
+	return nil
+}
@@ -0,0 +1,35 @@
+package models
+
+// AUTOGENERATED BY: HSM GEN
+
+// QuoteLineType represents the QuoteLineType enum
+type QuoteLineType string
+
+const (
+	// QuoteLineTypePickup represents Pickup QuoteLineType
+	QuoteLineTypePickup QuoteLineType = "Pickup"
+	// QuoteLineTypeDropoff represents Dropoff QuoteLineType
+	QuoteLineTypeDropoff QuoteLineType = "Dropoff"
+	// QuoteLineTypeFuelSurcharge represents FuelSurcharge QuoteLineType
+	QuoteLineTypeFuelSurcharge QuoteLineType = "FuelSurcharge"
+	// QuoteLineTypeAccessorial represents Accessorial QuoteLineType
+	QuoteLineTypeAccessorial QuoteLineType = "Accessorial"
+)
+
+var allowedQuoteLineType [4]QuoteLineType = [4]QuoteLineType{
+	QuoteLineTypePickup,
+	QuoteLineTypeDropoff,
+	QuoteLineTypeFuelSurcharge,
+	QuoteLineTypeAccessorial,
+}
+
+// String returns the string representation of
+func (k QuoteLineType) String() string {
+	return string(k)
+}
+
+// IsValidQuoteLineType validates if the input is a QuoteLineType
+func IsValidQuoteLineType(s string) bool {
+	t := QuoteLineType(s)
+	return QuoteLineTypePickup == t || QuoteLineTypeDropoff == t || QuoteLineTypeFuelSurcharge == t || QuoteLineTypeAccessorial == t
+}
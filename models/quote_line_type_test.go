@@ -0,0 +1,20 @@
+package models_test
+
+// AUTOGENERATED BY: HSM GEN
+
+import (
+	"testing"
+
+	m "github.com/bigpanther/trober/models"
+)
+
+func TestIsValidQuoteLineType(t *testing.T) {
+	var validVal = "Pickup"
+	var inValidVal = "_someInvalidval_"
+	if !m.IsValidQuoteLineType(validVal) {
+		t.Fatalf("IsValidQuoteLineType(%q) should be true", validVal)
+	}
+	if m.IsValidQuoteLineType(inValidVal) {
+		t.Fatalf("IsValidQuoteLineType(%q) should be false", inValidVal)
+	}
+}
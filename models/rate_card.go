@@ -0,0 +1,301 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gobuffalo/validate/v3"
+	"github.com/gobuffalo/validate/v3/validators"
+	"github.com/gofrs/uuid"
+)
+
+// ErrNoRate is returned when a rate card has no rate for a move or an accessorial
+var ErrNoRate = errors.New("no matching rate")
+
+// RateCard is used by pop to map your rate_cards database table to your go code.
+// A rate card prices the orders of a customer created between EffectiveFrom and EffectiveTo, which is exclusive
+// and open ended when not set. Its rates are all in the currency of the rate card.
+type RateCard struct {
+	ID                   uuid.UUID            `json:"id" db:"id"`
+	CreatedAt            time.Time            `json:"created_at" db:"created_at"`
+	UpdatedAt            time.Time            `json:"updated_at" db:"updated_at"`
+	CreatedBy            uuid.UUID            `json:"created_by" db:"created_by"`
+	TenantID             uuid.UUID            `json:"tenant_id" db:"tenant_id"`
+	CustomerID           uuid.UUID            `json:"customer_id" db:"customer_id"`
+	Name                 string               `json:"name" db:"name"`
+	Currency             string               `json:"currency" db:"currency"`
+	FuelSurchargePercent float64              `json:"fuel_surcharge_percent" db:"fuel_surcharge_percent"`
+	EffectiveFrom        time.Time            `json:"effective_from" db:"effective_from"`
+	EffectiveTo          nulls.Time           `json:"effective_to" db:"effective_to"`
+	Tenant               *Tenant              `belongs_to:"tenant" json:"-"`
+	Customer             *Customer            `belongs_to:"customer" json:"customer,omitempty"`
+	Lanes                RateCardLanes        `has_many:"rate_card_lanes" json:"lanes"`
+	Accessorials         RateCardAccessorials `has_many:"rate_card_accessorials" json:"accessorials"`
+}
+
+// RateCards is not required by pop and may be deleted
+type RateCards []RateCard
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (rc *RateCard) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.StringIsPresent{Field: rc.Name, Name: "Name"},
+		&validators.UUIDIsPresent{Field: rc.CustomerID, Name: "CustomerID"},
+		&validators.FuncValidator{Fn: func() bool {
+			return IsValidCurrency(rc.Currency)
+		}, Field: rc.Currency, Name: "Currency"},
+		&validators.FuncValidator{Fn: func() bool {
+			return rc.FuelSurchargePercent >= 0
+		}, Field: fmt.Sprint(rc.FuelSurchargePercent), Name: "FuelSurchargePercent"},
+		&validators.TimeIsPresent{Field: rc.EffectiveFrom, Name: "EffectiveFrom"},
+		&validators.FuncValidator{Fn: func() bool {
+			return !rc.EffectiveTo.Valid || rc.EffectiveTo.Time.After(rc.EffectiveFrom)
+		}, Field: "EffectiveTo", Name: "EffectiveTo", Message: "%s must be after EffectiveFrom"},
+		&validators.FuncValidator{Fn: func() bool {
+			for _, m := range rc.rates() {
+				if m.Currency != rc.Currency {
+					return false
+				}
+			}
+			return true
+		}, Field: "Currency", Name: "Currency", Message: "%s of the rates must be the currency of the rate card"},
+	), nil
+}
+
+// rates returns the rates of the lanes and the accessorials of the rate card
+func (rc *RateCard) rates() []Money {
+	var rates = []Money{}
+	for _, l := range rc.Lanes {
+		rates = append(rates, l.PickupRate, l.DropoffRate)
+	}
+	for _, a := range rc.Accessorials {
+		rates = append(rates, a.Rate)
+	}
+	return rates
+}
+
+// SetDefaultCurrency sets the currency of the rates that were given without one to the currency of the rate card
+func (rc *RateCard) SetDefaultCurrency() {
+	for i := range rc.Lanes {
+		if rc.Lanes[i].PickupRate.Currency == "" {
+			rc.Lanes[i].PickupRate.Currency = rc.Currency
+		}
+		if rc.Lanes[i].DropoffRate.Currency == "" {
+			rc.Lanes[i].DropoffRate.Currency = rc.Currency
+		}
+	}
+	for i := range rc.Accessorials {
+		if rc.Accessorials[i].Rate.Currency == "" {
+			rc.Accessorials[i].Rate.Currency = rc.Currency
+		}
+	}
+}
+
+// Overlaps checks if another rate card of the customer is effective during part of the period of this one.
+// Periods must not overlap so that exactly one rate card prices an order.
+func (rc *RateCard) Overlaps(tx *pop.Connection) (bool, error) {
+	q := tx.Where("customer_id = ?", rc.CustomerID).Where("id <> ?", rc.ID).
+		Where("(effective_to IS NULL OR effective_to > ?)", rc.EffectiveFrom)
+	if rc.EffectiveTo.Valid {
+		q = q.Where("effective_from < ?", rc.EffectiveTo.Time)
+	}
+	return q.Exists(&RateCards{})
+}
+
+// FindRateCard returns the rate card of the customer effective at the given time, with its rates.
+// It returns sql.ErrNoRows when the customer has none.
+func FindRateCard(tx *pop.Connection, customerID uuid.UUID, at time.Time) (*RateCard, error) {
+	rc := &RateCard{}
+	err := tx.Eager("Lanes", "Accessorials").Where("customer_id = ?", customerID).
+		Where("effective_from <= ?", at).Where("(effective_to IS NULL OR effective_to > ?)", at).
+		Order("effective_from DESC").First(rc)
+	return rc, err
+}
+
+// Lane returns the rate of a move from a terminal to a destination. A lane for the size of the container
+// takes precedence over a lane for any size. Destinations are matched ignoring case and surrounding spaces.
+func (rc *RateCard) Lane(terminalID uuid.UUID, destination string, size string) (*RateCardLane, error) {
+	var match *RateCardLane
+	for i, l := range rc.Lanes {
+		if l.TerminalID != terminalID || !strings.EqualFold(strings.TrimSpace(l.Destination), strings.TrimSpace(destination)) {
+			continue
+		}
+		if l.Size.Valid && l.Size.String == size {
+			return &rc.Lanes[i], nil
+		}
+		if !l.Size.Valid {
+			match = &rc.Lanes[i]
+		}
+	}
+	if match == nil {
+		return nil, fmt.Errorf("%w for a %s container to %q", ErrNoRate, size, destination)
+	}
+	return match, nil
+}
+
+// Accessorial returns the rate of an accessorial charge type
+func (rc *RateCard) Accessorial(chargeType string) (*RateCardAccessorial, error) {
+	for i, a := range rc.Accessorials {
+		if a.Type == chargeType {
+			return &rc.Accessorials[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w for %s", ErrNoRate, chargeType)
+}
+
+// FuelSurcharge returns the fuel surcharge on a base rate, rounded to the nearest minor unit
+func (rc *RateCard) FuelSurcharge(rate Money) Money {
+	return NewMoney(int(math.Round(float64(rate.Amount)*rc.FuelSurchargePercent/100)), rate.Currency)
+}
+
+// Quote prices the moves of the shipments from the terminal, and the accessorial charges.
+// Only the type and quantity of the accessorial charges are used.
+func (rc *RateCard) Quote(terminalID nulls.UUID, shipments Shipments, accessorials ShipmentCharges) (*Quote, error) {
+	if len(shipments) == 0 {
+		return nil, errors.New("at least one shipment is required")
+	}
+	if !terminalID.Valid {
+		return nil, fmt.Errorf("%w without a terminal", ErrNoRate)
+	}
+	quote := &Quote{RateCardID: rc.ID, CustomerID: rc.CustomerID, Currency: rc.Currency, Lines: QuoteLines{}, Total: NewMoney(0, rc.Currency)}
+	var fuelSurcharge = NewMoney(0, rc.Currency)
+	for _, s := range shipments {
+		lane, err := rc.Lane(terminalID.UUID, s.Destination.String, s.Size.String)
+		if err != nil {
+			return nil, err
+		}
+		var move = fmt.Sprintf("%s to %s", s.Size.String, lane.Destination)
+		if err := quote.add(QuoteLineTypePickup, "Pickup - "+move, 1, lane.PickupRate); err != nil {
+			return nil, err
+		}
+		if err := quote.add(QuoteLineTypeDropoff, "Dropoff - "+move, 1, lane.DropoffRate); err != nil {
+			return nil, err
+		}
+		if fuelSurcharge, err = SumMoney(fuelSurcharge, rc.FuelSurcharge(lane.PickupRate), rc.FuelSurcharge(lane.DropoffRate)); err != nil {
+			return nil, err
+		}
+	}
+	if fuelSurcharge.Amount != 0 {
+		if err := quote.add(QuoteLineTypeFuelSurcharge, fmt.Sprintf("Fuel surcharge - %g%%", rc.FuelSurchargePercent), 1, fuelSurcharge); err != nil {
+			return nil, err
+		}
+	}
+	for _, c := range accessorials {
+		if c.Quantity <= 0 {
+			return nil, fmt.Errorf("invalid quantity %d for %s", c.Quantity, c.Type)
+		}
+		a, err := rc.Accessorial(c.Type)
+		if err != nil {
+			return nil, err
+		}
+		if err := quote.add(QuoteLineTypeAccessorial, a.Type, c.Quantity, a.Rate); err != nil {
+			return nil, err
+		}
+	}
+	return quote, nil
+}
+
+// FillCharges sets the pickup and dropoff charges of the order that were not given from the rate card.
+// The charges include the fuel surcharge. Charges that were given are kept as is.
+func (rc *RateCard) FillCharges(o *Order) error {
+	if o.PickupCharges.Valid && o.DropoffCharges.Valid {
+		return nil
+	}
+	if len(o.Shipments) == 0 {
+		return errors.New("an order without shipments cannot be priced")
+	}
+	if !o.TerminalID.Valid {
+		return fmt.Errorf("%w without a terminal", ErrNoRate)
+	}
+	var pickup, dropoff = NewMoney(0, rc.Currency), NewMoney(0, rc.Currency)
+	for _, s := range o.Shipments {
+		lane, err := rc.Lane(o.TerminalID.UUID, s.Destination.String, s.Size.String)
+		if err != nil {
+			return err
+		}
+		if pickup, err = SumMoney(pickup, lane.PickupRate, rc.FuelSurcharge(lane.PickupRate)); err != nil {
+			return err
+		}
+		if dropoff, err = SumMoney(dropoff, lane.DropoffRate, rc.FuelSurcharge(lane.DropoffRate)); err != nil {
+			return err
+		}
+	}
+	if !o.PickupCharges.Valid {
+		o.PickupCharges = NullMoney{Money: pickup, Valid: true}
+	}
+	if !o.DropoffCharges.Valid {
+		o.DropoffCharges = NullMoney{Money: dropoff, Valid: true}
+	}
+	return nil
+}
+
+// RateCardLane is used by pop to map your rate_card_lanes database table to your go code.
+// It is the base rate of a move from a terminal to a destination, for a container size or for any size when not set.
+type RateCardLane struct {
+	ID          uuid.UUID    `json:"id" db:"id"`
+	CreatedAt   time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at" db:"updated_at"`
+	RateCardID  uuid.UUID    `json:"rate_card_id" db:"rate_card_id"`
+	TerminalID  uuid.UUID    `json:"terminal_id" db:"terminal_id"`
+	Destination string       `json:"destination" db:"destination"`
+	Size        nulls.String `json:"size" db:"size"`
+	PickupRate  Money        `json:"pickup_rate" db:"pickup_rate"`
+	DropoffRate Money        `json:"dropoff_rate" db:"dropoff_rate"`
+	RateCard    *RateCard    `belongs_to:"rate_card" json:"-"`
+}
+
+// RateCardLanes is not required by pop and may be deleted
+type RateCardLanes []RateCardLane
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (l *RateCardLane) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.UUIDIsPresent{Field: l.TerminalID, Name: "TerminalID"},
+		&validators.StringIsPresent{Field: l.Destination, Name: "Destination"},
+		&validators.FuncValidator{Fn: func() bool {
+			// Value can be null
+			return !l.Size.Valid || IsValidShipmentSize(l.Size.String)
+		}, Field: l.Size.String, Name: "Size"},
+		&validators.IntIsGreaterThan{Field: l.PickupRate.Amount, Name: "PickupRate", Compared: -1},
+		&validators.IntIsGreaterThan{Field: l.DropoffRate.Amount, Name: "DropoffRate", Compared: -1},
+		&validators.FuncValidator{Fn: func() bool {
+			return IsValidCurrency(l.PickupRate.Currency) && l.DropoffRate.Currency == l.PickupRate.Currency
+		}, Field: "Currency", Name: "Currency", Message: "%s of the rates must be valid and the same"},
+	), nil
+}
+
+// RateCardAccessorial is used by pop to map your rate_card_accessorials database table to your go code.
+// It is the unit rate of an accessorial charge type.
+type RateCardAccessorial struct {
+	ID         uuid.UUID `json:"id" db:"id"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+	RateCardID uuid.UUID `json:"rate_card_id" db:"rate_card_id"`
+	Type       string    `json:"type" db:"type"`
+	Rate       Money     `json:"rate" db:"rate"`
+	RateCard   *RateCard `belongs_to:"rate_card" json:"-"`
+}
+
+// RateCardAccessorials is not required by pop and may be deleted
+type RateCardAccessorials []RateCardAccessorial
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (a *RateCardAccessorial) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.FuncValidator{Fn: func() bool {
+			return IsValidShipmentChargeType(a.Type)
+		}, Field: a.Type, Name: "Type"},
+		&validators.IntIsGreaterThan{Field: a.Rate.Amount, Name: "Rate", Compared: -1},
+		&validators.FuncValidator{Fn: func() bool {
+			return IsValidCurrency(a.Rate.Currency)
+		}, Field: a.Rate.Currency, Name: "Currency"},
+	), nil
+}
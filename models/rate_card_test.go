@@ -0,0 +1,159 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gobuffalo/validate/v3"
+	"github.com/gofrs/uuid"
+)
+
+func (ms *ModelSuite) Test_RateCard() {
+	var customerID = uuid.Must(uuid.NewV4())
+	var from = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	var tests = []struct {
+		rateCard                 *RateCard
+		expectedValidationErrors int
+	}{
+		{&RateCard{}, 4},
+		{&RateCard{Name: "2021", CustomerID: customerID, Currency: "CAD", EffectiveFrom: from}, 0},
+		{&RateCard{Name: "2021", CustomerID: customerID, Currency: "cad", EffectiveFrom: from, FuelSurchargePercent: -1}, 2},
+		{&RateCard{Name: "2021", CustomerID: customerID, Currency: "CAD", EffectiveFrom: from, EffectiveTo: nulls.NewTime(from)}, 1},
+		{&RateCard{Name: "2021", CustomerID: customerID, Currency: "CAD", EffectiveFrom: from, EffectiveTo: nulls.NewTime(from.AddDate(1, 0, 0)), FuelSurchargePercent: 12.5}, 0},
+		{&RateCard{Name: "2021", CustomerID: customerID, Currency: "CAD", EffectiveFrom: from, Accessorials: RateCardAccessorials{{Rate: NewMoney(9000, "USD")}}}, 1},
+	}
+	for i, test := range tests {
+		ms.T().Run(fmt.Sprint(i), func(t *testing.T) {
+			v, err := test.rateCard.Validate(ms.DB)
+			ms.Nil(err)
+			ms.Equal(test.expectedValidationErrors, len(v.Errors))
+		})
+	}
+}
+
+func (ms *ModelSuite) Test_RateCardRates() {
+	var terminalID = uuid.Must(uuid.NewV4())
+	var tests = []struct {
+		rate interface {
+			Validate(*pop.Connection) (*validate.Errors, error)
+		}
+		expectedValidationErrors int
+	}{
+		{&RateCardLane{}, 3},
+		{&RateCardLane{TerminalID: terminalID, Destination: "Surrey", Size: nulls.NewString("53ST"), PickupRate: NewMoney(0, "CAD"), DropoffRate: NewMoney(0, "CAD")}, 1},
+		{&RateCardLane{TerminalID: terminalID, Destination: "Surrey", PickupRate: NewMoney(-1, "CAD"), DropoffRate: NewMoney(-1, "CAD")}, 2},
+		{&RateCardLane{TerminalID: terminalID, Destination: "Surrey", PickupRate: NewMoney(100, "CAD"), DropoffRate: NewMoney(100, "USD")}, 1},
+		{&RateCardLane{TerminalID: terminalID, Destination: "Surrey", Size: nulls.NewString(ShipmentSize40HC.String()), PickupRate: NewMoney(30000, "CAD"), DropoffRate: NewMoney(0, "CAD")}, 0},
+		{&RateCardAccessorial{}, 2},
+		{&RateCardAccessorial{Type: ShipmentChargeTypeDetention.String(), Rate: NewMoney(-1, "CAD")}, 1},
+		{&RateCardAccessorial{Type: ShipmentChargeTypeDetention.String(), Rate: NewMoney(9000, "CAD")}, 0},
+	}
+	for i, test := range tests {
+		ms.T().Run(fmt.Sprint(i), func(t *testing.T) {
+			v, err := test.rate.Validate(ms.DB)
+			ms.Nil(err)
+			ms.Equal(test.expectedValidationErrors, len(v.Errors))
+		})
+	}
+}
+
+func newTestRateCard(terminalID uuid.UUID) *RateCard {
+	return &RateCard{
+		Currency:             "CAD",
+		FuelSurchargePercent: 10,
+		Lanes: RateCardLanes{
+			{TerminalID: terminalID, Destination: "Surrey", PickupRate: NewMoney(20000, "CAD"), DropoffRate: NewMoney(10000, "CAD")},
+			{TerminalID: terminalID, Destination: "Surrey", Size: nulls.NewString(ShipmentSize20ST.String()), PickupRate: NewMoney(15000, "CAD"), DropoffRate: NewMoney(5000, "CAD")},
+			{TerminalID: terminalID, Destination: "Delta", Size: nulls.NewString(ShipmentSize40HC.String()), PickupRate: NewMoney(12345, "CAD"), DropoffRate: NewMoney(0, "CAD")},
+		},
+		Accessorials: RateCardAccessorials{
+			{Type: ShipmentChargeTypeDetention.String(), Rate: NewMoney(9000, "CAD")},
+		},
+	}
+}
+
+func (ms *ModelSuite) Test_RateCardLane() {
+	var terminalID = uuid.Must(uuid.NewV4())
+	rc := newTestRateCard(terminalID)
+	var tests = []struct {
+		terminalID  uuid.UUID
+		destination string
+		size        string
+		pickupRate  int
+		err         bool
+	}{
+		{terminalID, "Surrey", ShipmentSize20ST.String(), 15000, false},
+		{terminalID, " surrey ", ShipmentSize40ST.String(), 20000, false},
+		{terminalID, "Surrey", "", 20000, false},
+		{terminalID, "Delta", ShipmentSize40HC.String(), 12345, false},
+		{terminalID, "Delta", ShipmentSize40ST.String(), 0, true},
+		{terminalID, "Langley", ShipmentSize40ST.String(), 0, true},
+		{uuid.Must(uuid.NewV4()), "Surrey", ShipmentSize40ST.String(), 0, true},
+	}
+	for i, test := range tests {
+		ms.T().Run(fmt.Sprint(i), func(t *testing.T) {
+			lane, err := rc.Lane(test.terminalID, test.destination, test.size)
+			if test.err {
+				ms.True(errors.Is(err, ErrNoRate))
+				return
+			}
+			ms.Nil(err)
+			ms.Equal(NewMoney(test.pickupRate, "CAD"), lane.PickupRate)
+		})
+	}
+}
+
+func (ms *ModelSuite) Test_RateCardQuote() {
+	var terminalID = uuid.Must(uuid.NewV4())
+	rc := newTestRateCard(terminalID)
+	shipments := Shipments{
+		{Destination: nulls.NewString("Surrey"), Size: nulls.NewString(ShipmentSize20ST.String())},
+		{Destination: nulls.NewString("Delta"), Size: nulls.NewString(ShipmentSize40HC.String())},
+	}
+	quote, err := rc.Quote(nulls.NewUUID(terminalID), shipments, ShipmentCharges{{Type: ShipmentChargeTypeDetention.String(), Quantity: 2}})
+	ms.Nil(err)
+	ms.Equal(6, len(quote.Lines))
+	ms.Equal(QuoteLineTypeFuelSurcharge.String(), quote.Lines[4].Type)
+	// 10% of 15000, 5000 and 12345 rounded
+	ms.Equal(NewMoney(1500+500+1235, "CAD"), quote.Lines[4].Amount)
+	ms.Equal(NewMoney(18000, "CAD"), quote.Lines[5].Amount)
+	ms.Equal(NewMoney(15000+5000+12345+0+3235+18000, "CAD"), quote.Total)
+
+	_, err = rc.Quote(nulls.UUID{}, shipments, nil)
+	ms.True(errors.Is(err, ErrNoRate))
+	_, err = rc.Quote(nulls.NewUUID(terminalID), Shipments{}, nil)
+	ms.Error(err)
+	_, err = rc.Quote(nulls.NewUUID(terminalID), shipments, ShipmentCharges{{Type: ShipmentChargeTypeStorage.String(), Quantity: 1}})
+	ms.True(errors.Is(err, ErrNoRate))
+	_, err = rc.Quote(nulls.NewUUID(terminalID), shipments, ShipmentCharges{{Type: ShipmentChargeTypeDetention.String()}})
+	ms.Error(err)
+}
+
+func (ms *ModelSuite) Test_RateCardFillCharges() {
+	var terminalID = uuid.Must(uuid.NewV4())
+	rc := newTestRateCard(terminalID)
+	var shipments = Shipments{
+		{Destination: nulls.NewString("Surrey"), Size: nulls.NewString(ShipmentSize20ST.String())},
+		{Destination: nulls.NewString("Surrey"), Size: nulls.NewString(ShipmentSize40ST.String())},
+	}
+	order := &Order{TerminalID: nulls.NewUUID(terminalID), Shipments: shipments}
+	ms.Nil(rc.FillCharges(order))
+	ms.Equal(NewNullMoney(16500+22000, "CAD"), order.PickupCharges)
+	ms.Equal(NewNullMoney(5500+11000, "CAD"), order.DropoffCharges)
+
+	order = &Order{TerminalID: nulls.NewUUID(terminalID), Shipments: shipments, PickupCharges: NewNullMoney(100, "CAD")}
+	ms.Nil(rc.FillCharges(order))
+	ms.Equal(NewNullMoney(100, "CAD"), order.PickupCharges)
+	ms.Equal(NewNullMoney(16500, "CAD"), order.DropoffCharges)
+
+	order = &Order{Shipments: shipments}
+	ms.True(errors.Is(rc.FillCharges(order), ErrNoRate))
+	ms.False(order.PickupCharges.Valid)
+	order = &Order{TerminalID: nulls.NewUUID(terminalID)}
+	ms.Error(rc.FillCharges(order))
+	ms.False(order.PickupCharges.Valid)
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/gobuffalo/nulls"
@@ -11,31 +12,45 @@ import (
 )
 
 // Shipment is used by pop to map your shipments database table to your go code.
+// Origin and Destination can reference a stored Location, they take the name of the location when they have no text.
+// Eta is predicted from the GPS points of the driver while the shipment is in transit, EtaAlertedAt is when the customer
+// was told it runs late. TractorID and ChassisID are the Equipment that moves it. LfdAlertedThreshold is the time before
+// the last free day, in seconds, of the last demurrage alert sent for it.
 type Shipment struct {
-	ID              uuid.UUID    `json:"id" db:"id"`
-	CreatedAt       time.Time    `json:"created_at" db:"created_at"`
-	UpdatedAt       time.Time    `json:"updated_at" db:"updated_at"`
-	CreatedBy       uuid.UUID    `json:"created_by" db:"created_by"`
-	TenantID        uuid.UUID    `json:"tenant_id" db:"tenant_id"`
-	CarrierID       nulls.UUID   `json:"carrier_id" db:"carrier_id"`
-	TerminalID      nulls.UUID   `json:"terminal_id" db:"terminal_id"`
-	OrderID         nulls.UUID   `json:"order_id" db:"order_id"`
-	CustomerID      nulls.UUID   `json:"customer_id" db:"customer_id"`
-	SerialNumber    string       `json:"serial_number" db:"serial_number"`
-	Origin          nulls.String `json:"origin" db:"origin"`
-	Destination     nulls.String `json:"destination" db:"destination"`
-	Lfd             nulls.Time   `json:"lfd" db:"lfd"`
-	ReservationTime nulls.Time   `json:"reservation_time" db:"reservation_time"`
-	Size            nulls.String `json:"size" db:"size"`
-	Type            string       `json:"type" db:"type"`
-	Status          string       `json:"status" db:"status"`
-	DriverID        nulls.UUID   `json:"driver_id" db:"driver_id"`
-	Tenant          *Tenant      `belongs_to:"tenant" json:"-"`
-	Terminal        *Terminal    `belongs_to:"terminal"  json:"terminal,omitempty"`
-	Carrier         *Carrier     `belongs_to:"carrier" json:"carrier,omitempty"`
-	Order           *Order       `belongs_to:"order" json:"order,omitempty"`
-	Customer        *Customer    `belongs_to:"customer" json:"customer,omitempty"`
-	Driver          *User        `belongs_to:"user" json:"driver,omitempty"`
+	ID                    uuid.UUID    `json:"id" db:"id"`
+	CreatedAt             time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt             time.Time    `json:"updated_at" db:"updated_at"`
+	CreatedBy             uuid.UUID    `json:"created_by" db:"created_by"`
+	TenantID              uuid.UUID    `json:"tenant_id" db:"tenant_id"`
+	CarrierID             nulls.UUID   `json:"carrier_id" db:"carrier_id"`
+	TerminalID            nulls.UUID   `json:"terminal_id" db:"terminal_id"`
+	OrderID               nulls.UUID   `json:"order_id" db:"order_id"`
+	CustomerID            nulls.UUID   `json:"customer_id" db:"customer_id"`
+	SerialNumber          string       `json:"serial_number" db:"serial_number"`
+	Origin                nulls.String `json:"origin" db:"origin"`
+	Destination           nulls.String `json:"destination" db:"destination"`
+	OriginLocationID      nulls.UUID   `json:"origin_location_id" db:"origin_location_id"`
+	DestinationLocationID nulls.UUID   `json:"destination_location_id" db:"destination_location_id"`
+	Lfd                   nulls.Time   `json:"lfd" db:"lfd"`
+	ReservationTime       nulls.Time   `json:"reservation_time" db:"reservation_time"`
+	Size                  nulls.String `json:"size" db:"size"`
+	Type                  string       `json:"type" db:"type"`
+	Status                string       `json:"status" db:"status"`
+	DriverID              nulls.UUID   `json:"driver_id" db:"driver_id"`
+	Miles                 nulls.Int    `json:"miles" db:"miles"`
+	TerminalSlotID        nulls.UUID   `json:"terminal_slot_id" db:"terminal_slot_id"`
+	Eta                   nulls.Time   `json:"eta" db:"eta"`
+	EtaUpdatedAt          nulls.Time   `json:"eta_updated_at" db:"eta_updated_at"`
+	EtaAlertedAt          nulls.Time   `json:"eta_alerted_at" db:"eta_alerted_at"`
+	TractorID             nulls.UUID   `json:"tractor_id" db:"tractor_id"`
+	ChassisID             nulls.UUID   `json:"chassis_id" db:"chassis_id"`
+	LfdAlertedThreshold   nulls.Int    `json:"lfd_alerted_threshold" db:"lfd_alerted_threshold"`
+	Tenant                *Tenant      `belongs_to:"tenant" json:"-"`
+	Terminal              *Terminal    `belongs_to:"terminal"  json:"terminal,omitempty"`
+	Carrier               *Carrier     `belongs_to:"carrier" json:"carrier,omitempty"`
+	Order                 *Order       `belongs_to:"order" json:"order,omitempty"`
+	Customer              *Customer    `belongs_to:"customer" json:"customer,omitempty"`
+	Driver                *User        `belongs_to:"user" json:"driver,omitempty"`
 }
 
 // Shipments is not required by pop and may be deleted
@@ -57,6 +72,10 @@ func (c *Shipment) Validate(tx *pop.Connection) (*validate.Errors, error) {
 		&validators.FuncValidator{Fn: func() bool {
 			return IsValidShipmentType(c.Type)
 		}, Field: c.Type, Name: "Type"},
+		&validators.FuncValidator{Fn: func() bool {
+			// Value can be null
+			return !c.Miles.Valid || c.Miles.Int >= 0
+		}, Field: fmt.Sprint(c.Miles.Int), Name: "Miles"},
 	), nil
 }
 
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gobuffalo/validate/v3"
+	"github.com/gobuffalo/validate/v3/validators"
+	"github.com/gofrs/uuid"
+)
+
+// ShipmentCharge is used by pop to map your shipment_charges database table to your go code.
+// Rates are per unit of quantity. The customer side is billed and the cost side is paid out, both in the same currency.
+type ShipmentCharge struct {
+	ID           uuid.UUID    `json:"id" db:"id"`
+	CreatedAt    time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at" db:"updated_at"`
+	CreatedBy    uuid.UUID    `json:"created_by" db:"created_by"`
+	TenantID     uuid.UUID    `json:"tenant_id" db:"tenant_id"`
+	ShipmentID   uuid.UUID    `json:"shipment_id" db:"shipment_id"`
+	Type         string       `json:"type" db:"type"`
+	Description  nulls.String `json:"description" db:"description"`
+	Quantity     int          `json:"quantity" db:"quantity"`
+	CustomerRate Money        `json:"customer_rate" db:"customer_rate"`
+	CostRate     Money        `json:"cost_rate" db:"cost_rate"`
+	Tenant       *Tenant      `belongs_to:"tenant" json:"-"`
+	Shipment     *Shipment    `belongs_to:"shipment" json:"-"`
+}
+
+// ShipmentCharges is not required by pop and may be deleted
+type ShipmentCharges []ShipmentCharge
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (s *ShipmentCharge) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.UUIDIsPresent{Field: s.ShipmentID, Name: "ShipmentID"},
+		&validators.FuncValidator{Fn: func() bool {
+			return IsValidShipmentChargeType(s.Type)
+		}, Field: s.Type, Name: "Type"},
+		&validators.IntIsGreaterThan{Field: s.Quantity, Name: "Quantity", Compared: 0},
+		&validators.IntIsGreaterThan{Field: s.CustomerRate.Amount, Name: "CustomerRate", Compared: -1},
+		&validators.IntIsGreaterThan{Field: s.CostRate.Amount, Name: "CostRate", Compared: -1},
+		&validators.FuncValidator{Fn: func() bool {
+			return IsValidCurrency(s.CustomerRate.Currency) && s.CostRate.Currency == s.CustomerRate.Currency
+		}, Field: "Currency", Name: "Currency", Message: "%s of the rates must be valid and the same"},
+	), nil
+}
+
+// Currency returns the currency of the charge
+func (s *ShipmentCharge) Currency() string {
+	return s.CustomerRate.Currency
+}
+
+// SetDefaultCurrency sets the currency of the rates that were given without one
+func (s *ShipmentCharge) SetDefaultCurrency(currency string) {
+	if s.CustomerRate.Currency == "" {
+		s.CustomerRate.Currency = currency
+	}
+	if s.CostRate.Currency == "" {
+		s.CostRate.Currency = currency
+	}
+}
+
+// CustomerAmount returns the amount billed to the customer for the charge
+func (s *ShipmentCharge) CustomerAmount() Money {
+	return s.CustomerRate.Multiply(s.Quantity)
+}
+
+// CostAmount returns the amount paid out for the charge
+func (s *ShipmentCharge) CostAmount() Money {
+	return s.CostRate.Multiply(s.Quantity)
+}
+
+// CustomerShipmentCharge is a ShipmentCharge as its customer sees it, without the cost side
+type CustomerShipmentCharge struct {
+	ID           uuid.UUID    `json:"id"`
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+	TenantID     uuid.UUID    `json:"tenant_id"`
+	ShipmentID   uuid.UUID    `json:"shipment_id"`
+	Type         string       `json:"type"`
+	Description  nulls.String `json:"description"`
+	Quantity     int          `json:"quantity"`
+	CustomerRate Money        `json:"customer_rate"`
+}
+
+// ForCustomer returns the charge as its customer sees it
+func (s *ShipmentCharge) ForCustomer() CustomerShipmentCharge {
+	return CustomerShipmentCharge{
+		ID:           s.ID,
+		CreatedAt:    s.CreatedAt,
+		UpdatedAt:    s.UpdatedAt,
+		TenantID:     s.TenantID,
+		ShipmentID:   s.ShipmentID,
+		Type:         s.Type,
+		Description:  s.Description,
+		Quantity:     s.Quantity,
+		CustomerRate: s.CustomerRate,
+	}
+}
+
+// ForCustomer returns the charges as their customer sees them
+func (s ShipmentCharges) ForCustomer() []CustomerShipmentCharge {
+	var charges = make([]CustomerShipmentCharge, len(s))
+	for i := range s {
+		charges[i] = s[i].ForCustomer()
+	}
+	return charges
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gofrs/uuid"
+)
+
+func (ms *ModelSuite) Test_ShipmentCharge() {
+	var shipmentID = uuid.Must(uuid.NewV4())
+	var tests = []struct {
+		charge                   *ShipmentCharge
+		expectedValidationErrors int
+	}{
+		{&ShipmentCharge{}, 4},
+		{&ShipmentCharge{ShipmentID: shipmentID, Type: "invalid", Quantity: 1, CustomerRate: NewMoney(0, "CAD"), CostRate: NewMoney(0, "CAD")}, 1},
+		{&ShipmentCharge{ShipmentID: shipmentID, Type: ShipmentChargeTypeStorage.String(), Quantity: 1, CustomerRate: NewMoney(0, "cad"), CostRate: NewMoney(0, "cad")}, 1},
+		{&ShipmentCharge{ShipmentID: shipmentID, Type: ShipmentChargeTypeStorage.String(), Quantity: 1, CustomerRate: NewMoney(100, "CAD"), CostRate: NewMoney(50, "USD")}, 1},
+		{&ShipmentCharge{ShipmentID: shipmentID, Type: ShipmentChargeTypeStorage.String(), Quantity: 1, CustomerRate: NewMoney(-1, "CAD"), CostRate: NewMoney(-1, "CAD")}, 2},
+		{&ShipmentCharge{ShipmentID: shipmentID, Type: ShipmentChargeTypeStorage.String(), Quantity: 2, CustomerRate: NewMoney(100, "CAD"), CostRate: NewMoney(0, "CAD")}, 0},
+	}
+	for i, test := range tests {
+		ms.T().Run(fmt.Sprint(i), func(t *testing.T) {
+			v, err := test.charge.Validate(ms.DB)
+			ms.Nil(err)
+			ms.Equal(test.expectedValidationErrors, len(v.Errors))
+		})
+	}
+	charge := &ShipmentCharge{Quantity: 3, CustomerRate: NewMoney(120, ""), CostRate: NewMoney(80, "")}
+	charge.SetDefaultCurrency("CAD")
+	ms.Equal("CAD", charge.Currency())
+	ms.Equal(NewMoney(360, "CAD"), charge.CustomerAmount())
+	ms.Equal(NewMoney(240, "CAD"), charge.CostAmount())
+}
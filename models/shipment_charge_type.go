@@ -0,0 +1,47 @@
+package models
+
+// AUTOGENERATED BY: HSM GEN
+
+// ShipmentChargeType represents the ShipmentChargeType enum
+type ShipmentChargeType string
+
+const (
+	// ShipmentChargeTypeDetention represents Detention ShipmentChargeType
+	ShipmentChargeTypeDetention ShipmentChargeType = "Detention"
+	// ShipmentChargeTypeDemurrage represents Demurrage ShipmentChargeType
+	ShipmentChargeTypeDemurrage ShipmentChargeType = "Demurrage"
+	// ShipmentChargeTypePerDiem represents PerDiem ShipmentChargeType
+	ShipmentChargeTypePerDiem ShipmentChargeType = "PerDiem"
+	// ShipmentChargeTypeStorage represents Storage ShipmentChargeType
+	ShipmentChargeTypeStorage ShipmentChargeType = "Storage"
+	// ShipmentChargeTypeChassisSplit represents ChassisSplit ShipmentChargeType
+	ShipmentChargeTypeChassisSplit ShipmentChargeType = "ChassisSplit"
+	// ShipmentChargeTypePrePull represents PrePull ShipmentChargeType
+	ShipmentChargeTypePrePull ShipmentChargeType = "PrePull"
+	// ShipmentChargeTypeOverweight represents Overweight ShipmentChargeType
+	ShipmentChargeTypeOverweight ShipmentChargeType = "Overweight"
+	// ShipmentChargeTypeOther represents Other ShipmentChargeType
+	ShipmentChargeTypeOther ShipmentChargeType = "Other"
+)
+
+var allowedShipmentChargeType [8]ShipmentChargeType = [8]ShipmentChargeType{
+	ShipmentChargeTypeDetention,
+	ShipmentChargeTypeDemurrage,
+	ShipmentChargeTypePerDiem,
+	ShipmentChargeTypeStorage,
+	ShipmentChargeTypeChassisSplit,
+	ShipmentChargeTypePrePull,
+	ShipmentChargeTypeOverweight,
+	ShipmentChargeTypeOther,
+}
+
+// String returns the string representation of
+func (k ShipmentChargeType) String() string {
+	return string(k)
+}
+
+// IsValidShipmentChargeType validates if the input is a ShipmentChargeType
+func IsValidShipmentChargeType(s string) bool {
+	t := ShipmentChargeType(s)
+	return ShipmentChargeTypeDetention == t || ShipmentChargeTypeDemurrage == t || ShipmentChargeTypePerDiem == t || ShipmentChargeTypeStorage == t || ShipmentChargeTypeChassisSplit == t || ShipmentChargeTypePrePull == t || ShipmentChargeTypeOverweight == t || ShipmentChargeTypeOther == t
+}
@@ -0,0 +1,20 @@
+package models_test
+
+// AUTOGENERATED BY: HSM GEN
+
+import (
+	"testing"
+
+	m "github.com/bigpanther/trober/models"
+)
+
+func TestIsValidShipmentChargeType(t *testing.T) {
+	var validVal = "Detention"
+	var inValidVal = "_someInvalidval_"
+	if !m.IsValidShipmentChargeType(validVal) {
+		t.Fatalf("IsValidShipmentChargeType(%q) should be true", validVal)
+	}
+	if m.IsValidShipmentChargeType(inValidVal) {
+		t.Fatalf("IsValidShipmentChargeType(%q) should be false", inValidVal)
+	}
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gobuffalo/validate/v3"
+	"github.com/gobuffalo/validate/v3/validators"
+	"github.com/gofrs/uuid"
+)
+
+// ShipmentEvent is used by pop to map your shipment_events database table to your go code.
+// A ShipmentEvent is recorded every time a shipment is created or updated.
+type ShipmentEvent struct {
+	ID         uuid.UUID    `json:"id" db:"id"`
+	CreatedAt  time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time    `json:"updated_at" db:"updated_at"`
+	CreatedBy  uuid.UUID    `json:"created_by" db:"created_by"`
+	TenantID   uuid.UUID    `json:"tenant_id" db:"tenant_id"`
+	ShipmentID uuid.UUID    `json:"shipment_id" db:"shipment_id"`
+	FromStatus nulls.String `json:"from_status" db:"from_status"`
+	ToStatus   string       `json:"to_status" db:"to_status"`
+	DriverID   nulls.UUID   `json:"driver_id" db:"driver_id"`
+	Tenant     *Tenant      `belongs_to:"tenant" json:"-"`
+	Shipment   *Shipment    `belongs_to:"shipment" json:"-"`
+}
+
+// ShipmentEvents is not required by pop and may be deleted
+type ShipmentEvents []ShipmentEvent
+
+// NewShipmentEvent returns the event for a shipment that moved from the given status to its current one.
+// An empty from status denotes a newly created shipment.
+func NewShipmentEvent(s *Shipment, from string, driverID nulls.UUID, createdBy uuid.UUID) *ShipmentEvent {
+	e := &ShipmentEvent{
+		CreatedBy:  createdBy,
+		TenantID:   s.TenantID,
+		ShipmentID: s.ID,
+		ToStatus:   s.Status,
+		DriverID:   driverID,
+	}
+	if from != "" {
+		e.FromStatus = nulls.NewString(from)
+	}
+	return e
+}
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (e *ShipmentEvent) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.UUIDIsPresent{Field: e.ShipmentID, Name: "ShipmentID"},
+		&validators.FuncValidator{Fn: func() bool {
+			return IsValidShipmentStatus(e.ToStatus)
+		}, Field: e.ToStatus, Name: "ToStatus"},
+		&validators.FuncValidator{Fn: func() bool {
+			// Value can be null
+			return !e.FromStatus.Valid || IsValidShipmentStatus(e.FromStatus.String)
+		}, Field: e.FromStatus.String, Name: "FromStatus"},
+	), nil
+}
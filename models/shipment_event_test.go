@@ -0,0 +1,30 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/gofrs/uuid"
+)
+
+func (ms *ModelSuite) Test_ShipmentEvent() {
+	var shipmentID = uuid.Must(uuid.NewV4())
+	var tests = []struct {
+		event                    *ShipmentEvent
+		expectedValidationErrors int
+	}{
+		{&ShipmentEvent{}, 2},
+		{&ShipmentEvent{ShipmentID: shipmentID}, 1},
+		{&ShipmentEvent{ShipmentID: shipmentID, ToStatus: ShipmentStatusAssigned.String()}, 0},
+		{&ShipmentEvent{ShipmentID: shipmentID, ToStatus: ShipmentStatusAssigned.String(), FromStatus: nulls.NewString("invalid")}, 1},
+		{NewShipmentEvent(&Shipment{ID: shipmentID, Status: ShipmentStatusLoaded.String()}, ShipmentStatusAccepted.String(), nulls.UUID{}, shipmentID), 0},
+	}
+	for i, test := range tests {
+		ms.T().Run(fmt.Sprint(i), func(t *testing.T) {
+			v, err := test.event.Validate(ms.DB)
+			ms.Nil(err)
+			ms.Equal(test.expectedValidationErrors, len(v.Errors))
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gobuffalo/validate/v3"
+	"github.com/gobuffalo/validate/v3/validators"
+	"github.com/gofrs/uuid"
+)
+
+// ShipmentStatusSuggestion is used by pop to map your shipment_status_suggestions database table to your go code.
+// It is a status change suggested when the driver crosses the geofence of the terminal of a shipment, the driver
+// or back office accepts or dismisses it. Latitude, Longitude and RecordedAt are of the point across the geofence.
+type ShipmentStatusSuggestion struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at" db:"updated_at"`
+	TenantID   uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	ShipmentID uuid.UUID  `json:"shipment_id" db:"shipment_id"`
+	DriverID   uuid.UUID  `json:"driver_id" db:"driver_id"`
+	TerminalID nulls.UUID `json:"terminal_id" db:"terminal_id"`
+	FromStatus string     `json:"from_status" db:"from_status"`
+	ToStatus   string     `json:"to_status" db:"to_status"`
+	Status     string     `json:"status" db:"status"`
+	Latitude   float64    `json:"latitude" db:"latitude"`
+	Longitude  float64    `json:"longitude" db:"longitude"`
+	RecordedAt time.Time  `json:"recorded_at" db:"recorded_at"`
+	ReviewedBy nulls.UUID `json:"reviewed_by" db:"reviewed_by"`
+	ReviewedAt nulls.Time `json:"reviewed_at" db:"reviewed_at"`
+	Tenant     *Tenant    `belongs_to:"tenant" json:"-"`
+	Shipment   *Shipment  `belongs_to:"shipment" json:"shipment,omitempty"`
+}
+
+// ShipmentStatusSuggestions is not required by pop and may be deleted
+type ShipmentStatusSuggestions []ShipmentStatusSuggestion
+
+// NewShipmentStatusSuggestion returns the pending suggestion of a status change caused by the geofence
+func NewShipmentStatusSuggestion(t *GeofenceTransition) *ShipmentStatusSuggestion {
+	return &ShipmentStatusSuggestion{
+		TenantID:   t.Shipment.TenantID,
+		ShipmentID: t.Shipment.ID,
+		DriverID:   t.Location.DriverID,
+		TerminalID: t.Shipment.TerminalID,
+		FromStatus: t.From.String(),
+		ToStatus:   t.To.String(),
+		Status:     StatusSuggestionStatusPending.String(),
+		Latitude:   t.Location.Latitude,
+		Longitude:  t.Location.Longitude,
+		RecordedAt: t.Location.RecordedAt,
+	}
+}
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (s *ShipmentStatusSuggestion) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.UUIDIsPresent{Field: s.ShipmentID, Name: "ShipmentID"},
+		&validators.UUIDIsPresent{Field: s.DriverID, Name: "DriverID"},
+		&validators.FuncValidator{Fn: func() bool {
+			return IsValidShipmentStatus(s.FromStatus)
+		}, Field: s.FromStatus, Name: "FromStatus"},
+		&validators.FuncValidator{Fn: func() bool {
+			return IsValidShipmentStatus(s.ToStatus)
+		}, Field: s.ToStatus, Name: "ToStatus"},
+		&validators.FuncValidator{Fn: func() bool {
+			return IsValidStatusSuggestionStatus(s.Status)
+		}, Field: s.Status, Name: "Status"},
+	), nil
+}
+
+// IsReviewed checks if the suggestion was accepted or dismissed
+func (s *ShipmentStatusSuggestion) IsReviewed() bool {
+	return s.Status != StatusSuggestionStatusPending.String()
+}
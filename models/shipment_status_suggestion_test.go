@@ -0,0 +1,39 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/gofrs/uuid"
+)
+
+func (ms *ModelSuite) Test_ShipmentStatusSuggestion() {
+	var shipment = &Shipment{ID: uuid.Must(uuid.NewV4()), TenantID: uuid.Must(uuid.NewV4()), TerminalID: nulls.NewUUID(uuid.Must(uuid.NewV4())), Status: ShipmentStatusAccepted.String()}
+	var location = DriverLocation{DriverID: uuid.Must(uuid.NewV4()), RecordedAt: time.Now().UTC(), Latitude: deltaport.Lat, Longitude: deltaport.Lng}
+	var suggestion = NewShipmentStatusSuggestion(&GeofenceTransition{Shipment: shipment, From: ShipmentStatusAccepted, To: ShipmentStatusArrived, Location: location})
+	ms.Equal(shipment.ID, suggestion.ShipmentID)
+	ms.Equal(shipment.TerminalID, suggestion.TerminalID)
+	ms.Equal(location.DriverID, suggestion.DriverID)
+	ms.Equal(deltaport.Lat, suggestion.Latitude)
+	ms.False(suggestion.IsReviewed())
+	var tests = []struct {
+		suggestion               *ShipmentStatusSuggestion
+		expectedValidationErrors int
+	}{
+		{&ShipmentStatusSuggestion{}, 5},
+		{suggestion, 0},
+		{&ShipmentStatusSuggestion{ShipmentID: shipment.ID, DriverID: location.DriverID, FromStatus: "Accepted", ToStatus: "Here", Status: "Pending"}, 1},
+		{&ShipmentStatusSuggestion{ShipmentID: shipment.ID, DriverID: location.DriverID, FromStatus: "Accepted", ToStatus: "Arrived", Status: "Maybe"}, 1},
+	}
+	for i, test := range tests {
+		ms.T().Run(fmt.Sprint(i), func(t *testing.T) {
+			v, err := test.suggestion.Validate(ms.DB)
+			ms.Nil(err)
+			ms.Equal(test.expectedValidationErrors, len(v.Errors))
+		})
+	}
+	suggestion.Status = StatusSuggestionStatusDismissed.String()
+	ms.True(suggestion.IsReviewed())
+}
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// shipmentStatusNew is the status of a shipment that has not been created yet.
+// Its transitions are the statuses a shipment can be created with.
+const shipmentStatusNew ShipmentStatus = ""
+
+var backOfficeShipmentStatusTransitions = map[ShipmentStatus][]ShipmentStatus{
+	shipmentStatusNew:        {ShipmentStatusUnassigned, ShipmentStatusAssigned},
+	ShipmentStatusUnassigned: {ShipmentStatusAssigned},
+	ShipmentStatusAssigned:   {ShipmentStatusUnassigned, ShipmentStatusAccepted, ShipmentStatusRejected},
+	ShipmentStatusAccepted:   {ShipmentStatusUnassigned, ShipmentStatusArrived, ShipmentStatusLoaded, ShipmentStatusRejected},
+	ShipmentStatusRejected:   {ShipmentStatusUnassigned, ShipmentStatusAssigned},
+	ShipmentStatusArrived:    {ShipmentStatusLoaded},
+	ShipmentStatusLoaded:     {ShipmentStatusInTransit, ShipmentStatusDelivered},
+	ShipmentStatusInTransit:  {ShipmentStatusDelivered},
+}
+
+var driverShipmentStatusTransitions = map[ShipmentStatus][]ShipmentStatus{
+	shipmentStatusNew:       {ShipmentStatusAssigned, ShipmentStatusAccepted},
+	ShipmentStatusAssigned:  {ShipmentStatusAccepted, ShipmentStatusRejected},
+	ShipmentStatusAccepted:  {ShipmentStatusLoaded, ShipmentStatusRejected},
+	ShipmentStatusArrived:   {ShipmentStatusLoaded},
+	ShipmentStatusLoaded:    {ShipmentStatusDelivered},
+	ShipmentStatusInTransit: {ShipmentStatusDelivered},
+}
+
+// geofenceShipmentStatusTransitions are the status changes the driver causes by entering or leaving the geofence of the terminal
+var geofenceShipmentStatusTransitions = map[ShipmentStatus][]ShipmentStatus{
+	ShipmentStatusAccepted: {ShipmentStatusArrived},
+	ShipmentStatusLoaded:   {ShipmentStatusInTransit},
+}
+
+// shipmentStatusTransitions is the shipment state machine keyed by role.
+// Roles that are missing cannot change the status of a shipment.
+var shipmentStatusTransitions = map[UserRole]map[ShipmentStatus][]ShipmentStatus{
+	UserRoleSuperAdmin: backOfficeShipmentStatusTransitions,
+	UserRoleAdmin:      backOfficeShipmentStatusTransitions,
+	UserRoleBackOffice: backOfficeShipmentStatusTransitions,
+	UserRoleDriver:     driverShipmentStatusTransitions,
+}
+
+// ShipmentStatusTransitionError is returned when a user attempts a status change that is not allowed
+type ShipmentStatusTransitionError struct {
+	From    ShipmentStatus
+	To      ShipmentStatus
+	Allowed []ShipmentStatus
+}
+
+func (e *ShipmentStatusTransitionError) Error() string {
+	var allowed = make([]string, len(e.Allowed))
+	for i, s := range e.Allowed {
+		allowed[i] = s.String()
+	}
+	if e.From == shipmentStatusNew {
+		return fmt.Sprintf("invalid initial status %q, allowed: [%s]", e.To, strings.Join(allowed, ", "))
+	}
+	return fmt.Sprintf("invalid status transition from %q to %q, allowed: [%s]", e.From, e.To, strings.Join(allowed, ", "))
+}
+
+// NextShipmentStatuses returns the statuses the user can move a shipment to from the given status.
+// An empty from status returns the statuses a shipment can be created with.
+func NextShipmentStatuses(u *User, from ShipmentStatus) []ShipmentStatus {
+	var next = shipmentStatusTransitions[UserRole(u.Role)][from]
+	if next == nil {
+		return []ShipmentStatus{}
+	}
+	return next
+}
+
+// CheckShipmentStatusTransition validates that the user can move a shipment from one status to another.
+// Staying on the same status is always allowed.
+func CheckShipmentStatusTransition(u *User, from ShipmentStatus, to ShipmentStatus) error {
+	if from == to {
+		return nil
+	}
+	var allowed = NextShipmentStatuses(u, from)
+	for _, s := range allowed {
+		if s == to {
+			return nil
+		}
+	}
+	return &ShipmentStatusTransitionError{From: from, To: to, Allowed: allowed}
+}
+
+// CheckGeofenceShipmentStatusTransition validates that the position of the driver can move a shipment from one status to another
+func CheckGeofenceShipmentStatusTransition(from ShipmentStatus, to ShipmentStatus) error {
+	var allowed = geofenceShipmentStatusTransitions[from]
+	for _, s := range allowed {
+		if s == to {
+			return nil
+		}
+	}
+	if allowed == nil {
+		allowed = []ShipmentStatus{}
+	}
+	return &ShipmentStatusTransitionError{From: from, To: to, Allowed: allowed}
+}
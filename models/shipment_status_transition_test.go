@@ -0,0 +1,72 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func (ms *ModelSuite) Test_ShipmentStatusTransition() {
+	var backOffice = &User{Role: UserRoleBackOffice.String()}
+	var admin = &User{Role: UserRoleAdmin.String()}
+	var driver = &User{Role: UserRoleDriver.String()}
+	var customer = &User{Role: UserRoleCustomer.String()}
+	var tests = []struct {
+		user    *User
+		from    ShipmentStatus
+		to      ShipmentStatus
+		allowed bool
+	}{
+		{backOffice, "", ShipmentStatusUnassigned, true},
+		{backOffice, "", ShipmentStatusDelivered, false},
+		{backOffice, ShipmentStatusUnassigned, ShipmentStatusAssigned, true},
+		{backOffice, ShipmentStatusAccepted, ShipmentStatusArrived, true},
+		{backOffice, ShipmentStatusDelivered, ShipmentStatusLoaded, false},
+		{backOffice, ShipmentStatusDelivered, ShipmentStatusDelivered, true},
+		{admin, ShipmentStatusRejected, ShipmentStatusAssigned, true},
+		{driver, "", ShipmentStatusAccepted, true},
+		{driver, ShipmentStatusAssigned, ShipmentStatusAccepted, true},
+		{driver, ShipmentStatusAssigned, ShipmentStatusDelivered, false},
+		{driver, ShipmentStatusAccepted, ShipmentStatusArrived, false},
+		{driver, ShipmentStatusLoaded, ShipmentStatusDelivered, true},
+		{driver, ShipmentStatusRejected, ShipmentStatusAssigned, false},
+		{customer, ShipmentStatusAssigned, ShipmentStatusAccepted, false},
+	}
+	for i, test := range tests {
+		ms.T().Run(fmt.Sprint(i), func(t *testing.T) {
+			err := CheckShipmentStatusTransition(test.user, test.from, test.to)
+			if test.allowed {
+				ms.Nil(err)
+				return
+			}
+			var transitionErr *ShipmentStatusTransitionError
+			ms.True(errors.As(err, &transitionErr))
+			ms.Equal(NextShipmentStatuses(test.user, test.from), transitionErr.Allowed)
+		})
+	}
+}
+
+func (ms *ModelSuite) Test_GeofenceShipmentStatusTransition() {
+	var tests = []struct {
+		from    ShipmentStatus
+		to      ShipmentStatus
+		allowed bool
+	}{
+		{ShipmentStatusAccepted, ShipmentStatusArrived, true},
+		{ShipmentStatusLoaded, ShipmentStatusInTransit, true},
+		{ShipmentStatusAssigned, ShipmentStatusArrived, false},
+		{ShipmentStatusArrived, ShipmentStatusArrived, false},
+		{ShipmentStatusLoaded, ShipmentStatusDelivered, false},
+	}
+	for i, test := range tests {
+		ms.T().Run(fmt.Sprint(i), func(t *testing.T) {
+			err := CheckGeofenceShipmentStatusTransition(test.from, test.to)
+			if test.allowed {
+				ms.Nil(err)
+				return
+			}
+			var transitionErr *ShipmentStatusTransitionError
+			ms.True(errors.As(err, &transitionErr))
+		})
+	}
+}
@@ -17,6 +17,8 @@ func (ms *ModelSuite) Test_Shipment() {
 		{&Shipment{SerialNumber: "CANV0001", Type: ShipmentTypeInbound.String()}, 1},
 		{&Shipment{SerialNumber: "CANV0001", Type: ShipmentTypeInbound.String(), Status: ShipmentStatusDelivered.String()}, 0},
 		{&Shipment{Size: nulls.NewString("Invalid size")}, 4},
+		{&Shipment{SerialNumber: "CANV0001", Type: ShipmentTypeInbound.String(), Status: ShipmentStatusDelivered.String(), Miles: nulls.NewInt(-1)}, 1},
+		{&Shipment{SerialNumber: "CANV0001", Type: ShipmentTypeInbound.String(), Status: ShipmentStatusDelivered.String(), Miles: nulls.NewInt(42)}, 0},
 	}
 	for i, test := range tests {
 		ms.T().Run(fmt.Sprint(i), func(t *testing.T) {
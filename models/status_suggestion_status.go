@@ -0,0 +1,32 @@
+package models
+
+// AUTOGENERATED BY: HSM GEN
+
+// StatusSuggestionStatus represents the StatusSuggestionStatus enum
+type StatusSuggestionStatus string
+
+const (
+	// StatusSuggestionStatusPending represents Pending StatusSuggestionStatus
+	StatusSuggestionStatusPending StatusSuggestionStatus = "Pending"
+	// StatusSuggestionStatusAccepted represents Accepted StatusSuggestionStatus
+	StatusSuggestionStatusAccepted StatusSuggestionStatus = "Accepted"
+	// StatusSuggestionStatusDismissed represents Dismissed StatusSuggestionStatus
+	StatusSuggestionStatusDismissed StatusSuggestionStatus = "Dismissed"
+)
+
+var allowedStatusSuggestionStatus [3]StatusSuggestionStatus = [3]StatusSuggestionStatus{
+	StatusSuggestionStatusPending,
+	StatusSuggestionStatusAccepted,
+	StatusSuggestionStatusDismissed,
+}
+
+// String returns the string representation of
+func (k StatusSuggestionStatus) String() string {
+	return string(k)
+}
+
+// IsValidStatusSuggestionStatus validates if the input is a StatusSuggestionStatus
+func IsValidStatusSuggestionStatus(s string) bool {
+	t := StatusSuggestionStatus(s)
+	return StatusSuggestionStatusPending == t || StatusSuggestionStatusAccepted == t || StatusSuggestionStatusDismissed == t
+}
@@ -0,0 +1,20 @@
+package models_test
+
+// AUTOGENERATED BY: HSM GEN
+
+import (
+	"testing"
+
+	m "github.com/bigpanther/trober/models"
+)
+
+func TestIsValidStatusSuggestionStatus(t *testing.T) {
+	var validVal = "Pending"
+	var inValidVal = "_someInvalidval_"
+	if !m.IsValidStatusSuggestionStatus(validVal) {
+		t.Fatalf("IsValidStatusSuggestionStatus(%q) should be true", validVal)
+	}
+	if m.IsValidStatusSuggestionStatus(inValidVal) {
+		t.Fatalf("IsValidStatusSuggestionStatus(%q) should be false", inValidVal)
+	}
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/gobuffalo/nulls"
@@ -11,14 +12,21 @@ import (
 )
 
 // Tenant is used by pop to map your tenants database table to your go code.
+// The GPS points of the drivers are kept for LocationRetentionDays, DefaultLocationRetentionDays when not set.
+// GeofenceArrivedMode and GeofenceInTransitMode choose if entering and leaving the geofence of a terminal
+// suggest the status change to the driver or apply it.
 type Tenant struct {
-	ID        uuid.UUID  `json:"id" db:"id"`
-	CreatedAt time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
-	CreatedBy nulls.UUID `json:"created_by" db:"created_by"`
-	Name      string     `json:"name" db:"name"`
-	Type      string     `json:"type" db:"type"`
-	Code      string     `json:"code" db:"code"`
+	ID                    uuid.UUID  `json:"id" db:"id"`
+	CreatedAt             time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt             time.Time  `json:"updated_at" db:"updated_at"`
+	CreatedBy             nulls.UUID `json:"created_by" db:"created_by"`
+	Name                  string     `json:"name" db:"name"`
+	Type                  string     `json:"type" db:"type"`
+	Code                  string     `json:"code" db:"code"`
+	Currency              string     `json:"currency" db:"currency"`
+	LocationRetentionDays nulls.Int  `json:"location_retention_days" db:"location_retention_days"`
+	GeofenceArrivedMode   string     `json:"geofence_arrived_mode" db:"geofence_arrived_mode"`
+	GeofenceInTransitMode string     `json:"geofence_in_transit_mode" db:"geofence_in_transit_mode"`
 }
 
 // Tenants is not required by pop and may be deleted
@@ -34,5 +42,31 @@ func (t *Tenant) Validate(tx *pop.Connection) (*validate.Errors, error) {
 		&validators.FuncValidator{Fn: func() bool {
 			return IsValidTenantType(t.Type)
 		}, Field: t.Type, Name: "Type"},
+		&validators.FuncValidator{Fn: func() bool {
+			return IsValidCurrency(t.Currency)
+		}, Field: t.Currency, Name: "Currency"},
+		&validators.FuncValidator{Fn: func() bool {
+			// Value can be null
+			return !t.LocationRetentionDays.Valid || t.LocationRetentionDays.Int > 0
+		}, Field: fmt.Sprint(t.LocationRetentionDays.Int), Name: "LocationRetentionDays"},
+		&validators.FuncValidator{Fn: func() bool {
+			return IsValidGeofenceTransitionMode(t.GeofenceArrivedMode)
+		}, Field: t.GeofenceArrivedMode, Name: "GeofenceArrivedMode"},
+		&validators.FuncValidator{Fn: func() bool {
+			return IsValidGeofenceTransitionMode(t.GeofenceInTransitMode)
+		}, Field: t.GeofenceInTransitMode, Name: "GeofenceInTransitMode"},
 	), nil
 }
+
+// GeofenceTransitionMode returns if the status change to the given status is suggested or applied when the driver
+// crosses the geofence of a terminal
+func (t *Tenant) GeofenceTransitionMode(to ShipmentStatus) GeofenceTransitionMode {
+	var mode = t.GeofenceArrivedMode
+	if to == ShipmentStatusInTransit {
+		mode = t.GeofenceInTransitMode
+	}
+	if mode == "" {
+		return GeofenceTransitionModeSuggest
+	}
+	return GeofenceTransitionMode(mode)
+}
@@ -1,5 +1,47 @@
 package models
 
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gobuffalo/nulls"
+)
+
 func (ms *ModelSuite) Test_Tenant() {
-	ms.False(false)
+	var suggest = GeofenceTransitionModeSuggest.String()
+	var tests = []struct {
+		tenant                   *Tenant
+		expectedValidationErrors int
+	}{
+		{&Tenant{Name: "Test", Type: TenantTypeTest.String(), Code: "test", Currency: DefaultCurrency, GeofenceArrivedMode: suggest, GeofenceInTransitMode: suggest}, 0},
+		{&Tenant{Name: "Test", Type: TenantTypeTest.String(), Code: "test", Currency: "USD", GeofenceArrivedMode: suggest, GeofenceInTransitMode: suggest}, 0},
+		{&Tenant{Name: "Test", Type: TenantTypeTest.String(), Code: "test", Currency: "dollars", GeofenceArrivedMode: suggest, GeofenceInTransitMode: suggest}, 1},
+		{&Tenant{Name: "Test", Type: TenantTypeTest.String(), Code: "test", GeofenceArrivedMode: suggest, GeofenceInTransitMode: suggest}, 1},
+		{&Tenant{Name: "Test", Type: TenantTypeTest.String(), Code: "test", Currency: DefaultCurrency, GeofenceArrivedMode: suggest, GeofenceInTransitMode: suggest, LocationRetentionDays: nulls.NewInt(90)}, 0},
+		{&Tenant{Name: "Test", Type: TenantTypeTest.String(), Code: "test", Currency: DefaultCurrency, GeofenceArrivedMode: suggest, GeofenceInTransitMode: suggest, LocationRetentionDays: nulls.NewInt(0)}, 1},
+		{&Tenant{Name: "Test", Type: TenantTypeTest.String(), Code: "test", Currency: DefaultCurrency, GeofenceArrivedMode: "Sometimes", GeofenceInTransitMode: suggest}, 1},
+	}
+	for i, test := range tests {
+		ms.T().Run(fmt.Sprint(i), func(t *testing.T) {
+			currency := test.tenant.Currency
+			v, err := test.tenant.Validate(ms.DB)
+			ms.Nil(err)
+			ms.Equal(test.expectedValidationErrors, len(v.Errors))
+			// Validation does not fill in a default currency
+			ms.Equal(currency, test.tenant.Currency)
+		})
+	}
+}
+
+func (ms *ModelSuite) Test_TenantGeofenceTransitionMode() {
+	var tenant = &Tenant{}
+	ms.Equal(GeofenceTransitionModeSuggest, tenant.GeofenceTransitionMode(ShipmentStatusArrived))
+	ms.Equal(GeofenceTransitionModeSuggest, tenant.GeofenceTransitionMode(ShipmentStatusInTransit))
+	tenant.GeofenceInTransitMode = GeofenceTransitionModeAuto.String()
+	ms.Equal(GeofenceTransitionModeSuggest, tenant.GeofenceTransitionMode(ShipmentStatusArrived))
+	ms.Equal(GeofenceTransitionModeAuto, tenant.GeofenceTransitionMode(ShipmentStatusInTransit))
+	v, err := tenant.Validate(ms.DB)
+	ms.Nil(err)
+	ms.Equal("", tenant.GeofenceArrivedMode)
+	ms.Equal(5, len(v.Errors))
 }
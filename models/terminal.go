@@ -1,8 +1,11 @@
 package models
 
 import (
+	"fmt"
+	"sort"
 	"time"
 
+	"github.com/gobuffalo/nulls"
 	"github.com/gobuffalo/pop/v6"
 	"github.com/gobuffalo/validate/v3"
 	"github.com/gobuffalo/validate/v3/validators"
@@ -10,15 +13,38 @@ import (
 )
 
 // Terminal is used by pop to map your terminals database table to your go code.
+// FreeTimeDays and DemurrageRate (per day) are the demurrage schedule of the terminal,
+// imports and exports can have their own free time. Hours and Holidays are when the gate is open, in the Timezone
+// of the terminal, and no reservation is taken in the last GateCutoffMinutes before the gate closes.
+// Latitude and Longitude locate the gate, GeofenceRadiusMeters or GeofencePolygon is the area of the terminal.
+// Drivers are only dispatched to the terminal with all its RequiredCredentials.
 type Terminal struct {
-	ID        uuid.UUID `json:"id" db:"id"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
-	CreatedBy uuid.UUID `json:"created_by" db:"created_by"`
-	Name      string    `json:"name" db:"name"`
-	Type      string    `json:"type" db:"type"`
-	TenantID  uuid.UUID `json:"tenant_id" db:"tenant_id"`
-	Tenant    *Tenant   `belongs_to:"tenant" json:"-"`
+	ID                   uuid.UUID         `json:"id" db:"id"`
+	CreatedAt            time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt            time.Time         `json:"updated_at" db:"updated_at"`
+	CreatedBy            uuid.UUID         `json:"created_by" db:"created_by"`
+	Name                 string            `json:"name" db:"name"`
+	Type                 string            `json:"type" db:"type"`
+	TenantID             uuid.UUID         `json:"tenant_id" db:"tenant_id"`
+	FreeTimeDays         nulls.Int         `json:"free_time_days" db:"free_time_days"`
+	ImportFreeTimeDays   nulls.Int         `json:"import_free_time_days" db:"import_free_time_days"`
+	ExportFreeTimeDays   nulls.Int         `json:"export_free_time_days" db:"export_free_time_days"`
+	DemurrageRate        NullMoney         `json:"demurrage_rate" db:"demurrage_rate"`
+	Timezone             string            `json:"timezone" db:"timezone"`
+	GateCutoffMinutes    nulls.Int         `json:"gate_cutoff_minutes" db:"gate_cutoff_minutes"`
+	AddressLine          nulls.String      `json:"address_line" db:"address_line"`
+	City                 nulls.String      `json:"city" db:"city"`
+	Region               nulls.String      `json:"region" db:"region"`
+	PostalCode           nulls.String      `json:"postal_code" db:"postal_code"`
+	Country              nulls.String      `json:"country" db:"country"`
+	Latitude             nulls.Float64     `json:"latitude" db:"latitude"`
+	Longitude            nulls.Float64     `json:"longitude" db:"longitude"`
+	GeofenceRadiusMeters nulls.Int         `json:"geofence_radius_meters" db:"geofence_radius_meters"`
+	GeofencePolygon      GeoPolygon        `json:"geofence_polygon" db:"geofence_polygon"`
+	RequiredCredentials  DriverCredentials `json:"required_credentials" db:"required_credentials"`
+	Tenant               *Tenant           `belongs_to:"tenant" json:"-"`
+	Hours                TerminalHours     `has_many:"terminal_hours" json:"hours"`
+	Holidays             TerminalHolidays  `has_many:"terminal_holidays" json:"holidays"`
 }
 
 // Terminals is not required by pop and may be deleted
@@ -32,5 +58,82 @@ func (t *Terminal) Validate(tx *pop.Connection) (*validate.Errors, error) {
 		&validators.FuncValidator{Fn: func() bool {
 			return IsValidTerminalType(t.Type)
 		}, Field: t.Type, Name: "Type"},
+		&validators.FuncValidator{Fn: func() bool {
+			// Value can be null
+			return !t.FreeTimeDays.Valid || t.FreeTimeDays.Int >= 0
+		}, Field: fmt.Sprint(t.FreeTimeDays.Int), Name: "FreeTimeDays"},
+		&validators.FuncValidator{Fn: func() bool {
+			// Value can be null
+			return !t.ImportFreeTimeDays.Valid || t.ImportFreeTimeDays.Int >= 0
+		}, Field: fmt.Sprint(t.ImportFreeTimeDays.Int), Name: "ImportFreeTimeDays"},
+		&validators.FuncValidator{Fn: func() bool {
+			// Value can be null
+			return !t.ExportFreeTimeDays.Valid || t.ExportFreeTimeDays.Int >= 0
+		}, Field: fmt.Sprint(t.ExportFreeTimeDays.Int), Name: "ExportFreeTimeDays"},
+		&validators.FuncValidator{Fn: func() bool {
+			// Value can be null
+			return !t.DemurrageRate.Valid || (t.DemurrageRate.Money.Amount >= 0 && IsValidCurrency(t.DemurrageRate.Money.Currency))
+		}, Field: t.DemurrageRate.Money.String(), Name: "DemurrageRate"},
+		&validators.FuncValidator{Fn: func() bool {
+			// Empty is UTC
+			_, err := time.LoadLocation(t.Timezone)
+			return err == nil
+		}, Field: t.Timezone, Name: "Timezone"},
+		&validators.FuncValidator{Fn: func() bool {
+			// Value can be null
+			return !t.GateCutoffMinutes.Valid || t.GateCutoffMinutes.Int >= 0
+		}, Field: fmt.Sprint(t.GateCutoffMinutes.Int), Name: "GateCutoffMinutes"},
+		&validators.FuncValidator{Fn: func() bool {
+			// Value can be null
+			return validCoordinates(t.Latitude, t.Longitude)
+		}, Field: fmt.Sprintf("%v,%v", t.Latitude.Float64, t.Longitude.Float64), Name: "Latitude"},
+		&validators.FuncValidator{Fn: func() bool {
+			// Value can be null
+			return !t.GeofenceRadiusMeters.Valid || t.GeofenceRadiusMeters.Int > 0
+		}, Field: fmt.Sprint(t.GeofenceRadiusMeters.Int), Name: "GeofenceRadiusMeters"},
+		&validators.FuncValidator{Fn: func() bool {
+			return t.GeofencePolygon.IsValid()
+		}, Field: fmt.Sprint(len(t.GeofencePolygon)), Name: "GeofencePolygon"},
+		&validators.FuncValidator{Fn: func() bool {
+			return t.RequiredCredentials.IsValid()
+		}, Field: fmt.Sprint(t.RequiredCredentials), Name: "RequiredCredentials"},
 	), nil
 }
+
+// Position returns where the terminal is, false when it has no coordinates
+func (t *Terminal) Position() (GeoPoint, bool) {
+	return NewGeoPoint(t.Latitude, t.Longitude)
+}
+
+// Geofence returns the area of the terminal
+func (t *Terminal) Geofence() Geofence {
+	return NewGeofence(t.Latitude, t.Longitude, t.GeofenceRadiusMeters, t.GeofencePolygon)
+}
+
+// NearbyTerminal is a terminal with its distance to a position
+type NearbyTerminal struct {
+	Terminal   Terminal `json:"terminal"`
+	DistanceKm float64  `json:"distance_km"`
+}
+
+// NearbyTerminals is a list of NearbyTerminal
+type NearbyTerminals []NearbyTerminal
+
+// FindNearbyTerminals returns the terminals within the radius of a position, the closest first.
+// Terminals without coordinates are left out.
+func FindNearbyTerminals(terminals Terminals, at GeoPoint, radiusKm float64) NearbyTerminals {
+	var nearby = NearbyTerminals{}
+	for _, t := range terminals {
+		p, ok := t.Position()
+		if !ok {
+			continue
+		}
+		if d := DistanceKm(at, p); d <= radiusKm {
+			nearby = append(nearby, NearbyTerminal{Terminal: t, DistanceKm: d})
+		}
+	}
+	sort.SliceStable(nearby, func(i, j int) bool {
+		return nearby[i].DistanceKm < nearby[j].DistanceKm
+	})
+	return nearby
+}
@@ -0,0 +1,168 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gobuffalo/validate/v3"
+	"github.com/gobuffalo/validate/v3/validators"
+	"github.com/gofrs/uuid"
+)
+
+// ErrTerminalClosed is returned when a reservation falls outside of the gate hours of its terminal
+var ErrTerminalClosed = errors.New("terminal gate is closed")
+
+// holidayDateFormat is the format of the date of a holiday
+const holidayDateFormat = "2006-01-02"
+
+// TerminalHour is used by pop to map your terminal_hours database table to your go code.
+// The gate of the terminal is open from OpenTime to CloseTime on the weekday, in the timezone of the terminal.
+type TerminalHour struct {
+	ID         uuid.UUID `json:"id" db:"id"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+	TerminalID uuid.UUID `json:"terminal_id" db:"terminal_id"`
+	Weekday    int       `json:"weekday" db:"weekday"`
+	OpenTime   string    `json:"open_time" db:"open_time"`
+	CloseTime  string    `json:"close_time" db:"close_time"`
+	Terminal   *Terminal `belongs_to:"terminal" json:"-"`
+}
+
+// TerminalHours is not required by pop and may be deleted
+type TerminalHours []TerminalHour
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (h *TerminalHour) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.FuncValidator{Fn: func() bool {
+			return h.Weekday >= int(time.Sunday) && h.Weekday <= int(time.Saturday)
+		}, Field: fmt.Sprint(h.Weekday), Name: "Weekday"},
+		&validators.FuncValidator{Fn: func() bool {
+			_, err := clockMinutes(h.OpenTime)
+			return err == nil
+		}, Field: h.OpenTime, Name: "OpenTime"},
+		&validators.FuncValidator{Fn: func() bool {
+			open, _ := clockMinutes(h.OpenTime)
+			closing, err := clockMinutes(h.CloseTime)
+			return err == nil && closing > open
+		}, Field: h.CloseTime, Name: "CloseTime"},
+	), nil
+}
+
+// TerminalHoliday is used by pop to map your terminal_holidays database table to your go code.
+// The terminal is closed for the whole Date, in the timezone of the terminal.
+type TerminalHoliday struct {
+	ID         uuid.UUID `json:"id" db:"id"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+	TerminalID uuid.UUID `json:"terminal_id" db:"terminal_id"`
+	Date       time.Time `json:"date" db:"date"`
+	Name       string    `json:"name" db:"name"`
+	Terminal   *Terminal `belongs_to:"terminal" json:"-"`
+}
+
+// TerminalHolidays is not required by pop and may be deleted
+type TerminalHolidays []TerminalHoliday
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (h *TerminalHoliday) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.TimeIsPresent{Field: h.Date, Name: "Date"},
+		&validators.StringIsPresent{Field: h.Name, Name: "Name"},
+	), nil
+}
+
+// IsOn checks if the holiday is on the date of a local time
+func (h *TerminalHoliday) IsOn(local time.Time) bool {
+	return h.Date.UTC().Format(holidayDateFormat) == local.Format(holidayDateFormat)
+}
+
+// Location returns the timezone of the terminal, UTC when not set
+func (t *Terminal) Location() *time.Location {
+	if t.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(t.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// FreeTimeDaysFor returns the free time of the terminal for a type of shipment. Imports and exports
+// fall back on FreeTimeDays when they have no free time of their own.
+func (t *Terminal) FreeTimeDaysFor(shipmentType string) (int, bool) {
+	switch ShipmentType(shipmentType) {
+	case ShipmentTypeInbound:
+		if t.ImportFreeTimeDays.Valid {
+			return t.ImportFreeTimeDays.Int, true
+		}
+	case Outbound:
+		if t.ExportFreeTimeDays.Valid {
+			return t.ExportFreeTimeDays.Int, true
+		}
+	}
+	return t.FreeTimeDays.Int, t.FreeTimeDays.Valid
+}
+
+// AddFreeDays returns the time the given number of free days after a time. Holidays of the terminal are not counted.
+// The calendar date of from is taken as is, since ETAs are dates stored at midnight UTC, and the days are
+// counted on the calendar of the terminal.
+func (t *Terminal) AddFreeDays(from time.Time, days int) time.Time {
+	y, m, d := from.Date()
+	var day = time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+	var elapsed = 0
+	for counted := 0; counted < days; elapsed++ {
+		day = day.AddDate(0, 0, 1)
+		if !t.isHoliday(day) {
+			counted++
+		}
+	}
+	return from.AddDate(0, 0, elapsed)
+}
+
+// CheckGateHours returns why a reservation at the given time cannot go through the gate of the terminal,
+// nothing when the gate is open. A terminal without hours is open around the clock outside of its holidays.
+func (t *Terminal) CheckGateHours(at time.Time) []string {
+	var reasons = []string{}
+	local := at.In(t.Location())
+	for _, h := range t.Holidays {
+		if h.IsOn(local) {
+			reasons = append(reasons, fmt.Sprintf("Closed on %s for %s", local.Format(holidayDateFormat), h.Name))
+		}
+	}
+	if len(t.Hours) == 0 {
+		return reasons
+	}
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+	for _, h := range t.Hours {
+		if h.Weekday != int(local.Weekday()) {
+			continue
+		}
+		open, _ := clockMinutes(h.OpenTime)
+		closing, _ := clockMinutes(h.CloseTime)
+		opensAt, closesAt := day.Add(time.Duration(open)*time.Minute), day.Add(time.Duration(closing)*time.Minute)
+		if local.Before(opensAt) || !local.Before(closesAt) {
+			continue
+		}
+		if t.GateCutoffMinutes.Valid && !local.Before(closesAt.Add(-time.Duration(t.GateCutoffMinutes.Int)*time.Minute)) {
+			return append(reasons, fmt.Sprintf("After the gate cut-off, %d minutes before closing at %s", t.GateCutoffMinutes.Int, h.CloseTime))
+		}
+		return reasons
+	}
+	return append(reasons, fmt.Sprintf("Outside of the gate hours on %s", local.Weekday()))
+}
+
+// isHoliday checks if the terminal is closed for a holiday on the date of a local time
+func (t *Terminal) isHoliday(local time.Time) bool {
+	for _, h := range t.Holidays {
+		if h.IsOn(local) {
+			return true
+		}
+	}
+	return false
+}
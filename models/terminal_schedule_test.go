@@ -0,0 +1,118 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/gofrs/uuid"
+)
+
+func (ms *ModelSuite) Test_TerminalHour() {
+	var terminalID = uuid.Must(uuid.NewV4())
+	var tests = []struct {
+		terminalHour             *TerminalHour
+		expectedValidationErrors int
+	}{
+		{&TerminalHour{}, 2},
+		{&TerminalHour{TerminalID: terminalID, Weekday: 1, OpenTime: "07:00", CloseTime: "17:00"}, 0},
+		{&TerminalHour{TerminalID: terminalID, Weekday: 7, OpenTime: "07:00", CloseTime: "17:00"}, 1},
+		{&TerminalHour{TerminalID: terminalID, Weekday: 1, OpenTime: "17:00", CloseTime: "07:00"}, 1},
+		{&TerminalHour{TerminalID: terminalID, Weekday: 1, OpenTime: "7am", CloseTime: "17:00"}, 1},
+	}
+	for i, test := range tests {
+		ms.T().Run(fmt.Sprint(i), func(t *testing.T) {
+			v, err := test.terminalHour.Validate(ms.DB)
+			ms.Nil(err)
+			ms.Equal(test.expectedValidationErrors, len(v.Errors))
+		})
+	}
+}
+
+func (ms *ModelSuite) Test_TerminalHoliday() {
+	var tests = []struct {
+		terminalHoliday          *TerminalHoliday
+		expectedValidationErrors int
+	}{
+		{&TerminalHoliday{}, 2},
+		{&TerminalHoliday{Date: time.Date(2026, 12, 25, 0, 0, 0, 0, time.UTC)}, 1},
+		{&TerminalHoliday{Date: time.Date(2026, 12, 25, 0, 0, 0, 0, time.UTC), Name: "Christmas"}, 0},
+	}
+	for i, test := range tests {
+		ms.T().Run(fmt.Sprint(i), func(t *testing.T) {
+			v, err := test.terminalHoliday.Validate(ms.DB)
+			ms.Nil(err)
+			ms.Equal(test.expectedValidationErrors, len(v.Errors))
+		})
+	}
+}
+
+func (ms *ModelSuite) Test_TerminalCheckGateHours() {
+	var monday = time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	var terminal = &Terminal{
+		GateCutoffMinutes: nulls.NewInt(60),
+		Hours:             TerminalHours{{Weekday: int(time.Monday), OpenTime: "07:00", CloseTime: "17:00"}, {Weekday: int(time.Tuesday), OpenTime: "07:00", CloseTime: "12:00"}},
+		Holidays:          TerminalHolidays{{Date: time.Date(2026, 10, 21, 0, 0, 0, 0, time.UTC), Name: "Founders Day"}},
+	}
+	var tests = []struct {
+		name    string
+		at      time.Time
+		reasons int
+	}{
+		{"open", monday.Add(9 * time.Hour), 0},
+		{"before opening", monday.Add(6 * time.Hour), 1},
+		{"after cut-off", monday.Add(16*time.Hour + 30*time.Minute), 1},
+		{"closed", monday.Add(17 * time.Hour), 1},
+		{"other day", monday.AddDate(0, 0, 1).Add(9 * time.Hour), 0},
+		{"no hours on the day", monday.AddDate(0, 0, 3).Add(9 * time.Hour), 1},
+		{"holiday", monday.AddDate(0, 0, 2).Add(9 * time.Hour), 2},
+	}
+	for _, test := range tests {
+		ms.T().Run(test.name, func(t *testing.T) {
+			ms.Equal(test.reasons, len(terminal.CheckGateHours(test.at)))
+		})
+	}
+	var open = &Terminal{Holidays: terminal.Holidays}
+	ms.Empty(open.CheckGateHours(monday.Add(3 * time.Hour)))
+	ms.Equal(1, len(open.CheckGateHours(monday.AddDate(0, 0, 2))))
+}
+
+func (ms *ModelSuite) Test_TerminalLocation() {
+	ms.Equal(time.UTC, (&Terminal{}).Location())
+	ms.Equal(time.UTC, (&Terminal{Timezone: "Mars/Olympus"}).Location())
+	var terminal = &Terminal{Timezone: "America/Vancouver", Hours: TerminalHours{{Weekday: int(time.Monday), OpenTime: "07:00", CloseTime: "17:00"}}}
+	ms.Equal("America/Vancouver", terminal.Location().String())
+	// 15:00 UTC is 08:00 in Vancouver
+	ms.Empty(terminal.CheckGateHours(time.Date(2026, 10, 19, 15, 0, 0, 0, time.UTC)))
+	ms.Equal(1, len(terminal.CheckGateHours(time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC))))
+}
+
+func (ms *ModelSuite) Test_TerminalFreeTime() {
+	var terminal = &Terminal{FreeTimeDays: nulls.NewInt(4), ImportFreeTimeDays: nulls.NewInt(5)}
+	days, ok := terminal.FreeTimeDaysFor(ShipmentTypeInbound.String())
+	ms.True(ok)
+	ms.Equal(5, days)
+	days, ok = terminal.FreeTimeDaysFor(Outbound.String())
+	ms.True(ok)
+	ms.Equal(4, days)
+	_, ok = (&Terminal{}).FreeTimeDaysFor(Outbound.String())
+	ms.False(ok)
+
+	var from = time.Date(2026, 12, 23, 12, 0, 0, 0, time.UTC)
+	terminal.Holidays = TerminalHolidays{
+		{Date: time.Date(2026, 12, 25, 0, 0, 0, 0, time.UTC), Name: "Christmas"},
+		{Date: time.Date(2026, 12, 26, 0, 0, 0, 0, time.UTC), Name: "Boxing Day"},
+	}
+	ms.Equal(from.AddDate(0, 0, 1), terminal.AddFreeDays(from, 1))
+	ms.Equal(from.AddDate(0, 0, 6), terminal.AddFreeDays(from, 4))
+	ms.Equal(from, terminal.AddFreeDays(from, 0))
+
+	// An ETA at midnight UTC is still the 23rd in Vancouver, where Christmas is not a free day
+	var eta = time.Date(2026, 12, 23, 0, 0, 0, 0, time.UTC)
+	var vancouver = &Terminal{Timezone: "America/Vancouver", Holidays: TerminalHolidays{
+		{Date: time.Date(2026, 12, 25, 0, 0, 0, 0, time.UTC), Name: "Christmas"},
+	}}
+	ms.Equal(eta.AddDate(0, 0, 1), vancouver.AddFreeDays(eta, 1))
+	ms.Equal(eta.AddDate(0, 0, 3), vancouver.AddFreeDays(eta, 2))
+}
@@ -0,0 +1,182 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gobuffalo/validate/v3"
+	"github.com/gobuffalo/validate/v3/validators"
+	"github.com/gofrs/uuid"
+)
+
+// ErrTerminalSlotFull is returned when every appointment slot matching a reservation is full
+var ErrTerminalSlotFull = errors.New("appointment slot is full")
+
+// ErrNoTerminalSlot is returned when a terminal with appointment slots has none for a reservation
+var ErrNoTerminalSlot = errors.New("no appointment slot at the terminal for the reservation")
+
+// TerminalSlot is used by pop to map your terminal_slots database table to your go code.
+// It is a weekly appointment window of a terminal, from StartTime to EndTime, taking up to Capacity reservations.
+// A slot with a Size or a Type only takes the shipments of that size or type.
+type TerminalSlot struct {
+	ID         uuid.UUID    `json:"id" db:"id"`
+	CreatedAt  time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time    `json:"updated_at" db:"updated_at"`
+	CreatedBy  uuid.UUID    `json:"created_by" db:"created_by"`
+	TenantID   uuid.UUID    `json:"tenant_id" db:"tenant_id"`
+	TerminalID uuid.UUID    `json:"terminal_id" db:"terminal_id"`
+	Weekday    int          `json:"weekday" db:"weekday"`
+	StartTime  string       `json:"start_time" db:"start_time"`
+	EndTime    string       `json:"end_time" db:"end_time"`
+	Capacity   int          `json:"capacity" db:"capacity"`
+	Size       nulls.String `json:"size" db:"size"`
+	Type       nulls.String `json:"type" db:"type"`
+	Tenant     *Tenant      `belongs_to:"tenant" json:"-"`
+	Terminal   *Terminal    `belongs_to:"terminal" json:"-"`
+}
+
+// TerminalSlots is not required by pop and may be deleted
+type TerminalSlots []TerminalSlot
+
+// TerminalSlotAvailability is how many reservations an appointment slot takes on a given day
+type TerminalSlotAvailability struct {
+	Slot      TerminalSlot `json:"slot"`
+	StartsAt  time.Time    `json:"starts_at"`
+	EndsAt    time.Time    `json:"ends_at"`
+	Reserved  int          `json:"reserved"`
+	Remaining int          `json:"remaining"`
+}
+
+// TerminalSlotAvailabilities is a list of TerminalSlotAvailability
+type TerminalSlotAvailabilities []TerminalSlotAvailability
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (s *TerminalSlot) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.UUIDIsPresent{Field: s.TerminalID, Name: "TerminalID"},
+		&validators.FuncValidator{Fn: func() bool {
+			return s.Weekday >= int(time.Sunday) && s.Weekday <= int(time.Saturday)
+		}, Field: fmt.Sprint(s.Weekday), Name: "Weekday"},
+		&validators.FuncValidator{Fn: func() bool {
+			_, err := clockMinutes(s.StartTime)
+			return err == nil
+		}, Field: s.StartTime, Name: "StartTime"},
+		&validators.FuncValidator{Fn: func() bool {
+			from, _ := clockMinutes(s.StartTime)
+			to, err := clockMinutes(s.EndTime)
+			return err == nil && to > from
+		}, Field: s.EndTime, Name: "EndTime"},
+		&validators.FuncValidator{Fn: func() bool {
+			return s.Capacity > 0
+		}, Field: fmt.Sprint(s.Capacity), Name: "Capacity"},
+		&validators.FuncValidator{Fn: func() bool {
+			// Value can be null
+			return !s.Size.Valid || IsValidShipmentSize(s.Size.String)
+		}, Field: s.Size.String, Name: "Size"},
+		&validators.FuncValidator{Fn: func() bool {
+			// Value can be null
+			return !s.Type.Valid || IsValidShipmentType(s.Type.String)
+		}, Field: s.Type.String, Name: "Type"},
+	), nil
+}
+
+// WindowOn returns when the slot opens and closes on the day of a date, in the given location
+func (s *TerminalSlot) WindowOn(date time.Time, loc *time.Location) (time.Time, time.Time) {
+	local := date.In(loc)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	from, _ := clockMinutes(s.StartTime)
+	to, _ := clockMinutes(s.EndTime)
+	return day.Add(time.Duration(from) * time.Minute), day.Add(time.Duration(to) * time.Minute)
+}
+
+// Covers checks if a time falls within the slot, in the given location
+func (s *TerminalSlot) Covers(at time.Time, loc *time.Location) bool {
+	if int(at.In(loc).Weekday()) != s.Weekday {
+		return false
+	}
+	start, end := s.WindowOn(at, loc)
+	return !at.Before(start) && at.Before(end)
+}
+
+// Accepts checks if the size and the type of a shipment are allowed in the slot
+func (s *TerminalSlot) Accepts(shipment *Shipment) bool {
+	if s.Size.Valid && s.Size != shipment.Size {
+		return false
+	}
+	return !s.Type.Valid || s.Type.String == shipment.Type
+}
+
+// Reserved returns how many shipments other than the given one hold the slot between two times
+func (s *TerminalSlot) Reserved(tx *pop.Connection, start time.Time, end time.Time, excludeID uuid.UUID) (int, error) {
+	return tx.Where("terminal_slot_id = ?", s.ID).Where("id <> ?", excludeID).
+		Where("reservation_time >= ?", start).Where("reservation_time < ?", end).Count(&Shipments{})
+}
+
+// ReserveTerminalSlot sets the appointment slot of a shipment from its terminal and reservation time.
+// The slot is locked until the end of the transaction so that concurrent reservations cannot overbook it.
+// Terminals without slots take any reservation.
+func ReserveTerminalSlot(tx *pop.Connection, shipment *Shipment, loc *time.Location) error {
+	shipment.TerminalSlotID = nulls.UUID{}
+	if !shipment.TerminalID.Valid || !shipment.ReservationTime.Valid {
+		return nil
+	}
+	slots := TerminalSlots{}
+	if err := tx.Where("terminal_id = ?", shipment.TerminalID.UUID).Order("start_time ASC").All(&slots); err != nil {
+		return err
+	}
+	if len(slots) == 0 {
+		return nil
+	}
+	at := shipment.ReservationTime.Time
+	var full *TerminalSlot
+	for i := range slots {
+		slot := &slots[i]
+		if !slot.Covers(at, loc) || !slot.Accepts(shipment) {
+			continue
+		}
+		if err := tx.RawQuery("SELECT id FROM terminal_slots WHERE id = ? FOR UPDATE", slot.ID).Exec(); err != nil {
+			return err
+		}
+		start, end := slot.WindowOn(at, loc)
+		reserved, err := slot.Reserved(tx, start, end, shipment.ID)
+		if err != nil {
+			return err
+		}
+		if reserved < slot.Capacity {
+			shipment.TerminalSlotID = nulls.NewUUID(slot.ID)
+			return nil
+		}
+		full = slot
+	}
+	if full != nil {
+		return fmt.Errorf("%w: %s-%s takes %d reservations", ErrTerminalSlotFull, full.StartTime, full.EndTime, full.Capacity)
+	}
+	return ErrNoTerminalSlot
+}
+
+// LoadTerminalSlotAvailabilities returns the appointment slots of a terminal on the day of a date with their remaining capacity
+func LoadTerminalSlotAvailabilities(tx *pop.Connection, terminalID uuid.UUID, date time.Time, loc *time.Location) (TerminalSlotAvailabilities, error) {
+	slots := TerminalSlots{}
+	weekday := int(date.In(loc).Weekday())
+	if err := tx.Where("terminal_id = ?", terminalID).Where("weekday = ?", weekday).Order("start_time ASC").All(&slots); err != nil {
+		return nil, err
+	}
+	var availabilities = TerminalSlotAvailabilities{}
+	for _, slot := range slots {
+		start, end := slot.WindowOn(date, loc)
+		reserved, err := slot.Reserved(tx, start, end, uuid.Nil)
+		if err != nil {
+			return nil, err
+		}
+		var remaining = slot.Capacity - reserved
+		if remaining < 0 {
+			remaining = 0
+		}
+		availabilities = append(availabilities, TerminalSlotAvailability{Slot: slot, StartsAt: start, EndsAt: end, Reserved: reserved, Remaining: remaining})
+	}
+	return availabilities, nil
+}
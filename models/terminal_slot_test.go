@@ -0,0 +1,70 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/gofrs/uuid"
+)
+
+func (ms *ModelSuite) Test_TerminalSlot() {
+	var terminalID = uuid.Must(uuid.NewV4())
+	var tests = []struct {
+		terminalSlot             *TerminalSlot
+		expectedValidationErrors int
+	}{
+		{&TerminalSlot{}, 4},
+		{&TerminalSlot{TerminalID: terminalID, Weekday: 1, StartTime: "08:00", EndTime: "10:00", Capacity: 2}, 0},
+		{&TerminalSlot{TerminalID: terminalID, Weekday: 1, StartTime: "08:00", EndTime: "10:00", Capacity: 2, Size: nulls.NewString(ShipmentSize20ST.String()), Type: nulls.NewString(Outbound.String())}, 0},
+		{&TerminalSlot{TerminalID: terminalID, Weekday: -1, StartTime: "08:00", EndTime: "10:00", Capacity: 2}, 1},
+		{&TerminalSlot{TerminalID: terminalID, Weekday: 1, StartTime: "10:00", EndTime: "08:00", Capacity: 2}, 1},
+		{&TerminalSlot{TerminalID: terminalID, Weekday: 1, StartTime: "08:00", EndTime: "10:00", Capacity: 0}, 1},
+		{&TerminalSlot{TerminalID: terminalID, Weekday: 1, StartTime: "08:00", EndTime: "10:00", Capacity: 2, Size: nulls.NewString("45ST"), Type: nulls.NewString("Sideways")}, 2},
+	}
+	for i, test := range tests {
+		ms.T().Run(fmt.Sprint(i), func(t *testing.T) {
+			v, err := test.terminalSlot.Validate(ms.DB)
+			ms.Nil(err)
+			ms.Equal(test.expectedValidationErrors, len(v.Errors))
+		})
+	}
+}
+
+func (ms *ModelSuite) Test_TerminalSlotCovers() {
+	var monday = time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	var slot = TerminalSlot{Weekday: int(time.Monday), StartTime: "08:00", EndTime: "10:30"}
+	start, end := slot.WindowOn(monday.Add(15*time.Hour), time.UTC)
+	ms.Equal(monday.Add(8*time.Hour), start)
+	ms.Equal(monday.Add(10*time.Hour+30*time.Minute), end)
+	ms.True(slot.Covers(monday.Add(8*time.Hour), time.UTC))
+	ms.True(slot.Covers(monday.Add(10*time.Hour), time.UTC))
+	ms.False(slot.Covers(monday.Add(10*time.Hour+30*time.Minute), time.UTC))
+	ms.False(slot.Covers(monday.Add(7*time.Hour), time.UTC))
+	ms.False(slot.Covers(monday.AddDate(0, 0, 1).Add(9*time.Hour), time.UTC))
+	vancouver, err := time.LoadLocation("America/Vancouver")
+	ms.Nil(err)
+	ms.True(slot.Covers(time.Date(2026, 10, 19, 16, 0, 0, 0, time.UTC), vancouver))
+}
+
+func (ms *ModelSuite) Test_TerminalSlotAccepts() {
+	var tests = []struct {
+		name     string
+		slot     TerminalSlot
+		shipment Shipment
+		accepts  bool
+	}{
+		{"any", TerminalSlot{}, Shipment{Type: ShipmentTypeInbound.String()}, true},
+		{"size", TerminalSlot{Size: nulls.NewString(ShipmentSize20ST.String())}, Shipment{Type: ShipmentTypeInbound.String(), Size: nulls.NewString(ShipmentSize20ST.String())}, true},
+		{"other size", TerminalSlot{Size: nulls.NewString(ShipmentSize20ST.String())}, Shipment{Type: ShipmentTypeInbound.String(), Size: nulls.NewString(ShipmentSize40HC.String())}, false},
+		{"no size", TerminalSlot{Size: nulls.NewString(ShipmentSize20ST.String())}, Shipment{Type: ShipmentTypeInbound.String()}, false},
+		{"type", TerminalSlot{Type: nulls.NewString(ShipmentTypeInbound.String())}, Shipment{Type: ShipmentTypeInbound.String()}, true},
+		{"other type", TerminalSlot{Type: nulls.NewString(ShipmentTypeInbound.String())}, Shipment{Type: Outbound.String()}, false},
+	}
+	for _, test := range tests {
+		ms.T().Run(test.name, func(t *testing.T) {
+			ms.Equal(test.accepts, test.slot.Accepts(&test.shipment))
+		})
+	}
+}
@@ -3,6 +3,8 @@ package models
 import (
 	"fmt"
 	"testing"
+
+	"github.com/gobuffalo/nulls"
 )
 
 func (ms *ModelSuite) Test_Terminal() {
@@ -14,6 +16,17 @@ func (ms *ModelSuite) Test_Terminal() {
 		{&Terminal{Type: "invalid"}, 2},
 		{&Terminal{Name: "some name"}, 1},
 		{&Terminal{Name: "some name", Type: TerminalTypeAirport.String()}, 0},
+		{&Terminal{Name: "some name", Type: TerminalTypeAirport.String(), FreeTimeDays: nulls.NewInt(-1), DemurrageRate: NewNullMoney(-1, "CAD")}, 2},
+		{&Terminal{Name: "some name", Type: TerminalTypeAirport.String(), DemurrageRate: NewNullMoney(20000, "cad")}, 1},
+		{&Terminal{Name: "some name", Type: TerminalTypeAirport.String(), FreeTimeDays: nulls.NewInt(0), DemurrageRate: NewNullMoney(20000, "CAD")}, 0},
+		{&Terminal{Name: "some name", Type: TerminalTypeAirport.String(), ImportFreeTimeDays: nulls.NewInt(-1), ExportFreeTimeDays: nulls.NewInt(-1), GateCutoffMinutes: nulls.NewInt(-1)}, 3},
+		{&Terminal{Name: "some name", Type: TerminalTypeAirport.String(), Timezone: "Mars/Olympus"}, 1},
+		{&Terminal{Name: "some name", Type: TerminalTypeAirport.String(), Timezone: "America/Vancouver", ImportFreeTimeDays: nulls.NewInt(5), ExportFreeTimeDays: nulls.NewInt(3), GateCutoffMinutes: nulls.NewInt(30)}, 0},
+		{&Terminal{Name: "some name", Type: TerminalTypeAirport.String(), Latitude: nulls.NewFloat64(49.0069), Longitude: nulls.NewFloat64(-123.1548), GeofenceRadiusMeters: nulls.NewInt(500)}, 0},
+		{&Terminal{Name: "some name", Type: TerminalTypeAirport.String(), Longitude: nulls.NewFloat64(-123.1548), GeofenceRadiusMeters: nulls.NewInt(-5)}, 2},
+		{&Terminal{Name: "some name", Type: TerminalTypeAirport.String(), GeofencePolygon: GeoPolygon{{Lat: 49, Lng: -123.2}}}, 1},
+		{&Terminal{Name: "some name", Type: TerminalTypePort.String(), RequiredCredentials: DriverCredentials{"PortPass", "Twic"}}, 0},
+		{&Terminal{Name: "some name", Type: TerminalTypePort.String(), RequiredCredentials: DriverCredentials{"PortPass", "Visa"}}, 1},
 	}
 	for i, test := range tests {
 		ms.T().Run(fmt.Sprint(i), func(t *testing.T) {
@@ -23,3 +36,20 @@ func (ms *ModelSuite) Test_Terminal() {
 		})
 	}
 }
+
+func (ms *ModelSuite) Test_FindNearbyTerminals() {
+	var terminals = Terminals{
+		{Name: "Vanterm", Latitude: nulls.NewFloat64(vancouver.Lat), Longitude: nulls.NewFloat64(vancouver.Lng)},
+		{Name: "Seattle"},
+		{Name: "Deltaport", Latitude: nulls.NewFloat64(deltaport.Lat), Longitude: nulls.NewFloat64(deltaport.Lng)},
+		{Name: "Harbor Island", Latitude: nulls.NewFloat64(seattle.Lat), Longitude: nulls.NewFloat64(seattle.Lng)},
+	}
+	var at = GeoPoint{Lat: 49.05, Lng: -123.15}
+	nearby := FindNearbyTerminals(terminals, at, 50)
+	ms.Len(nearby, 2)
+	ms.Equal("Deltaport", nearby[0].Terminal.Name)
+	ms.Equal("Vanterm", nearby[1].Terminal.Name)
+	ms.InDelta(4.8, nearby[0].DistanceKm, 0.5)
+	ms.Len(FindNearbyTerminals(terminals, at, 300), 3)
+	ms.Len(FindNearbyTerminals(terminals, at, 1), 0)
+}
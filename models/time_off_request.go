@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gobuffalo/validate/v3"
+	"github.com/gobuffalo/validate/v3/validators"
+	"github.com/gofrs/uuid"
+)
+
+// TimeOffRequest is used by pop to map your time_off_requests database table to your go code.
+// Drivers request time off from StartsAt until EndsAt, it counts once back office approves it.
+type TimeOffRequest struct {
+	ID         uuid.UUID    `json:"id" db:"id"`
+	CreatedAt  time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time    `json:"updated_at" db:"updated_at"`
+	CreatedBy  uuid.UUID    `json:"created_by" db:"created_by"`
+	TenantID   uuid.UUID    `json:"tenant_id" db:"tenant_id"`
+	DriverID   uuid.UUID    `json:"driver_id" db:"driver_id"`
+	StartsAt   time.Time    `json:"starts_at" db:"starts_at"`
+	EndsAt     time.Time    `json:"ends_at" db:"ends_at"`
+	Reason     nulls.String `json:"reason" db:"reason"`
+	Status     string       `json:"status" db:"status"`
+	ReviewedBy nulls.UUID   `json:"reviewed_by" db:"reviewed_by"`
+	ReviewedAt nulls.Time   `json:"reviewed_at" db:"reviewed_at"`
+	Tenant     *Tenant      `belongs_to:"tenant" json:"-"`
+	Driver     *User        `belongs_to:"user" json:"driver,omitempty"`
+}
+
+// TimeOffRequests is not required by pop and may be deleted
+type TimeOffRequests []TimeOffRequest
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (t *TimeOffRequest) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.UUIDIsPresent{Field: t.DriverID, Name: "DriverID"},
+		&validators.TimeIsPresent{Field: t.StartsAt, Name: "StartsAt"},
+		&validators.FuncValidator{Fn: func() bool {
+			return t.EndsAt.After(t.StartsAt)
+		}, Field: "EndsAt", Name: "EndsAt", Message: "%s must be after StartsAt"},
+		&validators.FuncValidator{Fn: func() bool {
+			return IsValidTimeOffRequestStatus(t.Status)
+		}, Field: t.Status, Name: "Status"},
+	), nil
+}
+
+// IsReviewed checks if back office has approved or rejected the request
+func (t *TimeOffRequest) IsReviewed() bool {
+	return t.Status == TimeOffRequestStatusApproved.String() || t.Status == TimeOffRequestStatusRejected.String()
+}
+
+// Covers checks if a time falls within the time off
+func (t *TimeOffRequest) Covers(at time.Time) bool {
+	return !at.Before(t.StartsAt) && at.Before(t.EndsAt)
+}
@@ -0,0 +1,32 @@
+package models
+
+// AUTOGENERATED BY: HSM GEN
+
+// TimeOffRequestStatus represents the TimeOffRequestStatus enum
+type TimeOffRequestStatus string
+
+const (
+	// TimeOffRequestStatusRequested represents Requested TimeOffRequestStatus
+	TimeOffRequestStatusRequested TimeOffRequestStatus = "Requested"
+	// TimeOffRequestStatusApproved represents Approved TimeOffRequestStatus
+	TimeOffRequestStatusApproved TimeOffRequestStatus = "Approved"
+	// TimeOffRequestStatusRejected represents Rejected TimeOffRequestStatus
+	TimeOffRequestStatusRejected TimeOffRequestStatus = "Rejected"
+)
+
+var allowedTimeOffRequestStatus [3]TimeOffRequestStatus = [3]TimeOffRequestStatus{
+	TimeOffRequestStatusRequested,
+	TimeOffRequestStatusApproved,
+	TimeOffRequestStatusRejected,
+}
+
+// String returns the string representation of
+func (k TimeOffRequestStatus) String() string {
+	return string(k)
+}
+
+// IsValidTimeOffRequestStatus validates if the input is a TimeOffRequestStatus
+func IsValidTimeOffRequestStatus(s string) bool {
+	t := TimeOffRequestStatus(s)
+	return TimeOffRequestStatusRequested == t || TimeOffRequestStatusApproved == t || TimeOffRequestStatusRejected == t
+}
@@ -0,0 +1,20 @@
+package models_test
+
+// AUTOGENERATED BY: HSM GEN
+
+import (
+	"testing"
+
+	m "github.com/bigpanther/trober/models"
+)
+
+func TestIsValidTimeOffRequestStatus(t *testing.T) {
+	var validVal = "Requested"
+	var inValidVal = "_someInvalidval_"
+	if !m.IsValidTimeOffRequestStatus(validVal) {
+		t.Fatalf("IsValidTimeOffRequestStatus(%q) should be true", validVal)
+	}
+	if m.IsValidTimeOffRequestStatus(inValidVal) {
+		t.Fatalf("IsValidTimeOffRequestStatus(%q) should be false", inValidVal)
+	}
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+func (ms *ModelSuite) Test_TimeOffRequest() {
+	var driverID = uuid.Must(uuid.NewV4())
+	var start = time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	var tests = []struct {
+		timeOffRequest           *TimeOffRequest
+		expectedValidationErrors int
+	}{
+		{&TimeOffRequest{}, 4},
+		{&TimeOffRequest{DriverID: driverID, StartsAt: start, EndsAt: start.AddDate(0, 0, 2), Status: TimeOffRequestStatusRequested.String()}, 0},
+		{&TimeOffRequest{DriverID: driverID, StartsAt: start, EndsAt: start, Status: TimeOffRequestStatusApproved.String()}, 1},
+		{&TimeOffRequest{DriverID: driverID, StartsAt: start, EndsAt: start.Add(-time.Hour), Status: TimeOffRequestStatusApproved.String()}, 1},
+		{&TimeOffRequest{DriverID: driverID, StartsAt: start, EndsAt: start.AddDate(0, 0, 2), Status: "Pending"}, 1},
+	}
+	for i, test := range tests {
+		ms.T().Run(fmt.Sprint(i), func(t *testing.T) {
+			v, err := test.timeOffRequest.Validate(ms.DB)
+			ms.Nil(err)
+			ms.Equal(test.expectedValidationErrors, len(v.Errors))
+		})
+	}
+}
+
+func (ms *ModelSuite) Test_TimeOffRequestCovers() {
+	var start = time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	var timeOff = TimeOffRequest{StartsAt: start, EndsAt: start.AddDate(0, 0, 2)}
+	ms.True(timeOff.Covers(start))
+	ms.True(timeOff.Covers(start.Add(36 * time.Hour)))
+	ms.False(timeOff.Covers(start.AddDate(0, 0, 2)))
+	ms.False(timeOff.Covers(start.Add(-time.Minute)))
+	ms.False(timeOff.IsReviewed())
+	timeOff.Status = TimeOffRequestStatusRequested.String()
+	ms.False(timeOff.IsReviewed())
+	timeOff.Status = TimeOffRequestStatusRejected.String()
+	ms.True(timeOff.IsReviewed())
+}